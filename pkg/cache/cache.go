@@ -0,0 +1,18 @@
+package cache
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Interface is a store for the objects produced by a renderer, keyed by an opaque string.
+//
+// The engine deep copies objects both before Set and after Get, so implementations
+// may store and return the slices they are given as-is.
+// Implementations must be safe for concurrent use, as renderers may run in parallel.
+type Interface interface {
+	// Get returns the objects stored under key and whether they were found.
+	Get(key string) ([]unstructured.Unstructured, bool)
+
+	// Set stores objects under key.
+	Set(key string, objects []unstructured.Unstructured)
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// LRU is an in-memory cache holding a fixed number of entries.
+// When full, setting a new key evicts the least recently used entry.
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type lruEntry struct {
+	key     string
+	objects []unstructured.Unstructured
+}
+
+// NewLRU creates an LRU cache holding at most capacity entries.
+// A capacity lower than 1 is treated as 1.
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: max(capacity, 1),
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the objects stored under key and marks the entry as most recently used.
+func (c *LRU) Get(key string) ([]unstructured.Unstructured, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+
+	//nolint:forcetypeassert
+	return elem.Value.(*lruEntry).objects, true
+}
+
+// Set stores objects under key, evicting the least recently used entry if the cache is full.
+func (c *LRU) Set(key string, objects []unstructured.Unstructured) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		//nolint:forcetypeassert
+		elem.Value.(*lruEntry).objects = objects
+		c.order.MoveToFront(elem)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, objects: objects})
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+
+		//nolint:forcetypeassert
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
@@ -0,0 +1,82 @@
+package cache_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/cache"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLRU(t *testing.T) {
+
+	t.Run("should return stored objects", func(t *testing.T) {
+		g := NewWithT(t)
+		c := cache.NewLRU(2)
+
+		c.Set("a", []unstructured.Unstructured{makePod("pod1")})
+
+		objects, ok := c.Get("a")
+		g.Expect(ok).Should(BeTrue())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("pod1"))
+
+		_, ok = c.Get("b")
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should evict the least recently used entry", func(t *testing.T) {
+		g := NewWithT(t)
+		c := cache.NewLRU(2)
+
+		c.Set("a", []unstructured.Unstructured{makePod("pod1")})
+		c.Set("b", []unstructured.Unstructured{makePod("pod2")})
+
+		// touch "a" so that "b" becomes the least recently used
+		_, ok := c.Get("a")
+		g.Expect(ok).Should(BeTrue())
+
+		c.Set("c", []unstructured.Unstructured{makePod("pod3")})
+		g.Expect(c.Len()).Should(Equal(2))
+
+		_, ok = c.Get("b")
+		g.Expect(ok).Should(BeFalse())
+		_, ok = c.Get("a")
+		g.Expect(ok).Should(BeTrue())
+		_, ok = c.Get("c")
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should replace existing entries", func(t *testing.T) {
+		g := NewWithT(t)
+		c := cache.NewLRU(2)
+
+		c.Set("a", []unstructured.Unstructured{makePod("pod1")})
+		c.Set("a", []unstructured.Unstructured{makePod("pod2")})
+		g.Expect(c.Len()).Should(Equal(1))
+
+		objects, ok := c.Get("a")
+		g.Expect(ok).Should(BeTrue())
+		g.Expect(objects[0].GetName()).Should(Equal("pod2"))
+	})
+
+	t.Run("should hold at least one entry", func(t *testing.T) {
+		g := NewWithT(t)
+		c := cache.NewLRU(0)
+
+		c.Set("a", []unstructured.Unstructured{makePod("pod1")})
+		_, ok := c.Get("a")
+		g.Expect(ok).Should(BeTrue())
+	})
+}
+
+func makePod(name string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	obj.SetName(name)
+
+	return obj
+}
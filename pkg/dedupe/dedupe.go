@@ -0,0 +1,176 @@
+// Package dedupe combines duplicate objects of a render result.
+package dedupe
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// ErrConflict is returned when duplicate objects set different values for the same field.
+var ErrConflict = errors.New("conflicting values")
+
+// provenanceAnnotations are kept from the first copy of an object, since copies produced by
+// different renderers legitimately disagree on them.
+//
+//nolint:gochecknoglobals
+var provenanceAnnotations = []string{
+	types.AnnotationSourceType,
+	types.AnnotationSourcePath,
+	types.AnnotationSourceFile,
+	types.AnnotationSourceName,
+}
+
+type key struct {
+	gvk schema.GroupVersionKind
+	nn  k8stypes.NamespacedName
+}
+
+// Merge combines objects sharing the same GroupVersionKind, namespace and name into a single
+// object, for resources to which several renderers each contribute a part.
+//
+// Copies are deep merged in order: maps, including labels, annotations and spec, are combined
+// key by key, and the merged object takes the position of the first copy. Any other value,
+// including lists, must be equal in every copy that sets it; otherwise Merge returns an error
+// wrapping ErrConflict and naming the object and the path of the conflicting field.
+// Provenance annotations (types.AnnotationSource*) are taken from the first copy that sets them.
+// Objects without a name are never merged. The input objects are not modified.
+func Merge(objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	result := make([]unstructured.Unstructured, 0, len(objects))
+	index := make(map[key]int, len(objects))
+
+	for _, obj := range objects {
+		if obj.GetName() == "" {
+			result = append(result, obj)
+
+			continue
+		}
+
+		k := key{
+			gvk: obj.GroupVersionKind(),
+			nn:  k8stypes.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		}
+
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(result)
+			result = append(result, obj)
+
+			continue
+		}
+
+		merged, err := merge(result[i], obj)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"unable to merge %s:%s %s (namespace: %s): %w",
+				k.gvk.GroupVersion(),
+				k.gvk.Kind,
+				k.nn.Name,
+				k.nn.Namespace,
+				err,
+			)
+		}
+
+		result[i] = merged
+	}
+
+	return result, nil
+}
+
+// merge deep merges overlay into a copy of base.
+func merge(base unstructured.Unstructured, overlay unstructured.Unstructured) (unstructured.Unstructured, error) {
+	overlay = *overlay.DeepCopy()
+
+	// provenance annotations of the first copy win
+	if annotations := overlay.GetAnnotations(); annotations != nil {
+		existing := base.GetAnnotations()
+		for _, name := range provenanceAnnotations {
+			if _, ok := existing[name]; ok {
+				delete(annotations, name)
+			}
+		}
+
+		if len(annotations) == 0 {
+			unstructured.RemoveNestedField(overlay.Object, "metadata", "annotations")
+		} else {
+			overlay.SetAnnotations(annotations)
+		}
+	}
+
+	result := base.DeepCopy()
+	if err := mergeMaps(result.Object, overlay.Object, nil); err != nil {
+		return unstructured.Unstructured{}, err
+	}
+
+	return *result, nil
+}
+
+// mergeMaps merges overlay into base in place. path is the location of base within the object.
+func mergeMaps(base map[string]any, overlay map[string]any, path []string) error {
+	keys := make([]string, 0, len(overlay))
+	for k := range overlay {
+		keys = append(keys, k)
+	}
+
+	// sorted for a deterministic choice of the reported conflict
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		value := overlay[k]
+
+		existing, ok := base[k]
+		if !ok || existing == nil {
+			base[k] = value
+
+			continue
+		}
+
+		if value == nil {
+			continue
+		}
+
+		existingMap, existingIsMap := existing.(map[string]any)
+		valueMap, valueIsMap := value.(map[string]any)
+
+		if existingIsMap && valueIsMap {
+			if err := mergeMaps(existingMap, valueMap, append(path, k)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !equality.Semantic.DeepEqual(existing, value) {
+			return fmt.Errorf("%w at %s: %v != %v", ErrConflict, fieldPath(append(path, k)), existing, value)
+		}
+	}
+
+	return nil
+}
+
+// fieldPath formats path as a dotted field path, bracketing keys that contain dots or slashes,
+// e.g. metadata.labels[app.kubernetes.io/name].
+func fieldPath(path []string) string {
+	var sb strings.Builder
+
+	for i, k := range path {
+		switch {
+		case strings.ContainsAny(k, "./"):
+			sb.WriteString("[" + k + "]")
+		case i > 0:
+			sb.WriteString("." + k)
+		default:
+			sb.WriteString(k)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,154 @@
+package dedupe_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/dedupe"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeDeployment(name string, metadata map[string]any, spec map[string]any) unstructured.Unstructured {
+	meta := map[string]any{"name": name, "namespace": "default"}
+	for k, v := range metadata {
+		meta[k] = v
+	}
+
+	obj := map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   meta,
+	}
+
+	if spec != nil {
+		obj["spec"] = spec
+	}
+
+	return unstructured.Unstructured{Object: obj}
+}
+
+func TestMerge(t *testing.T) {
+
+	t.Run("should merge labels, annotations and specs of duplicates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := []unstructured.Unstructured{
+			makeDeployment("app", map[string]any{
+				"labels": map[string]any{"app": "web"},
+			}, map[string]any{
+				"replicas": int64(2),
+			}),
+			makeDeployment("other", nil, nil),
+			makeDeployment("app", map[string]any{
+				"labels":      map[string]any{"tier": "frontend"},
+				"annotations": map[string]any{"team": "a"},
+			}, map[string]any{
+				"replicas": int64(2),
+				"template": map[string]any{"metadata": map[string]any{"labels": map[string]any{"app": "web"}}},
+			}),
+		}
+
+		result, err := dedupe.Merge(objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(2))
+		g.Expect(result[0].GetName()).Should(Equal("app"))
+		g.Expect(result[1].GetName()).Should(Equal("other"))
+
+		g.Expect(result[0].GetLabels()).Should(Equal(map[string]string{"app": "web", "tier": "frontend"}))
+		g.Expect(result[0].GetAnnotations()).Should(Equal(map[string]string{"team": "a"}))
+
+		replicas, _, _ := unstructured.NestedInt64(result[0].Object, "spec", "replicas")
+		g.Expect(replicas).Should(Equal(int64(2)))
+		g.Expect(result[0].Object).Should(HaveKey("spec"))
+		g.Expect(result[0].Object["spec"]).Should(HaveKey("template"))
+
+		// the inputs are left untouched
+		g.Expect(objects[0].GetLabels()).Should(Equal(map[string]string{"app": "web"}))
+	})
+
+	t.Run("should report the path of a conflicting scalar", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := dedupe.Merge([]unstructured.Unstructured{
+			makeDeployment("app", nil, map[string]any{"replicas": int64(1)}),
+			makeDeployment("app", nil, map[string]any{"replicas": int64(3)}),
+		})
+		g.Expect(err).Should(MatchError(dedupe.ErrConflict))
+		g.Expect(err).Should(MatchError(ContainSubstring("apps/v1:Deployment app (namespace: default)")))
+		g.Expect(err).Should(MatchError(ContainSubstring("at spec.replicas: 1 != 3")))
+	})
+
+	t.Run("should bracket keys containing dots in the conflict path", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := dedupe.Merge([]unstructured.Unstructured{
+			makeDeployment("app", map[string]any{"labels": map[string]any{"app.kubernetes.io/name": "a"}}, nil),
+			makeDeployment("app", map[string]any{"labels": map[string]any{"app.kubernetes.io/name": "b"}}, nil),
+		})
+		g.Expect(err).Should(MatchError(ContainSubstring("at metadata.labels[app.kubernetes.io/name]")))
+	})
+
+	t.Run("should treat differing lists as conflicts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := dedupe.Merge([]unstructured.Unstructured{
+			makeDeployment("app", nil, map[string]any{"args": []any{"a"}}),
+			makeDeployment("app", nil, map[string]any{"args": []any{"b"}}),
+		})
+		g.Expect(err).Should(MatchError(dedupe.ErrConflict))
+		g.Expect(err).Should(MatchError(ContainSubstring("at spec.args")))
+	})
+
+	t.Run("should keep the provenance of the first copy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := dedupe.Merge([]unstructured.Unstructured{
+			makeDeployment("app", map[string]any{
+				"annotations": map[string]any{types.AnnotationSourceName: "helm"},
+			}, nil),
+			makeDeployment("app", map[string]any{
+				"annotations": map[string]any{types.AnnotationSourceName: "kustomize", "team": "a"},
+			}, nil),
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(1))
+		g.Expect(result[0].GetAnnotations()).Should(Equal(map[string]string{
+			types.AnnotationSourceName: "helm",
+			"team":                     "a",
+		}))
+	})
+
+	t.Run("should not merge objects of different kinds or namespaces", func(t *testing.T) {
+		g := NewWithT(t)
+
+		other := makeDeployment("app", map[string]any{"namespace": "other"}, nil)
+		service := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata":   map[string]any{"name": "app", "namespace": "default"},
+		}}
+
+		result, err := dedupe.Merge([]unstructured.Unstructured{makeDeployment("app", nil, nil), other, service})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(3))
+	})
+
+	t.Run("should not merge objects without a name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		unnamed := func() unstructured.Unstructured {
+			return unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "batch/v1",
+				"kind":       "Job",
+				"metadata":   map[string]any{"generateName": "migrate-"},
+			}}
+		}
+
+		result, err := dedupe.Merge([]unstructured.Unstructured{unnamed(), unnamed()})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(2))
+	})
+}
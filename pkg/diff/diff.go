@@ -0,0 +1,297 @@
+// Package diff predicts the changes applying rendered objects would make to a live cluster.
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/normalize"
+)
+
+// Action describes what applying an object would do.
+type Action string
+
+const (
+	// ActionCreate means the object does not exist and would be created.
+	ActionCreate Action = "create"
+
+	// ActionUpdate means the object exists and would be changed.
+	ActionUpdate Action = "update"
+
+	// ActionNone means the object exists and would be left unchanged.
+	ActionNone Action = "none"
+
+	// ActionError means the change could not be predicted, see ObjectDiff.Err.
+	ActionError Action = "error"
+)
+
+// Change is a single field difference between the live and the predicted object.
+type Change struct {
+	// Path is the dot-separated path of the field, e.g. "spec.replicas".
+	Path string
+
+	// Live is the current value of the field, nil for an added field.
+	Live any
+
+	// Predicted is the value of the field after applying, nil for a removed field.
+	Predicted any
+}
+
+// ObjectDiff is the predicted effect of applying a single rendered object.
+type ObjectDiff struct {
+	// Object is the rendered object.
+	Object unstructured.Unstructured
+
+	// Action is what applying the object would do.
+	Action Action
+
+	// Added are the fields the object would gain.
+	Added []Change
+
+	// Removed are the fields the object would lose.
+	Removed []Change
+
+	// Changed are the fields whose value would change.
+	Changed []Change
+
+	// DryRun reports whether the prediction comes from a server-side apply dry-run. When false,
+	// the server does not support dry-run for this object and the rendered object was compared
+	// to the live one directly, so only added and changed fields are reported.
+	DryRun bool
+
+	// Err is the error that occurred, if Action is ActionError.
+	Err error
+}
+
+// Against fetches the live counterpart of each rendered object and returns one ObjectDiff per
+// object, in the same order. It is a consumer of the objects returned by Engine.Render and is
+// not part of the render pipeline; nothing is persisted.
+//
+// Existing objects are submitted as a dry-run server-side apply, so the diff accounts for
+// defaulting, admission and the merge with fields owned by other managers. Objects that do
+// not exist are reported with ActionCreate and no field changes.
+// A failure does not stop the remaining objects from being compared; all failures are also
+// returned, joined, as the error.
+func Against(
+	ctx context.Context,
+	client dynamic.Interface,
+	objs []unstructured.Unstructured,
+	opts ...Option,
+) ([]ObjectDiff, error) {
+	options := Options{
+		FieldManager: DefaultFieldManager,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	if options.IgnoreFields == nil {
+		options.IgnoreFields = normalize.DefaultFields()
+	}
+
+	results := make([]ObjectDiff, 0, len(objs))
+
+	var errs []error
+
+	for _, obj := range objs {
+		result := against(ctx, client, options, obj)
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func against(ctx context.Context, client dynamic.Interface, options Options, obj unstructured.Unstructured) ObjectDiff {
+	ri, err := resourceFor(client, options.RESTMapper, obj)
+	if err != nil {
+		return failed(obj, err)
+	}
+
+	live, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return ObjectDiff{
+			Object: obj,
+			Action: ActionCreate,
+		}
+	}
+
+	if err != nil {
+		return failed(obj, fmt.Errorf("failed to get live object: %w", err))
+	}
+
+	dryRun := true
+
+	predicted, err := ri.Apply(ctx, obj.GetName(), &obj, metav1.ApplyOptions{
+		DryRun:       []string{metav1.DryRunAll},
+		FieldManager: options.FieldManager,
+		Force:        true,
+	})
+
+	switch {
+	case err == nil:
+	case apierrors.IsMethodNotSupported(err) || apierrors.IsUnsupportedMediaType(err):
+		dryRun = false
+	default:
+		return failed(obj, fmt.Errorf("dry-run apply failed: %w", err))
+	}
+
+	liveContent, err := normalized(live.Object, options.IgnoreFields)
+	if err != nil {
+		return failed(obj, err)
+	}
+
+	var predictedContent map[string]any
+	if dryRun {
+		predictedContent, err = normalized(predicted.Object, options.IgnoreFields)
+	} else {
+		predictedContent, err = normalized(obj.Object, options.IgnoreFields)
+	}
+
+	if err != nil {
+		return failed(obj, err)
+	}
+
+	result := ObjectDiff{
+		Object: obj,
+		DryRun: dryRun,
+	}
+
+	compare(nil, liveContent, predictedContent, dryRun, &result)
+
+	result.Action = ActionNone
+	if len(result.Added)+len(result.Removed)+len(result.Changed) > 0 {
+		result.Action = ActionUpdate
+	}
+
+	return result
+}
+
+// normalized returns a copy of content without the ignored fields, with numbers normalized
+// to int64 or float64 as the API server would return them.
+func normalized(content map[string]any, ignoreFields []string) (map[string]any, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode object: %w", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode object: %w", err)
+	}
+
+	for _, field := range ignoreFields {
+		unstructured.RemoveNestedField(result, strings.Split(field, ".")...)
+	}
+
+	return result, nil
+}
+
+// compare records the differences between the live and predicted maps at path.
+// Lists are compared as a whole. Fields missing from predicted are only reported as removed
+// when complete is set, i.e. when predicted is the full object rather than the rendered subset.
+func compare(path []string, live map[string]any, predicted map[string]any, complete bool, result *ObjectDiff) {
+	keys := make([]string, 0, len(live)+len(predicted))
+	for key := range live {
+		keys = append(keys, key)
+	}
+
+	for key := range predicted {
+		if _, ok := live[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		fieldPath := append(slices.Clone(path), key)
+		liveValue, inLive := live[key]
+		predictedValue, inPredicted := predicted[key]
+
+		switch {
+		case !inLive:
+			result.Added = append(result.Added, Change{Path: strings.Join(fieldPath, "."), Predicted: predictedValue})
+		case !inPredicted:
+			if complete {
+				result.Removed = append(result.Removed, Change{Path: strings.Join(fieldPath, "."), Live: liveValue})
+			}
+		default:
+			liveMap, liveIsMap := liveValue.(map[string]any)
+			predictedMap, predictedIsMap := predictedValue.(map[string]any)
+
+			if liveIsMap && predictedIsMap {
+				compare(fieldPath, liveMap, predictedMap, complete, result)
+
+				continue
+			}
+
+			if !reflect.DeepEqual(liveValue, predictedValue) {
+				result.Changed = append(result.Changed, Change{
+					Path:      strings.Join(fieldPath, "."),
+					Live:      liveValue,
+					Predicted: predictedValue,
+				})
+			}
+		}
+	}
+}
+
+// resourceFor returns the dynamic resource client for the object's kind and scope.
+func resourceFor(
+	client dynamic.Interface,
+	mapper meta.RESTMapper,
+	obj unstructured.Unstructured,
+) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+
+	if mapper == nil {
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		if obj.GetNamespace() == "" {
+			return client.Resource(gvr), nil
+		}
+
+		return client.Resource(gvr).Namespace(obj.GetNamespace()), nil
+	}
+
+	mapping, err := mapper.RESTMapping(schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}, gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to map %s to a resource: %w", gvk, err)
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return client.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+	}
+
+	return client.Resource(mapping.Resource), nil
+}
+
+func failed(obj unstructured.Unstructured, err error) ObjectDiff {
+	return ObjectDiff{
+		Object: obj,
+		Action: ActionError,
+		Err: fmt.Errorf(
+			"failed to diff %s %s (namespace: %s): %w",
+			obj.GroupVersionKind(),
+			obj.GetName(),
+			obj.GetNamespace(),
+			err,
+		),
+	}
+}
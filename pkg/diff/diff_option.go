@@ -0,0 +1,68 @@
+package diff
+
+import (
+	"slices"
+
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// DefaultFieldManager is the field manager used for dry-run requests when none is configured.
+const DefaultFieldManager = "k8s-manifest-kit"
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of a diff.
+type Options struct {
+	// FieldManager is the field manager sent with dry-run apply requests.
+	FieldManager string
+
+	// RESTMapper maps object kinds to API resources.
+	// If nil, resources are guessed from the kind and scope is inferred from metadata.namespace.
+	RESTMapper meta.RESTMapper
+
+	// IgnoreFields are the dot-separated paths of the fields left out of the comparison.
+	// If nil, normalize.DefaultFields() is used: status and server-populated metadata.
+	IgnoreFields []string
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.FieldManager != "" {
+		target.FieldManager = opts.FieldManager
+	}
+
+	if opts.RESTMapper != nil {
+		target.RESTMapper = opts.RESTMapper
+	}
+
+	if opts.IgnoreFields != nil {
+		target.IgnoreFields = slices.Clone(opts.IgnoreFields)
+	}
+}
+
+// WithFieldManager sets the field manager sent with dry-run apply requests.
+func WithFieldManager(fieldManager string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.FieldManager = fieldManager
+	})
+}
+
+// WithRESTMapper sets the RESTMapper used to resolve object kinds to API resources.
+// Without a mapper, the resource name is guessed from the kind, which does not work for
+// kinds with irregular plurals.
+func WithRESTMapper(mapper meta.RESTMapper) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.RESTMapper = mapper
+	})
+}
+
+// WithIgnoreFields sets the dot-separated paths of the fields left out of the comparison,
+// replacing the defaults.
+func WithIgnoreFields(fields ...string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.IgnoreFields = fields
+	})
+}
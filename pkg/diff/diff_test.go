@@ -0,0 +1,162 @@
+package diff_test
+
+import (
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/k8s-manifest-kit/engine/pkg/diff"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testNamespace = "default"
+)
+
+func TestAgainst(t *testing.T) {
+
+	t.Run("should mark missing objects as to be created", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+		results, err := diff.Against(t.Context(), client, []unstructured.Unstructured{
+			*makeConfigMap("new", map[string]any{"key": "value"}),
+		}, diff.WithRESTMapper(newMapper()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(results).Should(HaveLen(1))
+		g.Expect(results[0].Action).Should(Equal(diff.ActionCreate))
+		g.Expect(results[0].Changed).Should(BeEmpty())
+	})
+
+	t.Run("should diff the dry-run result against the live object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		live := makeConfigMap("app", map[string]any{"keep": "same", "update": "old", "drop": "gone"})
+		live.SetResourceVersion("1")
+		live.SetUID("uid")
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), live)
+
+		var patchType string
+		client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			patchType = string(action.(k8stesting.PatchAction).GetPatchType())
+
+			predicted := makeConfigMap("app", map[string]any{"keep": "same", "update": "new", "add": "value"})
+			predicted.SetResourceVersion("2")
+			predicted.SetUID("uid")
+
+			return true, predicted, nil
+		})
+
+		results, err := diff.Against(t.Context(), client, []unstructured.Unstructured{
+			*makeConfigMap("app", map[string]any{"keep": "same", "update": "new", "add": "value"}),
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(patchType).Should(Equal("application/apply-patch+yaml"))
+
+		result := results[0]
+		g.Expect(result.Action).Should(Equal(diff.ActionUpdate))
+		g.Expect(result.DryRun).Should(BeTrue())
+		g.Expect(result.Added).Should(Equal([]diff.Change{{Path: "data.add", Predicted: "value"}}))
+		g.Expect(result.Removed).Should(Equal([]diff.Change{{Path: "data.drop", Live: "gone"}}))
+		g.Expect(result.Changed).Should(Equal([]diff.Change{{Path: "data.update", Live: "old", Predicted: "new"}}))
+	})
+
+	t.Run("should report unchanged objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		live := makeConfigMap("app", map[string]any{"key": "value"})
+		live.SetResourceVersion("1")
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), live)
+		client.PrependReactor("patch", "*", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+			predicted := makeConfigMap("app", map[string]any{"key": "value"})
+			predicted.SetResourceVersion("2")
+
+			return true, predicted, nil
+		})
+
+		results, err := diff.Against(t.Context(), client, []unstructured.Unstructured{
+			*makeConfigMap("app", map[string]any{"key": "value"}),
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(results[0].Action).Should(Equal(diff.ActionNone))
+	})
+
+	t.Run("should compare the rendered object when dry-run is not supported", func(t *testing.T) {
+		g := NewWithT(t)
+
+		live := makeConfigMap("app", map[string]any{"update": "old", "other": "kept"})
+		live.Object["spec"] = map[string]any{"replicas": int64(1)}
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), live)
+		client.PrependReactor("patch", "*", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewMethodNotSupported(schema.GroupResource{Resource: "configmaps"}, "patch")
+		})
+
+		rendered := makeConfigMap("app", map[string]any{"update": "new"})
+		rendered.Object["spec"] = map[string]any{"replicas": 1}
+
+		results, err := diff.Against(t.Context(), client, []unstructured.Unstructured{*rendered})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result := results[0]
+		g.Expect(result.DryRun).Should(BeFalse())
+		g.Expect(result.Action).Should(Equal(diff.ActionUpdate))
+		g.Expect(result.Changed).Should(Equal([]diff.Change{{Path: "data.update", Live: "old", Predicted: "new"}}))
+		g.Expect(result.Removed).Should(BeEmpty())
+	})
+
+	t.Run("should continue after a failure and report it", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		client.PrependReactor("get", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.(k8stesting.GetAction).GetName() == "bad" {
+				return true, nil, errors.New("connection refused")
+			}
+
+			return false, nil, nil
+		})
+
+		results, err := diff.Against(t.Context(), client, []unstructured.Unstructured{
+			*makeConfigMap("bad", nil),
+			*makeConfigMap("good", nil),
+		})
+		g.Expect(err).Should(MatchError(ContainSubstring("connection refused")))
+		g.Expect(results).Should(HaveLen(2))
+		g.Expect(results[0].Action).Should(Equal(diff.ActionError))
+		g.Expect(results[0].Err).Should(MatchError(ContainSubstring("failed to diff /v1, Kind=ConfigMap bad")))
+		g.Expect(results[1].Action).Should(Equal(diff.ActionCreate))
+	})
+}
+
+func newMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	return mapper
+}
+
+func makeConfigMap(name string, data map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName(name)
+	obj.SetNamespace(testNamespace)
+
+	if data != nil {
+		obj.Object["data"] = data
+	}
+
+	return obj
+}
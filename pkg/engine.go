@@ -1,45 +1,133 @@
 package engine
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"slices"
 	"sync"
 	"time"
 
 	"github.com/k8s-manifest-kit/pkg/util/metrics"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
 	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/meta/namespace"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/provenance"
 	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/scope"
 )
 
+// ErrRendererEmpty is the renderer error reported when WithRequireNonEmpty is enabled and a
+// renderer produces no objects.
+var ErrRendererEmpty = errors.New("renderer produced no objects")
+
 // Engine represents the core manifest rendering and processing engine.
 type Engine struct {
-	options Options
+	options      Options
+	tracer       trace.Tracer
+	valuesSchema *spec.Schema
 }
 
 // New creates a new Engine with the given options.
+//
+// Struct-based options (Options or *Options) and functional options (WithRenderer, WithFilter, ...)
+// may be mixed. Whatever their position in opts, struct-based options are applied first, in order,
+// to form a base, and functional options are then applied on top of it, in order:
+//   - renderers, filters, transformers and validators accumulate, struct-based ones first
+//   - a functional option overrides the value of a struct-based one, e.g. WithParallel(false)
+//     disables parallel rendering enabled by Options{Parallel: true}
+//   - zero-valued struct fields never override a previously set value
 func New(opts ...Option) (*Engine, error) {
 	options := Options{
-		Renderers:    make([]types.Renderer, 0),
-		Filters:      make([]types.Filter, 0),
-		Transformers: make([]types.Transformer, 0),
+		Renderers:  make([]types.Renderer, 0),
+		Stages:     make([]types.Stage, 0),
+		Validators: make([]types.Validator, 0),
 	}
 
+	functional := make([]Option, 0, len(opts))
+
 	for _, opt := range opts {
+		switch o := opt.(type) {
+		case Options:
+			o.ApplyTo(&options)
+		case *Options:
+			if o != nil {
+				o.ApplyTo(&options)
+			}
+		default:
+			functional = append(functional, opt)
+		}
+	}
+
+	for _, opt := range functional {
 		opt.ApplyTo(&options)
 	}
 
+	if options.Logger == nil {
+		options.Logger = discardLogger
+	}
+
+	if options.TracerProvider == nil {
+		options.TracerProvider = noop.NewTracerProvider()
+	}
+
+	if options.Metrics == nil {
+		options.Metrics = noopMetrics{}
+	}
+
+	kubeVersion, err := kubeVersion(options.KubeVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var valuesSchema *spec.Schema
+	if options.ValuesSchema != nil {
+		if valuesSchema, err = parseValuesSchema(options.ValuesSchema); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validateErrorMode(options.FilterErrorMode); err != nil {
+		return nil, fmt.Errorf("engine filter error: %w", err)
+	}
+
+	if err := validateErrorMode(options.TransformerErrorMode); err != nil {
+		return nil, fmt.Errorf("engine transformer error: %w", err)
+	}
+
+	options.KubeVersion = kubeVersion
+
 	for _, renderer := range options.Renderers {
 		if err := types.ValidateRenderer(renderer); err != nil {
 			return nil, fmt.Errorf("invalid renderer: %w", err)
 		}
 	}
 
+	for i, stage := range options.Stages {
+		if err := types.ValidateStage(stage); err != nil {
+			return nil, fmt.Errorf("invalid stage %d: %w", i, err)
+		}
+	}
+
+	if len(options.RendererPriorities) > 0 {
+		slices.SortStableFunc(options.Renderers, func(a types.Renderer, b types.Renderer) int {
+			return cmp.Compare(options.RendererPriorities[a.Name()], options.RendererPriorities[b.Name()])
+		})
+	}
+
 	e := Engine{
-		options: options,
+		options:      options,
+		tracer:       options.TracerProvider.Tracer(tracerName),
+		valuesSchema: valuesSchema,
 	}
 
 	return &e, nil
@@ -50,86 +138,361 @@ func New(opts ...Option) (*Engine, error) {
 //
 // The rendering pipeline has three stages for filters and transformers:
 //  1. renderer-specific: Each renderer applies its own filters/transformers during Process()
-//  2. engine-level: Filters/transformers configured via New() are applied to aggregated results
+//  2. engine-level: Filters/transformers configured via New() are applied to the results of each renderer
 //  3. render-time: Filters/transformers passed via opts are merged with engine-level ones
 //
 // Render-time options are additive - they append to engine-level options.
+// Hooks registered with WithHook run before any renderer and on the final objects.
+// Engine-level validators run last, on the objects that would be returned.
 // Render-time values are passed to all renderers and deep merged with Source-level values.
 func (e *Engine) Render(ctx context.Context, opts ...RenderOption) ([]unstructured.Unstructured, error) {
+	objects, _, err := e.RenderWithReport(ctx, opts...)
+
+	return objects, err
+}
+
+// RenderWithReport behaves like Render but additionally returns a RenderReport describing
+// per-renderer timing and object counts, the number of objects dropped by filters, and totals.
+//
+// The report is always non-nil, including when rendering fails: in that case it contains
+// the entries of all renderers executed so far, including the failing one.
+func (e *Engine) RenderWithReport(
+	ctx context.Context,
+	opts ...RenderOption,
+) (_ []unstructured.Unstructured, _ *RenderReport, err error) {
 	startTime := time.Now()
+	report := &RenderReport{}
 
-	// Initialize render options by cloning the engine's options
-	renderOpts := RenderOptions{
-		Filters:      slices.Clone(e.options.Filters),
-		Transformers: slices.Clone(e.options.Transformers),
-		Values:       make(map[string]any),
+	ctx = types.WithRenderState(ctx)
+	ctx = types.WithWarningHandler(ctx, e.warningHandler(report))
+	ctx, span := e.tracer.Start(ctx, spanRender)
+
+	defer func() {
+		report.Duration = time.Since(startTime)
+
+		span.SetAttributes(attrObjectCount.Int(report.ObjectCount))
+		endSpan(span, err)
+	}()
+
+	renderOpts, err := e.renderOptions(opts)
+	if err != nil {
+		return nil, report, err
 	}
 
-	// Apply render options
-	for _, opt := range opts {
-		opt.ApplyTo(&renderOpts)
+	ctx, cancel := withRenderDeadline(ctx, renderOpts.Deadline)
+	defer cancel()
+
+	defer func() {
+		err = deadlineError(ctx, err)
+	}()
+
+	if renderOpts.KubeVersion != "" {
+		ctx = types.WithKubeVersion(ctx, renderOpts.KubeVersion)
+	}
+
+	renderOpts.Values, err = e.beforeRender(ctx, renderOpts.Values)
+	if err != nil {
+		return nil, report, err
 	}
 
-	var allObjects []unstructured.Unstructured
-	var err error
+	if err := e.validateValues(renderOpts.Values); err != nil {
+		return nil, report, err
+	}
+
+	p := e.newPipeline(ctx, renderOpts)
+
+	var transformed []unstructured.Unstructured
 
 	// Process renderers in parallel or sequentially
 	if e.options.Parallel {
-		allObjects, err = e.renderParallel(ctx, renderOpts.Values)
+		transformed, err = e.renderParallel(ctx, renderOpts, p, report)
 	} else {
-		allObjects, err = e.renderSequential(ctx, renderOpts.Values)
+		transformed, err = e.renderSequential(ctx, renderOpts, p, report)
+	}
+
+	for _, rr := range report.Renderers {
+		report.RenderedCount += rr.ObjectCount
+		report.DroppedCount += rr.DroppedCount
+		report.SkippedCount += rr.SkippedCount
+	}
+
+	if err == nil {
+		err = p.err()
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("rendering failed: %w", err)
+		return nil, report, err
 	}
 
-	// Apply filters
-	filtered, err := pipeline.ApplyFilters(ctx, allObjects, renderOpts.Filters)
+	transformed, err = e.dedupe(transformed)
 	if err != nil {
-		return nil, fmt.Errorf("engine filter error: %w", err)
+		return nil, report, err
 	}
 
-	// Apply transformers
-	transformed, err := pipeline.ApplyTransformers(ctx, filtered, renderOpts.Transformers)
+	transformed, err = e.afterRender(ctx, transformed)
 	if err != nil {
-		return nil, fmt.Errorf("engine transformer error: %w", err)
+		return nil, report, err
 	}
 
-	metrics.ObserveRender(ctx, time.Since(startTime), len(transformed))
+	transformed, err = e.stripSourceAnnotations(ctx, transformed)
+	if err != nil {
+		return nil, report, err
+	}
+
+	// Validate the final objects
+	if err := pipeline.ApplyValidators(ctx, transformed, e.options.Validators); err != nil {
+		return nil, report, fmt.Errorf("engine validation error: %w", err)
+	}
+
+	report.ObjectCount = len(transformed)
+
+	duration := time.Since(startTime)
+	metrics.ObserveRender(ctx, duration, len(transformed))
+	e.options.Metrics.ObserveRender(duration, len(transformed))
+
+	return transformed, report, nil
+}
+
+// renderOptions merges the render-time options of a Render() call with the engine's options,
+// and resolves the render-time values.
+func (e *Engine) renderOptions(opts []RenderOption) (RenderOptions, error) {
+	renderOpts := RenderOptions{
+		Values: make(map[string]any),
+	}
+
+	// Apply render options
+	for _, opt := range opts {
+		opt.ApplyTo(&renderOpts)
+	}
+
+	if _, _, err := e.filterModes(renderOpts); err != nil {
+		return RenderOptions{}, err
+	}
+
+	kubeVersion, err := kubeVersion(cmp.Or(renderOpts.KubeVersion, e.options.KubeVersion))
+	if err != nil {
+		return RenderOptions{}, err
+	}
+
+	renderOpts.KubeVersion = kubeVersion
+
+	if err := resolveValues(e.options.DefaultValues, &renderOpts); err != nil {
+		return RenderOptions{}, err
+	}
+
+	return renderOpts, nil
+}
+
+// newPipeline returns the render pipeline running the engine-level stages and the render-time
+// filters and transformers of renderOpts.
+func (e *Engine) newPipeline(ctx context.Context, renderOpts RenderOptions) *renderPipeline {
+	return &renderPipeline{
+		phases:      e.phases(ctx, renderOpts),
+		target:      target(renderOpts.Targets),
+		kubeVersion: renderOpts.KubeVersion,
+
+		filterErrorMode:      e.options.FilterErrorMode,
+		transformerErrorMode: e.options.TransformerErrorMode,
+	}
+}
+
+// renderPipeline runs the engine-level and render-time filters and transformers of a Render() call
+// on the objects of each renderer as soon as they are produced.
+// Calls are serialized, so filters and transformers never run concurrently, even in parallel mode.
+type renderPipeline struct {
+	mu     sync.Mutex
+	phases []phase
+
+	// target, when set, is passed to renderers implementing types.TargetedRenderer.
+	target types.Filter
+
+	// filterErrorMode and transformerErrorMode define how filter and transformer errors are handled.
+	filterErrorMode      ErrorMode
+	transformerErrorMode ErrorMode
+
+	// errs are the errors recorded with ErrorModeCollect.
+	errs []error
+
+	// kubeVersion, when set, is passed to renderers implementing types.VersionAwareRenderer.
+	kubeVersion string
+
+	// emit, when set, receives the resulting objects instead of apply returning them.
+	emit func(ctx context.Context, objects []unstructured.Unstructured) error
+}
+
+// apply filters and transforms objects, returning the resulting objects, and adds the number of
+// objects dropped by filters, or skipped because of an error, to rr. When an emit function is set,
+// the resulting objects are passed to it and apply returns no objects.
+func (p *renderPipeline) apply(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+	rr *RendererReport,
+) ([]unstructured.Unstructured, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	transformed, err := p.applyPhases(ctx, objects, rr)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.emit != nil {
+		if err := p.emit(ctx, transformed); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
 
 	return transformed, nil
 }
 
-// processRenderer executes a single renderer with timing, metrics, and error handling.
+// processRenderer executes a single renderer, the one at index in the engine's renderers, with timing,
+// metrics, and error handling, and runs its objects through the render pipeline.
 func (e *Engine) processRenderer(
 	ctx context.Context,
+	index int,
+	renderer types.Renderer,
+	values map[string]any,
+	p *renderPipeline,
+) ([]unstructured.Unstructured, RendererReport, error) {
+	ctx = types.WithRendererName(ctx, renderer.Name())
+	ctx, span := e.tracer.Start(ctx, spanRendererProcess, trace.WithAttributes(
+		attrRendererName.String(renderer.Name()),
+	))
+
+	logger := e.options.Logger
+	debug := logger.Enabled(ctx, slog.LevelDebug)
+
+	if debug {
+		logger.DebugContext(ctx, "renderer started", slog.String(logKeyRenderer, renderer.Name()))
+	}
+
+	rr := RendererReport{
+		Name: renderer.Name(),
+	}
+
+	var (
+		objects []unstructured.Unstructured
+		err     error
+	)
+
+	if sr, ok := renderer.(types.StreamingRenderer); ok {
+		objects, err = e.processStream(ctx, sr, values, p, &rr)
+	} else {
+		objects, err = e.processBatch(ctx, index, renderer, values, p, &rr)
+	}
+
+	// Checked on the renderer output, before engine-level and render-time filters drop objects.
+	// A renderer given the target of a partial render legitimately produces nothing when no object
+	// matches it.
+	if rr.Err == nil && e.options.RequireNonEmpty && rr.ObjectCount == 0 && !isTargeted(renderer, p.target) {
+		rr.Err = ErrRendererEmpty
+	}
+
+	metrics.ObserveRenderer(ctx, renderer.Name(), rr.Duration, rr.ObjectCount, rr.Err)
+	e.options.Metrics.ObserveRenderer(renderer.Name(), rr.Duration, rr.Err)
+
+	if debug {
+		logRendererDone(ctx, logger, renderer.Name(), rr.Duration, rr.ObjectCount, rr.Err)
+	}
+
+	span.SetAttributes(
+		attrRendererObjectCount.Int(rr.ObjectCount),
+		attrRendererCached.Bool(rr.Cached),
+	)
+	endSpan(span, rr.Err)
+
+	if rr.Err != nil {
+		rr.ObjectCount = 0
+		rr.DroppedCount = 0
+		rr.SkippedCount = 0
+
+		// A renderer-specific filter aborted the render: return the abort error as-is.
+		if filter.IsAbort(rr.Err) {
+			return nil, rr, rr.Err
+		}
+
+		return nil, rr, &types.RendererError{
+			Name: renderer.Name(),
+			Type: fmt.Sprintf("%T", renderer),
+			Err:  rr.Err,
+		}
+	}
+
+	if err != nil {
+		return nil, rr, err
+	}
+
+	return objects, rr, nil
+}
+
+// processBatch runs a renderer to completion, then runs all its objects through the render pipeline.
+// Renderer errors are recorded in rr.Err, pipeline errors are returned.
+func (e *Engine) processBatch(
+	ctx context.Context,
+	index int,
 	renderer types.Renderer,
 	values map[string]any,
+	p *renderPipeline,
+	rr *RendererReport,
 ) ([]unstructured.Unstructured, error) {
 	startTime := time.Now()
-	objects, err := renderer.Process(ctx, values)
+	objects, cached, err := e.process(ctx, index, versioned(targeted(renderer, p.target), p.kubeVersion), values)
 
-	metrics.ObserveRenderer(ctx, renderer.Name(), time.Since(startTime), len(objects), err)
+	if err == nil {
+		objects, err = pipeline.ApplyTransformers(ctx, objects, e.outputTransformers())
+	}
+
+	rr.Duration = time.Since(startTime)
+	rr.Cached = cached
+	rr.ObjectCount = len(objects)
 
 	if err != nil {
-		return nil, fmt.Errorf(
-			"error processing renderer %q (%T): %w",
-			renderer.Name(),
-			renderer,
-			err,
-		)
+		rr.Err = err
+
+		return nil, err
+	}
+
+	objects, err = p.apply(ctx, objects, rr)
+
+	return objects, err
+}
+
+// outputTransformers returns the engine's own transformers, applied to each object of a renderer
+// as it is produced, before engine-level and render-time filters.
+func (e *Engine) outputTransformers() []types.Transformer {
+	transformers := make([]types.Transformer, 0, 2)
+
+	if e.options.Provenance {
+		transformers = append(transformers, provenance.Stamp())
+	}
+
+	if e.options.DefaultNamespace != "" {
+		transformers = append(transformers, transformer.If(namespaced, namespace.EnsureDefault(e.options.DefaultNamespace)))
 	}
 
-	return objects, nil
+	return transformers
+}
+
+// namespaced keeps the objects that are not of a well-known cluster-scoped kind (see scope.IsClusterScoped).
+func namespaced(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+	return !scope.IsClusterScoped(obj.GroupVersionKind().GroupKind()), nil
 }
 
 // renderSequential processes renderers sequentially in order.
-func (e *Engine) renderSequential(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+func (e *Engine) renderSequential(
+	ctx context.Context,
+	renderOpts RenderOptions,
+	p *renderPipeline,
+	report *RenderReport,
+) ([]unstructured.Unstructured, error) {
 	allObjects := make([]unstructured.Unstructured, 0)
 
-	for _, renderer := range e.options.Renderers {
-		objects, err := e.processRenderer(ctx, renderer, values)
+	for i, renderer := range e.options.Renderers {
+		objects, rr, err := e.processRenderer(ctx, i, renderer, rendererValues(renderOpts, renderer), p)
+		report.Renderers = append(report.Renderers, rr)
+
 		if err != nil {
 			return nil, err
 		}
@@ -142,24 +505,32 @@ func (e *Engine) renderSequential(ctx context.Context, values map[string]any) ([
 
 // renderParallel processes all renderers concurrently using goroutines.
 // Results are collected in the original renderer order for consistent output.
-func (e *Engine) renderParallel(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+func (e *Engine) renderParallel(
+	ctx context.Context,
+	renderOpts RenderOptions,
+	p *renderPipeline,
+	report *RenderReport,
+) ([]unstructured.Unstructured, error) {
 	type result struct {
 		objects []unstructured.Unstructured
 		err     error
 	}
 
 	results := make([]result, len(e.options.Renderers))
+	report.Renderers = make([]RendererReport, len(e.options.Renderers))
+
 	var wg sync.WaitGroup
 
 	for i, renderer := range e.options.Renderers {
 		wg.Add(1)
 		go func(idx int, r types.Renderer) {
 			defer wg.Done()
-			objects, err := e.processRenderer(ctx, r, values)
+			objects, rr, err := e.processRenderer(ctx, idx, r, rendererValues(renderOpts, r), p)
 			results[idx] = result{
 				objects: objects,
 				err:     err,
 			}
+			report.Renderers[idx] = rr
 		}(i, renderer)
 	}
 
@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/k8s-manifest-kit/pkg/util/k8s"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// cacheKey returns a stable key for a renderer and the values it is invoked with.
+// The values are hashed through their JSON encoding, which sorts map keys.
+func cacheKey(name string, index int, values map[string]any) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("unable to hash values: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return fmt.Sprintf("%s#%d:%s", name, index, hex.EncodeToString(sum[:])), nil
+}
+
+// process runs the renderer, the one at index in the engine's renderers, serving and populating the
+// render cache when one is configured. Entries are keyed by the renderer name and index, so renderers
+// sharing a name never share entries. Values that cannot be hashed and targeted renders bypass the
+// cache, and version-aware renderers are cached per Kubernetes version. The returned flag reports a
+// cache hit.
+func (e *Engine) process(
+	ctx context.Context,
+	index int,
+	renderer types.Renderer,
+	values map[string]any,
+) ([]unstructured.Unstructured, bool, error) {
+	if _, ok := renderer.(targetedRenderer); ok || e.options.Cache == nil {
+		objects, err := e.runProcess(ctx, renderer, values)
+
+		return objects, false, err
+	}
+
+	name := renderer.Name()
+	if vr, ok := renderer.(versionedRenderer); ok {
+		name += "@" + vr.version
+	}
+
+	key, keyErr := cacheKey(name, index, values)
+	if keyErr == nil {
+		if objects, ok := e.options.Cache.Get(key); ok {
+			return k8s.DeepCloneUnstructuredSlice(objects), true, nil
+		}
+	}
+
+	objects, err := e.runProcess(ctx, renderer, values)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if keyErr == nil {
+		e.options.Cache.Set(key, k8s.DeepCloneUnstructuredSlice(objects))
+	}
+
+	return objects, false, nil
+}
@@ -0,0 +1,146 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/cache"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithRenderCache(t *testing.T) {
+
+	t.Run("should skip Process on cache hit", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+		}, nil).Once()
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithRenderCache(cache.NewLRU(10)),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		values := map[string]any{"replicas": 1, "image": map[string]any{"tag": "v1"}}
+
+		objects, report, err := e.RenderWithReport(t.Context(), engine.WithValues(values))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(report.Renderers[0].Cached).Should(BeFalse())
+
+		objects, report, err = e.RenderWithReport(t.Context(), engine.WithValues(values))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("pod1"))
+		g.Expect(report.Renderers[0].Cached).Should(BeTrue())
+
+		renderer.AssertNumberOfCalls(t, "Process", 1)
+	})
+
+	t.Run("should not share entries between renderers with the same name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		newRenderer := func(name string) *mockRenderer {
+			renderer := new(mockRenderer)
+			renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod(name)}, nil)
+			renderer.On("Name").Return("x")
+
+			return renderer
+		}
+
+		e, err := engine.New(
+			engine.WithRenderers(newRenderer("a"), newRenderer("b")),
+			engine.WithRenderCache(cache.NewLRU(10)),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		for range 2 {
+			objects, err := e.Render(t.Context())
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(objects).Should(HaveLen(2))
+			g.Expect(objects[0].GetName()).Should(Equal("a"))
+			g.Expect(objects[1].GetName()).Should(Equal("b"))
+		}
+	})
+
+	t.Run("should render again when values change", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithRenderCache(cache.NewLRU(10)),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), engine.WithValues(map[string]any{"replicas": 1}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), engine.WithValues(map[string]any{"replicas": 2}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		renderer.AssertNumberOfCalls(t, "Process", 2)
+	})
+
+	t.Run("should not share cached objects with callers", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+		}, nil).Once()
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithRenderCache(cache.NewLRU(10)),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		first, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		first[0].SetLabels(map[string]string{"mutated": "true"})
+
+		second, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		second[0].SetName("renamed")
+
+		third, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(third[0].GetLabels()).Should(BeEmpty())
+		g.Expect(third[0].GetName()).Should(Equal("pod1"))
+	})
+
+	t.Run("should not cache failures", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{}, errors.New("renderer failed"))
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithRenderCache(cache.NewLRU(10)),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(HaveOccurred())
+
+		renderer.AssertNumberOfCalls(t, "Process", 2)
+	})
+}
@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRenderDeadlineExceeded is the error returned when a Render() call exceeds the deadline set
+// with WithRenderDeadline.
+var ErrRenderDeadlineExceeded = errors.New("render deadline exceeded")
+
+// renderDeadlineKey marks the contexts bounded by a render deadline.
+type renderDeadlineKey struct{}
+
+// withRenderDeadline returns a context bounded by the render deadline d, if positive.
+// When the deadline expires, context.Cause returns an error wrapping ErrRenderDeadlineExceeded.
+func withRenderDeadline(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+
+	ctx = context.WithValue(ctx, renderDeadlineKey{}, d)
+
+	return context.WithTimeoutCause(ctx, d, fmt.Errorf("%w after %s", ErrRenderDeadlineExceeded, d))
+}
+
+// hasRenderDeadline reports whether ctx is bounded by a render deadline.
+func hasRenderDeadline(ctx context.Context) bool {
+	_, ok := ctx.Value(renderDeadlineKey{}).(time.Duration)
+
+	return ok
+}
+
+// deadlineError returns the render deadline error in place of err if the deadline of ctx expired,
+// whatever the stage that observed it, and err otherwise.
+func deadlineError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if cause := context.Cause(ctx); errors.Is(cause, ErrRenderDeadlineExceeded) {
+		return cause
+	}
+
+	return err
+}
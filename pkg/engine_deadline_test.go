@@ -0,0 +1,129 @@
+package engine_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithRenderDeadline(t *testing.T) {
+
+	// blockingRenderer returns a renderer waiting for the cancellation of its context.
+	blockingRenderer := func(name string) *mockRenderer {
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			ctx, _ := args.Get(0).(context.Context)
+			<-ctx.Done()
+		}).Return([]unstructured.Unstructured(nil), context.DeadlineExceeded)
+		renderer.On("Name").Return(name)
+
+		return renderer
+	}
+
+	t.Run("should fail a render exceeding its deadline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(engine.WithRenderer(blockingRenderer("slow")))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(), engine.WithRenderDeadline(20*time.Millisecond))
+		g.Expect(err).Should(MatchError(engine.ErrRenderDeadlineExceeded))
+		g.Expect(objects).Should(BeNil())
+	})
+
+	t.Run("should abandon a renderer ignoring the deadline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		release := make(chan struct{})
+		defer close(release)
+
+		slow := new(mockRenderer)
+		slow.On("Process", mock.Anything, mock.Anything).Run(func(_ mock.Arguments) {
+			<-release
+		}).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		slow.On("Name").Return("slow")
+
+		e, err := engine.New(engine.WithRenderer(slow))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), engine.WithRenderDeadline(20*time.Millisecond))
+		g.Expect(err).Should(MatchError(engine.ErrRenderDeadlineExceeded))
+	})
+
+	t.Run("should share the deadline across parallel renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderers(blockingRenderer("a"), blockingRenderer("b"), blockingRenderer("c")),
+			engine.WithParallel(true),
+			engine.WithRendererTimeout(time.Minute),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		start := time.Now()
+
+		_, err = e.Render(t.Context(), engine.WithRenderDeadline(20*time.Millisecond))
+		g.Expect(err).Should(MatchError(engine.ErrRenderDeadlineExceeded))
+		g.Expect(time.Since(start)).Should(BeNumerically("<", time.Minute))
+	})
+
+	t.Run("should bound the stages following the renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer.On("Name").Return("mock")
+
+		slowFilter := func(ctx context.Context, _ unstructured.Unstructured) (bool, error) {
+			<-ctx.Done()
+
+			return false, ctx.Err()
+		}
+
+		e, err := engine.New(engine.WithRenderer(renderer), engine.WithFilter(slowFilter))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), engine.WithRenderDeadline(20*time.Millisecond))
+		g.Expect(err).Should(MatchError(engine.ErrRenderDeadlineExceeded))
+	})
+
+	t.Run("should bound RenderTo", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(engine.WithRenderer(blockingRenderer("slow")))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		var buf bytes.Buffer
+
+		err = e.RenderTo(t.Context(), &buf, engine.WithRenderDeadline(20*time.Millisecond))
+		g.Expect(err).Should(MatchError(engine.ErrRenderDeadlineExceeded))
+	})
+
+	t.Run("should not affect renders completing in time", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(), engine.WithRenderDeadline(time.Minute))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+
+		plan, err := e.Explain(t.Context(), engine.WithRenderDeadline(time.Minute))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(plan.RenderDeadline).Should(Equal(time.Minute))
+	})
+}
@@ -0,0 +1,39 @@
+package engine
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/dedupe"
+)
+
+// DedupeStrategy defines how the engine handles objects rendered more than once,
+// i.e. sharing the same GroupVersionKind, namespace and name.
+type DedupeStrategy string
+
+const (
+	// DedupeNone keeps every copy of duplicate objects. This is the default.
+	DedupeNone DedupeStrategy = ""
+
+	// DedupeMerge combines the copies of duplicate objects into one with dedupe.Merge,
+	// for resources to which several renderers each contribute a part.
+	DedupeMerge DedupeStrategy = "merge"
+)
+
+// dedupe applies the configured dedupe strategy to the aggregated objects.
+func (e *Engine) dedupe(objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	switch e.options.Dedupe {
+	case DedupeNone:
+		return objects, nil
+	case DedupeMerge:
+		merged, err := dedupe.Merge(objects)
+		if err != nil {
+			return nil, fmt.Errorf("engine dedupe error: %w", err)
+		}
+
+		return merged, nil
+	default:
+		return nil, fmt.Errorf("engine dedupe error: unknown strategy %q", e.options.Dedupe)
+	}
+}
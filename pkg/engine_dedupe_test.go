@@ -0,0 +1,85 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/dedupe"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithDedupe(t *testing.T) {
+
+	newRenderer := func(name string, objects ...unstructured.Unstructured) *mockRenderer {
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return(objects, nil)
+		renderer.On("Name").Return(name)
+
+		return renderer
+	}
+
+	labeled := func(name string, labels map[string]string) unstructured.Unstructured {
+		pod := makePod(name)
+		pod.SetLabels(labels)
+
+		return pod
+	}
+
+	t.Run("should keep duplicates by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer("renderer1", makePod("pod1"))),
+			engine.WithRenderer(newRenderer("renderer2", makePod("pod1"))),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+	})
+
+	t.Run("should merge the parts contributed by several renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, parallel := range []bool{false, true} {
+			e, err := engine.New(
+				engine.WithRenderer(newRenderer("renderer1", labeled("pod1", map[string]string{"app": "web"}), makeService())),
+				engine.WithRenderer(newRenderer("renderer2", labeled("pod1", map[string]string{"tier": "frontend"}))),
+				engine.WithDedupe(engine.DedupeMerge),
+				engine.WithProvenance(true),
+				engine.WithParallel(parallel),
+			)
+			g.Expect(err).ShouldNot(HaveOccurred())
+
+			objects, report, err := e.RenderWithReport(t.Context())
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(objects).Should(HaveLen(2))
+			g.Expect(objects[0].GetLabels()).Should(Equal(map[string]string{"app": "web", "tier": "frontend"}))
+			g.Expect(objects[1].GetName()).Should(Equal("svc1"))
+			g.Expect(report.RenderedCount).Should(Equal(3))
+			g.Expect(report.ObjectCount).Should(Equal(2))
+		}
+	})
+
+	t.Run("should fail the render on conflicting duplicates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer("renderer1", labeled("pod1", map[string]string{"app": "web"}))),
+			engine.WithRenderer(newRenderer("renderer2", labeled("pod1", map[string]string{"app": "api"}))),
+			engine.WithDedupe(engine.DedupeMerge),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).Should(MatchError(dedupe.ErrConflict))
+		g.Expect(err).Should(MatchError(ContainSubstring("metadata.labels.app")))
+		g.Expect(objects).Should(BeNil())
+	})
+}
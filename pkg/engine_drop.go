@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// FilterStage identifies the pipeline stage of a filter, or of a transformer.
+type FilterStage string
+
+const (
+	// FilterStageEngine is the stage of engine-level filters, configured via New().
+	FilterStageEngine FilterStage = "engine"
+
+	// FilterStageRender is the stage of render-time filters, passed to a single Render() call.
+	FilterStageRender FilterStage = "render"
+)
+
+// DropHook is called each time an engine-level or render-time filter rejects an object.
+// The filterIndex is the position of the rejecting filter among the filters of its stage, in registration
+// order. In FilterModeAny, adjacent filters of a stage are combined into one, reported at the index of
+// the first of them. A render target is reported as the render-time filter following the others.
+type DropHook func(ctx context.Context, object unstructured.Unstructured, stage FilterStage, filterIndex int)
+
+// dropHookFilter wraps f, the filter at index within stage, so that hook is called for each object it rejects.
+func dropHookFilter(hook DropHook, stage FilterStage, index int, f types.Filter) types.Filter {
+	return func(ctx context.Context, obj unstructured.Unstructured) (bool, error) {
+		ok, err := f(ctx, obj)
+		if err == nil && !ok {
+			hook(ctx, obj, stage, index)
+		}
+
+		return ok, err
+	}
+}
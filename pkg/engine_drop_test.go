@@ -0,0 +1,91 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+type drop struct {
+	name  string
+	stage engine.FilterStage
+	index int
+}
+
+func TestWithDropHook(t *testing.T) {
+
+	rejectName := func(name string) func(context.Context, unstructured.Unstructured) (bool, error) {
+		return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+			return obj.GetName() != name, nil
+		}
+	}
+
+	t.Run("should report the stage and index of the rejecting filter", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+			makePod("pod2"),
+			makePod("pod3"),
+			makeService(),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		var drops []drop
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithFilter(podFilter()),
+			engine.WithFilter(rejectName("pod1")),
+			engine.WithDropHook(func(_ context.Context, obj unstructured.Unstructured, stage engine.FilterStage, i int) {
+				drops = append(drops, drop{name: obj.GetName(), stage: stage, index: i})
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context(), engine.WithRenderFilter(rejectName("pod3")))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(report.DroppedCount).Should(Equal(3))
+
+		g.Expect(drops).Should(Equal([]drop{
+			{name: "pod1", stage: engine.FilterStageEngine, index: 1},
+			{name: "pod3", stage: engine.FilterStageRender, index: 0},
+			{name: "svc1", stage: engine.FilterStageEngine, index: 0},
+		}))
+	})
+
+	t.Run("should not fire when a filter fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer.On("Name").Return("mock")
+
+		called := false
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithFilter(func(_ context.Context, _ unstructured.Unstructured) (bool, error) {
+				return false, errors.New("filter failed")
+			}),
+			engine.WithDropHook(func(_ context.Context, _ unstructured.Unstructured, _ engine.FilterStage, _ int) {
+				called = true
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(HaveOccurred())
+		g.Expect(called).Should(BeFalse())
+	})
+}
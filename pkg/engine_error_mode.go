@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// ErrorMode defines how the render handles an engine-level or render-time filter or transformer
+// failing on an object.
+type ErrorMode string
+
+const (
+	// ErrorModeFailFast fails the render with the first error. This is the default, also used
+	// when no mode is set.
+	ErrorModeFailFast ErrorMode = "fail-fast"
+
+	// ErrorModeSkipObject drops the object that caused the error, emits a WarningObjectSkipped
+	// warning with the error and continues with the other objects.
+	ErrorModeSkipObject ErrorMode = "skip-object"
+
+	// ErrorModeCollect drops the object that caused the error and continues with the other objects,
+	// then fails the render with all the errors joined once every object has been processed.
+	ErrorModeCollect ErrorMode = "collect"
+)
+
+// WarningObjectSkipped is the code of the warnings emitted for the objects dropped with ErrorModeSkipObject.
+const WarningObjectSkipped = "ObjectSkipped"
+
+// validateErrorMode returns an error if mode is not a known ErrorMode.
+func validateErrorMode(mode ErrorMode) error {
+	switch mode {
+	case "", ErrorModeFailFast, ErrorModeSkipObject, ErrorModeCollect:
+		return nil
+	default:
+		return fmt.Errorf("unknown error mode %q", mode)
+	}
+}
+
+// isolate runs stage, applying the filters or transformers named kind, on objects and returns its
+// result along with the number of objects dropped because of an error.
+//
+// With ErrorModeFailFast, stage runs on all objects at once and its error is returned. Otherwise it runs
+// on each object on its own, so an error only concerns the object causing it: the object is dropped,
+// and the error is emitted as a warning (ErrorModeSkipObject) or recorded in p.errs (ErrorModeCollect).
+// Errors aborting the render are always returned as-is. The caller must hold p.mu.
+func (p *renderPipeline) isolate(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+	mode ErrorMode,
+	kind string,
+	stage func(ctx context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error),
+) ([]unstructured.Unstructured, int, error) {
+	if mode == "" || mode == ErrorModeFailFast {
+		result, err := stage(ctx, objects)
+		if err != nil && !filter.IsAbort(err) {
+			err = fmt.Errorf("engine %s error: %w", kind, err)
+		}
+
+		return result, 0, err
+	}
+
+	result := make([]unstructured.Unstructured, 0, len(objects))
+	skipped := 0
+
+	for _, obj := range objects {
+		out, err := stage(ctx, []unstructured.Unstructured{obj})
+
+		switch {
+		case err == nil:
+			result = append(result, out...)
+
+			continue
+		case filter.IsAbort(err):
+			return nil, skipped, err
+		case mode == ErrorModeSkipObject:
+			types.WarnObject(ctx, obj, WarningObjectSkipped, fmt.Sprintf("%s error: %v", kind, err))
+		default:
+			p.errs = append(p.errs, fmt.Errorf("engine %s error: %w", kind, err))
+		}
+
+		skipped++
+	}
+
+	return result, skipped, nil
+}
+
+// err returns the errors recorded with ErrorModeCollect, joined, nil if there are none.
+func (p *renderPipeline) err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return errors.Join(p.errs...)
+}
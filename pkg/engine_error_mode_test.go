@@ -0,0 +1,218 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithTransformerErrorMode(t *testing.T) {
+
+	errBroken := errors.New("broken object")
+
+	// failOnBroken fails on the objects named "broken-*".
+	failOnBroken := func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if strings.HasPrefix(obj.GetName(), "broken-") {
+			return obj, errBroken
+		}
+
+		return obj, nil
+	}
+
+	newRenderer := func() *mockRenderer {
+		renderer := new(mockRenderer)
+		renderer.On("Name").Return("mock")
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+			makePod("broken-1"),
+			makePod("pod2"),
+			makePod("broken-2"),
+		}, nil)
+
+		return renderer
+	}
+
+	names := func(objects []unstructured.Unstructured) []string {
+		result := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			result = append(result, obj.GetName())
+		}
+
+		return result
+	}
+
+	t.Run("should fail fast by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithTransformer(failOnBroken),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(MatchError(errBroken))
+		g.Expect(err.Error()).Should(ContainSubstring("broken-1"))
+		g.Expect(err.Error()).ShouldNot(ContainSubstring("broken-2"))
+	})
+
+	t.Run("should skip failing objects with a warning", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithTransformer(failOnBroken),
+			engine.WithTransformerErrorMode(engine.ErrorModeSkipObject),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"pod1", "pod2"}))
+		g.Expect(report.SkippedCount).Should(Equal(2))
+		g.Expect(report.DroppedCount).Should(BeZero())
+
+		g.Expect(report.Warnings).Should(HaveLen(2))
+		g.Expect(report.Warnings[0].Code).Should(Equal(engine.WarningObjectSkipped))
+		g.Expect(report.Warnings[0].Renderer).Should(Equal("mock"))
+		g.Expect(report.Warnings[0].Object.GetName()).Should(Equal("broken-1"))
+		g.Expect(report.Warnings[0].Message).Should(ContainSubstring("broken object"))
+	})
+
+	t.Run("should collect the errors of every object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithTransformer(failOnBroken),
+			engine.WithTransformerErrorMode(engine.ErrorModeCollect),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).Should(MatchError(errBroken))
+		g.Expect(err.Error()).Should(ContainSubstring("broken-1"))
+		g.Expect(err.Error()).Should(ContainSubstring("broken-2"))
+		g.Expect(objects).Should(BeNil())
+		g.Expect(report.SkippedCount).Should(Equal(2))
+
+		var joined interface{ Unwrap() []error }
+		g.Expect(errors.As(err, &joined)).Should(BeTrue())
+		g.Expect(joined.Unwrap()).Should(HaveLen(2))
+
+		var transformerErr *transformer.Error
+		g.Expect(errors.As(joined.Unwrap()[1], &transformerErr)).Should(BeTrue())
+		g.Expect(transformerErr.Object.GetName()).Should(Equal("broken-2"))
+	})
+
+	t.Run("should collect errors when rendering to a writer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithTransformer(failOnBroken),
+			engine.WithTransformerErrorMode(engine.ErrorModeCollect),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		var out strings.Builder
+
+		err = e.RenderTo(t.Context(), &out)
+		g.Expect(err).Should(MatchError(errBroken))
+		g.Expect(err.Error()).Should(ContainSubstring("broken-2"))
+		g.Expect(out.String()).Should(ContainSubstring("pod2"))
+	})
+
+	t.Run("should apply the modes to filters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		failingFilter := func(ctx context.Context, obj unstructured.Unstructured) (bool, error) {
+			_, err := failOnBroken(ctx, obj)
+
+			return obj.GetName() != "pod2", err
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithFilterErrorMode(engine.ErrorModeSkipObject),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context(), engine.WithRenderFilter(failingFilter))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"pod1"}))
+		g.Expect(report.SkippedCount).Should(Equal(2))
+		g.Expect(report.DroppedCount).Should(Equal(1))
+	})
+
+	t.Run("should always fail on aborting filters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		abort := func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+			if obj.GetName() == "pod2" {
+				return false, filter.Abort("pods are forbidden")
+			}
+
+			return true, nil
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithFilter(abort),
+			engine.WithFilterErrorMode(engine.ErrorModeCollect),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(MatchError(filter.ErrAbort))
+		g.Expect(err.Error()).ShouldNot(ContainSubstring("engine filter error"))
+	})
+
+	t.Run("should reject unknown modes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(engine.WithTransformerErrorMode("ignore"))
+		g.Expect(err).Should(MatchError(ContainSubstring(`unknown error mode "ignore"`)))
+
+		_, err = engine.New(engine.WithFilterErrorMode("ignore"))
+		g.Expect(err).Should(MatchError(ContainSubstring(`unknown error mode "ignore"`)))
+	})
+
+	t.Run("should isolate streamed objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		stream := &streamRenderer{
+			name:    "stream",
+			objects: []unstructured.Unstructured{makePod("pod1"), makePod("broken-1")},
+		}
+
+		var warnings []types.Warning
+
+		e, err := engine.New(
+			engine.WithRenderer(stream),
+			engine.WithTransformer(failOnBroken),
+			engine.WithTransformerErrorMode(engine.ErrorModeSkipObject),
+			engine.WithWarningHandler(func(w types.Warning) {
+				warnings = append(warnings, w)
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"pod1"}))
+		g.Expect(warnings).Should(HaveLen(1))
+	})
+}
@@ -0,0 +1,200 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Names of the stages reported by Explain.
+const (
+	StageBeforeRender     = "before-render"
+	StageRender           = "render"
+	StageProvenance       = "provenance"
+	StageDefaultNamespace = "default-namespace"
+	StageFilter           = "filter"
+	StageTransform        = "transform"
+	StageDedupe           = "dedupe"
+	StageAfterRender      = "after-render"
+	StageStripAnnotations = "strip-annotations"
+	StageValidate         = "validate"
+)
+
+// Plan describes the pipeline a Render() call would run, as resolved by Explain.
+type Plan struct {
+	// Renderers holds one entry per registered renderer, in execution order, i.e. after priorities
+	// set with WithRendererPriority are applied.
+	Renderers []PlannedRenderer
+
+	// Stages names the stages the render would go through, in order. Stages that have nothing
+	// to do, e.g. validation when no validator is registered, are omitted.
+	Stages []string
+
+	// Filters is the number of engine-level and render-time filters, including the render target,
+	// run on the objects of each renderer.
+	Filters int
+
+	// Transformers is the number of engine-level and render-time transformers run on the objects
+	// of each renderer.
+	Transformers int
+
+	// Validators is the number of engine-level validators run on the final objects.
+	Validators int
+
+	// Hooks is the number of hooks run before and after the render.
+	Hooks int
+
+	// Parallel reports whether renderers run concurrently.
+	Parallel bool
+
+	// MaxConcurrency is the maximum number of renderers running at the same time: all of them
+	// in parallel mode, one otherwise.
+	MaxConcurrency int
+
+	// RendererTimeout is the time limit of each renderer, 0 if unbounded.
+	RendererTimeout time.Duration
+
+	// RenderDeadline bounds the whole render, 0 if unbounded.
+	RenderDeadline time.Duration
+
+	// DefaultNamespace is the namespace set on namespaced objects that have none, empty if disabled.
+	DefaultNamespace string
+
+	// KubeVersion is the Kubernetes version the render targets, in the "v1.31.0" form, empty if none.
+	KubeVersion string
+
+	// Dedupe is the strategy applied to objects rendered more than once.
+	Dedupe DedupeStrategy
+
+	// RequireNonEmpty reports whether a renderer producing no objects fails the render.
+	RequireNonEmpty bool
+
+	// IdentityCheck reports whether transformers are checked not to drop the identity of objects.
+	IdentityCheck bool
+
+	// Provenance reports whether objects are stamped with the renderer that produced them.
+	Provenance bool
+
+	// Cached reports whether renderer output is served from a render cache when possible.
+	Cached bool
+
+	// Values are the resolved render-time values, i.e. the engine defaults deep merged with
+	// the values sources and explicit values of the render options.
+	Values map[string]any
+}
+
+// PlannedRenderer describes a renderer of a Plan.
+type PlannedRenderer struct {
+	// Name is the renderer name as returned by Renderer.Name().
+	Name string
+
+	// Streaming reports whether the renderer implements types.StreamingRenderer.
+	Streaming bool
+
+	// VersionAware reports whether the renderer implements types.VersionAwareRenderer, seeing through
+	// wrappers as types.Implements does.
+	VersionAware bool
+
+	// Values are the render-time values passed to the renderer, including its WithRendererValues override.
+	Values map[string]any
+}
+
+// Explain returns the Plan of a Render() call with the given render-time options, without running it.
+//
+// It describes the effective configuration of the engine once struct-based and functional options
+// have been applied, which helps verifying how they combine. No renderer, filter, transformer, hook
+// or validator is called: the values returned are those before any BeforeRender hook runs.
+// Values sources are read, and Explain returns an error if one of them fails.
+func (e *Engine) Explain(ctx context.Context, opts ...RenderOption) (*Plan, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("engine explain canceled: %w", err)
+	}
+
+	renderOpts, err := e.renderOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := Plan{
+		Renderers:        make([]PlannedRenderer, 0, len(e.options.Renderers)),
+		Filters:          len(renderOpts.Filters),
+		Transformers:     len(renderOpts.Transformers),
+		Validators:       len(e.options.Validators),
+		Hooks:            len(e.options.Hooks),
+		Parallel:         e.options.Parallel,
+		MaxConcurrency:   min(1, len(e.options.Renderers)),
+		RendererTimeout:  e.options.RendererTimeout,
+		RenderDeadline:   renderOpts.Deadline,
+		DefaultNamespace: e.options.DefaultNamespace,
+		KubeVersion:      renderOpts.KubeVersion,
+		Dedupe:           e.options.Dedupe,
+		RequireNonEmpty:  e.options.RequireNonEmpty,
+		IdentityCheck:    !e.options.DisableIdentityCheck,
+		Provenance:       e.options.Provenance,
+		Cached:           e.options.Cache != nil,
+		Values:           renderOpts.Values,
+	}
+
+	if e.options.Parallel {
+		plan.MaxConcurrency = len(e.options.Renderers)
+	}
+
+	for _, renderer := range e.options.Renderers {
+		streaming := types.Implements[types.StreamingRenderer](renderer)
+		versionAware := types.Implements[types.VersionAwareRenderer](renderer)
+
+		plan.Renderers = append(plan.Renderers, PlannedRenderer{
+			Name:         renderer.Name(),
+			Streaming:    streaming,
+			VersionAware: versionAware,
+			Values:       rendererValues(renderOpts, renderer),
+		})
+	}
+
+	if target(renderOpts.Targets) != nil {
+		plan.Filters++
+	}
+
+	for _, stage := range e.options.Stages {
+		if stage.Filter != nil {
+			plan.Filters++
+		} else {
+			plan.Transformers++
+		}
+	}
+
+	plan.Stages = e.stages(ctx, renderOpts)
+
+	return &plan, nil
+}
+
+// stages returns the names of the stages a render with renderOpts would run, in order. Each run of
+// adjacent filters, or of adjacent transformers, is one StageFilter or StageTransform stage.
+func (e *Engine) stages(ctx context.Context, renderOpts RenderOptions) []string {
+	stages := make([]string, 0, 11)
+
+	add := func(enabled bool, name string) {
+		if enabled {
+			stages = append(stages, name)
+		}
+	}
+
+	add(len(e.options.Hooks) > 0, StageBeforeRender)
+	add(true, StageRender)
+	add(e.options.Provenance, StageProvenance)
+	add(e.options.DefaultNamespace != "", StageDefaultNamespace)
+
+	for _, ph := range e.phases(ctx, renderOpts) {
+		add(len(ph.filters) > 0, StageFilter)
+		add(len(ph.transformers) > 0, StageTransform)
+	}
+
+	add(e.options.Dedupe != DedupeNone, StageDedupe)
+	add(len(e.options.Hooks) > 0, StageAfterRender)
+	add(e.options.StripSourceAnnotations, StageStripAnnotations)
+	add(len(e.options.Validators) > 0, StageValidate)
+
+	return stages
+}
@@ -0,0 +1,156 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/validator/meta"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestExplain(t *testing.T) {
+
+	newRenderer := func(name string) *mockRenderer {
+		renderer := new(mockRenderer)
+		renderer.On("Name").Return(name)
+
+		return renderer
+	}
+
+	t.Run("should describe the effective configuration without rendering", func(t *testing.T) {
+		g := NewWithT(t)
+
+		first := newRenderer("first")
+		second := newRenderer("second")
+		stream := &streamRenderer{name: "stream"}
+
+		e, err := engine.New(
+			engine.Options{
+				Renderers:       []types.Renderer{second},
+				Filters:         []types.Filter{podFilter()},
+				RendererTimeout: time.Minute,
+				Parallel:        true,
+			},
+			engine.WithRenderer(first),
+			engine.WithRenderer(stream),
+			engine.WithRendererPriority("first", -1),
+			engine.WithTransformer(addLabels(map[string]string{"env": "prod"})),
+			engine.WithParallel(false),
+			engine.WithDefaultValues(map[string]any{"replicas": 1}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		plan, err := e.Explain(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(plan.Renderers).Should(Equal([]engine.PlannedRenderer{
+			{Name: "first", Values: map[string]any{"replicas": 1}},
+			{Name: "second", Values: map[string]any{"replicas": 1}},
+			{Name: "stream", Streaming: true, Values: map[string]any{"replicas": 1}},
+		}))
+		g.Expect(plan.Stages).Should(Equal([]string{engine.StageRender, engine.StageFilter, engine.StageTransform}))
+		g.Expect(plan.Filters).Should(Equal(1))
+		g.Expect(plan.Transformers).Should(Equal(1))
+		g.Expect(plan.Parallel).Should(BeFalse())
+		g.Expect(plan.MaxConcurrency).Should(Equal(1))
+		g.Expect(plan.RendererTimeout).Should(Equal(time.Minute))
+		g.Expect(plan.IdentityCheck).Should(BeTrue())
+		g.Expect(plan.Cached).Should(BeFalse())
+
+		first.AssertNotCalled(t, "Process")
+		second.AssertNotCalled(t, "Process")
+		g.Expect(stream.sent.Load()).Should(BeZero())
+	})
+
+	t.Run("should include render-time options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderers(newRenderer("a"), newRenderer("b")),
+			engine.WithFilter(podFilter()),
+			engine.WithParallel(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		plan, err := e.Explain(t.Context(),
+			engine.WithRenderFilter(podFilter()),
+			engine.WithRenderTransformer(addLabels(map[string]string{"env": "prod"})),
+			engine.WithValues(map[string]any{"env": "prod"}),
+			engine.WithRendererValues("b", map[string]any{"env": "dev"}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(plan.Filters).Should(Equal(2))
+		g.Expect(plan.Transformers).Should(Equal(1))
+		g.Expect(plan.MaxConcurrency).Should(Equal(2))
+		g.Expect(plan.Values).Should(Equal(map[string]any{"env": "prod"}))
+		g.Expect(plan.Renderers[0].Values).Should(Equal(map[string]any{"env": "prod"}))
+		g.Expect(plan.Renderers[1].Values).Should(Equal(map[string]any{"env": "dev"}))
+	})
+
+	t.Run("should list every enabled stage in order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		hookCalled := false
+		hook := funcHook{
+			before: func(_ context.Context, values map[string]any) (map[string]any, error) {
+				hookCalled = true
+
+				return values, nil
+			},
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer("a")),
+			engine.WithHook(hook),
+			engine.WithProvenance(true),
+			engine.WithDefaultNamespace("default"),
+			engine.WithFilter(podFilter()),
+			engine.WithTransformer(addLabels(map[string]string{"env": "prod"})),
+			engine.WithStage(types.Stage{Filter: podFilter()}),
+			engine.WithDedupe(engine.DedupeMerge),
+			engine.WithStripSourceAnnotations(true),
+			engine.WithValidator(meta.Validate()),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		plan, err := e.Explain(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(plan.Stages).Should(Equal([]string{
+			engine.StageBeforeRender,
+			engine.StageRender,
+			engine.StageProvenance,
+			engine.StageDefaultNamespace,
+			engine.StageFilter,
+			engine.StageTransform,
+			engine.StageFilter,
+			engine.StageDedupe,
+			engine.StageAfterRender,
+			engine.StageStripAnnotations,
+			engine.StageValidate,
+		}))
+		g.Expect(plan.Hooks).Should(Equal(1))
+		g.Expect(plan.Filters).Should(Equal(2))
+		g.Expect(plan.Transformers).Should(Equal(1))
+		g.Expect(plan.Validators).Should(Equal(1))
+		g.Expect(hookCalled).Should(BeFalse())
+	})
+
+	t.Run("should fail on a canceled context", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(engine.WithRenderer(newRenderer("a")))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err = e.Explain(ctx)
+		g.Expect(err).Should(MatchError(context.Canceled))
+	})
+}
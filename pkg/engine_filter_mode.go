@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// FilterMode defines how the engine-level or render-time filters of a render combine.
+type FilterMode string
+
+const (
+	// FilterModeAll keeps the objects accepted by every filter. This is the default, also used
+	// when no mode is set.
+	FilterModeAll FilterMode = "all"
+
+	// FilterModeAny keeps the objects accepted by at least one filter.
+	FilterModeAny FilterMode = "any"
+)
+
+// filterModes returns the modes of the engine-level and render-time filters of renderOpts.
+func (e *Engine) filterModes(renderOpts RenderOptions) (FilterMode, FilterMode, error) {
+	engineMode, renderMode := e.options.FilterMode, e.options.FilterMode
+	if renderOpts.FilterMode != "" {
+		renderMode = renderOpts.FilterMode
+	}
+
+	for _, mode := range []FilterMode{engineMode, renderMode} {
+		if mode != "" && mode != FilterModeAll && mode != FilterModeAny {
+			return "", "", fmt.Errorf("engine filter error: unknown filter mode %q", mode)
+		}
+	}
+
+	return engineMode, renderMode, nil
+}
+
+// combineFilters returns filters as a single filter.Or in FilterModeAny, and unchanged otherwise.
+func combineFilters(mode FilterMode, filters []types.Filter) []types.Filter {
+	if mode != FilterModeAny || len(filters) < 2 {
+		return filters
+	}
+
+	return []types.Filter{filter.Or(filters...)}
+}
@@ -0,0 +1,162 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/filter/meta/name"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithFilterMode(t *testing.T) {
+
+	newRenderer := func() *mockRenderer {
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePodWithNamespace("pod1", defaultNamespace),
+			makePodWithNamespace("pod2", systemNamespace),
+			makeService(),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		return renderer
+	}
+
+	inDefaultNamespace := func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		return obj.GetNamespace() == defaultNamespace, nil
+	}
+
+	names := func(objects []unstructured.Unstructured) []string {
+		result := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			result = append(result, obj.GetName())
+		}
+
+		return result
+	}
+
+	t.Run("should apply multiple filters in sequence with FilterModeAll", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithFilterMode(engine.FilterModeAll),
+			engine.WithFilter(podFilter()),
+			engine.WithFilter(inDefaultNamespace),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(names(objects)).To(Equal([]string{"pod1"}))
+	})
+
+	t.Run("should keep objects accepted by any filter with FilterModeAny", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithFilterMode(engine.FilterModeAny),
+			engine.WithFilter(podFilter()),
+			engine.WithFilter(inDefaultNamespace),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(names(objects)).To(Equal([]string{"pod1", "pod2"}))
+	})
+
+	t.Run("should apply the engine mode to render-time filters as a separate group", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithFilterMode(engine.FilterModeAny),
+			engine.WithFilter(podFilter()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(),
+			engine.WithRenderFilter(name.Exact("pod2")),
+			engine.WithRenderFilter(inDefaultNamespace),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(names(objects)).To(Equal([]string{"pod1", "pod2"}))
+	})
+
+	t.Run("should let render-time filters override the mode", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithFilterMode(engine.FilterModeAny),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(),
+			engine.WithRenderFilterMode(engine.FilterModeAll),
+			engine.WithRenderFilter(podFilter()),
+			engine.WithRenderFilter(inDefaultNamespace),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(names(objects)).To(Equal([]string{"pod1"}))
+	})
+
+	t.Run("should always apply targets", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithFilterMode(engine.FilterModeAny),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(),
+			engine.WithRenderFilter(podFilter()),
+			engine.WithRenderFilter(inDefaultNamespace),
+			engine.WithTarget(name.Exact("pod2")),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(names(objects)).To(Equal([]string{"pod2"}))
+	})
+
+	t.Run("should report combined filters to the drop hook", func(t *testing.T) {
+		g := NewWithT(t)
+
+		drops := make([]drop, 0)
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithFilterMode(engine.FilterModeAny),
+			engine.WithFilter(name.Exact("pod1")),
+			engine.WithFilter(name.Exact("pod2")),
+			engine.WithDropHook(func(_ context.Context, obj unstructured.Unstructured, stage engine.FilterStage, index int) {
+				drops = append(drops, drop{name: obj.GetName(), stage: stage, index: index})
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(drops).To(Equal([]drop{{name: "svc1", stage: engine.FilterStageEngine, index: 0}}))
+	})
+
+	t.Run("should reject an unknown mode", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithFilterMode("some"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(MatchError(ContainSubstring(`unknown filter mode "some"`)))
+	})
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// beforeRender chains the BeforeRender hooks on the render-time values. The hooks are given a deep
+// copy of values, so that modifying it in place does not affect the map passed to WithValues.
+func (e *Engine) beforeRender(ctx context.Context, values map[string]any) (map[string]any, error) {
+	if len(e.options.Hooks) == 0 {
+		return values, nil
+	}
+
+	values = copyValues(values)
+
+	for i, hook := range e.options.Hooks {
+		result, err := hook.BeforeRender(ctx, values)
+		if err != nil {
+			return nil, fmt.Errorf("engine hook error: hook %d (%T) before render: %w", i, hook, err)
+		}
+
+		if result == nil {
+			result = make(map[string]any)
+		}
+
+		values = result
+	}
+
+	return values, nil
+}
+
+// copyValues returns a deep copy of values: nested maps and slices are copied, other values are shared.
+func copyValues(values map[string]any) map[string]any {
+	if values == nil {
+		return nil
+	}
+
+	result := make(map[string]any, len(values))
+	for key, value := range values {
+		result[key] = copyValue(value)
+	}
+
+	return result
+}
+
+// copyValue returns a deep copy of a value of copyValues.
+func copyValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return copyValues(v)
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = copyValue(item)
+		}
+
+		return result
+	default:
+		return value
+	}
+}
+
+// afterRender chains the AfterRender hooks on the final objects.
+func (e *Engine) afterRender(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+) ([]unstructured.Unstructured, error) {
+	for i, hook := range e.options.Hooks {
+		result, err := hook.AfterRender(ctx, objects)
+		if err != nil {
+			return nil, fmt.Errorf("engine hook error: hook %d (%T) after render: %w", i, hook, err)
+		}
+
+		objects = result
+	}
+
+	return objects, nil
+}
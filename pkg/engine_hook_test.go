@@ -0,0 +1,226 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+// funcHook implements types.Hook with optional functions; nil functions pass their input through.
+type funcHook struct {
+	before func(ctx context.Context, values map[string]any) (map[string]any, error)
+	after  func(ctx context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error)
+}
+
+func (h funcHook) BeforeRender(ctx context.Context, values map[string]any) (map[string]any, error) {
+	if h.before == nil {
+		return values, nil
+	}
+
+	return h.before(ctx, values)
+}
+
+func (h funcHook) AfterRender(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+) ([]unstructured.Unstructured, error) {
+	if h.after == nil {
+		return objects, nil
+	}
+
+	return h.after(ctx, objects)
+}
+
+func TestWithHook(t *testing.T) {
+
+	t.Run("should chain BeforeRender hooks on the render-time values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, map[string]any{"env": "prod", "replicas": 3}).
+			Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithHook(funcHook{
+				before: func(_ context.Context, values map[string]any) (map[string]any, error) {
+					values["replicas"] = 1
+
+					return values, nil
+				},
+			}),
+			engine.WithHook(funcHook{
+				before: func(_ context.Context, values map[string]any) (map[string]any, error) {
+					replicas, _ := values["replicas"].(int)
+
+					return map[string]any{"env": values["env"], "replicas": replicas + 2}, nil
+				},
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(), engine.WithValues(map[string]any{"env": "prod"}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		renderer.AssertExpectations(t)
+	})
+
+	t.Run("should not let BeforeRender hooks modify the caller's values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).
+			Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithHook(funcHook{
+				before: func(_ context.Context, values map[string]any) (map[string]any, error) {
+					values["env"] = "dev"
+					values["image"].(map[string]any)["tag"] = "latest"
+					values["zones"].([]any)[0] = "b"
+
+					return values, nil
+				},
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		values := map[string]any{
+			"env":   "prod",
+			"image": map[string]any{"tag": "1.0"},
+			"zones": []any{"a"},
+		}
+
+		_, err = e.Render(t.Context(), engine.WithValues(values))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(values).Should(Equal(map[string]any{
+			"env":   "prod",
+			"image": map[string]any{"tag": "1.0"},
+			"zones": []any{"a"},
+		}))
+	})
+
+	t.Run("should let AfterRender hooks modify the final objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+			makePod("pod2"),
+			makeService(),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		var seen []string
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithFilter(podFilter()),
+			engine.WithHook(funcHook{
+				after: func(_ context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+					for _, obj := range objects {
+						seen = append(seen, obj.GetName())
+					}
+
+					return objects[:1], nil
+				},
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(seen).Should(Equal([]string{"pod1", "pod2"}))
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("pod1"))
+		g.Expect(report.ObjectCount).Should(Equal(1))
+	})
+
+	t.Run("should validate the output of AfterRender hooks", func(t *testing.T) {
+		g := NewWithT(t)
+
+		validationErr := errors.New("invalid pod")
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithHook(funcHook{
+				after: func(_ context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+					return append(objects, makePod("injected")), nil
+				},
+			}),
+			engine.WithValidator(func(_ context.Context, obj unstructured.Unstructured) error {
+				if obj.GetName() == "injected" {
+					return validationErr
+				}
+
+				return nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(MatchError(validationErr))
+	})
+
+	t.Run("should abort the render when BeforeRender fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		hookErr := errors.New("missing values")
+		renderer := new(mockRenderer)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithHook(funcHook{
+				before: func(_ context.Context, _ map[string]any) (map[string]any, error) {
+					return nil, hookErr
+				},
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).Should(MatchError(hookErr))
+		g.Expect(err).Should(MatchError(ContainSubstring("engine hook error")))
+		g.Expect(objects).Should(BeNil())
+		g.Expect(report.Renderers).Should(BeEmpty())
+		renderer.AssertNotCalled(t, "Process", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should abort the render when AfterRender fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		hookErr := errors.New("audit failed")
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithHook(funcHook{
+				after: func(_ context.Context, _ []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+					return nil, hookErr
+				},
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).Should(MatchError(hookErr))
+		g.Expect(objects).Should(BeNil())
+	})
+}
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// ErrIdentityLost is the transformer error reported when an engine-level or render-time transformer
+// returns an object without the apiVersion, kind or name of the object it received.
+var ErrIdentityLost = errors.New("transformer dropped the object identity")
+
+// identityCheckTransformer wraps t, the transformer at index within stage, so that an object losing its
+// apiVersion, kind or name fails the render with a transformer.Error identifying the object as it was
+// before the transformer. Fields the input object did not set, such as the name of an object using
+// generateName, are not checked.
+func identityCheckTransformer(stage FilterStage, index int, t types.Transformer) types.Transformer {
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		// Capture the identity first, since transformers may mutate obj in place.
+		before := identity(obj)
+
+		result, err := t(ctx, obj)
+		if err != nil {
+			return result, err
+		}
+
+		lost := (before.GetAPIVersion() != "" && result.GetAPIVersion() == "") ||
+			(before.GetKind() != "" && result.GetKind() == "") ||
+			(before.GetName() != "" && result.GetName() == "")
+
+		if lost {
+			return unstructured.Unstructured{}, &transformer.Error{
+				Object: before,
+				Err: fmt.Errorf(
+					"%w: %s transformer %d returned an object without apiVersion, kind or name",
+					ErrIdentityLost,
+					stage,
+					index,
+				),
+			}
+		}
+
+		return result, nil
+	}
+}
+
+// identity returns an object carrying only the apiVersion, kind, name and namespace of obj.
+func identity(obj unstructured.Unstructured) unstructured.Unstructured {
+	result := unstructured.Unstructured{Object: map[string]any{}}
+	result.SetAPIVersion(obj.GetAPIVersion())
+	result.SetKind(obj.GetKind())
+	result.SetName(obj.GetName())
+	result.SetNamespace(obj.GetNamespace())
+
+	return result
+}
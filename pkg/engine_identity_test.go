@@ -0,0 +1,125 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithIdentityCheck(t *testing.T) {
+
+	// zeroTransformer is the buggy transformer returning a zero object without an error.
+	zeroTransformer := func(_ context.Context, _ unstructured.Unstructured) (unstructured.Unstructured, error) {
+		return unstructured.Unstructured{}, nil
+	}
+
+	newEngine := func(g *WithT, objects []unstructured.Unstructured, opts ...engine.Option) *engine.Engine {
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return(objects, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(append([]engine.Option{engine.WithRenderer(renderer)}, opts...)...)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		return e
+	}
+
+	t.Run("should fail when a transformer returns a zero object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := newEngine(g, []unstructured.Unstructured{makePod("pod1")},
+			engine.WithTransformer(addLabels(map[string]string{"env": "prod"})),
+			engine.WithTransformer(zeroTransformer),
+		)
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).Should(MatchError(engine.ErrIdentityLost))
+		g.Expect(err.Error()).Should(ContainSubstring("engine transformer 1"))
+		g.Expect(objects).Should(BeNil())
+
+		var transformerErr *transformer.Error
+		g.Expect(errors.As(err, &transformerErr)).Should(BeTrue())
+		g.Expect(transformerErr.Object.GetKind()).Should(Equal("Pod"))
+		g.Expect(transformerErr.Object.GetName()).Should(Equal("pod1"))
+	})
+
+	t.Run("should report the index of the transformer among the engine-level ones", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := newEngine(g, []unstructured.Unstructured{makePod("pod1")},
+			engine.WithTransformer(addLabels(map[string]string{"env": "prod"})),
+			engine.WithFilter(podFilter()),
+			engine.WithTransformer(addLabels(map[string]string{"tier": "web"})),
+			engine.WithTransformer(zeroTransformer),
+		)
+
+		_, err := e.Render(t.Context())
+		g.Expect(err).Should(MatchError(engine.ErrIdentityLost))
+		g.Expect(err.Error()).Should(ContainSubstring("engine transformer 2"))
+
+		e = newEngine(g, []unstructured.Unstructured{makePod("pod1")},
+			engine.WithTransformer(addLabels(map[string]string{"env": "prod"})),
+		)
+
+		_, err = e.Render(t.Context(),
+			engine.WithRenderTransformer(addLabels(map[string]string{"tier": "web"})),
+			engine.WithRenderTransformer(zeroTransformer),
+		)
+		g.Expect(err).Should(MatchError(engine.ErrIdentityLost))
+		g.Expect(err.Error()).Should(ContainSubstring("render transformer 1"))
+	})
+
+	t.Run("should check render-time transformers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := newEngine(g, []unstructured.Unstructured{makePod("pod1")})
+
+		_, err := e.Render(t.Context(), engine.WithRenderTransformer(zeroTransformer))
+		g.Expect(err).Should(MatchError(engine.ErrIdentityLost))
+	})
+
+	t.Run("should accept renamed objects and objects without a name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		generated := makePod("")
+		generated.SetGenerateName("pod-")
+
+		rename := func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+			if obj.GetName() != "" {
+				obj.SetName("renamed-" + obj.GetName())
+			}
+
+			return obj, nil
+		}
+
+		e := newEngine(g, []unstructured.Unstructured{makePod("pod1"), generated}, engine.WithTransformer(rename))
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetName()).Should(Equal("renamed-pod1"))
+	})
+
+	t.Run("should let objects through when disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := newEngine(g, []unstructured.Unstructured{makePod("pod1")},
+			engine.WithTransformer(zeroTransformer),
+			engine.WithIdentityCheck(false),
+		)
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetKind()).Should(BeEmpty())
+	})
+}
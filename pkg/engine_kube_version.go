@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// ErrInvalidKubeVersion is returned when the Kubernetes version set with WithKubeVersion or
+// WithRenderKubeVersion cannot be parsed.
+var ErrInvalidKubeVersion = errors.New("invalid Kubernetes version")
+
+// kubeVersion parses a Kubernetes version and returns it in the "v1.31.0" form.
+// An empty version is returned as-is.
+func kubeVersion(v string) (string, error) {
+	if v == "" {
+		return "", nil
+	}
+
+	parsed, err := version.ParseGeneric(v)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %w", ErrInvalidKubeVersion, v, err)
+	}
+
+	return fmt.Sprintf("v%d.%d.%d", parsed.Major(), parsed.Minor(), parsed.Patch()), nil
+}
+
+// versionedRenderer adapts a types.VersionAwareRenderer to the Kubernetes version of a render,
+// calling ProcessVersion with that version instead of Process.
+type versionedRenderer struct {
+	types.VersionAwareRenderer
+
+	version string
+}
+
+func (r versionedRenderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	return r.ProcessVersion(ctx, values, r.version)
+}
+
+// versioned returns renderer adapted to the Kubernetes version of the render, when one is set and
+// the renderer implements types.VersionAwareRenderer, and renderer itself otherwise.
+func versioned(renderer types.Renderer, version string) types.Renderer {
+	if version == "" || !types.Implements[types.VersionAwareRenderer](renderer) {
+		return renderer
+	}
+
+	vr, _ := renderer.(types.VersionAwareRenderer)
+
+	return versionedRenderer{
+		VersionAwareRenderer: vr,
+		version:              version,
+	}
+}
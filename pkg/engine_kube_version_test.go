@@ -0,0 +1,210 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/cache"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/validator/apiversion"
+
+	. "github.com/onsi/gomega"
+)
+
+// versionRenderer renders a pod named after the Kubernetes version it is rendered for.
+type versionRenderer struct {
+	versions []string
+	calls    int
+}
+
+func (r *versionRenderer) Name() string {
+	return "versioned"
+}
+
+func (r *versionRenderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	return r.ProcessVersion(ctx, values, "")
+}
+
+func (r *versionRenderer) ProcessVersion(
+	_ context.Context,
+	_ map[string]any,
+	version string,
+) ([]unstructured.Unstructured, error) {
+	r.calls++
+	r.versions = append(r.versions, version)
+
+	return []unstructured.Unstructured{makePod("pod-" + version)}, nil
+}
+
+func TestWithKubeVersion(t *testing.T) {
+
+	t.Run("should pass the normalized version to version-aware renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &versionRenderer{}
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithKubeVersion("1.31"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("pod-v1.31.0"))
+		g.Expect(renderer.versions).Should(Equal([]string{"v1.31.0"}))
+	})
+
+	t.Run("should call Process without a version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &versionRenderer{}
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(renderer.versions).Should(Equal([]string{""}))
+	})
+
+	t.Run("should let render-time versions override the engine version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &versionRenderer{}
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithKubeVersion("v1.29.0"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), engine.WithRenderKubeVersion("v1.31.2-gke.100"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(renderer.versions).Should(Equal([]string{"v1.31.2", "v1.29.0"}))
+	})
+
+	t.Run("should carry the version in the render context", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var seen []string
+
+		record := func(ctx context.Context) {
+			version, _ := types.KubeVersion(ctx)
+			seen = append(seen, version)
+		}
+
+		renderer := new(mockRenderer)
+		renderer.On("Name").Return("mock")
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithKubeVersion("v1.30.1"),
+			engine.WithHook(funcHook{
+				before: func(ctx context.Context, values map[string]any) (map[string]any, error) {
+					record(ctx)
+
+					return values, nil
+				},
+			}),
+			engine.WithValidator(func(ctx context.Context, _ unstructured.Unstructured) error {
+				record(ctx)
+
+				return nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(seen).Should(Equal([]string{"v1.30.1", "v1.30.1"}))
+	})
+
+	t.Run("should cache results per version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &versionRenderer{}
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithRenderCache(cache.NewLRU(10)),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		for _, version := range []string{"v1.30.0", "v1.31.0", "v1.30.0"} {
+			_, err = e.Render(t.Context(), engine.WithRenderKubeVersion(version))
+			g.Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		g.Expect(renderer.calls).Should(Equal(2))
+	})
+
+	t.Run("should reject invalid versions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(engine.WithKubeVersion("latest"))
+		g.Expect(err).Should(MatchError(engine.ErrInvalidKubeVersion))
+
+		e, err := engine.New(engine.WithRenderer(&versionRenderer{}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), engine.WithRenderKubeVersion("1"))
+		g.Expect(err).Should(MatchError(engine.ErrInvalidKubeVersion))
+	})
+
+	t.Run("should validate apiVersions against the targeted version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cronJob := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "batch/v1beta1",
+			"kind":       "CronJob",
+			"metadata":   map[string]any{"name": "backup", "namespace": defaultNamespace},
+		}}
+
+		renderer := new(mockRenderer)
+		renderer.On("Name").Return("mock")
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{cronJob}, nil)
+
+		supported, err := apiversion.Supported()
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithValidator(supported),
+			engine.WithKubeVersion("v1.24.0"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), engine.WithRenderKubeVersion("v1.25.0"))
+		g.Expect(err).Should(MatchError(apiversion.ErrRemoved))
+	})
+
+	t.Run("should be reported by Explain", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(&versionRenderer{}),
+			engine.WithKubeVersion("v1.31"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		plan, err := e.Explain(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(plan.KubeVersion).Should(Equal("v1.31.0"))
+		g.Expect(plan.Renderers[0].VersionAware).Should(BeTrue())
+	})
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Log attribute keys used consistently across all engine log records.
+const (
+	logKeyRenderer    = "renderer"
+	logKeyGVK         = "gvk"
+	logKeyNamespace   = "namespace"
+	logKeyName        = "name"
+	logKeyDuration    = "duration"
+	logKeyObjectCount = "objects"
+	logKeyStage       = "stage"
+	logKeyIndex       = "index"
+	logKeyError       = "error"
+)
+
+// discardLogger is the default logger, it drops all records without formatting them.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// objectLogAttrs returns the log attributes identifying an object.
+func objectLogAttrs(obj unstructured.Unstructured) []any {
+	return []any{
+		slog.String(logKeyGVK, obj.GroupVersionKind().String()),
+		slog.String(logKeyNamespace, obj.GetNamespace()),
+		slog.String(logKeyName, obj.GetName()),
+	}
+}
+
+// logRendererDone logs the completion of a renderer, successful or not.
+func logRendererDone(
+	ctx context.Context,
+	logger *slog.Logger,
+	name string,
+	duration time.Duration,
+	objectCount int,
+	err error,
+) {
+	if err != nil {
+		logger.DebugContext(ctx, "renderer failed",
+			slog.String(logKeyRenderer, name),
+			slog.Duration(logKeyDuration, duration),
+			slog.Any(logKeyError, err),
+		)
+
+		return
+	}
+
+	logger.DebugContext(ctx, "renderer finished",
+		slog.String(logKeyRenderer, name),
+		slog.Duration(logKeyDuration, duration),
+		slog.Int(logKeyObjectCount, objectCount),
+	)
+}
+
+// stageLogAttrs returns the log attributes identifying the filter or transformer at index within stage,
+// the renderer whose objects it processes, if known, and obj.
+func stageLogAttrs(ctx context.Context, stage FilterStage, index int, obj unstructured.Unstructured) []any {
+	var attrs []any
+
+	if name, ok := types.RendererName(ctx); ok {
+		attrs = append(attrs, slog.String(logKeyRenderer, name))
+	}
+
+	attrs = append(attrs, slog.String(logKeyStage, string(stage)), slog.Int(logKeyIndex, index))
+
+	return append(attrs, objectLogAttrs(obj)...)
+}
+
+// loggingFilter wraps f, the filter at index within stage, so that each object it rejects is logged.
+func loggingFilter(logger *slog.Logger, stage FilterStage, index int, f types.Filter) types.Filter {
+	return func(ctx context.Context, obj unstructured.Unstructured) (bool, error) {
+		ok, err := f(ctx, obj)
+		if err == nil && !ok {
+			logger.DebugContext(ctx, "object dropped by filter", stageLogAttrs(ctx, stage, index, obj)...)
+		}
+
+		return ok, err
+	}
+}
+
+// loggingTransformer wraps t, the transformer at index within stage, so that each application is logged.
+func loggingTransformer(logger *slog.Logger, stage FilterStage, index int, t types.Transformer) types.Transformer {
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		attrs := stageLogAttrs(ctx, stage, index, obj)
+
+		result, err := t(ctx, obj)
+		if err != nil {
+			logger.DebugContext(ctx, "transformer failed", append(attrs, slog.Any(logKeyError, err))...)
+
+			return result, err
+		}
+
+		logger.DebugContext(ctx, "transformer applied", attrs...)
+
+		return result, nil
+	}
+}
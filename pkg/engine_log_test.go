@@ -0,0 +1,155 @@
+package engine_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithLogger(t *testing.T) {
+
+	t.Run("should log renderer, filter and transformer activity at debug level", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePodWithNamespace("pod1", defaultNamespace),
+			makeService(),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithFilter(podFilter()),
+			engine.WithTransformer(addLabels(map[string]string{"env": "test"})),
+			engine.WithLogger(logger),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+
+		records := decodeLogRecords(t, &buf)
+		g.Expect(records).Should(ContainElement(And(
+			HaveKeyWithValue("msg", "renderer started"),
+			HaveKeyWithValue("renderer", "mock"),
+		)))
+		g.Expect(records).Should(ContainElement(And(
+			HaveKeyWithValue("msg", "renderer finished"),
+			HaveKeyWithValue("renderer", "mock"),
+			HaveKey("duration"),
+			HaveKeyWithValue("objects", BeNumerically("==", 2)),
+		)))
+		g.Expect(records).Should(ContainElement(And(
+			HaveKeyWithValue("msg", "object dropped by filter"),
+			HaveKeyWithValue("renderer", "mock"),
+			HaveKeyWithValue("stage", "engine"),
+			HaveKeyWithValue("gvk", "/v1, Kind=Service"),
+			HaveKeyWithValue("namespace", ""),
+			HaveKeyWithValue("name", "svc1"),
+		)))
+		g.Expect(records).Should(ContainElement(And(
+			HaveKeyWithValue("msg", "transformer applied"),
+			HaveKeyWithValue("renderer", "mock"),
+			HaveKeyWithValue("stage", "engine"),
+			HaveKeyWithValue("gvk", "/v1, Kind=Pod"),
+			HaveKeyWithValue("namespace", defaultNamespace),
+			HaveKeyWithValue("name", "pod1"),
+		)))
+	})
+
+	t.Run("should log the index of filters and transformers within their stage", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+			makeService(),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithTransformer(addLabels(map[string]string{"env": "test"})),
+			engine.WithFilter(podFilter()),
+			engine.WithTransformer(addLabels(map[string]string{"tier": "web"})),
+			engine.WithLogger(logger),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(),
+			engine.WithRenderTransformer(addLabels(map[string]string{"team": "platform"})),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		records := decodeLogRecords(t, &buf)
+		g.Expect(records).Should(ContainElement(And(
+			HaveKeyWithValue("msg", "transformer applied"),
+			HaveKeyWithValue("stage", "engine"),
+			HaveKeyWithValue("index", BeNumerically("==", 1)),
+			HaveKeyWithValue("name", "pod1"),
+		)))
+		g.Expect(records).Should(ContainElement(And(
+			HaveKeyWithValue("msg", "transformer applied"),
+			HaveKeyWithValue("stage", "render"),
+			HaveKeyWithValue("index", BeNumerically("==", 0)),
+			HaveKeyWithValue("name", "pod1"),
+		)))
+		g.Expect(records).Should(ContainElement(And(
+			HaveKeyWithValue("msg", "transformer applied"),
+			HaveKeyWithValue("stage", "engine"),
+			HaveKeyWithValue("index", BeNumerically("==", 0)),
+			HaveKeyWithValue("name", "svc1"),
+		)))
+	})
+
+	t.Run("should not log above debug level", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer.On("Name").Return("mock")
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithFilter(podFilter()),
+			engine.WithLogger(logger),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.Len()).Should(BeZero())
+	})
+}
+
+// decodeLogRecords parses JSON log lines into maps.
+func decodeLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	records := make([]map[string]any, 0)
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var record map[string]any
+		NewWithT(t).Expect(dec.Decode(&record)).Should(Succeed())
+		records = append(records, record)
+	}
+
+	return records
+}
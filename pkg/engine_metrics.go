@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Metrics receives measurements of the renders of an engine, e.g. to export them to Prometheus
+// (see the metrics/prometheus package). Implementations must be safe for concurrent use, as
+// renders, and the renderers of a parallel render, may run concurrently.
+type Metrics interface {
+	// ObserveRender is called once per successful render with its total duration and the number
+	// of objects it returned.
+	ObserveRender(duration time.Duration, objectCount int)
+
+	// ObserveRenderer is called once per renderer execution with the renderer name, its duration
+	// and its error, nil on success.
+	ObserveRenderer(name string, duration time.Duration, err error)
+
+	// IncFilterDrop is called each time an engine-level or render-time filter rejects an object.
+	IncFilterDrop(stage FilterStage)
+}
+
+// noopMetrics is the default Metrics, it discards all measurements.
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveRender(time.Duration, int) {}
+
+func (noopMetrics) ObserveRenderer(string, time.Duration, error) {}
+
+func (noopMetrics) IncFilterDrop(FilterStage) {}
+
+// dropHook returns the DropHook of the engine, also counting drops in its Metrics if set.
+func (e *Engine) dropHook() DropHook {
+	if _, ok := e.options.Metrics.(noopMetrics); ok {
+		return e.options.DropHook
+	}
+
+	hook := e.options.DropHook
+
+	return func(ctx context.Context, object unstructured.Unstructured, stage FilterStage, filterIndex int) {
+		e.options.Metrics.IncFilterDrop(stage)
+
+		if hook != nil {
+			hook(ctx, object, stage, filterIndex)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/filter/meta/name"
+
+	. "github.com/onsi/gomega"
+)
+
+type rendererObservation struct {
+	name string
+	err  error
+}
+
+// recordingMetrics records the measurements it receives.
+type recordingMetrics struct {
+	mu        sync.Mutex
+	renders   []int
+	renderers []rendererObservation
+	drops     map[engine.FilterStage]int
+}
+
+func (m *recordingMetrics) ObserveRender(_ time.Duration, objectCount int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.renders = append(m.renders, objectCount)
+}
+
+func (m *recordingMetrics) ObserveRenderer(name string, _ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.renderers = append(m.renderers, rendererObservation{name: name, err: err})
+}
+
+func (m *recordingMetrics) IncFilterDrop(stage engine.FilterStage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.drops == nil {
+		m.drops = make(map[engine.FilterStage]int)
+	}
+
+	m.drops[stage]++
+}
+
+func TestWithMetrics(t *testing.T) {
+
+	t.Run("should observe renders, renderers and filter drops", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+			makePod("pod2"),
+			makeService(),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		m := &recordingMetrics{}
+		drops := 0
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithMetrics(m),
+			engine.WithFilter(podFilter()),
+			engine.WithDropHook(func(_ context.Context, _ unstructured.Unstructured, _ engine.FilterStage, _ int) {
+				drops++
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(), engine.WithRenderFilter(name.Exact("pod1")))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+
+		g.Expect(m.renders).To(Equal([]int{1}))
+		g.Expect(m.renderers).To(Equal([]rendererObservation{{name: "mock"}}))
+		g.Expect(m.drops).To(Equal(map[engine.FilterStage]int{
+			engine.FilterStageEngine: 1,
+			engine.FilterStageRender: 1,
+		}))
+		g.Expect(drops).To(Equal(2))
+	})
+
+	t.Run("should observe failing renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		errRender := errors.New("render failed")
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{}, errRender)
+		renderer.On("Name").Return("mock")
+
+		m := &recordingMetrics{}
+
+		e, err := engine.New(engine.WithRenderer(renderer), engine.WithMetrics(m))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(MatchError(errRender))
+
+		g.Expect(m.renders).To(BeEmpty())
+		g.Expect(m.renderers).To(HaveLen(1))
+		g.Expect(m.renderers[0].err).To(MatchError(errRender))
+	})
+}
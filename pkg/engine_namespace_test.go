@@ -0,0 +1,88 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithDefaultNamespace(t *testing.T) {
+
+	makeObject := func(apiVersion string, kind string, name string, namespace string) unstructured.Unstructured {
+		obj := unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetName(name)
+		obj.SetNamespace(namespace)
+
+		return obj
+	}
+
+	newEngine := func(g *WithT, opts ...engine.Option) *engine.Engine {
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makeObject("v1", "ConfigMap", "cm1", ""),
+			makeObject("v1", "ConfigMap", "cm2", "kube-system"),
+			makeObject("rbac.authorization.k8s.io/v1", "ClusterRole", "reader", ""),
+			makeObject("v1", "Namespace", "apps", ""),
+			makeObject("example.com/v1", "Widget", "w1", ""),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(append([]engine.Option{engine.WithRenderer(renderer)}, opts...)...)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		return e
+	}
+
+	namespaces := func(objects []unstructured.Unstructured) []string {
+		result := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			result = append(result, obj.GetNamespace())
+		}
+
+		return result
+	}
+
+	t.Run("should fill the namespace of namespaced objects only", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := newEngine(g, engine.WithDefaultNamespace("apps"))
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(namespaces(objects)).Should(Equal([]string{"apps", "kube-system", "", "", "apps"}))
+	})
+
+	t.Run("should default the namespace before filters run", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := newEngine(g,
+			engine.WithDefaultNamespace("apps"),
+			engine.WithFilter(func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				return obj.GetNamespace() == "apps", nil
+			}),
+		)
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetName()).Should(Equal("cm1"))
+		g.Expect(objects[1].GetName()).Should(Equal("w1"))
+	})
+
+	t.Run("should leave namespaces unchanged by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := newEngine(g).Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(namespaces(objects)).Should(Equal([]string{"", "kube-system", "", "", ""}))
+	})
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/k8s-manifest-kit/pkg/util/k8s"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/meta/namespace"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/scope"
+)
+
+// ClusterScoped is the key of the RenderForNamespaces result holding the cluster-scoped objects.
+const ClusterScoped = ""
+
+// ErrNamespaceEmpty is returned by RenderForNamespaces when one of the namespaces is empty.
+var ErrNamespaceEmpty = errors.New("namespace must not be empty")
+
+// RenderForNamespaces renders once and returns a copy of the namespaced objects for each of the given
+// namespaces, keyed by namespace, with metadata.namespace set to it. It avoids re-running expensive
+// renderers when the same set of objects is deployed to several namespaces.
+//
+// Well-known cluster-scoped objects (see scope.IsClusterScoped), such as ClusterRoles or CRDs, would
+// collide across namespaces: they are emitted once, under the ClusterScoped key, which is absent when
+// there are none. The namespace of every other object is overwritten, whatever it was rendered with.
+//
+// Hooks and validators run once, on the objects rendered before they are copied per namespace.
+func (e *Engine) RenderForNamespaces(
+	ctx context.Context,
+	namespaces []string,
+	opts ...RenderOption,
+) (map[string][]unstructured.Unstructured, error) {
+	for i, ns := range namespaces {
+		if ns == "" {
+			return nil, fmt.Errorf("%w: namespaces[%d]", ErrNamespaceEmpty, i)
+		}
+	}
+
+	objects, err := e.Render(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var cluster, namespaced []unstructured.Unstructured
+
+	for _, obj := range objects {
+		if scope.IsClusterScoped(obj.GroupVersionKind().GroupKind()) {
+			cluster = append(cluster, obj)
+		} else {
+			namespaced = append(namespaced, obj)
+		}
+	}
+
+	result := make(map[string][]unstructured.Unstructured, len(namespaces)+1)
+	if len(cluster) > 0 {
+		result[ClusterScoped] = cluster
+	}
+
+	for _, ns := range namespaces {
+		copies, err := pipeline.ApplyTransformers(
+			ctx,
+			k8s.DeepCloneUnstructuredSlice(namespaced),
+			[]types.Transformer{namespace.Set(ns)},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("engine namespace error: %w", err)
+		}
+
+		result[ns] = copies
+	}
+
+	return result, nil
+}
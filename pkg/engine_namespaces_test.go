@@ -0,0 +1,85 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRenderForNamespaces(t *testing.T) {
+
+	clusterRole := func() unstructured.Unstructured {
+		obj := unstructured.Unstructured{}
+		obj.SetAPIVersion("rbac.authorization.k8s.io/v1")
+		obj.SetKind("ClusterRole")
+		obj.SetName("reader")
+
+		return obj
+	}
+
+	newEngine := func(g *WithT, objects ...unstructured.Unstructured) (*engine.Engine, *mockRenderer) {
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return(objects, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		return e, renderer
+	}
+
+	t.Run("should render once and namespace a copy per namespace", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, renderer := newEngine(g, makePod("web"), makePodWithNamespace("db", "data"), clusterRole())
+
+		result, err := e.RenderForNamespaces(t.Context(), []string{"team-a", "team-b"})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		renderer.AssertNumberOfCalls(t, "Process", 1)
+
+		g.Expect(result).Should(HaveLen(3))
+
+		for _, ns := range []string{"team-a", "team-b"} {
+			g.Expect(result[ns]).Should(HaveLen(2))
+
+			for _, obj := range result[ns] {
+				g.Expect(obj.GetNamespace()).Should(Equal(ns))
+			}
+		}
+
+		g.Expect(result[engine.ClusterScoped]).Should(HaveLen(1))
+		g.Expect(result[engine.ClusterScoped][0].GetName()).Should(Equal("reader"))
+		g.Expect(result[engine.ClusterScoped][0].GetNamespace()).Should(BeEmpty())
+
+		result["team-a"][0].SetLabels(map[string]string{"changed": "true"})
+		g.Expect(result["team-b"][0].GetLabels()).Should(BeEmpty())
+	})
+
+	t.Run("should omit the cluster-scoped key when there are none", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, _ := newEngine(g, makePod("web"))
+
+		result, err := e.RenderForNamespaces(t.Context(), []string{"team-a"}, engine.WithRenderFilter(podFilter()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(1))
+		g.Expect(result).ShouldNot(HaveKey(engine.ClusterScoped))
+	})
+
+	t.Run("should reject an empty namespace", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, renderer := newEngine(g, makePod("web"))
+
+		_, err := e.RenderForNamespaces(t.Context(), []string{"team-a", ""})
+		g.Expect(err).Should(MatchError(engine.ErrNamespaceEmpty))
+		g.Expect(err).Should(MatchError(ContainSubstring("namespaces[1]")))
+		renderer.AssertNotCalled(t, "Process", mock.Anything, mock.Anything)
+	})
+}
@@ -1,10 +1,15 @@
 package engine
 
 import (
+	"io"
+	"log/slog"
 	"maps"
+	"time"
 
 	"github.com/k8s-manifest-kit/pkg/util"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/k8s-manifest-kit/engine/pkg/cache"
 	"github.com/k8s-manifest-kit/engine/pkg/types"
 )
 
@@ -21,6 +26,28 @@ type RenderOptions struct {
 	// Values are render-time values passed to all renderers during this specific Render() call.
 	// These values are deep merged with Source-level values, with render-time values taking precedence.
 	Values map[string]any
+
+	// ValuesSources are read, in order, when the render starts. Their values are deep merged together
+	// and form the base of Values, which take precedence.
+	ValuesSources []ValuesSource
+
+	// RendererValues are per-renderer value overrides, keyed by Renderer.Name().
+	// A renderer receives Values deep merged with its override, with the override taking precedence.
+	RendererValues map[string]map[string]any
+
+	// FilterMode, if set, overrides the engine filter mode for the render-time filters of this Render() call.
+	FilterMode FilterMode
+
+	// KubeVersion, if set, overrides the Kubernetes version targeted by the engine for this Render() call.
+	KubeVersion string
+
+	// Deadline bounds the whole Render() call, including all renderers, filters, transformers,
+	// hooks and validators. Zero means no deadline.
+	Deadline time.Duration
+
+	// Targets select the objects of a partial render. They are applied as a render-time filter,
+	// and passed to renderers implementing types.TargetedRenderer so they can skip work.
+	Targets []types.Filter
 }
 
 // ApplyTo implements the Option interface for RenderOptions.
@@ -28,39 +55,262 @@ func (opts RenderOptions) ApplyTo(target *RenderOptions) {
 	target.Filters = append(target.Filters, opts.Filters...)
 	target.Transformers = append(target.Transformers, opts.Transformers...)
 
+	target.ValuesSources = append(target.ValuesSources, opts.ValuesSources...)
+	target.Targets = append(target.Targets, opts.Targets...)
+
+	if opts.FilterMode != "" {
+		target.FilterMode = opts.FilterMode
+	}
+
+	if opts.KubeVersion != "" {
+		target.KubeVersion = opts.KubeVersion
+	}
+
+	if opts.Deadline != 0 {
+		target.Deadline = opts.Deadline
+	}
+
 	if opts.Values != nil {
 		target.Values = maps.Clone(opts.Values)
 	}
+
+	for name, values := range opts.RendererValues {
+		mergeRendererValues(target, name, values)
+	}
+}
+
+// mergeRendererValues deep merges values into the override for the named renderer.
+func mergeRendererValues(target *RenderOptions, name string, values map[string]any) {
+	if target.RendererValues == nil {
+		target.RendererValues = make(map[string]map[string]any)
+	}
+
+	target.RendererValues[name] = util.DeepMerge(target.RendererValues[name], values)
+}
+
+// rendererValues returns the values to pass to the given renderer.
+func rendererValues(opts RenderOptions, renderer types.Renderer) map[string]any {
+	override, ok := opts.RendererValues[renderer.Name()]
+	if !ok {
+		return opts.Values
+	}
+
+	return util.DeepMerge(opts.Values, override)
 }
 
 // Options represents the processing options for the engine.
 type Options struct {
-	// Filters are engine-level filters applied to all renders.
+	// Filters are engine-level filters applied to all renders. When the options are applied,
+	// they are added to Stages, ahead of Transformers and of the stages listed in Stages.
 	Filters []types.Filter
 
-	// Transformers are engine-level transformers applied to all renders.
+	// Transformers are engine-level transformers applied to all renders. When the options are applied,
+	// they are added to Stages, ahead of the stages listed in Stages.
 	Transformers []types.Transformer
 
+	// Stages are the engine-level filters and transformers applied to all renders, in the order
+	// they are listed.
+	Stages []types.Stage
+
+	// Validators are engine-level validators run on the final objects of every render.
+	Validators []types.Validator
+
+	// Hooks run custom logic before and after every render, in order.
+	Hooks []types.Hook
+
 	// Values are values passed to renderers (used internally during rendering).
 	Values map[string]any
 
 	// Renderers are the manifest sources to process (e.g., Helm, Kustomize, YAML).
 	Renderers []types.Renderer
 
+	// RendererPriorities are the priorities of renderers, keyed by Renderer.Name(). Renderers run,
+	// and their objects are concatenated, by ascending priority; unlisted renderers have priority 0.
+	RendererPriorities map[string]int
+
 	// Parallel enables parallel execution of renderers.
 	Parallel bool
+
+	// RendererTimeout bounds the execution of each renderer. Zero means no timeout.
+	RendererTimeout time.Duration
+
+	// DefaultNamespace is set on namespaced objects rendered without a namespace.
+	DefaultNamespace string
+
+	// DefaultValues are deep merged under the render-time values of every Render() call.
+	DefaultValues map[string]any
+
+	// ValuesSchema is the JSON Schema, in JSON or YAML, the render-time values of every Render() call
+	// must match. If nil, values are not validated.
+	ValuesSchema []byte
+
+	// Dedupe is the strategy applied to objects rendered more than once.
+	Dedupe DedupeStrategy
+
+	// FilterMode defines how engine-level filters, and by default render-time filters, combine.
+	FilterMode FilterMode
+
+	// FilterErrorMode defines how errors of engine-level and render-time filters are handled.
+	FilterErrorMode ErrorMode
+
+	// TransformerErrorMode defines how errors of engine-level and render-time transformers are handled.
+	TransformerErrorMode ErrorMode
+
+	// KubeVersion is the Kubernetes version of the cluster renders target, e.g. "v1.31.0".
+	// If empty, renders do not target a version.
+	KubeVersion string
+
+	// RequireNonEmpty makes a render fail when any renderer produces no objects.
+	RequireNonEmpty bool
+
+	// DisableIdentityCheck stops failing the render when a transformer returns an object that lost
+	// its apiVersion, kind or name.
+	DisableIdentityCheck bool
+
+	// Provenance enables stamping each object with the name of the renderer that produced it.
+	Provenance bool
+
+	// StripSourceAnnotations removes the source annotations (see SourceAnnotations) from the final
+	// objects, whatever set them.
+	StripSourceAnnotations bool
+
+	// StripSourceAnnotationKeys are additional annotation keys removed from the final objects
+	// when StripSourceAnnotations is enabled.
+	StripSourceAnnotationKeys []string
+
+	// DropHook is called each time an engine-level or render-time filter rejects an object.
+	// If nil, dropped objects are only counted in the RenderReport.
+	DropHook DropHook
+
+	// WarningHandler receives the warnings emitted during every render.
+	// If nil, warnings are only collected in the RenderReport.
+	WarningHandler types.WarningHandler
+
+	// Logger receives debug-level records about the render pipeline.
+	// If nil, logging is disabled.
+	Logger *slog.Logger
+
+	// Metrics receives measurements of every render.
+	// If nil, measurements are discarded.
+	Metrics Metrics
+
+	// Cache stores renderer results keyed by renderer name, position and values.
+	// If nil, renderers are always executed.
+	Cache cache.Interface
+
+	// TracerProvider is used to create spans around Render() and each renderer.
+	// If nil, a noop provider is used.
+	TracerProvider trace.TracerProvider
 }
 
 // ApplyTo implements the Option interface for Options.
+// Slices are appended to the target's, and non-zero fields override the target's;
+// zero-valued fields leave the target unchanged. See New for the precedence between
+// struct-based and functional options.
 func (opts Options) ApplyTo(target *Options) {
 	target.Renderers = append(target.Renderers, opts.Renderers...)
-	target.Filters = append(target.Filters, opts.Filters...)
-	target.Transformers = append(target.Transformers, opts.Transformers...)
-	target.Parallel = opts.Parallel
+	target.Validators = append(target.Validators, opts.Validators...)
+	target.Hooks = append(target.Hooks, opts.Hooks...)
+
+	for _, f := range opts.Filters {
+		target.Stages = append(target.Stages, types.Stage{Filter: f})
+	}
+
+	for _, t := range opts.Transformers {
+		target.Stages = append(target.Stages, types.Stage{Transformer: t})
+	}
+
+	target.Stages = append(target.Stages, opts.Stages...)
+
+	for name, priority := range opts.RendererPriorities {
+		setRendererPriority(target, name, priority)
+	}
+
+	if opts.Parallel {
+		target.Parallel = true
+	}
+
+	if opts.RendererTimeout != 0 {
+		target.RendererTimeout = opts.RendererTimeout
+	}
+
+	if opts.DefaultNamespace != "" {
+		target.DefaultNamespace = opts.DefaultNamespace
+	}
+
+	if opts.DefaultValues != nil {
+		target.DefaultValues = util.DeepMerge(target.DefaultValues, opts.DefaultValues)
+	}
+
+	if opts.ValuesSchema != nil {
+		target.ValuesSchema = opts.ValuesSchema
+	}
+
+	if opts.Dedupe != DedupeNone {
+		target.Dedupe = opts.Dedupe
+	}
+
+	if opts.FilterMode != "" {
+		target.FilterMode = opts.FilterMode
+	}
+
+	if opts.FilterErrorMode != "" {
+		target.FilterErrorMode = opts.FilterErrorMode
+	}
+
+	if opts.TransformerErrorMode != "" {
+		target.TransformerErrorMode = opts.TransformerErrorMode
+	}
+
+	if opts.KubeVersion != "" {
+		target.KubeVersion = opts.KubeVersion
+	}
+
+	if opts.RequireNonEmpty {
+		target.RequireNonEmpty = true
+	}
+
+	if opts.DisableIdentityCheck {
+		target.DisableIdentityCheck = true
+	}
+
+	if opts.Provenance {
+		target.Provenance = true
+	}
+
+	if opts.StripSourceAnnotations {
+		target.StripSourceAnnotations = true
+	}
+
+	target.StripSourceAnnotationKeys = append(target.StripSourceAnnotationKeys, opts.StripSourceAnnotationKeys...)
 
 	if opts.Values != nil {
 		target.Values = maps.Clone(opts.Values)
 	}
+
+	if opts.DropHook != nil {
+		target.DropHook = opts.DropHook
+	}
+
+	if opts.WarningHandler != nil {
+		target.WarningHandler = opts.WarningHandler
+	}
+
+	if opts.Logger != nil {
+		target.Logger = opts.Logger
+	}
+
+	if opts.TracerProvider != nil {
+		target.TracerProvider = opts.TracerProvider
+	}
+
+	if opts.Cache != nil {
+		target.Cache = opts.Cache
+	}
+
+	if opts.Metrics != nil {
+		target.Metrics = opts.Metrics
+	}
 }
 
 // Option is a generic option for Options.
@@ -77,23 +327,164 @@ func WithRenderer(r types.Renderer) Option {
 	})
 }
 
+// WithRenderers adds several configured renderers to the engine, in order.
+// It is equivalent to calling WithRenderer for each of them, and can be mixed with other
+// functional options; New validates every renderer the same way.
+// Can only be used during engine creation.
+func WithRenderers(rs ...types.Renderer) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Renderers = append(o.Renderers, rs...)
+	})
+}
+
+// WithRendererOptions adds a configured renderer to the engine, like WithRenderer, with filters and
+// transformers applied to the output of that renderer only. They run right after the renderer, in
+// order (filters first), before the objects go through the engine-level and render-time stages and
+// are aggregated with those of other renderers; they behave like the renderer's own filters and
+// transformers (e.g. helm.WithFilter), so provenance and the default namespace are not set yet.
+// Results served from the render cache already went through them, and a streaming renderer keeps
+// streaming, each object going through them as it is received. The Kubernetes version and the render
+// target reach the renderer if it implements types.VersionAwareRenderer or types.TargetedRenderer.
+// Can only be used during engine creation.
+func WithRendererOptions(r types.Renderer, filters []types.Filter, transformers []types.Transformer) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Renderers = append(o.Renderers, scopeRenderer(r, filters, transformers))
+	})
+}
+
+// WithRendererPriority sets the priority of the renderers named name, matched by Renderer.Name().
+// Renderers are sorted by ascending priority when the engine is created, so lower numbers run first;
+// renderers without a priority have priority 0, and renderers of equal priority keep their
+// registration order. With WithParallel, all renderers still run concurrently, and the priority only
+// determines the order in which their objects are concatenated.
+// Can only be used during engine creation.
+func WithRendererPriority(name string, priority int) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		setRendererPriority(o, name, priority)
+	})
+}
+
+// setRendererPriority sets the priority of the named renderers.
+func setRendererPriority(target *Options, name string, priority int) {
+	if target.RendererPriorities == nil {
+		target.RendererPriorities = make(map[string]int)
+	}
+
+	target.RendererPriorities[name] = priority
+}
+
 // WithFilter adds an engine-level filter function to the processing chain.
-// Engine-level filters are applied to aggregated results from all renderers on every Render() call.
+// Engine-level filters are applied to the results of each renderer on every Render() call.
+// It is a shorthand for WithStage(types.Stage{Filter: f}): the filter runs in registration order
+// with the other engine-level filters and transformers. Filters used to run before all engine-level
+// transformers whatever the registration order; a filter added after a transformer now sees the
+// transformed objects.
 // For renderer-specific filtering, use the renderer's WithFilter option (e.g., helm.WithFilter).
 // For one-time filtering on a single Render() call, use WithRenderFilter.
 func WithFilter(f types.Filter) Option {
-	return util.FunctionalOption[Options](func(o *Options) {
-		o.Filters = append(o.Filters, f)
-	})
+	return WithStage(types.Stage{Filter: f})
 }
 
 // WithTransformer adds an engine-level transformer function to the processing chain.
-// Engine-level transformers are applied to aggregated results from all renderers on every Render() call.
+// Engine-level transformers are applied to the results of each renderer on every Render() call.
+// It is a shorthand for WithStage(types.Stage{Transformer: t}): the transformer runs in registration
+// order with the other engine-level filters and transformers. Transformers used to run after all
+// engine-level filters whatever the registration order; a transformer added before a filter now
+// runs first, and sees the objects that filter drops.
 // For renderer-specific transformation, use the renderer's WithTransformer option (e.g., helm.WithTransformer).
 // For one-time transformation on a single Render() call, use WithRenderTransformer.
 func WithTransformer(t types.Transformer) Option {
+	return WithStage(types.Stage{Transformer: t})
+}
+
+// WithStage adds an engine-level filter or transformer to the ordered stages of the pipeline.
+// Engine-level filters and transformers, whether added with WithStage, WithFilter or WithTransformer,
+// form a single list and run in the order they are added, so a transformer can run before a filter,
+// e.g. to set a label that a later filter selects on.
+//
+// The objects of each renderer go through the render target, then the engine-level stages, then the
+// render-time filters and transformers. Stage filters follow WithFilterMode, which combines adjacent
+// ones, and WithFilterErrorMode; stage transformers follow WithTransformerErrorMode. A stage not setting
+// exactly one of Filter and Transformer makes New fail with an error wrapping types.ErrStageInvalid.
+func WithStage(s types.Stage) Option {
 	return util.FunctionalOption[Options](func(o *Options) {
-		o.Transformers = append(o.Transformers, t)
+		o.Stages = append(o.Stages, s)
+	})
+}
+
+// WithValidator adds an engine-level validator function.
+// Validators run after all filters and transformers, on the objects Render() would return.
+// Every object is checked by every validator; if any check fails Render() returns an error
+// joining one validator.Error per failure, each identifying the offending object.
+func WithValidator(v types.Validator) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Validators = append(o.Validators, v)
+	})
+}
+
+// WithFilterMode sets how engine-level filters combine: with FilterModeAll (default), objects must
+// be accepted by every filter; with FilterModeAny, by at least one of the adjacent filters, those not
+// separated by a transformer. Filters separated by a transformer are ANDed whatever the mode, as the
+// objects must get through the first filter to reach the transformer and then the second one; before
+// filters and transformers were interleaved in registration order, FilterModeAny ORed all engine-level
+// filters. Render-time filters follow the same mode unless WithRenderFilterMode
+// overrides it, and form their own group: an object must pass both the engine-level and the render-time
+// filters. Targets set with WithTarget always apply. With FilterModeAny, combined filters are reported
+// to the DropHook and the debug logs as one.
+func WithFilterMode(mode FilterMode) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.FilterMode = mode
+	})
+}
+
+// WithTransformerErrorMode sets how the render handles an engine-level or render-time transformer
+// failing on an object. With ErrorModeFailFast (default), the render fails with the first error.
+// With ErrorModeSkipObject, the object is dropped with a WarningObjectSkipped warning and the render
+// continues, so it returns fewer objects than it would otherwise, and AfterRender hooks and validators
+// never see the skipped ones. With ErrorModeCollect, the render goes on with every object and then
+// fails with all the errors joined, each a transformer.Error identifying its object. Skipped objects
+// are counted in RenderReport.SkippedCount. Renderer-specific transformers always fail the renderer.
+// New fails with an error on an unknown mode.
+func WithTransformerErrorMode(mode ErrorMode) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.TransformerErrorMode = mode
+	})
+}
+
+// WithFilterErrorMode sets how the render handles an engine-level or render-time filter failing on an
+// object, with the same modes as WithTransformerErrorMode: an object a filter fails on is neither
+// kept nor counted as dropped by filters. Filters aborting the render (see filter.Abort) always fail it.
+func WithFilterErrorMode(mode ErrorMode) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.FilterErrorMode = mode
+	})
+}
+
+// WithKubeVersion sets the Kubernetes version of the cluster every render targets, so the output
+// matches what would be deployed there, e.g. Helm's .Capabilities.KubeVersion. The version is a
+// semantic version with an optional "v" prefix and an optional patch: "v1.31.0", "1.31.2" and "v1.31"
+// are valid, and pre-release or build metadata such as "v1.31.0-gke.100" is ignored. New fails with
+// an error wrapping ErrInvalidKubeVersion if it cannot be parsed.
+//
+// The version, normalized to the "v1.31.0" form, is passed to renderers implementing
+// types.VersionAwareRenderer, and set on the context of the render (see types.KubeVersion) so
+// filters, transformers, hooks and validators can adapt to it, e.g. apiversion.Supported.
+// Other renderers ignore it. When unset (default), renders do not target a version.
+func WithKubeVersion(v string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.KubeVersion = v
+	})
+}
+
+// WithHook adds a hook running custom logic around every Render() call.
+// BeforeRender hooks are chained in registration order, each receiving the values returned by the
+// previous one, before any renderer runs; the first one receives a deep copy of the render-time values,
+// so hooks may modify them in place without affecting the caller's map. AfterRender hooks are chained in the same order on the final
+// objects, after all filters and transformers and before validators. An error from any hook aborts
+// the render.
+func WithHook(h types.Hook) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Hooks = append(o.Hooks, h)
 	})
 }
 
@@ -115,9 +506,50 @@ func WithRenderTransformer(t types.Transformer) RenderOption {
 	})
 }
 
+// WithRenderFilterMode sets how the render-time filters of a single Render() call combine,
+// overriding the mode set with WithFilterMode. Engine-level filters keep the engine mode.
+func WithRenderFilterMode(mode FilterMode) RenderOption {
+	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
+		o.FilterMode = mode
+	})
+}
+
+// WithRenderKubeVersion sets the Kubernetes version targeted by a single Render() call, overriding
+// the version set with WithKubeVersion. It accepts the same formats, and the render fails with an
+// error wrapping ErrInvalidKubeVersion if it cannot be parsed.
+func WithRenderKubeVersion(v string) RenderOption {
+	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
+		o.KubeVersion = v
+	})
+}
+
+// WithRenderDeadline bounds a single Render() call to d, across all renderers, filters, transformers,
+// hooks and validators; in parallel mode the deadline is shared by all renderers. A render exceeding
+// it fails with an error wrapping ErrRenderDeadlineExceeded. The context passed to renderers is
+// canceled when the deadline expires, and a renderer ignoring it is abandoned rather than waited for.
+// Unlike WithRendererTimeout, which gives each renderer its own budget, it caps the total duration
+// of the render. Zero (default) means no deadline.
+func WithRenderDeadline(d time.Duration) RenderOption {
+	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
+		o.Deadline = d
+	})
+}
+
+// WithTarget restricts a single Render() call to the objects matching selector, for a partial render.
+// It behaves as a render-time filter, and several targets must all match. In addition, renderers
+// implementing types.TargetedRenderer receive the target and may skip rendering objects that cannot
+// match it; other renderers render everything, and their objects are filtered.
+func WithTarget(selector types.Filter) RenderOption {
+	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
+		o.Targets = append(o.Targets, selector)
+	})
+}
+
 // WithParallel enables or disables parallel execution of renderers.
 // When enabled, all renderers execute concurrently using goroutines.
 // When disabled (default), renderers execute sequentially.
+// Either way, the output order is the same: the objects of each renderer, in the order it returned
+// them, grouped by renderer in execution order, whatever the order in which renderers complete.
 // Parallel execution is beneficial for I/O-bound renderers (Helm OCI fetch, file reads).
 func WithParallel(enabled bool) Option {
 	return util.FunctionalOption[Options](func(o *Options) {
@@ -125,13 +557,240 @@ func WithParallel(enabled bool) Option {
 	})
 }
 
+// WithRendererTimeout bounds the execution of each renderer to d. Each renderer, including each
+// renderer of a parallel render, gets its own budget; a renderer exceeding it fails the render with
+// an error naming the renderer and wrapping ErrRendererTimeout. The context passed to Process is
+// canceled on timeout, and a renderer ignoring it is abandoned rather than waited for.
+// For a types.StreamingRenderer, the budget covers the consumption of the whole stream.
+// Results served from the render cache are not subject to the timeout. Zero (default) means no timeout.
+func WithRendererTimeout(d time.Duration) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.RendererTimeout = d
+	})
+}
+
+// WithDefaultNamespace sets namespace on the objects rendered without one, as kubectl -n would.
+// Objects that already have a namespace and well-known cluster-scoped kinds are left unchanged;
+// unlike a namespace.Set transformer, it only fills gaps. The namespace is set on the output of each
+// renderer, before engine-level and render-time filters and transformers, so they see the defaulted
+// namespace.
+func WithDefaultNamespace(namespace string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.DefaultNamespace = namespace
+	})
+}
+
+// WithDefaultValues sets values applying to every Render() call, such as the cluster name or region.
+// They are deep merged under the render-time values (see WithValuesLayers for the merge semantics), so
+// values set with WithValues, WithValuesLayers or WithValuesFile take precedence at the leaf.
+// Calling WithDefaultValues more than once merges the values; the input map is never modified.
+func WithDefaultValues(values map[string]any) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.DefaultValues = util.DeepMerge(o.DefaultValues, values)
+	})
+}
+
+// WithValuesSchema sets a JSON Schema, in JSON or YAML, that the render-time values must match, like
+// Helm's values.schema.json. The values are validated once per Render() call, after the defaults,
+// values sources and explicit values are merged and BeforeRender hooks ran, and before any renderer
+// runs; per-renderer overrides set with WithRendererValues are not validated. A mismatch fails the
+// render with a ValuesSchemaError listing every violation, which wraps ErrValuesSchema and is never
+// a types.RendererError. The schema uses the OpenAPI dialect of JSON Schema (draft 4) supported by
+// Kubernetes CRDs, without $ref; New fails with an error if it cannot be parsed.
+func WithValuesSchema(schema []byte) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.ValuesSchema = schema
+	})
+}
+
+// WithDedupe sets the strategy applied to objects rendered more than once, i.e. sharing the same
+// GroupVersionKind, namespace and name. It runs on the aggregated objects of all renderers, before
+// AfterRender hooks and validators. With DedupeMerge, duplicates are merged into the position of their
+// first copy and the render fails if they set conflicting values.
+func WithDedupe(strategy DedupeStrategy) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Dedupe = strategy
+	})
+}
+
+// WithRequireNonEmpty enables or disables failing the render when a renderer produces no objects,
+// a common symptom of misconfiguration such as a glob matching no file or a wrong chart path.
+// The check applies to the output of each renderer, including its renderer-specific filters, but
+// before engine-level and render-time filters, so filtering every object out is not an error.
+// Likewise, in a partial render (see WithTarget), renderers implementing types.TargetedRenderer are not
+// checked, as they only produce the objects matching the target.
+// The error names the renderer and wraps ErrRendererEmpty.
+// When disabled (default), empty renderers are accepted.
+func WithRequireNonEmpty(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.RequireNonEmpty = enabled
+	})
+}
+
+// WithIdentityCheck enables or disables failing the render when an engine-level or render-time
+// transformer returns an object without the apiVersion, kind or name of the object it received,
+// which is always a bug, such as returning a zero unstructured.Unstructured. The error is a
+// transformer.Error identifying the object before the faulty transformer and wrapping ErrIdentityLost;
+// its message names the transformer by its index among the engine-level or render-time transformers.
+// Enabled by default.
+func WithIdentityCheck(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.DisableIdentityCheck = !enabled
+	})
+}
+
+// WithProvenance enables or disables provenance stamping.
+// When enabled, the engine annotates every object with the renderer that produced it
+// (types.AnnotationSourceType and types.AnnotationSourceName, see provenance.Stamp) right after
+// the renderer runs, before results are merged, so attribution survives engine-level processing.
+// Annotations already set by the renderer are preserved.
+func WithProvenance(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Provenance = enabled
+	})
+}
+
+// WithStripSourceAnnotations enables or disables removing the source annotations (see SourceAnnotations)
+// from the final objects, for manifests applied without any trace of how they were rendered.
+// Annotations are removed whatever set them, the renderer itself or provenance stamping, after
+// AfterRender hooks and before validators run, so filters relying on them, e.g. managedby.Source,
+// keep working during the render.
+func WithStripSourceAnnotations(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.StripSourceAnnotations = enabled
+	})
+}
+
+// WithStripSourceAnnotationKeys adds annotation keys removed from the final objects along with the
+// source annotations, e.g. those a renderer sets to record its own inputs. It has no effect unless
+// WithStripSourceAnnotations is enabled.
+func WithStripSourceAnnotationKeys(keys ...string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.StripSourceAnnotationKeys = append(o.StripSourceAnnotationKeys, keys...)
+	})
+}
+
+// WithDropHook sets a function called each time an engine-level or render-time filter rejects
+// an object, e.g. to log or meter filtered-out resources. The hook receives the stage of the filter
+// and its index within that stage; it is not called for renderer-specific filters, nor when a
+// filter returns an error. Calls are serialized, even in parallel mode.
+// The total number of dropped objects is also available in RenderReport.DroppedCount.
+func WithDropHook(hook DropHook) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.DropHook = hook
+	})
+}
+
+// WithWarningHandler sets a function receiving the warnings emitted during every render by renderers,
+// filters, transformers, validators and hooks through types.Warn. Warnings never fail the render; they
+// are also collected in RenderReport.Warnings. Calls to handler are serialized, even in parallel mode.
+func WithWarningHandler(handler types.WarningHandler) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.WarningHandler = handler
+	})
+}
+
+// WithLogger sets the logger used to trace the render pipeline.
+// At debug level the engine logs the start and completion of each renderer (with duration),
+// every object dropped by a filter, and every transformer application.
+// Records use the attribute keys "renderer", "gvk", "namespace" and "name"; filter and transformer
+// records also carry "stage" and "index", the index of the filter or transformer among the engine-level
+// or render-time ones, as reported to the DropHook.
+// When unset, records are discarded without being formatted.
+func WithLogger(logger *slog.Logger) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Logger = logger
+	})
+}
+
+// WithTracerProvider sets the OpenTelemetry tracer provider used to trace renders.
+// Each Render() call creates an "engine.Render" span with one "renderer.Process" child span
+// per renderer, tagged with the renderer name and the number of objects it produced.
+// Renderer failures are recorded as span errors.
+// When unset, a noop provider is used.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.TracerProvider = tp
+	})
+}
+
+// WithMetrics sets the Metrics receiving the duration and object count of every render, the duration
+// and outcome of every renderer execution, and the objects dropped by engine-level and render-time
+// filters. Measurements from renderers served by the render cache are reported too.
+// When unset, measurements are discarded.
+func WithMetrics(m Metrics) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Metrics = m
+	})
+}
+
+// WithRenderCache sets the cache used to skip renderers whose inputs did not change.
+// Entries are keyed by the renderer name, its position among the engine's renderers, so renderers
+// sharing a name never share entries, and a SHA-256 hash of the values the renderer
+// receives (after WithValuesLayers and WithRendererValues are applied), so renderers must be
+// pure functions of their configuration and values for caching to be correct.
+// Objects are deep copied when stored and when served, so callers may freely mutate results.
+// Values that cannot be JSON-encoded bypass the cache. See cache.NewLRU for an in-memory implementation.
+func WithRenderCache(c cache.Interface) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Cache = c
+	})
+}
+
 // WithValues adds render-time values for a single Render() call.
 // These values are passed to all renderers and deep merged with Source-level values,
 // with render-time values taking precedence for conflicting keys.
 // Renderers that support dynamic values (Helm, Kustomize, GoTemplate) will use these values.
 // Renderers that don't support values (YAML, Mem) will ignore them.
+// WithValues replaces any previously set render-time values; use WithValuesLayers to merge them.
 func WithValues(values map[string]any) RenderOption {
 	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
 		o.Values = values
 	})
 }
+
+// WithValuesLayers deep merges the given value maps, left to right, into the render-time values
+// for a single Render() call. Later layers take precedence over earlier ones:
+//   - nested maps are merged key by key, so a layer only needs to contain the leaves it overrides
+//   - slices are replaced as a whole, never concatenated, so a layer fully controls any list it sets
+//   - any other value, or a value whose type differs between layers, is replaced
+//
+// Layers are merged on top of values set by previous options (e.g. WithValues), and the input maps
+// are never modified. A typical use is WithValuesLayers(base, environment, service).
+func WithValuesLayers(layers ...map[string]any) RenderOption {
+	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
+		for _, layer := range layers {
+			o.Values = util.DeepMerge(o.Values, layer)
+		}
+	})
+}
+
+// WithValuesFile reads render-time values from the YAML or JSON file at path for a single Render() call.
+// The file is read when the render starts, and the render fails with an error naming the file if it
+// cannot be read or parsed. Values files, including those passed with WithValuesReader, are deep merged
+// in order and form the base of the values set with WithValues or WithValuesLayers, which always take
+// precedence whatever the order of the options.
+func WithValuesFile(path string) RenderOption {
+	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
+		o.ValuesSources = append(o.ValuesSources, valuesFile(path))
+	})
+}
+
+// WithValuesReader reads render-time values from a YAML or JSON document in r for a single Render() call,
+// like WithValuesFile. The reader is consumed by the render, so the option cannot be reused.
+func WithValuesReader(r io.Reader) RenderOption {
+	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
+		o.ValuesSources = append(o.ValuesSources, valuesReader(r))
+	})
+}
+
+// WithRendererValues sets value overrides for the renderers named name for a single Render() call.
+// Renderers are matched by Renderer.Name(); a matching renderer receives the render-time values
+// deep merged with these overrides (see WithValuesLayers for the merge semantics), while other
+// renderers are unaffected. If several renderers share the same name, all of them receive the
+// override. Calling WithRendererValues more than once for the same name merges the overrides.
+func WithRendererValues(name string, values map[string]any) RenderOption {
+	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
+		mergeRendererValues(o, name, values)
+	})
+}
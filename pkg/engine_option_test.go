@@ -0,0 +1,127 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestOptionsPrecedence(t *testing.T) {
+
+	newRenderer := func(name string) *mockRenderer {
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod(name)}, nil)
+		renderer.On("Name").Return(name)
+
+		return renderer
+	}
+
+	// recordingFilter keeps every object and records its name in calls.
+	recordingFilter := func(calls *[]string, name string) types.Filter {
+		return func(_ context.Context, _ unstructured.Unstructured) (bool, error) {
+			*calls = append(*calls, name)
+
+			return true, nil
+		}
+	}
+
+	names := func(objects []unstructured.Unstructured) []string {
+		result := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			result = append(result, obj.GetName())
+		}
+
+		return result
+	}
+
+	t.Run("should support struct-only options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var calls []string
+
+		e, err := engine.New(
+			engine.Options{
+				Renderers:  []types.Renderer{newRenderer("pod1")},
+				Filters:    []types.Filter{recordingFilter(&calls, "first")},
+				Provenance: true,
+			},
+			&engine.Options{
+				Renderers: []types.Renderer{newRenderer("pod2")},
+				Filters:   []types.Filter{recordingFilter(&calls, "second")},
+			},
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"pod1", "pod2"}))
+		g.Expect(calls).Should(Equal([]string{"first", "second", "first", "second"}))
+
+		// the zero-valued Provenance of the second struct does not reset the first one
+		g.Expect(objects[1].GetAnnotations()).Should(HaveKey(types.AnnotationSourceName))
+	})
+
+	t.Run("should support functional-only options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer("pod1")),
+			engine.WithProvenance(true),
+			engine.WithProvenance(false),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects[0].GetAnnotations()).Should(BeEmpty())
+	})
+
+	t.Run("should layer functional options on top of struct options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var calls []string
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer("pod2")),
+			engine.WithFilter(recordingFilter(&calls, "functional")),
+			engine.WithProvenance(false),
+			&engine.Options{
+				Renderers:  []types.Renderer{newRenderer("pod1")},
+				Filters:    []types.Filter{recordingFilter(&calls, "struct")},
+				Provenance: true,
+			},
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		// struct-based renderers and filters come first, whatever the argument order
+		g.Expect(names(objects)).Should(Equal([]string{"pod1", "pod2"}))
+		g.Expect(calls[:2]).Should(Equal([]string{"struct", "functional"}))
+
+		// the functional option overrides the struct-based value
+		g.Expect(objects[0].GetAnnotations()).Should(BeEmpty())
+	})
+
+	t.Run("should ignore a nil struct option", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var options *engine.Options
+
+		e, err := engine.New(options, engine.WithRenderer(newRenderer("pod1")))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+	})
+}
@@ -0,0 +1,106 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithRendererPriority(t *testing.T) {
+
+	newRenderer := func(name string, calls *[]string) *mockRenderer {
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Run(func(_ mock.Arguments) {
+			if calls != nil {
+				*calls = append(*calls, name)
+			}
+		}).Return([]unstructured.Unstructured{makePod(name)}, nil)
+		renderer.On("Name").Return(name)
+
+		return renderer
+	}
+
+	names := func(objects []unstructured.Unstructured) []string {
+		result := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			result = append(result, obj.GetName())
+		}
+
+		return result
+	}
+
+	t.Run("should run renderers by ascending priority", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var calls []string
+
+		e, err := engine.New(
+			engine.WithRenderers(
+				newRenderer("apps", &calls),
+				newRenderer("crds", &calls),
+				newRenderer("namespaces", &calls),
+				newRenderer("monitoring", &calls),
+			),
+			engine.WithRendererPriority("namespaces", -20),
+			engine.WithRendererPriority("crds", -10),
+			engine.WithRendererPriority("monitoring", 10),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(calls).Should(Equal([]string{"namespaces", "crds", "apps", "monitoring"}))
+		g.Expect(names(objects)).Should(Equal(calls))
+		g.Expect(report.Renderers[0].Name).Should(Equal("namespaces"))
+	})
+
+	t.Run("should keep registration order for equal priorities", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderers(newRenderer("b", nil), newRenderer("c", nil), newRenderer("a", nil)),
+			engine.WithRendererPriority("a", 1),
+			engine.WithRendererPriority("b", 1),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"c", "b", "a"}))
+	})
+
+	t.Run("should concatenate parallel results by priority", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderers(newRenderer("apps", nil), newRenderer("namespaces", nil)),
+			engine.WithRendererPriority("namespaces", -1),
+			engine.WithParallel(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"namespaces", "apps"}))
+	})
+
+	t.Run("should support struct-based priorities", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderers(newRenderer("apps", nil), newRenderer("namespaces", nil)),
+			&engine.Options{RendererPriorities: map[string]int{"namespaces": -1}},
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"namespaces", "apps"}))
+	})
+}
@@ -0,0 +1,113 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithProvenance(t *testing.T) {
+
+	t.Run("should stamp objects with the renderer that produced them", func(t *testing.T) {
+		g := NewWithT(t)
+
+		stamped := makePod("pod2")
+		stamped.SetAnnotations(map[string]string{types.AnnotationSourceType: "chart"})
+
+		renderer1 := new(mockRenderer)
+		renderer1.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer1.On("Name").Return("helm")
+
+		renderer2 := new(mockRenderer)
+		renderer2.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{stamped}, nil)
+		renderer2.On("Name").Return("kustomize")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer1),
+			engine.WithRenderer(renderer2),
+			engine.WithProvenance(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+
+		g.Expect(objects[0].GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceType, "helm"))
+		g.Expect(objects[0].GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceName, "helm"))
+
+		// annotations set by the renderer are preserved
+		g.Expect(objects[1].GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceType, "chart"))
+		g.Expect(objects[1].GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceName, "kustomize"))
+
+		// objects retained by the renderer are not modified
+		g.Expect(stamped.GetAnnotations()).ShouldNot(HaveKey(types.AnnotationSourceName))
+	})
+
+	t.Run("should let engine-level transformers target a single renderer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer1 := new(mockRenderer)
+		renderer1.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer1.On("Name").Return("helm")
+
+		renderer2 := new(mockRenderer)
+		renderer2.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod2")}, nil)
+		renderer2.On("Name").Return("kustomize")
+
+		e, err := engine.New(
+			engine.WithRenderers(renderer1, renderer2),
+			engine.WithProvenance(true),
+			engine.WithTransformer(transformer.ForSource("helm", addLabels(map[string]string{"sidecar": "injected"}))),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetLabels()).Should(HaveKeyWithValue("sidecar", "injected"))
+		g.Expect(objects[1].GetLabels()).ShouldNot(HaveKey("sidecar"))
+	})
+
+	t.Run("should not stamp objects by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer.On("Name").Return("helm")
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects[0].GetAnnotations()).Should(BeEmpty())
+	})
+
+	t.Run("should expose the renderer name to Process", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var name string
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			name, _ = types.RendererName(args.Get(0).(context.Context))
+		}).Return([]unstructured.Unstructured{}, nil)
+		renderer.On("Name").Return("helm")
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(name).Should(Equal("helm"))
+	})
+}
@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/k8s-manifest-kit/pkg/util/metrics"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/output/yaml"
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// RenderTo behaves like Render but writes the final objects to w as a multi-document YAML stream
+// instead of returning them.
+//
+// In sequential mode, the objects of each renderer are written as soon as they have gone through
+// the filters, transformers and validators, and those of a types.StreamingRenderer one at a time,
+// so the whole output is never held in memory. Validators therefore run on each object before it is
+// written rather than on the complete set. In parallel mode, objects are written once all renderers
+// have completed, in registration order. The same applies when hooks are registered with WithHook
+// or a dedupe strategy is set with WithDedupe, since both need the complete set of objects.
+//
+// RenderTo returns the first error encountered; the documents written before it are left in w.
+func (e *Engine) RenderTo(ctx context.Context, w io.Writer, opts ...RenderOption) (err error) {
+	enc := yaml.NewEncoder(w)
+
+	if e.options.Parallel || len(e.options.Hooks) > 0 || e.options.Dedupe != DedupeNone {
+		objects, err := e.Render(ctx, opts...)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range objects {
+			if err := enc.Encode(obj); err != nil {
+				return fmt.Errorf("engine output error: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	startTime := time.Now()
+	count := 0
+
+	ctx = types.WithRenderState(ctx)
+	ctx = types.WithWarningHandler(ctx, e.warningHandler(nil))
+	ctx, span := e.tracer.Start(ctx, spanRender)
+
+	defer func() {
+		span.SetAttributes(attrObjectCount.Int(count))
+		endSpan(span, err)
+	}()
+
+	renderOpts, err := e.renderOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := withRenderDeadline(ctx, renderOpts.Deadline)
+	defer cancel()
+
+	defer func() {
+		err = deadlineError(ctx, err)
+	}()
+
+	if renderOpts.KubeVersion != "" {
+		ctx = types.WithKubeVersion(ctx, renderOpts.KubeVersion)
+	}
+
+	if err := e.validateValues(renderOpts.Values); err != nil {
+		return err
+	}
+
+	p := e.newPipeline(ctx, renderOpts)
+	p.emit = func(ctx context.Context, objects []unstructured.Unstructured) error {
+		objects, err := e.stripSourceAnnotations(ctx, objects)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range objects {
+			if err := pipeline.ApplyValidators(ctx, []unstructured.Unstructured{obj}, e.options.Validators); err != nil {
+				return fmt.Errorf("engine validation error: %w", err)
+			}
+
+			if err := enc.Encode(obj); err != nil {
+				return fmt.Errorf("engine output error: %w", err)
+			}
+
+			count++
+		}
+
+		return nil
+	}
+
+	if _, err := e.renderSequential(ctx, renderOpts, p, &RenderReport{}); err != nil {
+		return err
+	}
+
+	if err := p.err(); err != nil {
+		return err
+	}
+
+	duration := time.Since(startTime)
+	metrics.ObserveRender(ctx, duration, count)
+	e.options.Metrics.ObserveRender(duration, count)
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package engine_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRenderTo(t *testing.T) {
+
+	t.Run("should write filtered and transformed objects as YAML", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+			makeService(),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithFilter(podFilter()),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		err = e.RenderTo(t.Context(), &buf, engine.WithRenderTransformer(addLabels(map[string]string{"env": "prod"})))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(ContainSubstring("name: pod1"))
+		g.Expect(buf.String()).Should(ContainSubstring("env: prod"))
+		g.Expect(buf.String()).ShouldNot(ContainSubstring("kind: Service"))
+	})
+
+	t.Run("should separate the objects of all renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer1 := new(mockRenderer)
+		renderer1.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer1.On("Name").Return("renderer1")
+		renderer2 := &streamRenderer{
+			name:    "renderer2",
+			objects: []unstructured.Unstructured{makePod("pod2"), makePod("pod3")},
+		}
+
+		for _, parallel := range []bool{false, true} {
+			e, err := engine.New(
+				engine.WithRenderer(renderer1),
+				engine.WithRenderer(renderer2),
+				engine.WithParallel(parallel),
+			)
+			g.Expect(err).ShouldNot(HaveOccurred())
+
+			var buf bytes.Buffer
+			g.Expect(e.RenderTo(t.Context(), &buf)).Should(Succeed())
+
+			docs := strings.Split(buf.String(), "---\n")
+			g.Expect(docs).Should(HaveLen(3))
+			g.Expect(docs[0]).Should(ContainSubstring("name: pod1"))
+			g.Expect(docs[1]).Should(ContainSubstring("name: pod2"))
+			g.Expect(docs[2]).Should(ContainSubstring("name: pod3"))
+		}
+	})
+
+	t.Run("should keep objects written before an error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transformErr := errors.New("transform failed")
+		renderer := &streamRenderer{
+			name:    "stream",
+			objects: []unstructured.Unstructured{makePod("pod1"), makePod("pod2")},
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithTransformer(func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				if obj.GetName() == "pod2" {
+					return obj, transformErr
+				}
+
+				return obj, nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		err = e.RenderTo(t.Context(), &buf)
+		g.Expect(err).Should(MatchError(transformErr))
+		g.Expect(buf.String()).Should(ContainSubstring("name: pod1"))
+		g.Expect(buf.String()).ShouldNot(ContainSubstring("name: pod2"))
+	})
+
+	t.Run("should validate objects before writing them", func(t *testing.T) {
+		g := NewWithT(t)
+
+		validationErr := errors.New("invalid pod")
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+			makePod("pod2"),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithValidator(func(_ context.Context, obj unstructured.Unstructured) error {
+				if obj.GetName() == "pod2" {
+					return validationErr
+				}
+
+				return nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		err = e.RenderTo(t.Context(), &buf)
+		g.Expect(err).Should(MatchError(validationErr))
+		g.Expect(buf.String()).Should(ContainSubstring("name: pod1"))
+		g.Expect(buf.String()).ShouldNot(ContainSubstring("name: pod2"))
+	})
+}
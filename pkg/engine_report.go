@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// RendererReport holds the execution details of a single renderer during a Render() call.
+type RendererReport struct {
+	// Name is the renderer name as returned by Renderer.Name().
+	Name string
+
+	// Duration is the time spent in the renderer's Process() method, or serving it from the render cache.
+	// For a types.StreamingRenderer, it is the time spent consuming the stream, including the
+	// engine-level and render-time pipeline run on each object as it is received.
+	Duration time.Duration
+
+	// Cached reports whether the objects were served from the render cache without calling Process().
+	Cached bool
+
+	// ObjectCount is the number of objects produced by the renderer (0 if Err is non-nil).
+	ObjectCount int
+
+	// DroppedCount is the number of objects of this renderer discarded by engine-level and
+	// render-time filters (0 if Err is non-nil).
+	DroppedCount int
+
+	// SkippedCount is the number of objects of this renderer dropped because an engine-level or
+	// render-time filter or transformer failed on them, with ErrorModeSkipObject or ErrorModeCollect
+	// (0 if Err is non-nil).
+	SkippedCount int
+
+	// Err is the error returned by the renderer, nil on success.
+	Err error
+}
+
+// RenderReport summarizes the execution of a single Render() call.
+//
+// A report is returned even when rendering fails, so callers can inspect which
+// renderers completed and which one failed.
+type RenderReport struct {
+	// Renderers holds one entry per executed renderer, in registration order.
+	// In sequential mode, renderers after a failing one are not executed and have no entry.
+	Renderers []RendererReport
+
+	// Duration is the total time spent in the Render() call.
+	Duration time.Duration
+
+	// RenderedCount is the total number of objects produced by all renderers, before filtering.
+	RenderedCount int
+
+	// DroppedCount is the number of objects discarded by engine-level and render-time filters.
+	DroppedCount int
+
+	// SkippedCount is the number of objects dropped because a filter or transformer failed on them,
+	// see WithTransformerErrorMode and WithFilterErrorMode.
+	SkippedCount int
+
+	// ObjectCount is the number of objects returned after filtering and transformation.
+	ObjectCount int
+
+	// Warnings holds the warnings emitted during the render (see types.Warn), in emission order.
+	Warnings []types.Warning
+}
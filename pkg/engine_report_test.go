@@ -0,0 +1,111 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRenderWithReport(t *testing.T) {
+
+	t.Run("should report per-renderer counts and totals", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer1 := new(mockRenderer)
+		renderer1.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+			makeService(),
+		}, nil)
+		renderer1.On("Name").Return("renderer1")
+		renderer2 := new(mockRenderer)
+		renderer2.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod2")}, nil)
+		renderer2.On("Name").Return("renderer2")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer1),
+			engine.WithRenderer(renderer2),
+			engine.WithFilter(podFilter()),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+
+		g.Expect(report).ShouldNot(BeNil())
+		g.Expect(report.Renderers).Should(HaveLen(2))
+		g.Expect(report.Renderers[0].Name).Should(Equal("renderer1"))
+		g.Expect(report.Renderers[0].ObjectCount).Should(Equal(2))
+		g.Expect(report.Renderers[0].Err).ShouldNot(HaveOccurred())
+		g.Expect(report.Renderers[1].Name).Should(Equal("renderer2"))
+		g.Expect(report.Renderers[1].ObjectCount).Should(Equal(1))
+		g.Expect(report.RenderedCount).Should(Equal(3))
+		g.Expect(report.DroppedCount).Should(Equal(1))
+		g.Expect(report.ObjectCount).Should(Equal(2))
+		g.Expect(report.Duration).Should(BeNumerically(">", 0))
+	})
+
+	t.Run("should populate report when a renderer fails sequentially", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer1 := new(mockRenderer)
+		renderer1.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer1.On("Name").Return("renderer1")
+		renderer2 := new(mockRenderer)
+		renderer2.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{}, errors.New("renderer2 failed"))
+		renderer2.On("Name").Return("renderer2")
+		renderer3 := new(mockRenderer)
+		renderer3.On("Name").Return("renderer3")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer1),
+			engine.WithRenderer(renderer2),
+			engine.WithRenderer(renderer3),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).Should(HaveOccurred())
+		g.Expect(objects).Should(BeNil())
+
+		g.Expect(report).ShouldNot(BeNil())
+		g.Expect(report.Renderers).Should(HaveLen(2))
+		g.Expect(report.Renderers[0].Err).ShouldNot(HaveOccurred())
+		g.Expect(report.Renderers[0].ObjectCount).Should(Equal(1))
+		g.Expect(report.Renderers[1].Name).Should(Equal("renderer2"))
+		g.Expect(report.Renderers[1].Err).Should(MatchError("renderer2 failed"))
+		g.Expect(report.Renderers[1].ObjectCount).Should(Equal(0))
+		renderer3.AssertNotCalled(t, "Process", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should populate report when a renderer fails in parallel", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer1 := new(mockRenderer)
+		renderer1.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer1.On("Name").Return("renderer1")
+		renderer2 := new(mockRenderer)
+		renderer2.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{}, errors.New("renderer2 failed"))
+		renderer2.On("Name").Return("renderer2")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer1),
+			engine.WithRenderer(renderer2),
+			engine.WithParallel(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).Should(HaveOccurred())
+
+		g.Expect(report.Renderers).Should(HaveLen(2))
+		g.Expect(report.Renderers[0].Name).Should(Equal("renderer1"))
+		g.Expect(report.Renderers[0].ObjectCount).Should(Equal(1))
+		g.Expect(report.Renderers[1].Name).Should(Equal("renderer2"))
+		g.Expect(report.Renderers[1].Err).Should(HaveOccurred())
+	})
+}
@@ -0,0 +1,182 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// scopedRenderer applies renderer-local filters and transformers to the output of a renderer.
+type scopedRenderer struct {
+	renderer     types.Renderer
+	filters      []types.Filter
+	transformers []types.Transformer
+}
+
+// scopeRenderer returns r wrapped so that filters and transformers apply to its output.
+// Streaming renderers keep streaming, with the stages applied to each object as it is received.
+func scopeRenderer(r types.Renderer, filters []types.Filter, transformers []types.Transformer) types.Renderer {
+	if r == nil || (len(filters) == 0 && len(transformers) == 0) {
+		return r
+	}
+
+	scoped := &scopedRenderer{
+		renderer:     r,
+		filters:      filters,
+		transformers: transformers,
+	}
+
+	if stream, ok := r.(types.StreamingRenderer); ok {
+		return &scopedStreamingRenderer{scopedRenderer: scoped, stream: stream}
+	}
+
+	return scoped
+}
+
+// Name implements types.Renderer.
+func (r *scopedRenderer) Name() string {
+	return r.renderer.Name()
+}
+
+// Process implements types.Renderer.
+func (r *scopedRenderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	objects, err := r.renderer.Process(ctx, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.apply(ctx, objects)
+}
+
+// ProcessVersion implements types.VersionAwareRenderer, falling back to Process if the scoped
+// renderer does not implement it.
+func (r *scopedRenderer) ProcessVersion(
+	ctx context.Context,
+	values map[string]any,
+	version string,
+) ([]unstructured.Unstructured, error) {
+	vr, ok := r.renderer.(types.VersionAwareRenderer)
+	if !ok {
+		return r.Process(ctx, values)
+	}
+
+	objects, err := vr.ProcessVersion(ctx, values, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.apply(ctx, objects)
+}
+
+// ProcessTarget implements types.TargetedRenderer, falling back to Process if the scoped renderer
+// does not implement it.
+func (r *scopedRenderer) ProcessTarget(
+	ctx context.Context,
+	values map[string]any,
+	target types.Filter,
+) ([]unstructured.Unstructured, error) {
+	tr, ok := r.renderer.(types.TargetedRenderer)
+	if !ok {
+		return r.Process(ctx, values)
+	}
+
+	objects, err := tr.ProcessTarget(ctx, values, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.apply(ctx, objects)
+}
+
+// Unwrap returns the scoped renderer, see types.Implements.
+func (r *scopedRenderer) Unwrap() types.Renderer {
+	return r.renderer
+}
+
+// apply runs the renderer-local filters and transformers on objects.
+func (r *scopedRenderer) apply(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+) ([]unstructured.Unstructured, error) {
+	objects, err := pipeline.Apply(ctx, objects, r.filters, r.transformers)
+	if err != nil {
+		return nil, fmt.Errorf("renderer pipeline error: %w", err)
+	}
+
+	return objects, nil
+}
+
+// scopedStreamingRenderer is a scopedRenderer for a types.StreamingRenderer.
+type scopedStreamingRenderer struct {
+	*scopedRenderer
+
+	stream types.StreamingRenderer
+}
+
+// ProcessStream implements types.StreamingRenderer.
+func (r *scopedStreamingRenderer) ProcessStream(
+	ctx context.Context,
+	values map[string]any,
+) (<-chan unstructured.Unstructured, <-chan error) {
+	out := make(chan unstructured.Unstructured)
+	outErrs := make(chan error, 1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	in, inErrs := r.stream.ProcessStream(ctx, values)
+
+	go func() {
+		defer close(outErrs)
+		defer close(out)
+		defer cancel()
+
+		// Objects and errors are received together, as the inner renderer may block sending an
+		// error on an unbuffered channel while its object stream is still open.
+		for in != nil || inErrs != nil {
+			select {
+			case <-ctx.Done():
+				return
+
+			case err, ok := <-inErrs:
+				if !ok {
+					inErrs = nil
+
+					continue
+				}
+
+				if err != nil {
+					outErrs <- err
+
+					return
+				}
+
+			case obj, ok := <-in:
+				if !ok {
+					in = nil
+
+					continue
+				}
+
+				objects, err := r.apply(ctx, []unstructured.Unstructured{obj})
+				if err != nil {
+					outErrs <- err
+
+					return
+				}
+
+				for _, o := range objects {
+					select {
+					case out <- o:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return out, outErrs
+}
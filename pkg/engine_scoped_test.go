@@ -0,0 +1,244 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+// failingStreamRenderer streams one object, then fails on an unbuffered error channel while its
+// object stream is still open.
+type failingStreamRenderer struct {
+	err error
+}
+
+func (r *failingStreamRenderer) Name() string {
+	return "failing-stream"
+}
+
+func (r *failingStreamRenderer) Process(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	panic("Process must not be called on a streaming renderer")
+}
+
+func (r *failingStreamRenderer) ProcessStream(
+	ctx context.Context,
+	_ map[string]any,
+) (<-chan unstructured.Unstructured, <-chan error) {
+	objects := make(chan unstructured.Unstructured)
+	errs := make(chan error)
+
+	go func() {
+		defer close(errs)
+		defer close(objects)
+
+		select {
+		case objects <- makePod("pod1"):
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case errs <- r.err:
+		case <-ctx.Done():
+		}
+	}()
+
+	return objects, errs
+}
+
+func TestWithRendererOptions(t *testing.T) {
+
+	newRenderer := func(name string, objects ...unstructured.Unstructured) *mockRenderer {
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return(objects, nil)
+		renderer.On("Name").Return(name)
+
+		return renderer
+	}
+
+	t.Run("should apply the stages to the renderer output only", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRendererOptions(
+				newRenderer("scoped", makePod("pod1"), makeService()),
+				[]types.Filter{podFilter()},
+				[]types.Transformer{addLabels(map[string]string{"scope": "local"})},
+			),
+			engine.WithRenderer(newRenderer("other", makePod("pod2"), makeService())),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(3))
+
+		g.Expect(objects[0].GetName()).Should(Equal("pod1"))
+		g.Expect(objects[0].GetLabels()).Should(HaveKeyWithValue("scope", "local"))
+		g.Expect(objects[1].GetName()).Should(Equal("pod2"))
+		g.Expect(objects[1].GetLabels()).ShouldNot(HaveKey("scope"))
+		g.Expect(objects[2].GetKind()).Should(Equal("Service"))
+	})
+
+	t.Run("should run renderer-local stages before engine-level ones", func(t *testing.T) {
+		g := NewWithT(t)
+
+		copyScope := func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+			labels := obj.GetLabels()
+			labels["seen"] = labels["scope"]
+			obj.SetLabels(labels)
+
+			return obj, nil
+		}
+
+		e, err := engine.New(
+			engine.WithRendererOptions(
+				newRenderer("scoped", makePod("pod1")),
+				nil,
+				[]types.Transformer{addLabels(map[string]string{"scope": "local"})},
+			),
+			engine.WithTransformer(copyScope),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects[0].GetLabels()).Should(HaveKeyWithValue("seen", "local"))
+	})
+
+	t.Run("should keep streaming renderers streaming", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &streamRenderer{
+			name:    "stream",
+			objects: []unstructured.Unstructured{makePod("pod1"), makeService(), makePod("pod2")},
+		}
+
+		e, err := engine.New(engine.WithRendererOptions(renderer, []types.Filter{podFilter()}, nil))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(renderer.sent.Load()).Should(BeEquivalentTo(3))
+	})
+
+	t.Run("should forward the error of a streaming renderer failing mid-stream", func(t *testing.T) {
+		g := NewWithT(t)
+
+		errStream := errors.New("stream failed")
+
+		e, err := engine.New(engine.WithRendererOptions(
+			&failingStreamRenderer{err: errStream},
+			[]types.Filter{podFilter()},
+			nil,
+		))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+		defer cancel()
+
+		_, err = e.Render(ctx)
+		g.Expect(err).Should(MatchError(errStream))
+		g.Expect(ctx.Err()).ShouldNot(HaveOccurred())
+	})
+
+	t.Run("should report stage errors as errors of the renderer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		failing := func(_ context.Context, _ unstructured.Unstructured) (unstructured.Unstructured, error) {
+			return unstructured.Unstructured{}, errors.New("local transformer failed")
+		}
+
+		e, err := engine.New(engine.WithRendererOptions(
+			newRenderer("scoped", makePod("pod1")),
+			nil,
+			[]types.Transformer{failing},
+		))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(MatchError(ContainSubstring("local transformer failed")))
+
+		var rendererErr *types.RendererError
+		g.Expect(errors.As(err, &rendererErr)).Should(BeTrue())
+		g.Expect(rendererErr.Name).Should(Equal("scoped"))
+	})
+
+	t.Run("should forward the Kubernetes version to version-aware renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &versionRenderer{}
+
+		e, err := engine.New(
+			engine.WithRendererOptions(renderer, []types.Filter{podFilter()}, nil),
+			engine.WithKubeVersion("1.31"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(renderer.versions).Should(Equal([]string{"v1.31.0"}))
+
+		plan, err := e.Explain(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(plan.Renderers[0].VersionAware).Should(BeTrue())
+	})
+
+	t.Run("should forward the render target to targeted renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &targetRenderer{objects: []unstructured.Unstructured{makePod("web"), makeService()}}
+
+		e, err := engine.New(engine.WithRendererOptions(
+			renderer,
+			nil,
+			[]types.Transformer{addLabels(map[string]string{"scope": "local"})},
+		))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(), engine.WithTarget(podFilter()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetLabels()).Should(HaveKeyWithValue("scope", "local"))
+		g.Expect(renderer.targeted).Should(Equal(1))
+		g.Expect(renderer.full).Should(BeZero())
+	})
+
+	t.Run("should not report capabilities the scoped renderer lacks", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRendererOptions(newRenderer("scoped", makeService()), []types.Filter{podFilter()}, nil),
+			engine.WithRequireNonEmpty(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		plan, err := e.Explain(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(plan.Renderers[0].VersionAware).Should(BeFalse())
+		g.Expect(plan.Renderers[0].Streaming).Should(BeFalse())
+
+		// The renderer is not targeted, so an empty output still fails the render.
+		_, err = e.Render(t.Context(), engine.WithTarget(podFilter()))
+		g.Expect(err).Should(MatchError(engine.ErrRendererEmpty))
+	})
+
+	t.Run("should reject a nil renderer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(engine.WithRendererOptions(nil, []types.Filter{podFilter()}, nil))
+		g.Expect(err).Should(MatchError(types.ErrRendererNil))
+	})
+}
@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"context"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/meta/annotations"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// SourceAnnotations returns the annotation keys removed from the final objects by
+// WithStripSourceAnnotations, in addition to those set with WithStripSourceAnnotationKeys.
+// types.AnnotationSourceComments is not included: the YAML writer consumes and removes it.
+func SourceAnnotations() []string {
+	return []string{
+		types.AnnotationSourceType,
+		types.AnnotationSourcePath,
+		types.AnnotationSourceFile,
+		types.AnnotationSourceName,
+	}
+}
+
+// stripSourceAnnotations removes the source annotations from objects, if enabled.
+func (e *Engine) stripSourceAnnotations(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+) ([]unstructured.Unstructured, error) {
+	if !e.options.StripSourceAnnotations {
+		return objects, nil
+	}
+
+	keys := slices.Concat(SourceAnnotations(), e.options.StripSourceAnnotationKeys)
+
+	return pipeline.ApplyTransformers(ctx, objects, []types.Transformer{annotations.Remove(keys...)})
+}
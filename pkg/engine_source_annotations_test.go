@@ -0,0 +1,110 @@
+package engine_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/filter/managedby"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithStripSourceAnnotations(t *testing.T) {
+
+	stampedPod := func(name string) unstructured.Unstructured {
+		pod := makePod(name)
+		pod.SetAnnotations(map[string]string{
+			types.AnnotationSourceType: "helm",
+			types.AnnotationSourcePath: "oci://registry/chart",
+			types.AnnotationSourceFile: "templates/pod.yaml",
+			"example.com/chart":        "chart-1.0.0",
+			"example.com/owner":        "team-a",
+		})
+
+		return pod
+	}
+
+	newRenderer := func(objects ...unstructured.Unstructured) *mockRenderer {
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return(objects, nil)
+		renderer.On("Name").Return("helm")
+
+		return renderer
+	}
+
+	t.Run("should remove source annotations set by the renderer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pod := stampedPod("pod1")
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer(pod)),
+			engine.WithStripSourceAnnotations(true),
+			engine.WithStripSourceAnnotationKeys("example.com/chart"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetAnnotations()).Should(Equal(map[string]string{"example.com/owner": "team-a"}))
+
+		// objects retained by the renderer are not modified
+		g.Expect(pod.GetAnnotations()).Should(HaveKey(types.AnnotationSourceType))
+	})
+
+	t.Run("should remove provenance after filters relying on it ran", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer(makePod("pod1"))),
+			engine.WithProvenance(true),
+			engine.WithFilter(managedby.Source("helm")),
+			engine.WithStripSourceAnnotations(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetAnnotations()).Should(BeEmpty())
+	})
+
+	t.Run("should remove source annotations from RenderTo output", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer(stampedPod("pod1"))),
+			engine.WithStripSourceAnnotations(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		var buf bytes.Buffer
+
+		err = e.RenderTo(t.Context(), &buf)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).ShouldNot(ContainSubstring("manifests.k8s-manifests-lib/source"))
+		g.Expect(buf.String()).Should(ContainSubstring("example.com/owner"))
+	})
+
+	t.Run("should keep source annotations by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer(stampedPod("pod1"))),
+			engine.WithStripSourceAnnotationKeys("example.com/chart"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects[0].GetAnnotations()).Should(HaveKey(types.AnnotationSourceType))
+		g.Expect(objects[0].GetAnnotations()).Should(HaveKey("example.com/chart"))
+	})
+}
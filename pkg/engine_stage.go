@@ -0,0 +1,188 @@
+package engine
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// phase is a run of consecutive filters, or of consecutive transformers, of the render pipeline.
+// The filters of a phase are applied together, so each object goes through all of them in turn.
+type phase struct {
+	filters      []types.Filter
+	transformers []types.Transformer
+}
+
+// phases returns the phases a render with renderOpts runs on the objects of each renderer: the render
+// target first, as it selects objects as renderers produce them, then the engine-level stages in
+// registration order, then the render-time filters and transformers. Filters are wrapped with the
+// drop hook and debug logging, transformers with the identity check and debug logging, each knowing its
+// index among the engine-level or the render-time filters or transformers.
+func (e *Engine) phases(ctx context.Context, renderOpts RenderOptions) []phase {
+	// Modes are checked when the render options are resolved.
+	engineMode, renderMode, _ := e.filterModes(renderOpts)
+
+	b := phaseBuilder{
+		hook:          e.dropHook(),
+		identityCheck: !e.options.DisableIdentityCheck,
+	}
+
+	// Filters and transformers are not wrapped when debug logging is disabled, so there is no overhead.
+	if e.options.Logger.Enabled(ctx, slog.LevelDebug) {
+		b.logger = e.options.Logger
+	}
+
+	renderFilters := combineFilters(renderMode, renderOpts.Filters)
+
+	if t := target(renderOpts.Targets); t != nil {
+		b.addFilters(FilterStageRender, len(renderFilters), []types.Filter{t})
+	}
+
+	var pending []types.Filter
+
+	filterIndex, transformerIndex := 0, 0
+	flush := func() {
+		b.addFilters(FilterStageEngine, filterIndex, combineFilters(engineMode, pending))
+		filterIndex += len(pending)
+		pending = nil
+	}
+
+	for _, stage := range e.options.Stages {
+		if stage.Filter != nil {
+			pending = append(pending, stage.Filter)
+
+			continue
+		}
+
+		flush()
+		b.addTransformers(FilterStageEngine, transformerIndex, []types.Transformer{stage.Transformer})
+		transformerIndex++
+	}
+
+	flush()
+
+	b.addFilters(FilterStageRender, 0, renderFilters)
+	b.addTransformers(FilterStageRender, 0, renderOpts.Transformers)
+
+	return b.phases
+}
+
+// phaseBuilder groups filters and transformers into phases.
+type phaseBuilder struct {
+	hook          DropHook
+	identityCheck bool
+	logger        *slog.Logger // nil when debug logging is disabled
+	phases        []phase
+}
+
+// addFilters adds filters, the filters at index and after within stage, to the last phase if it is
+// a filter phase, and to a new phase otherwise.
+func (b *phaseBuilder) addFilters(stage FilterStage, index int, filters []types.Filter) {
+	if len(filters) == 0 {
+		return
+	}
+
+	if b.hook != nil || b.logger != nil {
+		wrapped := make([]types.Filter, len(filters))
+		for i, f := range filters {
+			if b.hook != nil {
+				f = dropHookFilter(b.hook, stage, index+i, f)
+			}
+
+			if b.logger != nil {
+				f = loggingFilter(b.logger, stage, index+i, f)
+			}
+
+			wrapped[i] = f
+		}
+
+		filters = wrapped
+	}
+
+	if n := len(b.phases); n > 0 && len(b.phases[n-1].filters) > 0 {
+		b.phases[n-1].filters = append(b.phases[n-1].filters, filters...)
+
+		return
+	}
+
+	b.phases = append(b.phases, phase{filters: slices.Clone(filters)})
+}
+
+// addTransformers adds transformers, the transformers at index and after within stage, to the last
+// phase if it is a transformer phase, and to a new phase otherwise.
+func (b *phaseBuilder) addTransformers(stage FilterStage, index int, transformers []types.Transformer) {
+	if len(transformers) == 0 {
+		return
+	}
+
+	if b.identityCheck || b.logger != nil {
+		wrapped := make([]types.Transformer, len(transformers))
+		for i, t := range transformers {
+			if b.identityCheck {
+				t = identityCheckTransformer(stage, index+i, t)
+			}
+
+			if b.logger != nil {
+				t = loggingTransformer(b.logger, stage, index+i, t)
+			}
+
+			wrapped[i] = t
+		}
+
+		transformers = wrapped
+	}
+
+	if n := len(b.phases); n > 0 && len(b.phases[n-1].transformers) > 0 {
+		b.phases[n-1].transformers = append(b.phases[n-1].transformers, transformers...)
+
+		return
+	}
+
+	b.phases = append(b.phases, phase{transformers: slices.Clone(transformers)})
+}
+
+// applyPhases runs objects through the phases, in order, and adds the number of objects dropped by
+// filters, or skipped because of an error, to rr. The caller must hold p.mu.
+func (p *renderPipeline) applyPhases(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+	rr *RendererReport,
+) ([]unstructured.Unstructured, error) {
+	for _, ph := range p.phases {
+		if len(ph.filters) > 0 {
+			filtered, skipped, err := p.isolate(ctx, objects, p.filterErrorMode, "filter",
+				func(ctx context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+					return pipeline.ApplyFilters(ctx, objects, ph.filters)
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			rr.DroppedCount += len(objects) - len(filtered) - skipped
+			rr.SkippedCount += skipped
+			objects = filtered
+
+			continue
+		}
+
+		transformed, skipped, err := p.isolate(ctx, objects, p.transformerErrorMode, "transformer",
+			func(ctx context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+				return pipeline.ApplyTransformers(ctx, objects, ph.transformers)
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		rr.SkippedCount += skipped
+		objects = transformed
+	}
+
+	return objects, nil
+}
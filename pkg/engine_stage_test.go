@@ -0,0 +1,202 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithStage(t *testing.T) {
+
+	newRenderer := func(objects ...unstructured.Unstructured) *mockRenderer {
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return(objects, nil)
+		renderer.On("Name").Return("mock")
+
+		return renderer
+	}
+
+	// hasLabel keeps the objects labelled key=value.
+	hasLabel := func(key string, value string) types.Filter {
+		return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+			return obj.GetLabels()[key] == value, nil
+		}
+	}
+
+	// tierByName labels the object named "web" as tier=frontend.
+	tierByName := func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if obj.GetName() == "web" {
+			obj.SetLabels(map[string]string{"tier": "frontend"})
+		}
+
+		return obj, nil
+	}
+
+	t.Run("should filter on the output of an earlier transformer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer(makePod("web"), makePod("db"))),
+			engine.WithStage(types.Stage{Transformer: tierByName}),
+			engine.WithStage(types.Stage{Filter: hasLabel("tier", "frontend")}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("web"))
+		g.Expect(report.DroppedCount).Should(Equal(1))
+	})
+
+	t.Run("should run filters and transformers in registration order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer(makePod("web"), makePod("db"))),
+			engine.WithTransformer(tierByName),
+			engine.WithFilter(hasLabel("tier", "frontend")),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("web"))
+	})
+
+	t.Run("should interleave stages with filters and transformers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var steps []string
+
+		step := func(name string) types.Transformer {
+			return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				steps = append(steps, name)
+
+				return obj, nil
+			}
+		}
+
+		check := func(name string) types.Filter {
+			return func(_ context.Context, _ unstructured.Unstructured) (bool, error) {
+				steps = append(steps, name)
+
+				return true, nil
+			}
+		}
+
+		e, err := engine.New(
+			engine.Options{
+				Filters: []types.Filter{check("struct-filter")},
+			},
+			engine.WithRenderer(newRenderer(makePod("web"))),
+			engine.WithStage(types.Stage{Transformer: step("stage-1")}),
+			engine.WithFilter(check("filter-1")),
+			engine.WithStage(types.Stage{Filter: check("stage-2")}),
+			engine.WithTransformer(step("transformer")),
+			engine.WithStage(types.Stage{Transformer: step("stage-3")}),
+			engine.WithFilter(check("filter-2")),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(),
+			engine.WithRenderTransformer(step("render-transformer")),
+			engine.WithRenderFilter(check("render-filter")),
+			engine.WithTarget(check("target")),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(steps).Should(Equal([]string{
+			"target",
+			"struct-filter",
+			"stage-1",
+			"filter-1",
+			"stage-2",
+			"transformer",
+			"stage-3",
+			"filter-2",
+			"render-filter",
+			"render-transformer",
+		}))
+	})
+
+	t.Run("should report stage filter drops to the drop hook", func(t *testing.T) {
+		g := NewWithT(t)
+
+		type drop struct {
+			name  string
+			stage engine.FilterStage
+			index int
+		}
+
+		var drops []drop
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer(makePod("web"), makePod("db"))),
+			engine.WithStage(types.Stage{Transformer: tierByName}),
+			engine.WithStage(types.Stage{Filter: hasLabel("tier", "frontend")}),
+			engine.WithDropHook(func(_ context.Context, obj unstructured.Unstructured, stage engine.FilterStage, i int) {
+				drops = append(drops, drop{name: obj.GetName(), stage: stage, index: i})
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(drops).Should(Equal([]drop{{name: "db", stage: engine.FilterStageEngine, index: 0}}))
+	})
+
+	t.Run("should follow the error modes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		errBroken := errors.New("broken")
+
+		failOnDB := func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+			if obj.GetName() == "db" {
+				return obj, errBroken
+			}
+
+			return obj, nil
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer(makePod("web"), makePod("db"))),
+			engine.WithStage(types.Stage{Transformer: failOnDB}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(MatchError(errBroken))
+
+		e, err = engine.New(
+			engine.WithRenderer(newRenderer(makePod("web"), makePod("db"))),
+			engine.WithStage(types.Stage{Transformer: failOnDB}),
+			engine.WithTransformerErrorMode(engine.ErrorModeSkipObject),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(report.SkippedCount).Should(Equal(1))
+	})
+
+	t.Run("should reject invalid stages", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(engine.WithStage(types.Stage{}))
+		g.Expect(err).Should(MatchError(types.ErrStageInvalid))
+
+		_, err = engine.New(engine.WithStage(types.Stage{Filter: podFilter(), Transformer: tierByName}))
+		g.Expect(err).Should(MatchError(types.ErrStageInvalid))
+	})
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// processStream consumes the objects of a streaming renderer, running each one through the render
+// pipeline as soon as it is received. Streamed results bypass the render cache.
+// Renderer errors are recorded in rr.Err, pipeline errors are returned; in both cases the renderer
+// is canceled before returning.
+func (e *Engine) processStream(
+	ctx context.Context,
+	renderer types.StreamingRenderer,
+	values map[string]any,
+	p *renderPipeline,
+	rr *RendererReport,
+) ([]unstructured.Unstructured, error) {
+	startTime := time.Now()
+
+	defer func() {
+		rr.Duration = time.Since(startTime)
+	}()
+
+	ctx, cancel := e.withRendererTimeout(ctx)
+	defer cancel()
+
+	stream, errs := renderer.ProcessStream(ctx, values)
+
+	output := e.outputTransformers()
+
+	result := make([]unstructured.Unstructured, 0)
+
+	for stream != nil || errs != nil {
+		select {
+		case <-ctx.Done():
+			rr.Err = fmt.Errorf("stream canceled: %w", context.Cause(ctx))
+
+			return nil, rr.Err
+
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+
+				continue
+			}
+
+			if err != nil {
+				// A renderer reporting the expiry of its timeout gets the engine's timeout error.
+				if cause := context.Cause(ctx); errors.Is(cause, ErrRendererTimeout) {
+					err = cause
+				}
+
+				rr.Err = err
+
+				return nil, err
+			}
+
+		case obj, ok := <-stream:
+			if !ok {
+				stream = nil
+
+				continue
+			}
+
+			rr.ObjectCount++
+
+			objects, err := pipeline.ApplyTransformers(ctx, []unstructured.Unstructured{obj}, output)
+			if err != nil {
+				rr.Err = err
+
+				return nil, err
+			}
+
+			objects, err = p.apply(ctx, objects, rr)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, objects...)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,195 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+// streamRenderer emits objects one at a time, optionally followed by an error.
+type streamRenderer struct {
+	name    string
+	objects []unstructured.Unstructured
+	err     error
+
+	// sent is the number of objects delivered to the engine.
+	sent atomic.Int32
+	// canceled reports whether the renderer observed the cancellation of its context.
+	canceled atomic.Bool
+}
+
+func (r *streamRenderer) Name() string {
+	return r.name
+}
+
+func (r *streamRenderer) Process(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	panic("Process must not be called on a streaming renderer")
+}
+
+func (r *streamRenderer) ProcessStream(
+	ctx context.Context,
+	_ map[string]any,
+) (<-chan unstructured.Unstructured, <-chan error) {
+	objects := make(chan unstructured.Unstructured)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(objects)
+
+		for _, obj := range r.objects {
+			select {
+			case objects <- obj:
+				r.sent.Add(1)
+			case <-ctx.Done():
+				r.canceled.Store(true)
+
+				return
+			}
+		}
+
+		if r.err != nil {
+			errs <- r.err
+		}
+	}()
+
+	return objects, errs
+}
+
+func TestStreamingRenderer(t *testing.T) {
+
+	t.Run("should filter and transform streamed objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &streamRenderer{
+			name:    "stream",
+			objects: []unstructured.Unstructured{makePod("pod1"), makeService(), makePod("pod2")},
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithFilter(podFilter()),
+			engine.WithTransformer(addLabels(map[string]string{"env": "prod"})),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetName()).Should(Equal("pod1"))
+		g.Expect(objects[1].GetName()).Should(Equal("pod2"))
+		g.Expect(objects[1].GetLabels()).Should(HaveKeyWithValue("env", "prod"))
+
+		g.Expect(report.Renderers).Should(HaveLen(1))
+		g.Expect(report.Renderers[0].ObjectCount).Should(Equal(3))
+		g.Expect(report.Renderers[0].DroppedCount).Should(Equal(1))
+		g.Expect(report.Renderers[0].Cached).Should(BeFalse())
+		g.Expect(report.RenderedCount).Should(Equal(3))
+		g.Expect(report.DroppedCount).Should(Equal(1))
+		g.Expect(report.ObjectCount).Should(Equal(2))
+	})
+
+	t.Run("should report a stream error as a renderer error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		streamErr := errors.New("stream failed")
+		renderer := &streamRenderer{
+			name:    "stream",
+			objects: []unstructured.Unstructured{makePod("pod1")},
+			err:     streamErr,
+		}
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).Should(MatchError(streamErr))
+		g.Expect(err).Should(MatchError(ContainSubstring(`error processing renderer "stream"`)))
+		g.Expect(objects).Should(BeNil())
+		g.Expect(report.Renderers[0].Err).Should(MatchError(streamErr))
+		g.Expect(report.Renderers[0].ObjectCount).Should(Equal(0))
+	})
+
+	t.Run("should cancel the stream when the pipeline fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		filterErr := errors.New("filter failed")
+		renderer := &streamRenderer{
+			name:    "stream",
+			objects: []unstructured.Unstructured{makePod("pod1"), makePod("pod2"), makePod("pod3")},
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithFilter(func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				if obj.GetName() == "pod1" {
+					return false, filterErr
+				}
+
+				return true, nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).Should(MatchError(filterErr))
+		g.Expect(report.Renderers[0].Err).ShouldNot(HaveOccurred())
+
+		g.Eventually(func() bool { return renderer.canceled.Load() }).Should(BeTrue())
+		g.Expect(renderer.sent.Load()).Should(Equal(int32(1)))
+	})
+
+	t.Run("should stamp streamed objects with provenance", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &streamRenderer{
+			name:    "stream",
+			objects: []unstructured.Unstructured{makePod("pod1")},
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithProvenance(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects[0].GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceName, "stream"))
+	})
+
+	t.Run("should keep renderer order in parallel mode", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer1 := &streamRenderer{
+			name:    "stream1",
+			objects: []unstructured.Unstructured{makePod("pod1"), makePod("pod2")},
+		}
+		renderer2 := &streamRenderer{
+			name:    "stream2",
+			objects: []unstructured.Unstructured{makePod("pod3")},
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer1),
+			engine.WithRenderer(renderer2),
+			engine.WithParallel(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(3))
+		g.Expect(objects[0].GetName()).Should(Equal("pod1"))
+		g.Expect(objects[1].GetName()).Should(Equal("pod2"))
+		g.Expect(objects[2].GetName()).Should(Equal("pod3"))
+	})
+}
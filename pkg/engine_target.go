@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// targetedRenderer adapts a types.TargetedRenderer to a partial render, calling ProcessTarget with
+// the render target instead of Process.
+type targetedRenderer struct {
+	types.TargetedRenderer
+
+	target types.Filter
+}
+
+func (r targetedRenderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	return r.ProcessTarget(ctx, values, r.target)
+}
+
+// target combines the targets of a render into one filter, nil if there are none.
+func target(targets []types.Filter) types.Filter {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	return filter.And(targets...)
+}
+
+// isTargeted reports whether renderer is given target in place of rendering everything, i.e. a target
+// is set and renderer implements types.TargetedRenderer without streaming, so it may produce no objects.
+func isTargeted(renderer types.Renderer, target types.Filter) bool {
+	if target == nil {
+		return false
+	}
+
+	if _, ok := renderer.(types.StreamingRenderer); ok {
+		return false
+	}
+
+	return types.Implements[types.TargetedRenderer](renderer)
+}
+
+// targeted returns renderer adapted to the render target, when one is set and the renderer
+// implements types.TargetedRenderer, and renderer itself otherwise.
+func targeted(renderer types.Renderer, target types.Filter) types.Renderer {
+	if target == nil || !types.Implements[types.TargetedRenderer](renderer) {
+		return renderer
+	}
+
+	tr, _ := renderer.(types.TargetedRenderer)
+
+	return targetedRenderer{
+		TargetedRenderer: tr,
+		target:           target,
+	}
+}
@@ -0,0 +1,143 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/cache"
+	"github.com/k8s-manifest-kit/engine/pkg/filter/meta/name"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+// targetRenderer renders its objects, skipping those not matching the target in ProcessTarget.
+type targetRenderer struct {
+	objects  []unstructured.Unstructured
+	full     int
+	targeted int
+}
+
+func (r *targetRenderer) Name() string {
+	return "targeted"
+}
+
+func (r *targetRenderer) Process(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	r.full++
+
+	return r.objects, nil
+}
+
+func (r *targetRenderer) ProcessTarget(
+	ctx context.Context,
+	_ map[string]any,
+	target types.Filter,
+) ([]unstructured.Unstructured, error) {
+	r.targeted++
+
+	result := make([]unstructured.Unstructured, 0, len(r.objects))
+
+	for _, obj := range r.objects {
+		ok, err := target(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			result = append(result, obj)
+		}
+	}
+
+	return result, nil
+}
+
+func TestWithTarget(t *testing.T) {
+
+	t.Run("should only return objects matching the target", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("web"),
+			makePod("db"),
+			makeService(),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(), engine.WithTarget(podFilter()), engine.WithTarget(name.Exact("web")))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("web"))
+	})
+
+	t.Run("should let targeted renderers skip work", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &targetRenderer{objects: []unstructured.Unstructured{makePod("web"), makeService()}}
+
+		e, err := engine.New(engine.WithRenderer(renderer), engine.WithRenderCache(cache.NewLRU(8)))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context(), engine.WithTarget(podFilter()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(report.Renderers[0].ObjectCount).Should(Equal(1))
+		g.Expect(renderer.targeted).Should(Equal(1))
+		g.Expect(renderer.full).Should(BeZero())
+
+		// Targeted results are never cached, so a full render still calls Process.
+		objects, err = e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(renderer.full).Should(Equal(1))
+	})
+
+	t.Run("should not require targeted renderers to match the target", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &targetRenderer{objects: []unstructured.Unstructured{makeService()}}
+
+		e, err := engine.New(engine.WithRenderer(renderer), engine.WithRequireNonEmpty(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(), engine.WithTarget(podFilter()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(BeEmpty())
+		g.Expect(renderer.targeted).Should(Equal(1))
+
+		// Renderers rendering everything are still checked.
+		empty := new(mockRenderer)
+		empty.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{}, nil)
+		empty.On("Name").Return("empty")
+
+		e, err = engine.New(engine.WithRenderers(renderer, empty), engine.WithRequireNonEmpty(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), engine.WithTarget(podFilter()))
+		g.Expect(err).Should(MatchError(engine.ErrRendererEmpty))
+		g.Expect(err).Should(MatchError(ContainSubstring(`"empty"`)))
+	})
+
+	t.Run("should call Process without a target", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &targetRenderer{objects: []unstructured.Unstructured{makePod("web"), makeService()}}
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(renderer.targeted).Should(BeZero())
+		g.Expect(renderer.full).Should(Equal(1))
+	})
+}
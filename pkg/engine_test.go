@@ -5,6 +5,7 @@ import (
 	"errors"
 	"maps"
 	"testing"
+	"time"
 
 	"github.com/k8s-manifest-kit/pkg/util/k8s"
 	"github.com/stretchr/testify/mock"
@@ -14,6 +15,8 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer"
 	"github.com/k8s-manifest-kit/engine/pkg/types"
 
 	. "github.com/onsi/gomega"
@@ -277,6 +280,13 @@ func TestEngineRender(t *testing.T) {
 		g.Expect(err).To(HaveOccurred())
 		g.Expect(err.Error()).To(ContainSubstring("renderer failed"))
 		g.Expect(objects).To(BeNil())
+
+		var rendererErr *types.RendererError
+		g.Expect(errors.As(err, &rendererErr)).To(BeTrue())
+		g.Expect(rendererErr.Name).To(Equal("mock"))
+		g.Expect(rendererErr.Type).To(Equal("*engine_test.mockRenderer"))
+		g.Expect(rendererErr.Err).To(MatchError("renderer failed"))
+		g.Expect(err.Error()).To(ContainSubstring(`renderer "mock" (*engine_test.mockRenderer)`))
 	})
 
 	t.Run("should return error from failing filter", func(t *testing.T) {
@@ -299,6 +309,76 @@ func TestEngineRender(t *testing.T) {
 		g.Expect(err).To(HaveOccurred())
 		g.Expect(err.Error()).To(ContainSubstring("filter failed"))
 		g.Expect(objects).To(BeNil())
+
+		var filterErr *types.FilterError
+		g.Expect(errors.As(err, &filterErr)).To(BeTrue())
+		g.Expect(filterErr.Object.GetName()).To(Equal("pod1"))
+		g.Expect(filterErr.Err).To(MatchError("filter failed"))
+	})
+
+	t.Run("should preserve the object identity of a failing filter", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePodWithNamespace("pod1", defaultNamespace),
+			makePodWithNamespace("pod2", systemNamespace),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		failingFilter := func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+			if obj.GetName() == "pod2" {
+				return false, errors.New("filter failed")
+			}
+
+			return true, nil
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithFilter(failingFilter),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(HaveOccurred())
+
+		filterErr, ok := filter.AsError(err)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(filterErr.Object.GetKind()).To(Equal("Pod"))
+		g.Expect(filterErr.Object.GetName()).To(Equal("pod2"))
+		g.Expect(filterErr.Object.GetNamespace()).To(Equal(systemNamespace))
+		g.Expect(filterErr.Err).To(MatchError("filter failed"))
+	})
+
+	t.Run("should return an abort error as-is", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+			makeService(),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		policyGate := func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+			if obj.GetKind() == "Service" {
+				return false, filter.Abort("services are forbidden")
+			}
+
+			return true, nil
+		}
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(), engine.WithRenderFilter(policyGate))
+		g.Expect(err).To(MatchError(filter.ErrAbort))
+		g.Expect(err.Error()).ToNot(ContainSubstring("engine filter error"))
+		g.Expect(err.Error()).To(ContainSubstring("services are forbidden"))
+		g.Expect(objects).To(BeNil())
+
+		filterErr, ok := filter.AsError(err)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(filterErr.Object.GetName()).To(Equal("svc1"))
 	})
 
 	t.Run("should return error from failing transformer", func(t *testing.T) {
@@ -321,6 +401,15 @@ func TestEngineRender(t *testing.T) {
 		g.Expect(err).To(HaveOccurred())
 		g.Expect(err.Error()).To(ContainSubstring("transformer failed"))
 		g.Expect(objects).To(BeNil())
+
+		var transformerErr *transformer.Error
+		g.Expect(errors.As(err, &transformerErr)).To(BeTrue())
+		g.Expect(transformerErr.Object.GetName()).To(Equal("pod1"))
+		g.Expect(transformerErr.Err).To(MatchError("transformer failed"))
+
+		var typesErr *types.TransformerError
+		g.Expect(errors.As(err, &typesErr)).To(BeTrue())
+		g.Expect(typesErr).To(BeIdenticalTo(transformerErr))
 	})
 
 	t.Run("should apply multiple filters in sequence", func(t *testing.T) {
@@ -426,6 +515,30 @@ func TestEngineRender(t *testing.T) {
 		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("engine", "level"))
 		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("render", "time"))
 	})
+
+	t.Run("should not mutate objects retained by the renderer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// the renderer keeps a reference to the slice it returns, e.g. as an internal cache
+		retained := []unstructured.Unstructured{makePod("pod1")}
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return(retained, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithTransformer(addLabels(map[string]string{"env": "test"})),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetLabels()).Should(HaveKeyWithValue("env", "test"))
+
+		g.Expect(retained[0].GetLabels()).Should(BeEmpty())
+	})
 }
 
 // Helper functions
@@ -543,7 +656,54 @@ func TestParallelRendering(t *testing.T) {
 		g.Expect(objects).To(HaveLen(3))
 
 		names := []string{objects[0].GetName(), objects[1].GetName(), objects[2].GetName()}
-		g.Expect(names).To(ContainElements("pod1", "pod2", "pod3"))
+		g.Expect(names).To(Equal([]string{"pod1", "pod2", "pod3"}))
+	})
+
+	t.Run("should return objects in the same order as sequential mode", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// Earlier renderers take longer, so they complete last in parallel mode.
+		newRenderer := func(name string, delay time.Duration, pods ...string) types.Renderer {
+			objects := make([]unstructured.Unstructured, 0, len(pods))
+			for _, pod := range pods {
+				objects = append(objects, makePod(pod))
+			}
+
+			r := new(mockRenderer)
+			r.On("Process", mock.Anything, mock.Anything).Return(objects, nil).After(delay)
+			r.On("Name").Return(name)
+
+			return r
+		}
+
+		renderers := []types.Renderer{
+			newRenderer("first", 30*time.Millisecond, "a3", "a1", "a2"),
+			newRenderer("second", 15*time.Millisecond, "b2", "b1"),
+			newRenderer("third", 0, "c1", "c3", "c2"),
+			&streamRenderer{name: "stream", objects: []unstructured.Unstructured{makePod("d2"), makePod("d1")}},
+		}
+
+		render := func(parallel bool) []string {
+			e, err := engine.New(engine.WithRenderers(renderers...), engine.WithParallel(parallel))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			objects, err := e.Render(t.Context())
+			g.Expect(err).ToNot(HaveOccurred())
+
+			names := make([]string, 0, len(objects))
+			for _, obj := range objects {
+				names = append(names, obj.GetName())
+			}
+
+			return names
+		}
+
+		sequential := render(false)
+		g.Expect(sequential).To(Equal([]string{"a3", "a1", "a2", "b2", "b1", "c1", "c3", "c2", "d2", "d1"}))
+
+		for range 5 {
+			g.Expect(render(true)).To(Equal(sequential))
+		}
 	})
 
 	t.Run("should render sequentially with parallel disabled", func(t *testing.T) {
@@ -844,6 +1004,164 @@ func TestRenderTimeValues(t *testing.T) {
 		g.Expect(capturedValues1).Should(Equal(renderValues))
 		g.Expect(capturedValues2).Should(Equal(renderValues))
 	})
+	t.Run("should deep merge values layers", func(t *testing.T) {
+		g := NewWithT(t)
+		var capturedValues map[string]any
+		renderer := new(mockRenderer)
+		renderer.On("Name").Return("mock")
+		renderer.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			capturedValues = args.Get(1).(map[string]any)
+		}).Return([]unstructured.Unstructured{makePod("test-pod")}, nil)
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		base := map[string]any{
+			"replicaCount": 1,
+			"image": map[string]any{
+				"repository": "nginx",
+				"tag":        "1.25",
+			},
+			"args": []any{"--verbose", "--debug"},
+		}
+		environment := map[string]any{
+			"replicaCount": 3,
+			"image": map[string]any{
+				"tag": "1.26",
+			},
+		}
+		service := map[string]any{
+			"args": []any{"--quiet"},
+		}
+
+		objects, err := e.Render(t.Context(), engine.WithValuesLayers(base, environment, service))
+
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(capturedValues).Should(Equal(map[string]any{
+			"replicaCount": 3,
+			"image": map[string]any{
+				"repository": "nginx",
+				"tag":        "1.26",
+			},
+			"args": []any{"--quiet"},
+		}))
+
+		// inputs must not be modified
+		g.Expect(base["image"]).Should(HaveKeyWithValue("tag", "1.25"))
+	})
+
+	t.Run("should merge values layers on top of values", func(t *testing.T) {
+		g := NewWithT(t)
+		var capturedValues map[string]any
+		renderer := new(mockRenderer)
+		renderer.On("Name").Return("mock")
+		renderer.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			capturedValues = args.Get(1).(map[string]any)
+		}).Return([]unstructured.Unstructured{makePod("test-pod")}, nil)
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(),
+			engine.WithValues(map[string]any{"a": 1, "b": 1}),
+			engine.WithValuesLayers(map[string]any{"b": 2}),
+		)
+
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(capturedValues).Should(Equal(map[string]any{"a": 1, "b": 2}))
+	})
+	t.Run("should merge per-renderer values with render-time values", func(t *testing.T) {
+		g := NewWithT(t)
+		var helmValues, templateValues, otherValues map[string]any
+
+		helm := new(mockRenderer)
+		helm.On("Name").Return("helm")
+		helm.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			helmValues = args.Get(1).(map[string]any)
+		}).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+
+		template := new(mockRenderer)
+		template.On("Name").Return("gotemplate")
+		template.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			templateValues = args.Get(1).(map[string]any)
+		}).Return([]unstructured.Unstructured{makePod("pod2")}, nil)
+
+		other := new(mockRenderer)
+		other.On("Name").Return("yaml")
+		other.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			otherValues = args.Get(1).(map[string]any)
+		}).Return([]unstructured.Unstructured{makePod("pod3")}, nil)
+
+		e, err := engine.New(
+			engine.WithRenderer(helm),
+			engine.WithRenderer(template),
+			engine.WithRenderer(other),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(),
+			engine.WithValues(map[string]any{
+				"env":   "production",
+				"image": map[string]any{"repository": "nginx", "tag": "1.25"},
+			}),
+			engine.WithRendererValues("helm", map[string]any{
+				"image": map[string]any{"tag": "1.26"},
+			}),
+			engine.WithRendererValues("gotemplate", map[string]any{"name": "web"}),
+		)
+
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(3))
+		g.Expect(helmValues).Should(Equal(map[string]any{
+			"env":   "production",
+			"image": map[string]any{"repository": "nginx", "tag": "1.26"},
+		}))
+		g.Expect(templateValues).Should(Equal(map[string]any{
+			"env":   "production",
+			"image": map[string]any{"repository": "nginx", "tag": "1.25"},
+			"name":  "web",
+		}))
+		g.Expect(otherValues).Should(Equal(map[string]any{
+			"env":   "production",
+			"image": map[string]any{"repository": "nginx", "tag": "1.25"},
+		}))
+	})
+
+	t.Run("should apply per-renderer values to all renderers sharing a name", func(t *testing.T) {
+		g := NewWithT(t)
+		var capturedValues1, capturedValues2 map[string]any
+
+		renderer1 := new(mockRenderer)
+		renderer1.On("Name").Return("helm")
+		renderer1.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			capturedValues1 = args.Get(1).(map[string]any)
+		}).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+
+		renderer2 := new(mockRenderer)
+		renderer2.On("Name").Return("helm")
+		renderer2.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			capturedValues2 = args.Get(1).(map[string]any)
+		}).Return([]unstructured.Unstructured{makePod("pod2")}, nil)
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer1),
+			engine.WithRenderer(renderer2),
+			engine.WithParallel(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(),
+			engine.WithRendererValues("helm", map[string]any{"a": 1}),
+			engine.WithRendererValues("helm", map[string]any{"b": 2}),
+		)
+
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(capturedValues1).Should(Equal(map[string]any{"a": 1, "b": 2}))
+		g.Expect(capturedValues2).Should(Equal(map[string]any{"a": 1, "b": 2}))
+	})
 }
 
 func TestSourceAnnotations(t *testing.T) {
@@ -1063,4 +1381,95 @@ func TestNewValidatesRenderers(t *testing.T) {
 		g.Expect(err).ShouldNot(HaveOccurred())
 		g.Expect(e).ShouldNot(BeNil())
 	})
+
+	t.Run("should add renderers in bulk alongside other options", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer1 := new(mockRenderer)
+		renderer1.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer1.On("Name").Return("renderer1")
+		renderer2 := new(mockRenderer)
+		renderer2.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makeService()}, nil)
+		renderer2.On("Name").Return("renderer2")
+
+		renderers := []types.Renderer{renderer1, renderer2}
+
+		e, err := engine.New(
+			engine.WithRenderers(renderers...),
+			engine.WithFilter(podFilter()),
+			engine.WithParallel(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(report.Renderers).Should(HaveLen(2))
+	})
+
+	t.Run("should validate renderers added in bulk", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(engine.WithRenderers(renderer, nil))
+		g.Expect(err).Should(MatchError(types.ErrRendererNil))
+		g.Expect(e).Should(BeNil())
+	})
+}
+
+func TestRequireNonEmpty(t *testing.T) {
+
+	t.Run("should fail when a renderer produces no objects", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer1 := new(mockRenderer)
+		renderer1.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer1.On("Name").Return("renderer1")
+		renderer2 := new(mockRenderer)
+		renderer2.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{}, nil)
+		renderer2.On("Name").Return("renderer2")
+
+		e, err := engine.New(
+			engine.WithRenderers(renderer1, renderer2),
+			engine.WithRequireNonEmpty(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).To(MatchError(engine.ErrRendererEmpty))
+		g.Expect(err.Error()).To(ContainSubstring(`"renderer2"`))
+		g.Expect(objects).To(BeNil())
+		g.Expect(report.Renderers[1].Err).To(MatchError(engine.ErrRendererEmpty))
+	})
+
+	t.Run("should check renderer output before engine-level filters", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makeService()}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithFilter(podFilter()),
+			engine.WithRequireNonEmpty(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(BeEmpty())
+	})
+
+	t.Run("should accept empty renderers by default", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(BeEmpty())
+	})
 }
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// ErrRendererTimeout is the renderer error reported when a renderer exceeds the timeout set
+// with WithRendererTimeout.
+var ErrRendererTimeout = errors.New("renderer timed out")
+
+// withRendererTimeout returns a context bounded by the renderer timeout, if one is configured.
+// When the timeout expires, context.Cause returns an error wrapping ErrRendererTimeout.
+func (e *Engine) withRendererTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.options.RendererTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeoutCause(
+		ctx,
+		e.options.RendererTimeout,
+		fmt.Errorf("%w after %s", ErrRendererTimeout, e.options.RendererTimeout),
+	)
+}
+
+// runProcess calls renderer.Process, bounded by the renderer timeout when one is configured.
+// A renderer that ignores the cancellation of its context is abandoned once the timeout, or the
+// render deadline, expires: its goroutine is left to finish on its own and its result is discarded.
+func (e *Engine) runProcess(
+	ctx context.Context,
+	renderer types.Renderer,
+	values map[string]any,
+) ([]unstructured.Unstructured, error) {
+	if e.options.RendererTimeout <= 0 && !hasRenderDeadline(ctx) {
+		return renderer.Process(ctx, values)
+	}
+
+	ctx, cancel := e.withRendererTimeout(ctx)
+	defer cancel()
+
+	type result struct {
+		objects []unstructured.Unstructured
+		err     error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		objects, err := renderer.Process(ctx, values)
+		done <- result{objects: objects, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil && errors.Is(context.Cause(ctx), ErrRendererTimeout) {
+			return nil, context.Cause(ctx)
+		}
+
+		return res.objects, res.err
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+}
@@ -0,0 +1,142 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithRendererTimeout(t *testing.T) {
+
+	t.Run("should fail a renderer exceeding its budget", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// the renderer ignores the cancellation of its context
+		release := make(chan struct{})
+		defer close(release)
+
+		slow := new(mockRenderer)
+		slow.On("Process", mock.Anything, mock.Anything).Run(func(_ mock.Arguments) {
+			<-release
+		}).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		slow.On("Name").Return("slow")
+
+		e, err := engine.New(
+			engine.WithRenderer(slow),
+			engine.WithRendererTimeout(50*time.Millisecond),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).Should(MatchError(engine.ErrRendererTimeout))
+		g.Expect(err.Error()).Should(ContainSubstring(`"slow"`))
+		g.Expect(objects).Should(BeNil())
+		g.Expect(report.Renderers[0].Err).Should(MatchError(engine.ErrRendererTimeout))
+	})
+
+	t.Run("should report a timeout observed by the renderer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			ctx, _ := args.Get(0).(context.Context)
+			<-ctx.Done()
+		}).Return([]unstructured.Unstructured(nil), context.DeadlineExceeded)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithRendererTimeout(20*time.Millisecond),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(MatchError(engine.ErrRendererTimeout))
+	})
+
+	t.Run("should give each parallel renderer its own budget", func(t *testing.T) {
+		g := NewWithT(t)
+
+		newRenderer := func(name string, delay time.Duration) *mockRenderer {
+			renderer := new(mockRenderer)
+			renderer.On("Process", mock.Anything, mock.Anything).Run(func(_ mock.Arguments) {
+				time.Sleep(delay)
+			}).Return([]unstructured.Unstructured{makePod(name)}, nil)
+			renderer.On("Name").Return(name)
+
+			return renderer
+		}
+
+		e, err := engine.New(
+			engine.WithRenderers(
+				newRenderer("pod1", 30*time.Millisecond),
+				newRenderer("pod2", 30*time.Millisecond),
+				newRenderer("pod3", 30*time.Millisecond),
+			),
+			engine.WithParallel(true),
+			engine.WithRendererTimeout(time.Second),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(3))
+	})
+
+	t.Run("should bound the consumption of a stream", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(stalledRenderer{}),
+			engine.WithRendererTimeout(20*time.Millisecond),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(MatchError(engine.ErrRendererTimeout))
+		g.Expect(err).Should(MatchError(ContainSubstring(`"stalled"`)))
+	})
+
+	t.Run("should not bound renderers without a timeout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Run(func(_ mock.Arguments) {
+			time.Sleep(20 * time.Millisecond)
+		}).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+	})
+}
+
+// stalledRenderer is a streaming renderer whose stream never produces anything.
+type stalledRenderer struct{}
+
+func (stalledRenderer) Name() string {
+	return "stalled"
+}
+
+func (stalledRenderer) Process(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	panic("Process must not be called on a streaming renderer")
+}
+
+func (stalledRenderer) ProcessStream(
+	_ context.Context,
+	_ map[string]any,
+) (<-chan unstructured.Unstructured, <-chan error) {
+	return make(chan unstructured.Unstructured), make(chan error)
+}
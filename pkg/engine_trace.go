@@ -0,0 +1,30 @@
+package engine
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName is the instrumentation scope name used for all engine spans.
+	tracerName = "github.com/k8s-manifest-kit/engine"
+
+	spanRender          = "engine.Render"
+	spanRendererProcess = "renderer.Process"
+
+	attrRendererName        = attribute.Key("renderer.name")
+	attrRendererObjectCount = attribute.Key("renderer.object_count")
+	attrRendererCached      = attribute.Key("renderer.cached")
+	attrObjectCount         = attribute.Key("engine.object_count")
+)
+
+// endSpan records err on the span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
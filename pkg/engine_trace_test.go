@@ -0,0 +1,117 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+
+	t.Run("should create a render span with one child span per renderer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r1 := new(mockRenderer)
+		r1.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+			makePod("pod2"),
+		}, nil)
+		r1.On("Name").Return("first")
+
+		r2 := new(mockRenderer)
+		r2.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makeService(),
+		}, nil)
+		r2.On("Name").Return("second")
+
+		recorder := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+		e, err := engine.New(
+			engine.WithRenderer(r1),
+			engine.WithRenderer(r2),
+			engine.WithTracerProvider(tp),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		spans := recorder.Ended()
+		g.Expect(spans).Should(HaveLen(3))
+
+		root := spans[2]
+		g.Expect(root.Name()).Should(Equal("engine.Render"))
+		g.Expect(root.Attributes()).Should(ContainElement(attribute.Int("engine.object_count", 3)))
+
+		g.Expect(spans[0].Name()).Should(Equal("renderer.Process"))
+		g.Expect(spans[0].Parent().SpanID()).Should(Equal(root.SpanContext().SpanID()))
+		g.Expect(spans[0].Attributes()).Should(ContainElements(
+			attribute.String("renderer.name", "first"),
+			attribute.Int("renderer.object_count", 2),
+		))
+
+		g.Expect(spans[1].Name()).Should(Equal("renderer.Process"))
+		g.Expect(spans[1].Parent().SpanID()).Should(Equal(root.SpanContext().SpanID()))
+		g.Expect(spans[1].Attributes()).Should(ContainElements(
+			attribute.String("renderer.name", "second"),
+			attribute.Int("renderer.object_count", 1),
+		))
+	})
+
+	t.Run("should record renderer errors on spans", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured(nil), errors.New("boom"))
+		renderer.On("Name").Return("broken")
+
+		recorder := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithTracerProvider(tp),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(HaveOccurred())
+
+		spans := recorder.Ended()
+		g.Expect(spans).Should(HaveLen(2))
+
+		for _, span := range spans {
+			g.Expect(span.Status().Code).Should(Equal(codes.Error))
+			g.Expect(span.Events()).Should(ContainElement(HaveField("Name", "exception")))
+		}
+	})
+
+	t.Run("should not fail without a tracer provider", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+	})
+}
@@ -0,0 +1,41 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Validate runs the engine-level validators, set with WithValidator, on objects without rendering
+// anything, e.g. to check manifests read from Git in a CI gate or to exercise validators in isolation.
+//
+// Validators run as they do at the end of a render: every object is checked and every failure is
+// returned, joined, in an error identifying the offending object. They see the Kubernetes version set
+// with WithKubeVersion through types.KubeVersion, and their warnings go to the handler set with
+// WithWarningHandler. Objects are not modified, and no renderer, filter, transformer or hook runs.
+func (e *Engine) Validate(ctx context.Context, objects []unstructured.Unstructured) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("engine validation canceled: %w", err)
+	}
+
+	version, err := kubeVersion(e.options.KubeVersion)
+	if err != nil {
+		return err
+	}
+
+	if version != "" {
+		ctx = types.WithKubeVersion(ctx, version)
+	}
+
+	ctx = types.WithWarningHandler(ctx, e.warningHandler(nil))
+
+	if err := pipeline.ApplyValidators(ctx, objects, e.options.Validators); err != nil {
+		return fmt.Errorf("engine validation error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/validator"
+	"github.com/k8s-manifest-kit/engine/pkg/validator/meta"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestValidate(t *testing.T) {
+
+	t.Run("should run the validators without rendering", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithValidator(meta.Validate()),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		err = e.Validate(t.Context(), []unstructured.Unstructured{makePod("pod1"), makeService()})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		renderer.AssertNotCalled(t, "Process", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should report every invalid object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(engine.WithValidator(meta.Validate()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		first := makePod("")
+		second := makePod("")
+		second.SetNamespace("other")
+
+		err = e.Validate(t.Context(), []unstructured.Unstructured{first, makePod("pod1"), second})
+		g.Expect(err).Should(HaveOccurred())
+
+		var validatorErr *validator.Error
+		g.Expect(errors.As(err, &validatorErr)).Should(BeTrue())
+
+		joined, ok := errors.Unwrap(err).(interface{ Unwrap() []error })
+		g.Expect(ok).Should(BeTrue())
+		g.Expect(joined.Unwrap()).Should(HaveLen(2))
+	})
+
+	t.Run("should pass the kube version and warnings to validators", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var warnings []types.Warning
+
+		e, err := engine.New(
+			engine.WithKubeVersion("1.31"),
+			engine.WithWarningHandler(func(warning types.Warning) {
+				warnings = append(warnings, warning)
+			}),
+			engine.WithValidator(func(ctx context.Context, obj unstructured.Unstructured) error {
+				version, _ := types.KubeVersion(ctx)
+				types.WarnObject(ctx, obj, "Version", version)
+
+				return nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		err = e.Validate(t.Context(), []unstructured.Unstructured{makePod("pod1")})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(warnings).Should(HaveLen(1))
+		g.Expect(warnings[0].Message).Should(Equal("v1.31.0"))
+	})
+
+	t.Run("should succeed without validators", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New()
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(e.Validate(t.Context(), []unstructured.Unstructured{makePod("")})).Should(Succeed())
+	})
+
+	t.Run("should fail on a canceled context", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(engine.WithValidator(meta.Validate()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		g.Expect(e.Validate(ctx, nil)).Should(MatchError(context.Canceled))
+	})
+}
@@ -0,0 +1,74 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/meta/namespace"
+	"github.com/k8s-manifest-kit/engine/pkg/validator"
+	"github.com/k8s-manifest-kit/engine/pkg/validator/meta"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithValidator(t *testing.T) {
+
+	t.Run("should return objects that pass validation", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+			makeService(),
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithValidator(meta.Validate()),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+	})
+
+	t.Run("should identify the offending object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ns := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata":   map[string]any{"name": "team-a"},
+		}}
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{
+			makePod("pod1"),
+			ns,
+		}, nil)
+		renderer.On("Name").Return("mock")
+
+		// Validators run after transformers, so the namespace set here must be rejected
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithTransformer(namespace.Set(defaultNamespace)),
+			engine.WithValidator(meta.Validate()),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).Should(MatchError(meta.ErrNamespaceOnClusterScoped))
+		g.Expect(objects).Should(BeNil())
+
+		var validatorErr *validator.Error
+		g.Expect(errors.As(err, &validatorErr)).Should(BeTrue())
+		g.Expect(validatorErr.Object.GetKind()).Should(Equal("Namespace"))
+		g.Expect(validatorErr.Object.GetName()).Should(Equal("team-a"))
+	})
+}
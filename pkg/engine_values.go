@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/k8s-manifest-kit/pkg/util"
+	"sigs.k8s.io/yaml"
+)
+
+// ValuesSource provides render-time values read when a render starts, such as a values file.
+type ValuesSource func() (map[string]any, error)
+
+// valuesFile returns a ValuesSource reading the YAML or JSON document at path.
+func valuesFile(path string) ValuesSource {
+	return func() (map[string]any, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read values file %q: %w", path, err)
+		}
+
+		values, err := parseValues(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse values file %q: %w", path, err)
+		}
+
+		return values, nil
+	}
+}
+
+// valuesReader returns a ValuesSource reading a YAML or JSON document from r.
+func valuesReader(r io.Reader) ValuesSource {
+	return func() (map[string]any, error) {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read values: %w", err)
+		}
+
+		values, err := parseValues(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse values: %w", err)
+		}
+
+		return values, nil
+	}
+}
+
+// parseValues decodes a YAML or JSON document, JSON being a subset of YAML, into a values map.
+// An empty document yields empty values.
+func parseValues(data []byte) (map[string]any, error) {
+	values := make(map[string]any)
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	if values == nil {
+		values = make(map[string]any)
+	}
+
+	return values, nil
+}
+
+// resolveValues deep merges defaults, then the values sources of renderOpts in order, as the base of
+// the explicit render-time values, which take precedence.
+func resolveValues(defaults map[string]any, renderOpts *RenderOptions) error {
+	if len(defaults) == 0 && len(renderOpts.ValuesSources) == 0 {
+		return nil
+	}
+
+	base := util.DeepMerge(nil, defaults)
+
+	for _, source := range renderOpts.ValuesSources {
+		values, err := source()
+		if err != nil {
+			return fmt.Errorf("engine values error: %w", err)
+		}
+
+		base = util.DeepMerge(base, values)
+	}
+
+	renderOpts.Values = util.DeepMerge(base, renderOpts.Values)
+
+	return nil
+}
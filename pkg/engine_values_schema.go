@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// ErrValuesSchema is wrapped by the ValuesSchemaError returned when render-time values do not match
+// the schema set with WithValuesSchema.
+var ErrValuesSchema = errors.New("render-time values do not match the values schema")
+
+// ValuesSchemaError reports the render-time values of a Render() call not matching the schema set
+// with WithValuesSchema. No renderer runs when it is returned.
+type ValuesSchemaError struct {
+	// Violations describe each mismatch, e.g. "replicas in body must be of type integer: \"string\"",
+	// sorted.
+	Violations []string
+}
+
+func (e *ValuesSchemaError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrValuesSchema, strings.Join(e.Violations, "; "))
+}
+
+func (e *ValuesSchemaError) Unwrap() error {
+	return ErrValuesSchema
+}
+
+// parseValuesSchema parses a JSON Schema, JSON being a subset of YAML, rejecting references.
+func parseValuesSchema(data []byte) (*spec.Schema, error) {
+	data, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid values schema: %w", err)
+	}
+
+	s := &spec.Schema{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("invalid values schema: %w", err)
+	}
+
+	if ref := schemaRef(s); ref != "" {
+		return nil, fmt.Errorf("invalid values schema: references are not supported: %s", ref)
+	}
+
+	return s, nil
+}
+
+// schemaRef returns the first $ref found in s or its subschemas, empty if there is none.
+func schemaRef(s *spec.Schema) string {
+	if s == nil {
+		return ""
+	}
+
+	if ref := s.Ref.String(); ref != "" {
+		return ref
+	}
+
+	subschemas := make([]*spec.Schema, 0)
+
+	for _, group := range [][]spec.Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for i := range group {
+			subschemas = append(subschemas, &group[i])
+		}
+	}
+
+	for _, group := range []map[string]spec.Schema{s.Properties, s.PatternProperties, s.Definitions} {
+		for _, key := range slices.Sorted(maps.Keys(group)) {
+			sub := group[key]
+			subschemas = append(subschemas, &sub)
+		}
+	}
+
+	subschemas = append(subschemas, s.Not)
+
+	if s.AdditionalProperties != nil {
+		subschemas = append(subschemas, s.AdditionalProperties.Schema)
+	}
+
+	if s.AdditionalItems != nil {
+		subschemas = append(subschemas, s.AdditionalItems.Schema)
+	}
+
+	if s.Items != nil {
+		subschemas = append(subschemas, s.Items.Schema)
+
+		for i := range s.Items.Schemas {
+			subschemas = append(subschemas, &s.Items.Schemas[i])
+		}
+	}
+
+	for _, sub := range subschemas {
+		if ref := schemaRef(sub); ref != "" {
+			return ref
+		}
+	}
+
+	return ""
+}
+
+// validateValues checks values against the values schema of the engine, if any.
+func (e *Engine) validateValues(values map[string]any) error {
+	if e.valuesSchema == nil {
+		return nil
+	}
+
+	// Round-trip through JSON, so that typed values such as int or []string validate as their JSON form.
+	data, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("engine values error: unable to encode values: %w", err)
+	}
+
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("engine values error: unable to decode values: %w", err)
+	}
+
+	result := validate.NewSchemaValidator(e.valuesSchema, nil, "", strfmt.Default).Validate(document)
+	if !result.HasErrors() {
+		return nil
+	}
+
+	violations := make([]string, 0, len(result.Errors))
+	for _, err := range result.Errors {
+		violations = append(violations, err.Error())
+	}
+
+	slices.Sort(violations)
+
+	return &ValuesSchemaError{Violations: slices.Compact(violations)}
+}
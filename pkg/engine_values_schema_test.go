@@ -0,0 +1,149 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+const valuesSchema = `{
+	"$schema": "http://json-schema.org/draft-04/schema#",
+	"type": "object",
+	"required": ["image"],
+	"properties": {
+		"image": {"type": "string"},
+		"replicas": {"type": "integer", "minimum": 1},
+		"ports": {"type": "array", "items": {"type": "integer"}}
+	},
+	"additionalProperties": false
+}`
+
+func TestWithValuesSchema(t *testing.T) {
+
+	newRenderer := func() *mockRenderer {
+		renderer := new(mockRenderer)
+		renderer.On("Name").Return("mock")
+		renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+
+		return renderer
+	}
+
+	t.Run("should render values matching the schema", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithValuesSchema([]byte(valuesSchema)),
+			engine.WithDefaultValues(map[string]any{"replicas": 2}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(), engine.WithValues(map[string]any{
+			"image": "nginx",
+			"ports": []int{80, 443},
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+	})
+
+	t.Run("should report every violation without rendering", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := newRenderer()
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithValuesSchema([]byte(valuesSchema)),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), engine.WithValues(map[string]any{
+			"replicas": "3",
+			"replics":  3,
+		}))
+		g.Expect(err).Should(MatchError(engine.ErrValuesSchema))
+
+		var schemaErr *engine.ValuesSchemaError
+		g.Expect(errors.As(err, &schemaErr)).Should(BeTrue())
+		g.Expect(schemaErr.Violations).Should(HaveLen(3))
+		g.Expect(err.Error()).Should(ContainSubstring("image"))
+		g.Expect(err.Error()).Should(ContainSubstring("replicas"))
+		g.Expect(err.Error()).Should(ContainSubstring("replics"))
+
+		var rendererErr *types.RendererError
+		g.Expect(errors.As(err, &rendererErr)).Should(BeFalse())
+
+		renderer.AssertNotCalled(t, "Process", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should validate the values returned by BeforeRender hooks", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithValuesSchema([]byte(valuesSchema)),
+			engine.WithHook(funcHook{
+				before: func(_ context.Context, values map[string]any) (map[string]any, error) {
+					values["image"] = "nginx"
+
+					return values, nil
+				},
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	t.Run("should validate values when rendering to a writer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithValuesSchema([]byte(valuesSchema)),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		var out strings.Builder
+
+		err = e.RenderTo(t.Context(), &out)
+		g.Expect(err).Should(MatchError(engine.ErrValuesSchema))
+		g.Expect(out.String()).Should(BeEmpty())
+	})
+
+	t.Run("should accept YAML schemas", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newRenderer()),
+			engine.WithValuesSchema([]byte("type: object\nrequired: [image]\n")),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(MatchError(engine.ErrValuesSchema))
+	})
+
+	t.Run("should reject invalid schemas", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(engine.WithValuesSchema([]byte(`{"type": `)))
+		g.Expect(err).Should(MatchError(ContainSubstring("invalid values schema")))
+
+		_, err = engine.New(engine.WithValuesSchema([]byte(
+			`{"properties": {"image": {"$ref": "#/definitions/image"}}}`,
+		)))
+		g.Expect(err).Should(MatchError(ContainSubstring("references are not supported")))
+	})
+}
@@ -0,0 +1,172 @@
+package engine_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithValuesFile(t *testing.T) {
+
+	writeFile := func(t *testing.T, name string, content string) string {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	// renderValues renders with opts and returns the values received by the renderer.
+	renderValues := func(g *WithT, opts ...engine.RenderOption) (map[string]any, error) {
+		var received map[string]any
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			received, _ = args.Get(1).(map[string]any)
+		}).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), opts...)
+
+		return received, err
+	}
+
+	t.Run("should read YAML and JSON files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		yamlFile := writeFile(t, "values.yaml", "image:\n  tag: v1\n  repository: web\n")
+		jsonFile := writeFile(t, "values.json", `{"image": {"tag": "v2"}, "debug": true}`)
+
+		values, err := renderValues(g, engine.WithValuesFile(yamlFile), engine.WithValuesFile(jsonFile))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(values).Should(Equal(map[string]any{
+			"image": map[string]any{"tag": "v2", "repository": "web"},
+			"debug": true,
+		}))
+	})
+
+	t.Run("should let explicit values take precedence whatever the order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		file := writeFile(t, "values.yaml", "image:\n  tag: v1\n  repository: web\n")
+
+		values, err := renderValues(g,
+			engine.WithValues(map[string]any{"image": map[string]any{"tag": "v3"}}),
+			engine.WithValuesFile(file),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(values).Should(Equal(map[string]any{
+			"image": map[string]any{"tag": "v3", "repository": "web"},
+		}))
+	})
+
+	t.Run("should read values from a reader", func(t *testing.T) {
+		g := NewWithT(t)
+
+		values, err := renderValues(g, engine.WithValuesReader(strings.NewReader("env: prod\n")))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(values).Should(Equal(map[string]any{"env": "prod"}))
+	})
+
+	t.Run("should accept an empty file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		values, err := renderValues(g, engine.WithValuesFile(writeFile(t, "values.yaml", "")))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(values).Should(BeEmpty())
+	})
+
+	t.Run("should identify a file that cannot be parsed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		file := writeFile(t, "values.yaml", "- not\n- a map\n")
+
+		_, err := renderValues(g, engine.WithValuesFile(file))
+		g.Expect(err).Should(MatchError(ContainSubstring("unable to parse values file %q", file)))
+	})
+
+	t.Run("should identify a file that cannot be read", func(t *testing.T) {
+		g := NewWithT(t)
+
+		file := filepath.Join(t.TempDir(), "missing.yaml")
+
+		_, err := renderValues(g, engine.WithValuesFile(file))
+		g.Expect(err).Should(MatchError(os.ErrNotExist))
+		g.Expect(err).Should(MatchError(ContainSubstring(file)))
+	})
+}
+
+func TestWithDefaultValues(t *testing.T) {
+
+	defaults := map[string]any{
+		"cluster":  map[string]any{"name": "prod-1", "region": "eu-west-1"},
+		"replicas": 1,
+	}
+
+	newEngine := func(g *WithT, received *map[string]any, opts ...engine.Option) *engine.Engine {
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*received, _ = args.Get(1).(map[string]any)
+		}).Return([]unstructured.Unstructured{makePod("pod1")}, nil)
+		renderer.On("Name").Return("mock")
+
+		e, err := engine.New(append([]engine.Option{engine.WithRenderer(renderer)}, opts...)...)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		return e
+	}
+
+	t.Run("should merge engine defaults under render values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var received map[string]any
+
+		e := newEngine(g, &received, engine.WithDefaultValues(defaults))
+
+		_, err := e.Render(t.Context(), engine.WithValues(map[string]any{
+			"cluster": map[string]any{"region": "us-east-1"},
+			"image":   "web:v2",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(received).Should(Equal(map[string]any{
+			"cluster":  map[string]any{"name": "prod-1", "region": "us-east-1"},
+			"replicas": 1,
+			"image":    "web:v2",
+		}))
+
+		g.Expect(defaults["cluster"]).Should(Equal(map[string]any{"name": "prod-1", "region": "eu-west-1"}))
+	})
+
+	t.Run("should pass engine defaults when no render values are set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var received map[string]any
+
+		e := newEngine(g, &received,
+			engine.WithDefaultValues(defaults),
+			engine.WithDefaultValues(map[string]any{"replicas": 3}),
+		)
+
+		_, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(received).Should(Equal(map[string]any{
+			"cluster":  map[string]any{"name": "prod-1", "region": "eu-west-1"},
+			"replicas": 3,
+		}))
+	})
+}
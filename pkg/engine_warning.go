@@ -0,0 +1,27 @@
+package engine
+
+import (
+	"sync"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// warningHandler returns the handler collecting the warnings of a render into report, when non-nil,
+// and forwarding them to the handler set with WithWarningHandler. Calls are serialized, so neither
+// the report nor the configured handler is accessed concurrently, even in parallel mode.
+func (e *Engine) warningHandler(report *RenderReport) types.WarningHandler {
+	var mu sync.Mutex
+
+	return func(warning types.Warning) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if report != nil {
+			report.Warnings = append(report.Warnings, warning)
+		}
+
+		if e.options.WarningHandler != nil {
+			e.options.WarningHandler(warning)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithWarningHandler(t *testing.T) {
+
+	warnServices := func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if obj.GetKind() == "Service" {
+			types.WarnObject(ctx, obj, "MissingLabel", "missing recommended label app.kubernetes.io/name")
+		}
+
+		return obj, nil
+	}
+
+	t.Run("should deliver structured warnings without failing the render", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := new(mockRenderer)
+		renderer.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			ctx, _ := args.Get(0).(context.Context)
+			types.Warn(ctx, types.Warning{Code: "Deprecated", Message: "chart is deprecated"})
+		}).Return([]unstructured.Unstructured{makePod("pod1"), makeService()}, nil)
+		renderer.On("Name").Return("mock")
+
+		var warnings []types.Warning
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithTransformer(warnServices),
+			engine.WithWarningHandler(func(w types.Warning) {
+				warnings = append(warnings, w)
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+
+		g.Expect(warnings).Should(HaveLen(2))
+		g.Expect(warnings[0].Code).Should(Equal("Deprecated"))
+		g.Expect(warnings[0].Renderer).Should(Equal("mock"))
+		g.Expect(warnings[0].Object).Should(BeNil())
+
+		g.Expect(warnings[1].Code).Should(Equal("MissingLabel"))
+		g.Expect(warnings[1].Object).ShouldNot(BeNil())
+		g.Expect(warnings[1].Object.GetName()).Should(Equal("svc1"))
+		g.Expect(warnings[1].String()).Should(ContainSubstring("v1:Service svc1"))
+
+		g.Expect(report.Warnings).Should(Equal(warnings))
+	})
+
+	t.Run("should collect warnings in the report without a handler", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, parallel := range []bool{false, true} {
+			newRenderer := func(name string) *mockRenderer {
+				renderer := new(mockRenderer)
+				renderer.On("Process", mock.Anything, mock.Anything).Return([]unstructured.Unstructured{makeService()}, nil)
+				renderer.On("Name").Return(name)
+
+				return renderer
+			}
+
+			e, err := engine.New(
+				engine.WithRenderers(newRenderer("renderer1"), newRenderer("renderer2")),
+				engine.WithTransformer(warnServices),
+				engine.WithParallel(parallel),
+			)
+			g.Expect(err).ShouldNot(HaveOccurred())
+
+			_, report, err := e.RenderWithReport(t.Context())
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(report.Warnings).Should(HaveLen(2))
+			g.Expect([]string{report.Warnings[0].Renderer, report.Warnings[1].Renderer}).Should(
+				ConsistOf("renderer1", "renderer2"),
+			)
+		}
+	})
+
+	t.Run("should discard warnings outside a render", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := warnServices(t.Context(), makeService())
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+}
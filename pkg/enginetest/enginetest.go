@@ -0,0 +1,66 @@
+// Package enginetest provides renderers for testing code built on the engine, without mocks.
+package enginetest
+
+import (
+	"context"
+
+	"github.com/k8s-manifest-kit/pkg/util/k8s"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/decode"
+)
+
+// renderer is a types.Renderer returning fixed objects or a fixed error.
+type renderer struct {
+	name    string
+	objects []unstructured.Unstructured
+	err     error
+}
+
+func (r *renderer) Name() string {
+	return r.name
+}
+
+// Process returns a deep copy of the renderer's objects, so the engine pipeline never modifies them
+// and every render sees the same output. Values are ignored.
+func (r *renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return k8s.DeepCloneUnstructuredSlice(r.objects), nil
+}
+
+// StaticRenderer returns a renderer named name that produces objs on every render.
+func StaticRenderer(name string, objs ...unstructured.Unstructured) types.Renderer {
+	return &renderer{
+		name:    name,
+		objects: k8s.DeepCloneUnstructuredSlice(objs),
+	}
+}
+
+// ErrRenderer returns a renderer named name that fails every render with err.
+func ErrRenderer(name string, err error) types.Renderer {
+	return &renderer{
+		name: name,
+		err:  err,
+	}
+}
+
+// FromYAML returns a renderer named name that produces the objects of a multi-document YAML string
+// on every render. If the YAML cannot be decoded, the renderer fails every render with the decoding
+// error, so that the failure surfaces where the renderer is used.
+func FromYAML(name string, yaml string) types.Renderer {
+	objects, err := decode.YAML([]byte(yaml))
+	if err != nil {
+		return ErrRenderer(name, err)
+	}
+
+	return StaticRenderer(name, objects...)
+}
@@ -0,0 +1,96 @@
+package enginetest_test
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/enginetest"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/meta/labels"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeConfigMap(name string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": name},
+	}}
+}
+
+func TestStaticRenderer(t *testing.T) {
+
+	t.Run("should produce the same objects on every render", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := enginetest.StaticRenderer("static", makeConfigMap("cm1"), makeConfigMap("cm2"))
+		g.Expect(renderer.Name()).Should(Equal("static"))
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithTransformer(labels.Set(map[string]string{"env": "prod"})),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		for range 2 {
+			objects, err := e.Render(t.Context())
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(objects).Should(HaveLen(2))
+			g.Expect(objects[0].GetLabels()).Should(HaveKeyWithValue("env", "prod"))
+		}
+
+		// the transformer never modifies the renderer's objects
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects[0].GetLabels()).Should(BeEmpty())
+	})
+}
+
+func TestErrRenderer(t *testing.T) {
+	g := NewWithT(t)
+
+	renderErr := errors.New("boom")
+
+	e, err := engine.New(engine.WithRenderer(enginetest.ErrRenderer("failing", renderErr)))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	_, err = e.Render(t.Context())
+	g.Expect(err).Should(MatchError(renderErr))
+}
+
+func TestFromYAML(t *testing.T) {
+
+	t.Run("should produce the decoded objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := enginetest.FromYAML("yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret1
+`)
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetKind()).Should(Equal("ConfigMap"))
+		g.Expect(objects[1].GetName()).Should(Equal("secret1"))
+	})
+
+	t.Run("should fail on render when the YAML is invalid", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := enginetest.FromYAML("yaml", "kind: [")
+
+		_, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
@@ -0,0 +1,28 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAbort is the sentinel error a filter returns to fail the entire render.
+//
+// A filter has three possible outcomes:
+//   - (true, nil) keeps the object and (false, nil) silently drops it
+//   - (false, err) reports that the filter could not evaluate the object; the render fails with
+//     an error wrapped as a filter error of the stage it belongs to
+//   - (false, Abort(reason)) rejects the object on purpose, e.g. in a policy gate; the engine stops
+//     and returns the abort error as-is, so callers can tell it apart with errors.Is(err, ErrAbort)
+//
+// In all error cases the returned error identifies the offending object, see AsError.
+var ErrAbort = errors.New("render aborted")
+
+// Abort returns an error wrapping ErrAbort with the given reason.
+func Abort(reason string) error {
+	return fmt.Errorf("%w: %s", ErrAbort, reason)
+}
+
+// IsAbort reports whether err, or any error in its chain, is ErrAbort.
+func IsAbort(err error) bool {
+	return errors.Is(err, ErrAbort)
+}
@@ -0,0 +1,40 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAbort(t *testing.T) {
+
+	t.Run("should wrap ErrAbort with the reason", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := filter.Abort("privileged containers are forbidden")
+		g.Expect(err).Should(MatchError(filter.ErrAbort))
+		g.Expect(err).Should(MatchError("render aborted: privileged containers are forbidden"))
+	})
+
+	t.Run("should be detected through wrapping", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := fmt.Errorf("outer: %w", filter.Wrap(makePod("pod1"), filter.Abort("forbidden")))
+		g.Expect(filter.IsAbort(err)).Should(BeTrue())
+
+		filterErr, ok := filter.AsError(err)
+		g.Expect(ok).Should(BeTrue())
+		g.Expect(filterErr.Object.GetName()).Should(Equal("pod1"))
+	})
+
+	t.Run("should not match other errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(filter.IsAbort(errors.New("boom"))).Should(BeFalse())
+		g.Expect(filter.IsAbort(nil)).Should(BeFalse())
+	})
+}
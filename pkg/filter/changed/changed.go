@@ -0,0 +1,72 @@
+// Package changed provides a filter keeping the objects that differ from a baseline, e.g. the objects
+// already applied to a cluster, for incremental apply.
+package changed
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/hash"
+)
+
+// key identifies an object.
+type key struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+func keyOf(obj unstructured.Unstructured) key {
+	return key{
+		gvk:       obj.GroupVersionKind(),
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+	}
+}
+
+// baselineEntry is the hash of a baseline object, or the error hashing it.
+type baselineEntry struct {
+	hash string
+	err  error
+}
+
+// Against returns a filter keeping an object only if baseline holds no object with the same
+// GroupVersionKind, namespace and name, or if that object has a different content.
+//
+// Contents are compared by their canonical hash (see hash.Objects), so the order of map keys and the
+// fields populated by the API server, such as status and metadata.resourceVersion, are ignored: an
+// unchanged object read back from a cluster matches the rendered one. Namespaces are compared as is,
+// so objects rendered without a namespace only match baseline objects without one.
+//
+// The baseline is hashed once, when the filter is created, and held in memory for the lifetime of the
+// filter: it must hold the full set of objects to compare against. A baseline object that cannot be
+// hashed makes the filter fail on the objects matching it.
+func Against(baseline []unstructured.Unstructured) types.Filter {
+	hashes := make(map[key]baselineEntry, len(baseline))
+
+	for _, obj := range baseline {
+		h, err := hash.Objects([]unstructured.Unstructured{obj})
+		hashes[keyOf(obj)] = baselineEntry{hash: h, err: err}
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		base, ok := hashes[keyOf(obj)]
+		if !ok {
+			return true, nil
+		}
+
+		if base.err != nil {
+			return false, base.err
+		}
+
+		h, err := hash.Objects([]unstructured.Unstructured{obj})
+		if err != nil {
+			return false, err
+		}
+
+		return h != base.hash, nil
+	}
+}
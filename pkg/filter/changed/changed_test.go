@@ -0,0 +1,79 @@
+package changed_test
+
+import (
+	"math"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter/changed"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeConfigMap(name string, data map[string]any) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+		"data":       data,
+	}}
+}
+
+func TestAgainst(t *testing.T) {
+	g := NewWithT(t)
+
+	applied := makeConfigMap("config", map[string]any{"key": "value"})
+	applied.SetResourceVersion("12345")
+	applied.SetUID("6f1c0f0e-0000-0000-0000-000000000000")
+
+	filter := changed.Against([]unstructured.Unstructured{applied})
+
+	t.Run("should drop unchanged objects", func(t *testing.T) {
+		ok, err := filter(t.Context(), makeConfigMap("config", map[string]any{"key": "value"}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should keep changed objects", func(t *testing.T) {
+		ok, err := filter(t.Context(), makeConfigMap("config", map[string]any{"key": "other"}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should keep new objects", func(t *testing.T) {
+		ok, err := filter(t.Context(), makeConfigMap("other", map[string]any{"key": "value"}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+
+		obj := makeConfigMap("config", map[string]any{"key": "value"})
+		obj.SetNamespace("prod")
+
+		ok, err = filter(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+
+		obj = makeConfigMap("config", map[string]any{"key": "value"})
+		obj.SetKind("Secret")
+
+		ok, err = filter(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should keep everything against an empty baseline", func(t *testing.T) {
+		ok, err := changed.Against(nil)(t.Context(), applied)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should fail on objects that cannot be hashed", func(t *testing.T) {
+		broken := makeConfigMap("config", map[string]any{"ratio": math.NaN()})
+
+		_, err := changed.Against([]unstructured.Unstructured{broken})(t.Context(), applied)
+		g.Expect(err).Should(MatchError(ContainSubstring("unable to encode")))
+
+		_, err = filter(t.Context(), broken)
+		g.Expect(err).Should(MatchError(ContainSubstring("unable to encode")))
+	})
+}
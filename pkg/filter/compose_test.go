@@ -273,6 +273,71 @@ func TestNestedComposition(t *testing.T) {
 	})
 }
 
+func TestErrorPropagation(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	tests := []struct {
+		name        string
+		filter      types.Filter
+		expected    bool
+		expectedErr error
+	}{
+		{
+			name:        "And returns the first error",
+			filter:      filter.And(alwaysTrue(), failWith(errFirst), failWith(errSecond)),
+			expectedErr: errFirst,
+		},
+		{
+			name:     "And short-circuits on false before an error",
+			filter:   filter.And(alwaysFalse(), failWith(errFirst)),
+			expected: false,
+		},
+		{
+			name:        "Or returns the first error",
+			filter:      filter.Or(alwaysFalse(), failWith(errFirst), failWith(errSecond)),
+			expectedErr: errFirst,
+		},
+		{
+			name:     "Or short-circuits on true before an error",
+			filter:   filter.Or(alwaysTrue(), failWith(errFirst)),
+			expected: true,
+		},
+		{
+			name:        "Not propagates the error instead of inverting",
+			filter:      filter.Not(failWith(errFirst)),
+			expectedErr: errFirst,
+		},
+		{
+			name:        "nested combinators propagate the innermost error",
+			filter:      filter.Not(filter.And(alwaysTrue(), filter.Or(alwaysFalse(), failWith(errSecond)))),
+			expectedErr: errSecond,
+		},
+		{
+			name:     "nested combinators short-circuit around errors",
+			filter:   filter.Or(filter.And(alwaysFalse(), failWith(errFirst)), filter.Not(alwaysFalse()), failWith(errSecond)),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ok, err := tt.filter(t.Context(), makePod("test"))
+			if tt.expectedErr != nil {
+				g.Expect(err).Should(MatchError(tt.expectedErr))
+				g.Expect(ok).Should(BeFalse())
+
+				return
+			}
+
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(ok).Should(Equal(tt.expected))
+		})
+	}
+}
+
 // Helper functions
 
 //nolint:unparam // Test helper needs consistent signature
@@ -308,3 +373,9 @@ func alwaysError() types.Filter {
 		return false, errors.New("filter error")
 	}
 }
+
+func failWith(err error) types.Filter {
+	return func(_ context.Context, _ unstructured.Unstructured) (bool, error) {
+		return false, err
+	}
+}
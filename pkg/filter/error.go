@@ -2,32 +2,15 @@ package filter
 
 import (
 	"errors"
-	"fmt"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
 )
 
 // Error represents an error that occurred during filter application.
-// It provides context about which object failed and the underlying error.
-type Error struct {
-	Object unstructured.Unstructured
-	Err    error
-}
-
-func (e *Error) Error() string {
-	return fmt.Sprintf(
-		"filter error for %s:%s %s (namespace: %s): %v",
-		e.Object.GroupVersionKind().GroupVersion(),
-		e.Object.GroupVersionKind().Kind,
-		e.Object.GetName(),
-		e.Object.GetNamespace(),
-		e.Err,
-	)
-}
-
-func (e *Error) Unwrap() error {
-	return e.Err
-}
+// It is an alias of types.FilterError, so either can be used with errors.As.
+type Error = types.FilterError
 
 // Wrap wraps an error with filter context.
 // If err is already an Error, it returns it as-is to avoid double-wrapping.
@@ -47,3 +30,14 @@ func Wrap(obj unstructured.Unstructured, err error) error {
 		Err:    err,
 	}
 }
+
+// AsError finds the first Error in err's chain and returns it.
+// It lets callers recover which object caused a filter to fail.
+func AsError(err error) (*Error, bool) {
+	var filterErr *Error
+	if errors.As(err, &filterErr) {
+		return filterErr, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,36 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAsError(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should extract a wrapped Error", func(t *testing.T) {
+		cause := errors.New("boom")
+		err := fmt.Errorf("engine filter error: %w", filter.Wrap(makePod("pod1"), cause))
+
+		filterErr, ok := filter.AsError(err)
+		g.Expect(ok).Should(BeTrue())
+		g.Expect(filterErr.Object.GetName()).Should(Equal("pod1"))
+		g.Expect(filterErr.Err).Should(MatchError(cause))
+	})
+
+	t.Run("should return false for other errors", func(t *testing.T) {
+		filterErr, ok := filter.AsError(errors.New("boom"))
+		g.Expect(ok).Should(BeFalse())
+		g.Expect(filterErr).Should(BeNil())
+	})
+
+	t.Run("should return false for nil", func(t *testing.T) {
+		_, ok := filter.AsError(nil)
+		g.Expect(ok).Should(BeFalse())
+	})
+}
@@ -0,0 +1,36 @@
+// Package group provides filters selecting objects by API group, e.g. for cleanup tooling
+// operating on all the resources of an operator.
+package group
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Core is the name of the core API group, to which objects with apiVersion "v1" belong.
+const Core = ""
+
+// Is returns a filter that keeps objects of the API group group, matched exactly against the group
+// of their apiVersion: "cert-manager.io" keeps cert-manager.io/v1 objects but not acme.cert-manager.io
+// ones. Use Core ("") to keep objects of the core group, such as ConfigMaps. Objects without an
+// apiVersion belong to no group and are always dropped.
+func Is(group string) types.Filter {
+	return In(group)
+}
+
+// In returns a filter that keeps objects whose API group is one of groups, matched exactly as by Is.
+func In(groups ...string) types.Filter {
+	s := sets.New(groups...)
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		if obj.GetAPIVersion() == "" {
+			return false, nil
+		}
+
+		return s.Has(obj.GroupVersionKind().Group), nil
+	}
+}
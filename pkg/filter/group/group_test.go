@@ -0,0 +1,86 @@
+package group_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter/group"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeObject(apiVersion string, kind string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName("test")
+
+	return obj
+}
+
+func TestIs(t *testing.T) {
+
+	t.Run("should match the group exactly", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := group.Is("cert-manager.io")
+
+		for apiVersion, expected := range map[string]bool{
+			"cert-manager.io/v1":      true,
+			"acme.cert-manager.io/v1": false,
+			"cert-manager.io.example": false,
+			"v1":                      false,
+		} {
+			keep, err := f(t.Context(), makeObject(apiVersion, "Certificate"))
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(keep).Should(Equal(expected), apiVersion)
+		}
+	})
+
+	t.Run("should match the core group", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := group.Is(group.Core)
+
+		keep, err := f(t.Context(), makeObject("v1", "ConfigMap"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(keep).Should(BeTrue())
+
+		keep, err = f(t.Context(), makeObject("apps/v1", "Deployment"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(keep).Should(BeFalse())
+
+		keep, err = f(t.Context(), makeObject("", "ConfigMap"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(keep).Should(BeFalse())
+	})
+}
+
+func TestIn(t *testing.T) {
+
+	t.Run("should match any of the groups", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := group.In("apps", group.Core)
+
+		for apiVersion, expected := range map[string]bool{
+			"apps/v1":                      true,
+			"v1":                           true,
+			"batch/v1":                     false,
+			"rbac.authorization.k8s.io/v1": false,
+		} {
+			keep, err := f(t.Context(), makeObject(apiVersion, "Any"))
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(keep).Should(Equal(expected), apiVersion)
+		}
+	})
+
+	t.Run("should match nothing without groups", func(t *testing.T) {
+		g := NewWithT(t)
+
+		keep, err := group.In()(t.Context(), makeObject("v1", "ConfigMap"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(keep).Should(BeFalse())
+	})
+}
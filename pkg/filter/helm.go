@@ -0,0 +1,15 @@
+package filter
+
+import (
+	"github.com/k8s-manifest-kit/engine/pkg/filter/meta/annotations"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// AnnotationHelmHook is the annotation marking Helm hook and test resources.
+const AnnotationHelmHook = "helm.sh/hook"
+
+// ExcludeHelmHooks returns a filter that drops Helm hook and test resources, i.e. objects
+// annotated with helm.sh/hook, which are not part of a chart's regular manifests.
+func ExcludeHelmHooks() types.Filter {
+	return annotations.Exclude(AnnotationHelmHook)
+}
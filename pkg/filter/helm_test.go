@@ -0,0 +1,33 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestExcludeHelmHooks(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should drop hook and test resources", func(t *testing.T) {
+		for _, hook := range []string{"pre-install", "post-upgrade", "test"} {
+			pod := makePod("hook")
+			pod.SetAnnotations(map[string]string{
+				filter.AnnotationHelmHook:    hook,
+				"helm.sh/hook-delete-policy": "hook-succeeded",
+			})
+
+			ok, err := filter.ExcludeHelmHooks()(t.Context(), pod)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(ok).Should(BeFalse())
+		}
+	})
+
+	t.Run("should keep regular resources", func(t *testing.T) {
+		ok, err := filter.ExcludeHelmHooks()(t.Context(), makePod("regular"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+}
@@ -0,0 +1,86 @@
+// Package jsonpath provides filters matching objects by Kubernetes JSONPath expressions,
+// with the syntax of kubectl -o jsonpath.
+package jsonpath
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Exists returns a filter that keeps objects for which path resolves to a non-empty result.
+// The path may be given with or without the enclosing braces, e.g. "{.spec.replicas}" or ".spec.replicas".
+// Missing fields are not an error, they simply produce an empty result.
+func Exists(path string) (types.Filter, error) {
+	eval, err := compile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		result, err := eval(obj)
+		if err != nil {
+			return false, err
+		}
+
+		return result != "", nil
+	}, nil
+}
+
+// Equals returns a filter that keeps objects for which the stringified result of path equals value.
+// Multiple results, e.g. from a wildcard, are joined by a space as with kubectl -o jsonpath.
+// A missing field produces an empty result, so Equals(path, "") also matches objects without it.
+func Equals(path string, value string) (types.Filter, error) {
+	eval, err := compile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		result, err := eval(obj)
+		if err != nil {
+			return false, err
+		}
+
+		return result == value, nil
+	}, nil
+}
+
+// compile parses path and returns a function evaluating it against an object.
+func compile(path string) (func(obj unstructured.Unstructured) (string, error), error) {
+	template := strings.TrimSpace(path)
+	if !strings.HasPrefix(template, "{") {
+		template = "{" + template + "}"
+	}
+
+	jp := jsonpath.New(path).AllowMissingKeys(true)
+	if err := jp.Parse(template); err != nil {
+		return nil, fmt.Errorf("invalid jsonpath %q: %w", path, err)
+	}
+
+	// A JSONPath keeps evaluation state, serialize executions so the filter is safe for concurrent use.
+	var mu sync.Mutex
+
+	return func(obj unstructured.Unstructured) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var buf bytes.Buffer
+		if err := jp.Execute(&buf, obj.Object); err != nil {
+			return "", &filter.Error{
+				Object: obj,
+				Err:    fmt.Errorf("error executing jsonpath %q: %w", path, err),
+			}
+		}
+
+		return buf.String(), nil
+	}, nil
+}
@@ -0,0 +1,113 @@
+package jsonpath_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter/jsonpath"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestExists(t *testing.T) {
+
+	t.Run("should keep objects with the field", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f, err := jsonpath.Exists("{.spec.replicas}")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ok, err := f(t.Context(), makeDeployment(3))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should drop objects without the field", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f, err := jsonpath.Exists(".spec.template.spec.nodeSelector")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ok, err := f(t.Context(), makeDeployment(3))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should match filter expressions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f, err := jsonpath.Exists(`{.spec.template.spec.containers[?(@.image=="nginx:1.27")].name}`)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ok, err := f(t.Context(), makeDeployment(3))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should fail on an invalid expression", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := jsonpath.Exists("{.spec[}")
+		g.Expect(err).Should(MatchError(ContainSubstring("invalid jsonpath")))
+	})
+}
+
+func TestEquals(t *testing.T) {
+
+	t.Run("should compare the stringified result", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f, err := jsonpath.Equals(".spec.replicas", "3")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ok, err := f(t.Context(), makeDeployment(3))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+
+		ok, err = f(t.Context(), makeDeployment(1))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should join multiple results with a space", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f, err := jsonpath.Equals("{.spec.template.spec.containers[*].name}", "web sidecar")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ok, err := f(t.Context(), makeDeployment(3))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should fail on an invalid expression", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := jsonpath.Equals("{.metadata.name", "web")
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
+
+func makeDeployment(replicas int64) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name": "web",
+			},
+			"spec": map[string]any{
+				"replicas": replicas,
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{"name": "web", "image": "nginx:1.27"},
+							map[string]any{"name": "sidecar", "image": "envoy:1.31"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
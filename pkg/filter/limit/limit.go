@@ -0,0 +1,46 @@
+// Package limit provides filters guarding against renders producing too many objects.
+package limit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// ErrMaxObjectsExceeded is returned by MaxObjects once more objects than allowed reached it.
+var ErrMaxObjectsExceeded = errors.New("maximum number of objects exceeded")
+
+// MaxObjects returns a filter that keeps objects until more than n of them have reached it during
+// a single render, and then fails the render with an error wrapping ErrMaxObjectsExceeded.
+// It is a safety valve against runaway renderers: only the objects reaching the filter are counted,
+// so its position among other filters matters.
+//
+// The count is kept in the types.RenderState of the render, so each Render() call starts from zero
+// even though the filter is created once. Outside the engine, when the context carries no
+// RenderState, the count spans the lifetime of the filter.
+//
+// The count is shared by all renderers of a render. With WithParallel, renderers run concurrently:
+// the count stays exact, but which object, and therefore which renderer, crosses the limit first
+// is not deterministic.
+func MaxObjects(n int) types.Filter {
+	// the counter used outside the engine also serves as the unique key of the filter's state
+	standalone := new(atomic.Int64)
+
+	return func(ctx context.Context, _ unstructured.Unstructured) (bool, error) {
+		counter := standalone
+		if state, ok := types.RenderStateFrom(ctx); ok {
+			counter, _ = state.Load(standalone, func() any { return new(atomic.Int64) }).(*atomic.Int64)
+		}
+
+		if counter.Add(1) > int64(n) {
+			return false, fmt.Errorf("%w: more than %d objects", ErrMaxObjectsExceeded, n)
+		}
+
+		return true, nil
+	}
+}
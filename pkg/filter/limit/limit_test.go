@@ -0,0 +1,79 @@
+package limit_test
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/enginetest"
+	"github.com/k8s-manifest-kit/engine/pkg/filter/limit"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeConfigMaps(prefix string, n int) []unstructured.Unstructured {
+	objects := make([]unstructured.Unstructured, 0, n)
+	for i := range n {
+		objects = append(objects, unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": fmt.Sprintf("%s-%d", prefix, i)},
+		}})
+	}
+
+	return objects
+}
+
+func TestMaxObjects(t *testing.T) {
+
+	t.Run("should keep objects up to the limit on every render", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(enginetest.StaticRenderer("static", makeConfigMaps("cm", 3)...)),
+			engine.WithFilter(limit.MaxObjects(3)),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		// each render starts from a fresh count
+		for range 3 {
+			objects, err := e.Render(t.Context())
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(objects).Should(HaveLen(3))
+		}
+	})
+
+	t.Run("should fail once the limit is exceeded across renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, parallel := range []bool{false, true} {
+			e, err := engine.New(
+				engine.WithRenderer(enginetest.StaticRenderer("first", makeConfigMaps("a", 2)...)),
+				engine.WithRenderer(enginetest.StaticRenderer("second", makeConfigMaps("b", 2)...)),
+				engine.WithFilter(limit.MaxObjects(3)),
+				engine.WithParallel(parallel),
+			)
+			g.Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = e.Render(t.Context())
+			g.Expect(err).Should(MatchError(limit.ErrMaxObjectsExceeded))
+			g.Expect(err).Should(MatchError(ContainSubstring("more than 3 objects")))
+		}
+	})
+
+	t.Run("should count for the lifetime of the filter outside the engine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		filter := limit.MaxObjects(1)
+		objects := makeConfigMaps("cm", 2)
+
+		keep, err := filter(t.Context(), objects[0])
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(keep).Should(BeTrue())
+
+		_, err = filter(t.Context(), objects[1])
+		g.Expect(err).Should(MatchError(limit.ErrMaxObjectsExceeded))
+	})
+}
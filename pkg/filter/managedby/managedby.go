@@ -0,0 +1,48 @@
+// Package managedby provides filters selecting objects by the controller managing them or the
+// renderer that produced them, to render a subset of the objects for targeted reconciliation.
+package managedby
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// DefaultKey is the recommended Kubernetes label naming the tool managing an object.
+const DefaultKey = "app.kubernetes.io/managed-by"
+
+// Is returns a filter that keeps objects managed by manager: objects whose managed-by key
+// (DefaultKey unless set with WithKey) equals manager. The key is looked up in the labels first,
+// then in the annotations. Objects without the key are dropped.
+func Is(manager string, opts ...Option) types.Filter {
+	options := Options{
+		Key: DefaultKey,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		if value, ok := obj.GetLabels()[options.Key]; ok {
+			return value == manager, nil
+		}
+
+		value, ok := obj.GetAnnotations()[options.Key]
+
+		return ok && value == manager, nil
+	}
+}
+
+// Source returns a filter that keeps objects produced by a renderer of the given type, i.e. whose
+// types.AnnotationSourceType annotation equals sourceType. The annotation is set by renderers or by
+// the engine with WithProvenance; objects without it are dropped.
+func Source(sourceType string) types.Filter {
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		value, ok := obj.GetAnnotations()[types.AnnotationSourceType]
+
+		return ok && value == sourceType, nil
+	}
+}
@@ -0,0 +1,29 @@
+package managedby
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the Is filter.
+type Options struct {
+	// Key is the label or annotation key naming the manager of an object.
+	// Defaults to DefaultKey.
+	Key string
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Key != "" {
+		target.Key = opts.Key
+	}
+}
+
+// WithKey sets the label or annotation key naming the manager of an object.
+func WithKey(key string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Key = key
+	})
+}
@@ -0,0 +1,117 @@
+package managedby_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+	"github.com/k8s-manifest-kit/engine/pkg/filter/managedby"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestIs(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		opts        []managedby.Option
+		labels      map[string]string
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name:     "should keep objects labeled with the manager",
+			labels:   map[string]string{managedby.DefaultKey: "argocd"},
+			expected: true,
+		},
+		{
+			name:     "should drop objects managed by another manager",
+			labels:   map[string]string{managedby.DefaultKey: "helm"},
+			expected: false,
+		},
+		{
+			name:        "should fall back to annotations",
+			annotations: map[string]string{managedby.DefaultKey: "argocd"},
+			expected:    true,
+		},
+		{
+			name:        "should prefer the label over the annotation",
+			labels:      map[string]string{managedby.DefaultKey: "helm"},
+			annotations: map[string]string{managedby.DefaultKey: "argocd"},
+			expected:    false,
+		},
+		{
+			name:     "should drop objects without the key",
+			labels:   map[string]string{"app": "web"},
+			expected: false,
+		},
+		{
+			name:     "should use a custom key",
+			opts:     []managedby.Option{managedby.WithKey("example.com/owner")},
+			labels:   map[string]string{"example.com/owner": "argocd", managedby.DefaultKey: "helm"},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := managedby.Is("argocd", tt.opts...)(t.Context(), makeObject(tt.labels, tt.annotations))
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(ok).Should(Equal(tt.expected))
+		})
+	}
+}
+
+func TestSource(t *testing.T) {
+
+	t.Run("should keep objects produced by the renderer type", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ok, err := managedby.Source("helm")(t.Context(), makeObject(nil, map[string]string{
+			types.AnnotationSourceType: "helm",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should drop objects produced by other renderers or without provenance", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ok, err := managedby.Source("helm")(t.Context(), makeObject(nil, map[string]string{
+			types.AnnotationSourceType: "kustomize",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+
+		ok, err = managedby.Source("helm")(t.Context(), makeObject(nil, nil))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should combine with Is", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := filter.And(managedby.Is("argocd"), managedby.Source("helm"))
+
+		ok, err := f(t.Context(), makeObject(
+			map[string]string{managedby.DefaultKey: "argocd"},
+			map[string]string{types.AnnotationSourceType: "helm"},
+		))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+}
+
+func makeObject(labels map[string]string, annotations map[string]string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName("config")
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+
+	return obj
+}
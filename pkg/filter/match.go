@@ -0,0 +1,117 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter/meta/annotations"
+	"github.com/k8s-manifest-kit/engine/pkg/filter/meta/labels"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// ErrMatchEmpty is returned for a Match that has no field set, which would otherwise match every object.
+var ErrMatchEmpty = errors.New("match must set at least one field")
+
+// Match describes the objects a filter keeps declaratively, e.g. when loaded from a YAML configuration.
+// All the fields that are set must match; empty fields are ignored.
+type Match struct {
+	// Group is the API group of the objects, e.g. "apps". The core group cannot be selected this way,
+	// as its name is empty, and Version "v1" is no substitute as it also matches groups such as
+	// apps/v1: combine the filter with group.Is(group.Core) to keep core objects only.
+	Group string `json:"group,omitempty"`
+
+	// Version is the API version of the objects without the group, e.g. "v1".
+	Version string `json:"version,omitempty"`
+
+	// Kind is the kind of the objects, e.g. "Deployment".
+	Kind string `json:"kind,omitempty"`
+
+	// Namespace is the namespace of the objects.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the objects.
+	Name string `json:"name,omitempty"`
+
+	// Labels are label key-values the objects must all have.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are annotation key-values the objects must all have.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Validate returns ErrMatchEmpty if no field of m is set.
+func (m Match) Validate() error {
+	if m.Group == "" && m.Version == "" && m.Kind == "" && m.Namespace == "" && m.Name == "" &&
+		len(m.Labels) == 0 && len(m.Annotations) == 0 {
+		return ErrMatchEmpty
+	}
+
+	return nil
+}
+
+// FromMatch returns a filter that keeps objects matching all the fields set in m.
+// If m is invalid, see Match.Validate, the filter returns the validation error for every object
+// so that the render fails instead of silently keeping or dropping everything.
+func FromMatch(m Match) types.Filter {
+	if err := m.Validate(); err != nil {
+		return failed(err)
+	}
+
+	matchLabels := labels.MatchLabels(m.Labels)
+	matchAnnotations := annotations.MatchAnnotations(m.Annotations)
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (bool, error) {
+		gvk := obj.GroupVersionKind()
+
+		switch {
+		case m.Group != "" && gvk.Group != m.Group:
+			return false, nil
+		case m.Version != "" && gvk.Version != m.Version:
+			return false, nil
+		case m.Kind != "" && gvk.Kind != m.Kind:
+			return false, nil
+		case m.Namespace != "" && obj.GetNamespace() != m.Namespace:
+			return false, nil
+		case m.Name != "" && obj.GetName() != m.Name:
+			return false, nil
+		}
+
+		ok, err := matchLabels(ctx, obj)
+		if err != nil || !ok {
+			return false, err
+		}
+
+		return matchAnnotations(ctx, obj)
+	}
+}
+
+// MatchAny returns a filter that keeps objects matching any of matches, see FromMatch.
+// If matches is empty, or one of them is invalid, the filter returns the validation error for
+// every object.
+func MatchAny(matches []Match) types.Filter {
+	if len(matches) == 0 {
+		return failed(ErrMatchEmpty)
+	}
+
+	filters := make([]types.Filter, 0, len(matches))
+
+	for i, m := range matches {
+		if err := m.Validate(); err != nil {
+			return failed(fmt.Errorf("match[%d]: %w", i, err))
+		}
+
+		filters = append(filters, FromMatch(m))
+	}
+
+	return Or(filters...)
+}
+
+// failed returns a filter that fails on every object with err.
+func failed(err error) types.Filter {
+	return func(_ context.Context, _ unstructured.Unstructured) (bool, error) {
+		return false, err
+	}
+}
@@ -0,0 +1,141 @@
+package filter_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFromMatch(t *testing.T) {
+
+	deployment := func(namespace string, name string) unstructured.Unstructured {
+		obj := unstructured.Unstructured{}
+		obj.SetAPIVersion("apps/v1")
+		obj.SetKind("Deployment")
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetLabels(map[string]string{"app": name, "tier": "frontend"})
+		obj.SetAnnotations(map[string]string{"owner": "team-a"})
+
+		return obj
+	}
+
+	tests := []struct {
+		name     string
+		match    filter.Match
+		obj      unstructured.Unstructured
+		expected bool
+	}{
+		{
+			name:     "should match on group, version and kind",
+			match:    filter.Match{Group: "apps", Version: "v1", Kind: "Deployment"},
+			obj:      deployment("prod", "web"),
+			expected: true,
+		},
+		{
+			name:     "should reject another kind",
+			match:    filter.Match{Kind: "StatefulSet"},
+			obj:      deployment("prod", "web"),
+			expected: false,
+		},
+		{
+			name:     "should reject another group",
+			match:    filter.Match{Group: "batch"},
+			obj:      deployment("prod", "web"),
+			expected: false,
+		},
+		{
+			name:     "should match the core group by version",
+			match:    filter.Match{Version: "v1", Kind: "Pod"},
+			obj:      makePod("pod1"),
+			expected: true,
+		},
+		{
+			name:     "should match on namespace and name",
+			match:    filter.Match{Namespace: "prod", Name: "web"},
+			obj:      deployment("prod", "web"),
+			expected: true,
+		},
+		{
+			name:     "should reject another namespace",
+			match:    filter.Match{Namespace: "dev", Name: "web"},
+			obj:      deployment("prod", "web"),
+			expected: false,
+		},
+		{
+			name:     "should match on a subset of labels and annotations",
+			match:    filter.Match{Labels: map[string]string{"tier": "frontend"}, Annotations: map[string]string{"owner": "team-a"}},
+			obj:      deployment("prod", "web"),
+			expected: true,
+		},
+		{
+			name:     "should reject a missing label",
+			match:    filter.Match{Kind: "Deployment", Labels: map[string]string{"tier": "backend"}},
+			obj:      deployment("prod", "web"),
+			expected: false,
+		},
+		{
+			name:     "should reject a missing annotation",
+			match:    filter.Match{Annotations: map[string]string{"owner": "team-b"}},
+			obj:      deployment("prod", "web"),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ok, err := filter.FromMatch(tt.match)(t.Context(), tt.obj)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(ok).Should(Equal(tt.expected))
+		})
+	}
+
+	t.Run("should fail on an empty match", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(filter.Match{}.Validate()).Should(MatchError(filter.ErrMatchEmpty))
+		g.Expect(filter.Match{Labels: map[string]string{}}.Validate()).Should(MatchError(filter.ErrMatchEmpty))
+
+		_, err := filter.FromMatch(filter.Match{})(t.Context(), makePod("pod1"))
+		g.Expect(err).Should(MatchError(filter.ErrMatchEmpty))
+	})
+}
+
+func TestMatchAny(t *testing.T) {
+
+	t.Run("should keep objects matching any match", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := filter.MatchAny([]filter.Match{
+			{Name: "pod1"},
+			{Name: "pod2"},
+		})
+
+		for name, expected := range map[string]bool{"pod1": true, "pod2": true, "pod3": false} {
+			ok, err := f(t.Context(), makePod(name))
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(ok).Should(Equal(expected), name)
+		}
+	})
+
+	t.Run("should fail on an empty list", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := filter.MatchAny(nil)(t.Context(), makePod("pod1"))
+		g.Expect(err).Should(MatchError(filter.ErrMatchEmpty))
+	})
+
+	t.Run("should fail on an empty match", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := filter.MatchAny([]filter.Match{{Name: "pod1"}, {}})(t.Context(), makePod("pod1"))
+		g.Expect(err).Should(MatchError(filter.ErrMatchEmpty))
+		g.Expect(err).Should(MatchError(ContainSubstring("match[1]")))
+	})
+}
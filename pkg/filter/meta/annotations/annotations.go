@@ -2,6 +2,9 @@ package annotations
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"regexp"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
@@ -45,3 +48,63 @@ func MatchAnnotations(matchAnnotations map[string]string) types.Filter {
 		return true, nil
 	}
 }
+
+// Exclude returns a filter that drops objects that have the specified annotation key, whatever its value.
+func Exclude(key string) types.Filter {
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		_, ok := obj.GetAnnotations()[key]
+
+		return !ok, nil
+	}
+}
+
+// ExcludeValue returns a filter that drops objects whose annotation key is set to value.
+// Objects without the annotation, or with a different value, are kept.
+func ExcludeValue(key string, value string) types.Filter {
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		objValue, ok := obj.GetAnnotations()[key]
+
+		return !ok || objValue != value, nil
+	}
+}
+
+// KeyMatches returns a filter that keeps objects having at least one annotation whose key matches the
+// glob pattern, using path.Match syntax: "foo.example.com/*" matches every annotation under that prefix,
+// while "*" does not match across "/". It returns an error if the pattern is malformed.
+// To drop the matching objects instead, combine it with filter.Not.
+func KeyMatches(pattern string) (types.Filter, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+	}
+
+	return keyMatches(func(key string) bool {
+		ok, _ := path.Match(pattern, key)
+
+		return ok
+	}), nil
+}
+
+// KeyRegex returns a filter that keeps objects having at least one annotation whose key matches the
+// regular expression pattern, e.g. `^(foo|bar)\.example\.com/`. It returns an error if the pattern
+// does not compile. To drop the matching objects instead, combine it with filter.Not.
+func KeyRegex(pattern string) (types.Filter, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %w", err)
+	}
+
+	return keyMatches(re.MatchString), nil
+}
+
+// keyMatches returns a filter that keeps objects having an annotation whose key satisfies match.
+func keyMatches(match func(key string) bool) types.Filter {
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		for key := range obj.GetAnnotations() {
+			if match(key) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
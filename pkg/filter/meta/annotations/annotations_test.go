@@ -6,6 +6,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
 	"github.com/k8s-manifest-kit/engine/pkg/filter/meta/annotations"
 
 	. "github.com/onsi/gomega"
@@ -99,6 +100,143 @@ func TestMatchAnnotations(t *testing.T) {
 	})
 }
 
+func TestExclude(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should drop objects with the annotation", func(t *testing.T) {
+		filter := annotations.Exclude("helm.sh/hook")
+
+		ok, err := filter(t.Context(), makePodWithAnnotations(map[string]string{
+			"helm.sh/hook": "pre-install",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should keep objects without the annotation", func(t *testing.T) {
+		filter := annotations.Exclude("helm.sh/hook")
+
+		ok, err := filter(t.Context(), makePodWithAnnotations(map[string]string{
+			"other": "value",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should handle objects with no annotations", func(t *testing.T) {
+		filter := annotations.Exclude("helm.sh/hook")
+
+		ok, err := filter(t.Context(), makePodWithAnnotations(nil))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+}
+
+func TestExcludeValue(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should drop objects with the annotation value", func(t *testing.T) {
+		filter := annotations.ExcludeValue("helm.sh/hook", "test")
+
+		ok, err := filter(t.Context(), makePodWithAnnotations(map[string]string{
+			"helm.sh/hook": "test",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should keep objects with a different value", func(t *testing.T) {
+		filter := annotations.ExcludeValue("helm.sh/hook", "test")
+
+		ok, err := filter(t.Context(), makePodWithAnnotations(map[string]string{
+			"helm.sh/hook": "pre-install",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should keep objects without the annotation", func(t *testing.T) {
+		filter := annotations.ExcludeValue("helm.sh/hook", "test")
+
+		ok, err := filter(t.Context(), makePodWithAnnotations(nil))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+}
+
+func TestKeyMatches(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should keep objects with an annotation under the prefix", func(t *testing.T) {
+		filter, err := annotations.KeyMatches("experimental.example.com/*")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ok, err := filter(t.Context(), makePodWithAnnotations(map[string]string{
+			"other":                           "value",
+			"experimental.example.com/canary": "true",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should exclude objects without a matching annotation", func(t *testing.T) {
+		filter, err := annotations.KeyMatches("experimental.example.com/*")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ok, err := filter(t.Context(), makePodWithAnnotations(map[string]string{
+			"example.com/canary": "true",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+
+		ok, err = filter(t.Context(), makePodWithAnnotations(nil))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should drop matching objects when negated", func(t *testing.T) {
+		match, err := annotations.KeyMatches("experimental.example.com/*")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ok, err := filter.Not(match)(t.Context(), makePodWithAnnotations(map[string]string{
+			"experimental.example.com/canary": "true",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should reject a malformed pattern", func(t *testing.T) {
+		_, err := annotations.KeyMatches("example.com/[")
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
+
+func TestKeyRegex(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should keep objects with a matching annotation", func(t *testing.T) {
+		filter, err := annotations.KeyRegex(`^(alpha|beta)\.example\.com/`)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ok, err := filter(t.Context(), makePodWithAnnotations(map[string]string{
+			"beta.example.com/feature": "on",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+
+		ok, err = filter(t.Context(), makePodWithAnnotations(map[string]string{
+			"gamma.example.com/feature": "on",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should reject an invalid pattern", func(t *testing.T) {
+		_, err := annotations.KeyRegex("(")
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
+
 // Helper function
 
 func makePodWithAnnotations(anns map[string]string) unstructured.Unstructured {
@@ -2,6 +2,7 @@ package namespace
 
 import (
 	"context"
+	"slices"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -27,3 +28,48 @@ func Exclude(namespaces ...string) types.Filter {
 		return !excluded.Has(obj.GetNamespace()), nil
 	}
 }
+
+// Allow returns a filter that keeps only objects in the specified namespaces, like Filter, with
+// explicit control over cluster-scoped objects, hence the namespaces given as a slice followed by
+// options. Cluster-scoped objects, i.e. objects with an empty namespace, are dropped unless
+// WithClusterScoped(true) is given, whether or not namespaces lists the empty namespace.
+func Allow(namespaces []string, opts ...Option) types.Filter {
+	options := Options{
+		ClusterScoped: false,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return Filter(withClusterScoped(namespaces, options.ClusterScoped)...)
+}
+
+// Deny returns a filter that drops objects in the specified namespaces, like Exclude, with explicit
+// control over cluster-scoped objects, hence the namespaces given as a slice followed by options.
+// Cluster-scoped objects, i.e. objects with an empty namespace, are kept unless
+// WithClusterScoped(false) is given, whether or not namespaces lists the empty namespace.
+func Deny(namespaces []string, opts ...Option) types.Filter {
+	options := Options{
+		ClusterScoped: true,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return Exclude(withClusterScoped(namespaces, !options.ClusterScoped)...)
+}
+
+// withClusterScoped returns namespaces without the empty namespace, with it appended if add is set.
+func withClusterScoped(namespaces []string, add bool) []string {
+	result := slices.DeleteFunc(slices.Clone(namespaces), func(ns string) bool {
+		return ns == ""
+	})
+
+	if add {
+		result = append(result, "")
+	}
+
+	return result
+}
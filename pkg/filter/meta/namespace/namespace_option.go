@@ -0,0 +1,27 @@
+package namespace
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the Allow and Deny filters.
+type Options struct {
+	// ClusterScoped controls whether objects with an empty namespace are kept.
+	ClusterScoped bool
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.ClusterScoped = opts.ClusterScoped
+}
+
+// WithClusterScoped controls whether objects with an empty namespace, such as cluster-scoped
+// objects, are kept (true) or dropped (false) by Allow and Deny.
+func WithClusterScoped(keep bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.ClusterScoped = keep
+	})
+}
@@ -105,6 +105,78 @@ func TestExclude(t *testing.T) {
 	})
 }
 
+func TestAllow(t *testing.T) {
+
+	t.Run("should keep only objects in allowed namespaces", func(t *testing.T) {
+		g := NewWithT(t)
+
+		filter := namespace.Allow([]string{defaultNS, prodNS})
+
+		ok, err := filter(t.Context(), makePodInNamespace("test", prodNS))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+
+		ok, err = filter(t.Context(), makePodInNamespace("test", systemNS))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should drop cluster-scoped objects by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ok, err := namespace.Allow([]string{defaultNS})(t.Context(), makePodInNamespace("test", ""))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+
+		filter := namespace.Allow([]string{defaultNS}, namespace.WithClusterScoped(true))
+		ok, err = filter(t.Context(), makePodInNamespace("test", ""))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should ignore the empty namespace in favour of the option", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ok, err := namespace.Allow([]string{""})(t.Context(), makePodInNamespace("test", ""))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+
+		ok, err = namespace.Deny([]string{""})(t.Context(), makePodInNamespace("test", ""))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+}
+
+func TestDeny(t *testing.T) {
+
+	t.Run("should drop objects in denied namespaces", func(t *testing.T) {
+		g := NewWithT(t)
+
+		filter := namespace.Deny([]string{systemNS})
+
+		ok, err := filter(t.Context(), makePodInNamespace("test", systemNS))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+
+		ok, err = filter(t.Context(), makePodInNamespace("test", defaultNS))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should keep cluster-scoped objects by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ok, err := namespace.Deny([]string{systemNS})(t.Context(), makePodInNamespace("test", ""))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+
+		filter := namespace.Deny([]string{systemNS}, namespace.WithClusterScoped(false))
+		ok, err = filter(t.Context(), makePodInNamespace("test", ""))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+}
+
 // Helper functions
 
 //nolint:unparam // Test helper needs consistent signature
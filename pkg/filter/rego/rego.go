@@ -0,0 +1,84 @@
+// Package rego provides a filter evaluating objects against an Open Policy Agent Rego policy.
+package rego
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/open-policy-agent/opa/v1/rego"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+const moduleName = "filter.rego"
+
+var (
+	// ErrRegoMustReturnBoolean is returned when a Rego query doesn't return a boolean.
+	ErrRegoMustReturnBoolean = errors.New("rego query must return a boolean")
+)
+
+// Filter creates a filter evaluating query against the Rego module, with each object as input.
+//
+// The module is compiled and the query prepared once, when the filter is created. An object is kept
+// when the query returns true and denied when it returns false or is undefined, e.g. for a rule
+// without a default value. A denied object is dropped, or fails the render with WithAbort.
+//
+// Example:
+//
+//	f, err := rego.Filter(`
+//		package k8s
+//
+//		default allow := false
+//
+//		allow if input.metadata.labels.team == "platform"
+//	`, "data.k8s.allow")
+func Filter(module string, query string, opts ...Option) (types.Filter, error) {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	prepared, err := rego.New(
+		rego.Query(query),
+		rego.Module(moduleName, module),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error compiling rego module: %w", err)
+	}
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (bool, error) {
+		results, err := prepared.Eval(ctx, rego.EvalInput(obj.Object))
+		if err != nil {
+			return false, &filter.Error{
+				Object: obj,
+				Err:    fmt.Errorf("error evaluating rego query: %w", err),
+			}
+		}
+
+		allowed := false
+
+		if len(results) > 0 && len(results[0].Expressions) > 0 {
+			v := results[0].Expressions[0].Value
+
+			b, ok := v.(bool)
+			if !ok {
+				return false, &filter.Error{
+					Object: obj,
+					Err:    fmt.Errorf("%w, got %T", ErrRegoMustReturnBoolean, v),
+				}
+			}
+
+			allowed = b
+		}
+
+		if !allowed && options.Abort {
+			return false, filter.Abort(fmt.Sprintf("denied by rego query %s", query))
+		}
+
+		return allowed, nil
+	}, nil
+}
@@ -0,0 +1,29 @@
+package rego
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of a Rego filter.
+type Options struct {
+	// Abort makes a denied object fail the render with a filter.Abort error instead of being dropped.
+	Abort bool
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Abort {
+		target.Abort = opts.Abort
+	}
+}
+
+// WithAbort makes a denied object fail the render with a filter.Abort error instead of being dropped,
+// turning the filter into a policy gate.
+func WithAbort(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Abort = enabled
+	})
+}
@@ -0,0 +1,101 @@
+package rego_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+	"github.com/k8s-manifest-kit/engine/pkg/filter/rego"
+
+	. "github.com/onsi/gomega"
+)
+
+const policy = `
+package k8s
+
+default allow := false
+
+allow if input.metadata.labels.team == "platform"
+
+undefined if input.kind == "Service"
+
+name := input.metadata.name
+`
+
+func makePod(name string, labels map[string]string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+	}}
+	obj.SetLabels(labels)
+
+	return obj
+}
+
+func TestFilter(t *testing.T) {
+
+	t.Run("should keep objects allowed by the policy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f, err := rego.Filter(policy, "data.k8s.allow")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		keep, err := f(t.Context(), makePod("pod1", map[string]string{"team": "platform"}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(keep).Should(BeTrue())
+
+		keep, err = f(t.Context(), makePod("pod2", map[string]string{"team": "web"}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(keep).Should(BeFalse())
+	})
+
+	t.Run("should drop objects for which the query is undefined", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f, err := rego.Filter(policy, "data.k8s.undefined")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		keep, err := f(t.Context(), makePod("pod1", nil))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(keep).Should(BeFalse())
+	})
+
+	t.Run("should abort on denied objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f, err := rego.Filter(policy, "data.k8s.allow", rego.WithAbort(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		keep, err := f(t.Context(), makePod("pod1", map[string]string{"team": "platform"}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(keep).Should(BeTrue())
+
+		keep, err = f(t.Context(), makePod("pod2", nil))
+		g.Expect(keep).Should(BeFalse())
+		g.Expect(filter.IsAbort(err)).Should(BeTrue())
+		g.Expect(err).Should(MatchError(ContainSubstring("denied by rego query data.k8s.allow")))
+	})
+
+	t.Run("should fail on a non-boolean result", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f, err := rego.Filter(policy, "data.k8s.name")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = f(t.Context(), makePod("pod1", nil))
+		g.Expect(err).Should(MatchError(rego.ErrRegoMustReturnBoolean))
+
+		filterErr, ok := filter.AsError(err)
+		g.Expect(ok).Should(BeTrue())
+		g.Expect(filterErr.Object.GetName()).Should(Equal("pod1"))
+	})
+
+	t.Run("should fail on an invalid module", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := rego.Filter("package k8s\n\nallow if {", "data.k8s.allow")
+		g.Expect(err).Should(MatchError(ContainSubstring("error compiling rego module")))
+	})
+}
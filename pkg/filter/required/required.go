@@ -0,0 +1,54 @@
+// Package required provides filters enforcing the presence of metadata on every object.
+package required
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Labels returns a filter that keeps objects carrying all the given label keys and aborts the render
+// on the first object missing any of them, with a filter.Abort error listing the missing keys.
+// Through the engine, the error identifies the offending object (see filter.AsError).
+func Labels(keys ...string) types.Filter {
+	return require("labels", keys, func(obj unstructured.Unstructured) map[string]string {
+		return obj.GetLabels()
+	})
+}
+
+// Annotations returns a filter that keeps objects carrying all the given annotation keys and aborts
+// the render on the first object missing any of them, like Labels.
+func Annotations(keys ...string) types.Filter {
+	return require("annotations", keys, func(obj unstructured.Unstructured) map[string]string {
+		return obj.GetAnnotations()
+	})
+}
+
+func require(
+	field string,
+	keys []string,
+	values func(obj unstructured.Unstructured) map[string]string,
+) types.Filter {
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		present := values(obj)
+
+		var missing []string
+
+		for _, key := range keys {
+			if _, ok := present[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+
+		if len(missing) > 0 {
+			return false, filter.Abort(fmt.Sprintf("missing required %s: %s", field, strings.Join(missing, ", ")))
+		}
+
+		return true, nil
+	}
+}
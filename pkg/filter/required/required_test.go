@@ -0,0 +1,91 @@
+package required_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter"
+	"github.com/k8s-manifest-kit/engine/pkg/filter/required"
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	labelName   = "app.kubernetes.io/name"
+	labelPartOf = "app.kubernetes.io/part-of"
+)
+
+func makePod(name string, labels map[string]string, annotations map[string]string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+	}}
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+
+	return obj
+}
+
+func TestLabels(t *testing.T) {
+
+	t.Run("should keep objects carrying all required labels", func(t *testing.T) {
+		g := NewWithT(t)
+
+		keep, err := required.Labels(labelName, labelPartOf)(t.Context(), makePod("pod1", map[string]string{
+			labelName:   "web",
+			labelPartOf: "shop",
+			"extra":     "value",
+		}, nil))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(keep).Should(BeTrue())
+	})
+
+	t.Run("should abort with the missing labels", func(t *testing.T) {
+		g := NewWithT(t)
+
+		keep, err := required.Labels(labelName, labelPartOf, "team")(t.Context(), makePod("pod1", map[string]string{
+			labelName: "web",
+		}, nil))
+		g.Expect(keep).Should(BeFalse())
+		g.Expect(filter.IsAbort(err)).Should(BeTrue())
+		g.Expect(err).Should(MatchError(ContainSubstring("missing required labels: app.kubernetes.io/part-of, team")))
+	})
+
+	t.Run("should identify the offending object in the pipeline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := pipeline.ApplyFilters(t.Context(), []unstructured.Unstructured{
+			makePod("pod1", map[string]string{labelName: "web", labelPartOf: "shop"}, nil),
+			makePod("pod2", map[string]string{labelName: "api"}, nil),
+		}, []types.Filter{required.Labels(labelName, labelPartOf)})
+		g.Expect(filter.IsAbort(err)).Should(BeTrue())
+
+		filterErr, ok := filter.AsError(err)
+		g.Expect(ok).Should(BeTrue())
+		g.Expect(filterErr.Object.GetName()).Should(Equal("pod2"))
+	})
+}
+
+func TestAnnotations(t *testing.T) {
+
+	t.Run("should keep objects carrying all required annotations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		keep, err := required.Annotations("owner")(t.Context(), makePod("pod1", nil, map[string]string{"owner": "team-a"}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(keep).Should(BeTrue())
+	})
+
+	t.Run("should abort with the missing annotations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		keep, err := required.Annotations("owner")(t.Context(), makePod("pod1", map[string]string{"owner": "team-a"}, nil))
+		g.Expect(keep).Should(BeFalse())
+		g.Expect(err).Should(MatchError(filter.ErrAbort))
+		g.Expect(err).Should(MatchError(ContainSubstring("missing required annotations: owner")))
+	})
+}
@@ -0,0 +1,113 @@
+// Package prometheus provides an engine.Metrics exporting render measurements as Prometheus metrics.
+package prometheus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+)
+
+const (
+	labelRenderer = "renderer"
+	labelStage    = "stage"
+)
+
+var _ engine.Metrics = (*Metrics)(nil)
+
+// Metrics implements engine.Metrics with Prometheus collectors:
+//   - render_duration_seconds: histogram of the duration of successful renders
+//   - render_objects: histogram of the number of objects returned by successful renders
+//   - renderer_duration_seconds: histogram of the duration of renderer executions, by renderer
+//   - renderer_errors_total: counter of failed renderer executions, by renderer
+//   - filter_drops_total: counter of objects dropped by filters, by stage ("engine" or "render")
+type Metrics struct {
+	renderDuration   prometheus.Histogram
+	renderObjects    prometheus.Histogram
+	rendererDuration *prometheus.HistogramVec
+	rendererErrors   *prometheus.CounterVec
+	filterDrops      *prometheus.CounterVec
+}
+
+// New returns Metrics whose collectors are registered with registerer, e.g. prometheus.DefaultRegisterer.
+// Metric names are prefixed with DefaultNamespace unless set otherwise with WithNamespace, so that
+// several engines can register with the same registerer using different namespaces.
+func New(registerer prometheus.Registerer, opts ...Option) (*Metrics, error) {
+	options := Options{
+		Namespace: DefaultNamespace,
+		Buckets:   prometheus.DefBuckets,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	m := Metrics{
+		renderDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: options.Namespace,
+			Name:      "render_duration_seconds",
+			Help:      "Duration of successful renders.",
+			Buckets:   options.Buckets,
+		}),
+		renderObjects: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: options.Namespace,
+			Name:      "render_objects",
+			Help:      "Number of objects returned by successful renders.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+		}),
+		rendererDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: options.Namespace,
+			Name:      "renderer_duration_seconds",
+			Help:      "Duration of renderer executions.",
+			Buckets:   options.Buckets,
+		}, []string{labelRenderer}),
+		rendererErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: options.Namespace,
+			Name:      "renderer_errors_total",
+			Help:      "Number of failed renderer executions.",
+		}, []string{labelRenderer}),
+		filterDrops: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: options.Namespace,
+			Name:      "filter_drops_total",
+			Help:      "Number of objects dropped by engine-level and render-time filters.",
+		}, []string{labelStage}),
+	}
+
+	collectors := []prometheus.Collector{
+		m.renderDuration,
+		m.renderObjects,
+		m.rendererDuration,
+		m.rendererErrors,
+		m.filterDrops,
+	}
+
+	for _, c := range collectors {
+		if err := registerer.Register(c); err != nil {
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+
+	return &m, nil
+}
+
+// ObserveRender implements engine.Metrics.
+func (m *Metrics) ObserveRender(duration time.Duration, objectCount int) {
+	m.renderDuration.Observe(duration.Seconds())
+	m.renderObjects.Observe(float64(objectCount))
+}
+
+// ObserveRenderer implements engine.Metrics.
+func (m *Metrics) ObserveRenderer(name string, duration time.Duration, err error) {
+	m.rendererDuration.WithLabelValues(name).Observe(duration.Seconds())
+
+	if err != nil {
+		m.rendererErrors.WithLabelValues(name).Inc()
+	}
+}
+
+// IncFilterDrop implements engine.Metrics.
+func (m *Metrics) IncFilterDrop(stage engine.FilterStage) {
+	m.filterDrops.WithLabelValues(string(stage)).Inc()
+}
@@ -0,0 +1,46 @@
+package prometheus
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// DefaultNamespace is the default namespace of the metric names.
+const DefaultNamespace = "manifest_engine"
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the Prometheus metrics.
+type Options struct {
+	// Namespace prefixes the metric names, e.g. "manifest_engine_render_duration_seconds".
+	Namespace string
+
+	// Buckets are the upper bounds, in seconds, of the buckets of the duration histograms.
+	// If empty, prometheus.DefBuckets is used.
+	Buckets []float64
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Namespace != "" {
+		target.Namespace = opts.Namespace
+	}
+
+	if len(opts.Buckets) > 0 {
+		target.Buckets = opts.Buckets
+	}
+}
+
+// WithNamespace sets the namespace prefixing the metric names.
+func WithNamespace(namespace string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Namespace = namespace
+	})
+}
+
+// WithBuckets sets the upper bounds, in seconds, of the buckets of the duration histograms.
+func WithBuckets(buckets ...float64) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Buckets = buckets
+	})
+}
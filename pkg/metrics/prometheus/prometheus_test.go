@@ -0,0 +1,112 @@
+package prometheus_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/enginetest"
+	"github.com/k8s-manifest-kit/engine/pkg/filter/meta/name"
+	"github.com/k8s-manifest-kit/engine/pkg/metrics/prometheus"
+
+	. "github.com/onsi/gomega"
+)
+
+const manifests = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`
+
+func TestNew(t *testing.T) {
+
+	t.Run("should register the collectors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		registry := prom.NewPedanticRegistry()
+
+		m, err := prometheus.New(registry)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		m.ObserveRender(time.Second, 3)
+		m.ObserveRenderer("yaml", time.Second, nil)
+		m.IncFilterDrop(engine.FilterStageEngine)
+
+		count, err := testutil.GatherAndCount(registry)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(count).Should(Equal(4))
+	})
+
+	t.Run("should fail to register twice with the same namespace", func(t *testing.T) {
+		g := NewWithT(t)
+
+		registry := prom.NewRegistry()
+
+		_, err := prometheus.New(registry)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = prometheus.New(registry)
+		g.Expect(err).Should(HaveOccurred())
+
+		_, err = prometheus.New(registry, prometheus.WithNamespace("other"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+}
+
+func TestMetrics(t *testing.T) {
+
+	t.Run("should count renderer errors and filter drops of engine renders", func(t *testing.T) {
+		g := NewWithT(t)
+
+		registry := prom.NewRegistry()
+
+		m, err := prometheus.New(registry, prometheus.WithNamespace("test"), prometheus.WithBuckets(0.1, 1))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		e, err := engine.New(
+			engine.WithRenderer(enginetest.FromYAML("yaml", manifests)),
+			engine.WithFilter(name.Exact("first")),
+			engine.WithMetrics(m),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		failing, err := engine.New(
+			engine.WithRenderer(enginetest.ErrRenderer("broken", errors.New("boom"))),
+			engine.WithMetrics(m),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = failing.Render(t.Context())
+		g.Expect(err).Should(HaveOccurred())
+
+		expected := `
+# HELP test_filter_drops_total Number of objects dropped by engine-level and render-time filters.
+# TYPE test_filter_drops_total counter
+test_filter_drops_total{stage="engine"} 1
+# HELP test_renderer_errors_total Number of failed renderer executions.
+# TYPE test_renderer_errors_total counter
+test_renderer_errors_total{renderer="broken"} 1
+`
+		err = testutil.GatherAndCompare(registry, strings.NewReader(expected),
+			"test_filter_drops_total", "test_renderer_errors_total")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		count, err := testutil.GatherAndCount(registry, "test_renderer_duration_seconds")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(count).Should(Equal(2))
+	})
+}
@@ -0,0 +1,142 @@
+package order
+
+import (
+	"cmp"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// kinds lists well-known kinds in the order they should be applied to a cluster:
+// namespaces and cluster-wide definitions first, then configuration and RBAC,
+// then workloads, and finally objects that route traffic to or intercept requests for them.
+//
+//nolint:gochecknoglobals
+var kinds = []string{
+	"Namespace",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"CustomResourceDefinition",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"APIService",
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+}
+
+//nolint:gochecknoglobals
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// Rank returns the apply position of kind. Lower ranks are applied first.
+// Kinds not in the well-known list share the highest rank.
+func Rank(kind string) int {
+	if i := slices.Index(kinds, kind); i >= 0 {
+		return i
+	}
+
+	return len(kinds)
+}
+
+// Compare orders two objects by apply order.
+// Objects are compared by kind rank, then kind name (so unknown kinds are grouped),
+// then namespace and finally name, which makes the resulting order deterministic.
+func Compare(a unstructured.Unstructured, b unstructured.Unstructured) int {
+	return compare(a, b, nil)
+}
+
+// Sort returns a copy of objects sorted by apply order (see Compare).
+// The input slice is not modified.
+//
+// Sort is aware of the CustomResourceDefinitions among objects: the custom resources whose
+// GroupVersionKind is defined by one of them (from its spec.group, spec.names.kind and
+// spec.versions) are always placed after all CRDs, even when their kind shares the name of a
+// well-known kind ranked earlier. Objects matching no rendered CRD keep their normal position.
+func Sort(objects []unstructured.Unstructured) []unstructured.Unstructured {
+	sorted := slices.Clone(objects)
+	defined := definedKinds(sorted)
+
+	slices.SortStableFunc(sorted, func(a unstructured.Unstructured, b unstructured.Unstructured) int {
+		return compare(a, b, defined)
+	})
+
+	return sorted
+}
+
+// compare implements Compare, ranking the custom resources of defined kinds as unknown kinds.
+func compare(a unstructured.Unstructured, b unstructured.Unstructured, defined sets.Set[schema.GroupVersionKind]) int {
+	return cmp.Or(
+		cmp.Compare(rank(a, defined), rank(b, defined)),
+		cmp.Compare(a.GetKind(), b.GetKind()),
+		cmp.Compare(a.GetNamespace(), b.GetNamespace()),
+		cmp.Compare(a.GetName(), b.GetName()),
+	)
+}
+
+func rank(obj unstructured.Unstructured, defined sets.Set[schema.GroupVersionKind]) int {
+	if defined.Has(obj.GroupVersionKind()) {
+		return len(kinds)
+	}
+
+	return Rank(obj.GetKind())
+}
+
+// definedKinds returns the GroupVersionKinds defined by the CustomResourceDefinitions among objects.
+func definedKinds(objects []unstructured.Unstructured) sets.Set[schema.GroupVersionKind] {
+	defined := sets.New[schema.GroupVersionKind]()
+
+	for _, obj := range objects {
+		if obj.GroupVersionKind().GroupKind() != crdGroupKind {
+			continue
+		}
+
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+
+		if group == "" || kind == "" {
+			continue
+		}
+
+		versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+		for _, v := range versions {
+			version, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			if name, ok := version["name"].(string); ok && name != "" {
+				defined.Insert(schema.GroupVersionKind{Group: group, Version: name, Kind: kind})
+			}
+		}
+
+		// apiextensions.k8s.io/v1beta1 CRDs may declare a single version
+		if version, _, _ := unstructured.NestedString(obj.Object, "spec", "version"); version != "" {
+			defined.Insert(schema.GroupVersionKind{Group: group, Version: version, Kind: kind})
+		}
+	}
+
+	return defined
+}
@@ -0,0 +1,139 @@
+package order_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/order"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRank(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should rank namespaces before workloads", func(t *testing.T) {
+		g.Expect(order.Rank("Namespace")).Should(BeNumerically("<", order.Rank("Deployment")))
+	})
+
+	t.Run("should rank CRDs before workloads", func(t *testing.T) {
+		g.Expect(order.Rank("CustomResourceDefinition")).Should(BeNumerically("<", order.Rank("Deployment")))
+	})
+
+	t.Run("should rank unknown kinds last", func(t *testing.T) {
+		g.Expect(order.Rank("Widget")).Should(BeNumerically(">", order.Rank("ValidatingWebhookConfiguration")))
+		g.Expect(order.Rank("Widget")).Should(Equal(order.Rank("Gadget")))
+	})
+}
+
+func TestSort(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should sort by apply order, then kind, namespace and name", func(t *testing.T) {
+		objects := []unstructured.Unstructured{
+			makeObject("Widget", "b", "ns1"),
+			makeObject("Deployment", "web", "ns2"),
+			makeObject("Deployment", "api", "ns2"),
+			makeObject("Gadget", "a", "ns1"),
+			makeObject("Deployment", "web", "ns1"),
+			makeObject("Namespace", "ns1", ""),
+		}
+
+		sorted := order.Sort(objects)
+		g.Expect(identities(sorted)).Should(Equal([]string{
+			"Namespace//ns1",
+			"Deployment/ns1/web",
+			"Deployment/ns2/api",
+			"Deployment/ns2/web",
+			"Gadget/ns1/a",
+			"Widget/ns1/b",
+		}))
+	})
+
+	t.Run("should place custom resources after the CRD defining them", func(t *testing.T) {
+		crd := makeObject("CustomResourceDefinition", "secrets.example.com", "")
+		crd.SetAPIVersion("apiextensions.k8s.io/v1")
+		crd.Object["spec"] = map[string]any{
+			"group": "example.com",
+			"names": map[string]any{"kind": "Secret"},
+			"versions": []any{
+				map[string]any{"name": "v1"},
+			},
+		}
+
+		// a custom resource whose kind would otherwise be ranked before CRDs
+		defined := makeObject("Secret", "custom", "ns1")
+		defined.SetAPIVersion("example.com/v1")
+
+		// a version the CRD does not define keeps its normal position
+		undefined := makeObject("Secret", "other", "ns1")
+		undefined.SetAPIVersion("example.com/v2")
+
+		sorted := order.Sort([]unstructured.Unstructured{
+			defined,
+			makeObject("Deployment", "web", "ns1"),
+			undefined,
+			crd,
+			makeObject("Secret", "creds", "ns1"),
+		})
+		g.Expect(identities(sorted)).Should(Equal([]string{
+			"Secret/ns1/creds",
+			"Secret/ns1/other",
+			"CustomResourceDefinition//secrets.example.com",
+			"Deployment/ns1/web",
+			"Secret/ns1/custom",
+		}))
+	})
+
+	t.Run("should read the single version of v1beta1 CRDs", func(t *testing.T) {
+		crd := makeObject("CustomResourceDefinition", "namespaces.example.com", "")
+		crd.SetAPIVersion("apiextensions.k8s.io/v1beta1")
+		crd.Object["spec"] = map[string]any{
+			"group":   "example.com",
+			"version": "v1alpha1",
+			"names":   map[string]any{"kind": "Namespace"},
+		}
+
+		cr := makeObject("Namespace", "custom", "")
+		cr.SetAPIVersion("example.com/v1alpha1")
+
+		sorted := order.Sort([]unstructured.Unstructured{cr, crd})
+		g.Expect(identities(sorted)).Should(Equal([]string{
+			"CustomResourceDefinition//namespaces.example.com",
+			"Namespace//custom",
+		}))
+	})
+
+	t.Run("should not modify the input", func(t *testing.T) {
+		objects := []unstructured.Unstructured{
+			makeObject("Deployment", "web", "ns1"),
+			makeObject("Namespace", "ns1", ""),
+		}
+
+		_ = order.Sort(objects)
+		g.Expect(identities(objects)).Should(Equal([]string{
+			"Deployment/ns1/web",
+			"Namespace//ns1",
+		}))
+	})
+}
+
+func identities(objects []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, obj.GetKind()+"/"+obj.GetNamespace()+"/"+obj.GetName())
+	}
+
+	return result
+}
+
+func makeObject(kind string, name string, namespace string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+
+	return obj
+}
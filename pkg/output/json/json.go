@@ -0,0 +1,45 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// WriteArray writes objects to w as a single JSON array followed by a newline.
+// The array is compact unless WithIndent is given. An empty input is written as "[]".
+func WriteArray(w io.Writer, objs []unstructured.Unstructured, opts ...Option) error {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	content := make([]map[string]any, 0, len(objs))
+	for _, obj := range objs {
+		content = append(content, obj.Object)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", options.Indent)
+
+	if err := enc.Encode(content); err != nil {
+		return fmt.Errorf("failed to write JSON array: %w", err)
+	}
+
+	return nil
+}
+
+// WriteLines writes objects to w as JSON Lines: one compact JSON object per line.
+func WriteLines(w io.Writer, objs []unstructured.Unstructured) error {
+	enc := json.NewEncoder(w)
+
+	for _, obj := range objs {
+		if err := enc.Encode(obj.Object); err != nil {
+			return fmt.Errorf("failed to write %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+		}
+	}
+
+	return nil
+}
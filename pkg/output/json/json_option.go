@@ -0,0 +1,29 @@
+package json
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the JSON writers.
+type Options struct {
+	// Indent is the per-level indentation used by WriteArray.
+	// If empty, the array is written in compact form.
+	Indent string
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Indent != "" {
+		target.Indent = opts.Indent
+	}
+}
+
+// WithIndent sets the per-level indentation used by WriteArray (e.g. "  ").
+func WithIndent(indent string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Indent = indent
+	})
+}
@@ -0,0 +1,96 @@
+package json_test
+
+import (
+	"bytes"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/output/json"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWriteArray(t *testing.T) {
+
+	t.Run("should write a compact array", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		err := json.WriteArray(&buf, []unstructured.Unstructured{
+			makeObject("Pod", "pod1"),
+			makeObject("Service", "svc1"),
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(Equal(
+			`[{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod1"}},` +
+				`{"apiVersion":"v1","kind":"Service","metadata":{"name":"svc1"}}]` + "\n",
+		))
+	})
+
+	t.Run("should write an empty array for no objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		err := json.WriteArray(&buf, nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(Equal("[]\n"))
+	})
+
+	t.Run("should indent when requested", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		err := json.WriteArray(&buf, []unstructured.Unstructured{makeObject("Pod", "pod1")}, json.WithIndent("  "))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(Equal(`[
+  {
+    "apiVersion": "v1",
+    "kind": "Pod",
+    "metadata": {
+      "name": "pod1"
+    }
+  }
+]
+`))
+	})
+}
+
+func TestWriteLines(t *testing.T) {
+
+	t.Run("should write one object per line", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		err := json.WriteLines(&buf, []unstructured.Unstructured{
+			makeObject("Pod", "pod1"),
+			makeObject("Service", "svc1"),
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(Equal(
+			`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod1"}}` + "\n" +
+				`{"apiVersion":"v1","kind":"Service","metadata":{"name":"svc1"}}` + "\n",
+		))
+	})
+
+	t.Run("should write nothing for no objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		err := json.WriteLines(&buf, nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(BeEmpty())
+	})
+}
+
+func makeObject(kind string, name string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata": map[string]any{
+				"name": name,
+			},
+		},
+	}
+}
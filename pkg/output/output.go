@@ -0,0 +1,51 @@
+// Package output provides the presentation of rendered objects: the writers of its subpackages
+// and the canonical form of objects used for reproducible output.
+package output
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/normalize"
+)
+
+// Canonicalize returns deep copies of objects in canonical form, so that the same manifests always
+// compare and serialize the same way whatever the renderer that produced them:
+//   - the server-populated fields of normalize.DefaultFields are removed
+//   - values are round-tripped through JSON, whose object keys are sorted, so numbers take a single
+//     representation (int64 for integers, float64 otherwise) and typed values become plain maps,
+//     slices and scalars
+//
+// Canonicalize is idempotent and never modifies its input. Unlike a transformer it is purely
+// presentational, meant to run on the final objects, e.g. before comparing them with golden files.
+// Objects that cannot be encoded to JSON are deep copied without their server-populated fields.
+func Canonicalize(objects []unstructured.Unstructured) []unstructured.Unstructured {
+	result := make([]unstructured.Unstructured, 0, len(objects))
+
+	for _, obj := range objects {
+		result = append(result, canonical(obj))
+	}
+
+	return result
+}
+
+// canonical returns the canonical form of obj.
+func canonical(obj unstructured.Unstructured) unstructured.Unstructured {
+	var out unstructured.Unstructured
+
+	data, err := obj.MarshalJSON()
+	if err == nil {
+		err = out.UnmarshalJSON(data)
+	}
+
+	if err != nil {
+		out = *obj.DeepCopy()
+	}
+
+	for _, field := range normalize.DefaultFields() {
+		unstructured.RemoveNestedField(out.Object, strings.Split(field, ".")...)
+	}
+
+	return out
+}
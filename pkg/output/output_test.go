@@ -0,0 +1,91 @@
+package output_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/output"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeDeployment() unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]any{
+			"name":            "web",
+			"namespace":       "default",
+			"uid":             "6f1c0f0e-0000-0000-0000-000000000000",
+			"resourceVersion": "12345",
+			"labels":          map[string]string{"app": "web"},
+		},
+		"spec": map[string]any{
+			"replicas": 3,
+			"template": map[string]any{
+				"spec": map[string]any{
+					"terminationGracePeriodSeconds": float64(30),
+					"containers":                    []map[string]any{{"name": "web", "image": "nginx"}},
+				},
+			},
+		},
+		"status": map[string]any{"replicas": int32(3)},
+	}}
+}
+
+func TestCanonicalize(t *testing.T) {
+
+	t.Run("should normalize values and strip server fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := output.Canonicalize([]unstructured.Unstructured{makeDeployment()})
+		g.Expect(objects).Should(HaveLen(1))
+
+		g.Expect(objects[0].Object).Should(Equal(map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name":      "web",
+				"namespace": "default",
+				"labels":    map[string]any{"app": "web"},
+			},
+			"spec": map[string]any{
+				"replicas": int64(3),
+				"template": map[string]any{
+					"spec": map[string]any{
+						"terminationGracePeriodSeconds": int64(30),
+						"containers":                    []any{map[string]any{"name": "web", "image": "nginx"}},
+					},
+				},
+			},
+		}))
+	})
+
+	t.Run("should be idempotent", func(t *testing.T) {
+		g := NewWithT(t)
+
+		once := output.Canonicalize([]unstructured.Unstructured{makeDeployment()})
+		twice := output.Canonicalize(once)
+
+		g.Expect(twice).Should(Equal(once))
+	})
+
+	t.Run("should not modify the input", func(t *testing.T) {
+		g := NewWithT(t)
+
+		in := []unstructured.Unstructured{makeDeployment()}
+
+		objects := output.Canonicalize(in)
+		g.Expect(in[0]).Should(Equal(makeDeployment()))
+
+		objects[0].SetName("changed")
+		g.Expect(in[0].GetName()).Should(Equal("web"))
+	})
+
+	t.Run("should return an empty slice for no objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(output.Canonicalize(nil)).Should(BeEmpty())
+	})
+}
@@ -0,0 +1,145 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/order"
+	"github.com/k8s-manifest-kit/engine/pkg/util/comments"
+)
+
+const separator = "---\n"
+
+// Write writes objects to w as a multi-document YAML stream, with documents separated by "---".
+// Keys within each document are sorted, so the output is stable for a given set of objects.
+// The input objects are never modified.
+func Write(w io.Writer, objects []unstructured.Unstructured, opts ...Option) error {
+	enc := NewEncoder(w, opts...)
+
+	if enc.options.Sort {
+		objects = order.Sort(objects)
+	}
+
+	for _, obj := range objects {
+		if err := enc.Encode(obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Encoder writes objects to w as a multi-document YAML stream, one object at a time.
+// The Sort option is ignored, as objects are written in the order they are encoded.
+type Encoder struct {
+	w       io.Writer
+	options Options
+	count   int
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer, opts ...Option) *Encoder {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return &Encoder{
+		w:       w,
+		options: options,
+	}
+}
+
+// Encode writes obj as the next document of the stream, preceded by a "---" separator
+// unless it is the first one. The types.AnnotationSourceComments annotation is never written.
+// The object is never modified.
+func (e *Encoder) Encode(obj unstructured.Unstructured) error {
+	content, document := comments.Detach(obj)
+	if e.options.StripNoise {
+		content = stripNoise(unstructured.Unstructured{Object: content})
+	}
+
+	var data []byte
+	var err error
+
+	if (e.options.Comments || e.options.Anchors) && document != "" {
+		data, err = e.marshalWithSource(content, document)
+	} else {
+		data, err = yaml.Marshal(content)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, separator); err != nil {
+			return fmt.Errorf("failed to write document separator: %w", err)
+		}
+	}
+
+	if _, err := e.w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	e.count++
+
+	return nil
+}
+
+// marshalWithSource marshals content with the comments and anchors of its source document, as enabled.
+func (e *Encoder) marshalWithSource(content map[string]any, document string) ([]byte, error) {
+	node := &yamlv3.Node{}
+
+	if e.options.Comments {
+		restored, err := comments.Restore(content, document)
+		if err != nil {
+			return nil, err
+		}
+
+		node = restored
+	} else if err := node.Encode(content); err != nil {
+		return nil, err
+	}
+
+	if e.options.Anchors {
+		if err := comments.RestoreAnchors(node, document); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+
+	enc := yamlv3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	enc.CompactSeqIndent()
+
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// stripNoise returns a copy of the object content without status and null creationTimestamp.
+func stripNoise(obj unstructured.Unstructured) map[string]any {
+	content := obj.DeepCopy().Object
+
+	delete(content, "status")
+
+	if ts, found, _ := unstructured.NestedFieldNoCopy(content, "metadata", "creationTimestamp"); found && ts == nil {
+		unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+	}
+
+	return content
+}
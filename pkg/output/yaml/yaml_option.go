@@ -0,0 +1,73 @@
+package yaml
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the YAML writer.
+type Options struct {
+	// Sort writes objects in apply order (see the order package) instead of input order.
+	Sort bool
+
+	// StripNoise removes fields that are meaningless in a manifest file:
+	// status and a null metadata.creationTimestamp.
+	StripNoise bool
+
+	// Comments re-emits the comments of the source documents of objects decoded by a renderer
+	// preserving them (e.g. fsys.WithComments).
+	Comments bool
+
+	// Anchors re-emits the anchors and aliases of the source documents of objects decoded by a renderer
+	// preserving them (e.g. fsys.WithComments). By default, output is fully expanded.
+	Anchors bool
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.Sort = opts.Sort
+	target.StripNoise = opts.StripNoise
+	target.Comments = opts.Comments
+	target.Anchors = opts.Anchors
+}
+
+// WithSort enables or disables sorting objects in apply order before writing.
+func WithSort(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Sort = enabled
+	})
+}
+
+// WithStripNoise enables or disables removal of status and null metadata.creationTimestamp.
+// Objects built from typed Go structs commonly carry both, which adds noise to GitOps diffs.
+func WithStripNoise(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.StripNoise = enabled
+	})
+}
+
+// WithComments enables or disables re-emitting the comments of source documents, recorded by renderers
+// such as fsys with WithComments(true). Preservation is best-effort: comments are matched to the fields
+// of each object by path, so an untransformed object keeps all its comments, while comments on fields
+// removed or renamed by transformers are lost. Objects carrying comments are written with yaml.v3
+// rather than sigs.k8s.io/yaml, so scalar quoting may differ slightly from other documents.
+func WithComments(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Comments = enabled
+	})
+}
+
+// WithAnchors enables or disables re-emitting the anchors and aliases of source documents. It is disabled
+// by default, which guarantees fully expanded output: objects are anchor-expanded when decoded, and the
+// engine never carries anchors through the unstructured round-trip. Anchors can only be restored for
+// objects carrying their source document, recorded by renderers such as fsys with WithComments(true).
+// An alias is restored where the rendered value still equals the anchored one, otherwise it stays
+// expanded; merge keys ("<<") are always expanded. Such objects are written with yaml.v3, as with
+// WithComments.
+func WithAnchors(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Anchors = enabled
+	})
+}
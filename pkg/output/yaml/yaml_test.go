@@ -0,0 +1,316 @@
+package yaml_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/output/yaml"
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/fsys"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWrite(t *testing.T) {
+
+	t.Run("should write documents separated by ---", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		err := yaml.Write(&buf, []unstructured.Unstructured{
+			makeObject("Deployment", "web"),
+			makeObject("Namespace", "ns1"),
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(Equal(`apiVersion: v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ns1
+`))
+	})
+
+	t.Run("should write nothing for no objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		err := yaml.Write(&buf, nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(BeEmpty())
+	})
+
+	t.Run("should sort keys", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := makeObject("ConfigMap", "cfg")
+		obj.Object["data"] = map[string]any{"z": "1", "a": "2", "m": "3"}
+
+		var buf bytes.Buffer
+		err := yaml.Write(&buf, []unstructured.Unstructured{obj})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(HavePrefix(`apiVersion: v1
+data:
+  a: "2"
+  m: "3"
+  z: "1"
+kind: ConfigMap
+`))
+	})
+
+	t.Run("should sort documents in apply order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		err := yaml.Write(&buf, []unstructured.Unstructured{
+			makeObject("Deployment", "web"),
+			makeObject("Namespace", "ns1"),
+		}, yaml.WithSort(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(HavePrefix(`apiVersion: v1
+kind: Namespace
+`))
+	})
+
+	t.Run("should strip status and null creationTimestamp", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := makeObject("Deployment", "web")
+		obj.Object["status"] = map[string]any{"replicas": int64(1)}
+		obj.Object["metadata"].(map[string]any)["creationTimestamp"] = nil
+
+		var buf bytes.Buffer
+		err := yaml.Write(&buf, []unstructured.Unstructured{obj}, yaml.WithStripNoise(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(Equal(`apiVersion: v1
+kind: Deployment
+metadata:
+  name: web
+`))
+
+		// the input object must be left untouched
+		g.Expect(obj.Object).Should(HaveKey("status"))
+		g.Expect(obj.Object["metadata"]).Should(HaveKey("creationTimestamp"))
+	})
+
+	t.Run("should keep a non-null creationTimestamp", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := makeObject("Deployment", "web")
+		obj.Object["metadata"].(map[string]any)["creationTimestamp"] = "2024-01-01T00:00:00Z"
+
+		var buf bytes.Buffer
+		err := yaml.Write(&buf, []unstructured.Unstructured{obj}, yaml.WithStripNoise(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(ContainSubstring("creationTimestamp"))
+	})
+}
+
+func TestEncoder(t *testing.T) {
+
+	t.Run("should separate documents across Encode calls", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf, yaml.WithStripNoise(true))
+
+		g.Expect(enc.Encode(makeObject("Deployment", "web"))).Should(Succeed())
+		g.Expect(buf.String()).ShouldNot(HavePrefix("---"))
+
+		obj := makeObject("Namespace", "ns1")
+		obj.Object["status"] = map[string]any{"phase": "Active"}
+
+		g.Expect(enc.Encode(obj)).Should(Succeed())
+		g.Expect(buf.String()).Should(Equal(`apiVersion: v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ns1
+`))
+	})
+}
+
+func TestWithComments(t *testing.T) {
+
+	const commented = `# Application configuration
+apiVersion: v1
+data:
+  # one of debug or info
+  level: info
+  ports:
+  - "8080" # http
+  - "9090"
+kind: ConfigMap
+metadata:
+  labels:
+    app: web
+  name: app # overridden by overlays
+`
+
+	render := func(g *WithT, opts ...fsys.Option) []unstructured.Unstructured {
+		fs := fstest.MapFS{"app.yaml": {Data: []byte(commented)}}
+
+		r, err := fsys.New(fs, "*.yaml", append([]fsys.Option{fsys.WithComments(true)}, opts...)...)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		return objects
+	}
+
+	t.Run("should round-trip an unmodified document with its comments", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		err := yaml.Write(&buf, render(g), yaml.WithComments(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(Equal(commented))
+	})
+
+	t.Run("should keep the comments of untouched fields of transformed objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := render(g, fsys.WithTransformer(
+			func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				unstructured.RemoveNestedField(obj.Object, "data", "level")
+				obj.SetNamespace("prod")
+
+				return obj, nil
+			},
+		))
+
+		var buf bytes.Buffer
+		err := yaml.Write(&buf, objects, yaml.WithComments(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(ContainSubstring("# Application configuration"))
+		g.Expect(buf.String()).Should(ContainSubstring("name: app # overridden by overlays"))
+		g.Expect(buf.String()).Should(ContainSubstring("namespace: prod"))
+		g.Expect(buf.String()).ShouldNot(ContainSubstring("debug or info"))
+	})
+
+	t.Run("should never write the source document annotation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := render(g)
+		g.Expect(objects[0].GetAnnotations()).Should(HaveKey(types.AnnotationSourceComments))
+
+		var buf bytes.Buffer
+		err := yaml.Write(&buf, objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).ShouldNot(ContainSubstring("#"))
+		g.Expect(buf.String()).ShouldNot(ContainSubstring("annotations"))
+		g.Expect(objects[0].GetAnnotations()).Should(HaveKey(types.AnnotationSourceComments))
+	})
+}
+
+func TestWithAnchors(t *testing.T) {
+
+	const anchored = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  base: &defaults
+    level: info
+    format: json
+  web: *defaults
+  worker:
+    <<: *defaults
+    level: debug
+  ports: &ports ["8080", "9090"]
+  probes: *ports
+`
+
+	render := func(g *WithT, opts ...fsys.Option) []unstructured.Unstructured {
+		fs := fstest.MapFS{"app.yaml": {Data: []byte(anchored)}}
+
+		r, err := fsys.New(fs, "*.yaml", append([]fsys.Option{fsys.WithComments(true)}, opts...)...)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		return objects
+	}
+
+	t.Run("should write fully expanded output by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, opts := range [][]yaml.Option{nil, {yaml.WithComments(true)}, {yaml.WithAnchors(false)}} {
+			var buf bytes.Buffer
+			err := yaml.Write(&buf, render(g), opts...)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(buf.String()).ShouldNot(ContainSubstring("&"))
+			g.Expect(buf.String()).ShouldNot(ContainSubstring("*"))
+			g.Expect(buf.String()).Should(ContainSubstring("web:\n    format: json\n    level: info\n"))
+		}
+	})
+
+	t.Run("should restore the anchors of unchanged values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		err := yaml.Write(&buf, render(g), yaml.WithAnchors(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).Should(Equal(`apiVersion: v1
+data:
+  base: &defaults
+    format: json
+    level: info
+  ports: &ports
+  - "8080"
+  - "9090"
+  probes: *ports
+  web: *defaults
+  worker:
+    format: json
+    level: debug
+kind: ConfigMap
+metadata:
+  name: app
+`))
+	})
+
+	t.Run("should keep modified aliased values expanded", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := render(g, fsys.WithTransformer(
+			func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				err := unstructured.SetNestedField(obj.Object, "text", "data", "web", "format")
+
+				return obj, err
+			},
+		))
+
+		var buf bytes.Buffer
+		err := yaml.Write(&buf, objects, yaml.WithAnchors(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(buf.String()).ShouldNot(ContainSubstring("&defaults"))
+		g.Expect(buf.String()).Should(ContainSubstring("web:\n    format: text\n    level: info\n"))
+		g.Expect(buf.String()).Should(ContainSubstring("probes: *ports"))
+	})
+}
+
+func makeObject(kind string, name string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata": map[string]any{
+				"name": name,
+			},
+		},
+	}
+}
@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -9,6 +10,7 @@ import (
 	"github.com/k8s-manifest-kit/engine/pkg/filter"
 	"github.com/k8s-manifest-kit/engine/pkg/transformer"
 	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/validator"
 )
 
 // ApplyFilters applies a series of filters to objects, returning only those that match all filters.
@@ -48,6 +50,7 @@ func ApplyFilters(
 }
 
 // ApplyTransformers applies a series of transformers to objects, transforming each object sequentially.
+// Each object is deep copied before the first transformer runs, so the input objects are never mutated.
 // Returns Error with detailed context if any transformer fails.
 func ApplyTransformers(
 	ctx context.Context,
@@ -61,7 +64,9 @@ func ApplyTransformers(
 	transformed := make([]unstructured.Unstructured, 0, len(objects))
 
 	for _, obj := range objects {
-		result := obj
+		// Transformers commonly mutate the object's maps in place; work on a deep copy so
+		// that objects still referenced elsewhere (e.g. retained by a renderer) are never modified.
+		result := *obj.DeepCopy()
 		for _, t := range transformers {
 			r, err := t(ctx, result)
 			if err != nil {
@@ -77,6 +82,32 @@ func ApplyTransformers(
 	return transformed, nil
 }
 
+// ApplyValidators runs a series of validators against every object.
+// Unlike filters and transformers, validation does not stop at the first failure:
+// all objects are checked and every failure is returned, joined, as an Error
+// identifying the offending object.
+func ApplyValidators(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+	validators []types.Validator,
+) error {
+	if len(validators) == 0 {
+		return nil
+	}
+
+	var errs []error
+
+	for _, obj := range objects {
+		for _, v := range validators {
+			if err := v(ctx, obj); err != nil {
+				errs = append(errs, validator.Wrap(obj, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // Apply executes a filter and transformer pipeline on the given objects.
 // It applies filters first, then transformers, returning the transformed objects.
 // Callers should wrap returned errors with appropriate context.
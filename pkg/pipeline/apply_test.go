@@ -11,6 +11,7 @@ import (
 	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
 	"github.com/k8s-manifest-kit/engine/pkg/transformer"
 	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/validator"
 
 	. "github.com/onsi/gomega"
 )
@@ -303,6 +304,23 @@ func TestApplyTransformers(t *testing.T) {
 		g.Expect(result).To(HaveLen(1))
 		g.Expect(result[0].GetAnnotations()).To(HaveKeyWithValue("key", "overwritten"))
 	})
+	t.Run("should not mutate input objects", func(t *testing.T) {
+		g := NewWithT(t)
+		objects := []unstructured.Unstructured{
+			makeObject("Pod", "pod1"),
+		}
+
+		setLabel := func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+			obj.SetLabels(map[string]string{"transformed": labelValueTrue})
+
+			return obj, nil
+		}
+
+		result, err := pipeline.ApplyTransformers(ctx, objects, []types.Transformer{setLabel})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result[0].GetLabels()).To(HaveKeyWithValue("transformed", labelValueTrue))
+		g.Expect(objects[0].GetLabels()).To(BeEmpty())
+	})
 }
 
 func TestFilterError(t *testing.T) {
@@ -574,6 +592,63 @@ func TestApply(t *testing.T) {
 	})
 }
 
+func TestApplyValidators(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("should succeed when no validators", func(t *testing.T) {
+		g := NewWithT(t)
+		objects := []unstructured.Unstructured{
+			makeObject("Pod", "pod1"),
+		}
+
+		err := pipeline.ApplyValidators(ctx, objects, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("should succeed when all objects are valid", func(t *testing.T) {
+		g := NewWithT(t)
+		objects := []unstructured.Unstructured{
+			makeObject("Pod", "pod1"),
+			makeObject("Service", "svc1"),
+		}
+
+		acceptAll := func(_ context.Context, _ unstructured.Unstructured) error {
+			return nil
+		}
+
+		err := pipeline.ApplyValidators(ctx, objects, []types.Validator{acceptAll})
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("should report every invalid object", func(t *testing.T) {
+		g := NewWithT(t)
+		objects := []unstructured.Unstructured{
+			makeObject("Pod", "pod1"),
+			makeObject("Service", "svc1"),
+			makeObject("Pod", "pod2"),
+		}
+
+		rejectPods := func(_ context.Context, obj unstructured.Unstructured) error {
+			if obj.GetKind() == kindPod {
+				return errors.New("pods are not allowed")
+			}
+
+			return nil
+		}
+
+		err := pipeline.ApplyValidators(ctx, objects, []types.Validator{rejectPods})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("pod1"))
+		g.Expect(err.Error()).To(ContainSubstring("pod2"))
+		g.Expect(err.Error()).ToNot(ContainSubstring("svc1"))
+
+		var validatorErr *validator.Error
+		g.Expect(errors.As(err, &validatorErr)).To(BeTrue())
+		g.Expect(validatorErr.Object.GetName()).To(Equal("pod1"))
+		g.Expect(validatorErr.Err).To(MatchError("pods are not allowed"))
+	})
+}
+
 // Helper functions
 
 func makeObject(kind string, name string) unstructured.Unstructured {
@@ -0,0 +1,145 @@
+// Package auto provides a renderer constructor detecting the kind of a directory, for a
+// "just render this directory" experience.
+package auto
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/k8s-manifest-kit/engine/pkg/renderer"
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/fsys"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+const (
+	// KindHelm is the kind of a directory holding a Helm chart, i.e. a Chart.yaml file.
+	KindHelm = "helm"
+
+	// KindKustomize is the kind of a directory holding a kustomization file.
+	KindKustomize = "kustomize"
+
+	// KindYAML is the kind of any other directory, rendered as plain YAML and JSON manifests.
+	KindYAML = "yaml"
+
+	// DefaultGlob selects the .yaml and .yml files of a plain YAML directory, at any depth.
+	DefaultGlob = "**/*.y*ml"
+)
+
+var (
+	// ErrAmbiguous is returned when a directory is both a Helm chart and a kustomization.
+	ErrAmbiguous = errors.New("directory is both a helm chart and a kustomization")
+
+	// ErrNotDirectory is returned when the path given to New is not a directory.
+	ErrNotDirectory = errors.New("not a directory")
+)
+
+// Factory creates the renderer of a directory.
+type Factory func(dir string) (types.Renderer, error)
+
+//nolint:gochecknoglobals
+var (
+	helmFiles      = []string{"Chart.yaml"}
+	kustomizeFiles = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+)
+
+// Detect returns the kind of the directory dir: KindHelm if it contains a Chart.yaml file,
+// KindKustomize if it contains a kustomization.yaml, kustomization.yml or Kustomization file,
+// and KindYAML otherwise. Only dir itself is inspected, not its subdirectories.
+func Detect(dir string) (string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("unable to inspect %s: %w", dir, err)
+	}
+
+	if !info.IsDir() {
+		return "", fmt.Errorf("%w: %s", ErrNotDirectory, dir)
+	}
+
+	helm, err := containsAny(dir, helmFiles)
+	if err != nil {
+		return "", err
+	}
+
+	kustomize, err := containsAny(dir, kustomizeFiles)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case helm && kustomize:
+		return "", fmt.Errorf("%w: %s", ErrAmbiguous, dir)
+	case helm:
+		return KindHelm, nil
+	case kustomize:
+		return KindKustomize, nil
+	default:
+		return KindYAML, nil
+	}
+}
+
+// New detects the kind of the directory dir (see Detect) and returns the appropriate renderer.
+//
+// Plain YAML directories are rendered by an fsys renderer reading the files matching DefaultGlob,
+// or the glob set with WithGlob. Helm charts and kustomizations are rendered by the factory set with
+// WithFactory or, by default, by the renderer registered under KindHelm or KindKustomize in the
+// renderer registry (see renderer.Register), created with the configuration {"path": dir}.
+func New(dir string, opts ...Option) (types.Renderer, error) {
+	options := Options{
+		Glob: DefaultGlob,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	kind, err := Detect(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	factory, ok := options.Factories[kind]
+	if !ok {
+		factory = defaultFactory(kind, options.Glob)
+	}
+
+	r, err := factory(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s renderer for %s: %w", kind, dir, err)
+	}
+
+	return r, nil
+}
+
+// defaultFactory returns the factory used for kind when none is set with WithFactory.
+func defaultFactory(kind string, glob string) Factory {
+	if kind == KindYAML {
+		return func(dir string) (types.Renderer, error) {
+			return fsys.New(os.DirFS(dir), glob)
+		}
+	}
+
+	return func(dir string) (types.Renderer, error) {
+		return renderer.New(kind, map[string]any{"path": dir})
+	}
+}
+
+// containsAny reports whether dir contains a regular file with one of the given names.
+func containsAny(dir string, names []string) (bool, error) {
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(dir, name))
+
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			continue
+		case err != nil:
+			return false, fmt.Errorf("unable to inspect %s: %w", dir, err)
+		case info.Mode().IsRegular():
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,54 @@
+package auto
+
+import (
+	"maps"
+
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the auto-detecting renderer constructor.
+type Options struct {
+	// Factories create the renderer of a kind of directory, keyed by KindHelm, KindKustomize or KindYAML,
+	// overriding the default ones.
+	Factories map[string]Factory
+
+	// Glob selects the files of a plain YAML directory. Defaults to DefaultGlob.
+	Glob string
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if len(opts.Factories) > 0 {
+		if target.Factories == nil {
+			target.Factories = make(map[string]Factory, len(opts.Factories))
+		}
+
+		maps.Copy(target.Factories, opts.Factories)
+	}
+
+	if opts.Glob != "" {
+		target.Glob = opts.Glob
+	}
+}
+
+// WithFactory sets the factory creating the renderer of the given kind of directory, e.g. to use a
+// Helm or Kustomize renderer without registering it, or to configure it.
+func WithFactory(kind string, factory Factory) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if o.Factories == nil {
+			o.Factories = make(map[string]Factory)
+		}
+
+		o.Factories[kind] = factory
+	})
+}
+
+// WithGlob sets the glob selecting the files of a plain YAML directory, see fsys.New.
+func WithGlob(glob string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Glob = glob
+	})
+}
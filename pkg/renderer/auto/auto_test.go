@@ -0,0 +1,125 @@
+package auto_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k8s-manifest-kit/engine/pkg/renderer"
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/auto"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestDetect(t *testing.T) {
+
+	t.Run("should detect the kind of a directory", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for kind, files := range map[string]map[string]string{
+			auto.KindHelm:      {"Chart.yaml": "name: web\n", "templates/cm.yaml": ""},
+			auto.KindKustomize: {"kustomization.yaml": "resources: []\n"},
+			auto.KindYAML:      {"cm.yaml": "", "nested/Chart.yaml": ""},
+		} {
+			detected, err := auto.Detect(makeDir(t, files))
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(detected).Should(Equal(kind))
+		}
+	})
+
+	t.Run("should reject an ambiguous directory", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := auto.Detect(makeDir(t, map[string]string{"Chart.yaml": "", "Kustomization": ""}))
+		g.Expect(err).Should(MatchError(auto.ErrAmbiguous))
+	})
+
+	t.Run("should reject a file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := makeDir(t, map[string]string{"cm.yaml": ""})
+
+		_, err := auto.Detect(filepath.Join(dir, "cm.yaml"))
+		g.Expect(err).Should(MatchError(auto.ErrNotDirectory))
+
+		_, err = auto.Detect(filepath.Join(dir, "missing"))
+		g.Expect(err).Should(MatchError(os.ErrNotExist))
+	})
+}
+
+func TestNew(t *testing.T) {
+
+	t.Run("should render a plain YAML directory", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := makeDir(t, map[string]string{
+			"a.yaml":          "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n",
+			"nested/b.yml":    "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n",
+			"nested/c.json":   `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "c"}}`,
+			"nested/notes.md": "# notes\n",
+		})
+
+		r, err := auto.New(dir)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetName()).Should(Equal("a"))
+		g.Expect(objects[1].GetName()).Should(Equal("b"))
+
+		r, err = auto.New(dir, auto.WithGlob("**/*.json"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err = r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("c"))
+	})
+
+	t.Run("should use the factory of the detected kind", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := makeDir(t, map[string]string{"Chart.yaml": "name: web\n"})
+
+		var received string
+
+		r, err := auto.New(dir, auto.WithFactory(auto.KindHelm, func(dir string) (types.Renderer, error) {
+			received = dir
+
+			return auto.New(makeDir(t, nil))
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(r).ShouldNot(BeNil())
+		g.Expect(received).Should(Equal(dir))
+	})
+
+	t.Run("should fail without a registered renderer for the detected kind", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := auto.New(makeDir(t, map[string]string{"kustomization.yaml": ""}))
+		g.Expect(err).Should(MatchError(renderer.ErrUnknownKind))
+		g.Expect(err).Should(MatchError(ContainSubstring("failed to create kustomize renderer")))
+	})
+}
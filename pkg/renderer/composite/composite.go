@@ -0,0 +1,114 @@
+// Package composite provides a renderer grouping several renderers into a single unit, with its own
+// name, that can be registered and reused across engines like any other renderer.
+package composite
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Renderer runs a group of renderers sequentially and concatenates their output.
+type Renderer struct {
+	name      string
+	renderers []types.Renderer
+}
+
+// New returns a renderer named name running rs sequentially, in order, each with the same values,
+// and returning their objects concatenated. The engine sees a single renderer: its objects are
+// attributed to name, e.g. for provenance, and filters and transformers scoped to it with
+// engine.WithRendererOptions apply to the output of the whole group.
+//
+// The error of a child stops the group and is returned as a types.RendererError naming the child.
+// Children implementing types.VersionAwareRenderer or types.TargetedRenderer are called accordingly
+// when the group is; other children are called with Process. The engine only treats the group as
+// version-aware or targeted when one of its children is (see types.Implements), so that, e.g.,
+// WithRequireNonEmpty still checks a group without targeted children in a partial render.
+// Children are not cached individually.
+func New(name string, rs ...types.Renderer) types.Renderer {
+	return &Renderer{
+		name:      name,
+		renderers: rs,
+	}
+}
+
+// Name implements types.Renderer.
+func (r *Renderer) Name() string {
+	return r.name
+}
+
+// Unwrap returns the children of the group, see types.Implements.
+func (r *Renderer) Unwrap() []types.Renderer {
+	return r.renderers
+}
+
+// Process implements types.Renderer.
+func (r *Renderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	return r.run(ctx, func(child types.Renderer) ([]unstructured.Unstructured, error) {
+		return child.Process(ctx, values)
+	})
+}
+
+// ProcessVersion implements types.VersionAwareRenderer.
+func (r *Renderer) ProcessVersion(
+	ctx context.Context,
+	values map[string]any,
+	version string,
+) ([]unstructured.Unstructured, error) {
+	return r.run(ctx, func(child types.Renderer) ([]unstructured.Unstructured, error) {
+		if vr, ok := child.(types.VersionAwareRenderer); ok {
+			return vr.ProcessVersion(ctx, values, version)
+		}
+
+		return child.Process(ctx, values)
+	})
+}
+
+// ProcessTarget implements types.TargetedRenderer.
+func (r *Renderer) ProcessTarget(
+	ctx context.Context,
+	values map[string]any,
+	target types.Filter,
+) ([]unstructured.Unstructured, error) {
+	return r.run(ctx, func(child types.Renderer) ([]unstructured.Unstructured, error) {
+		if tr, ok := child.(types.TargetedRenderer); ok {
+			return tr.ProcessTarget(ctx, values, target)
+		}
+
+		return child.Process(ctx, values)
+	})
+}
+
+// run calls process on each child in order and concatenates the objects they return.
+func (r *Renderer) run(
+	ctx context.Context,
+	process func(child types.Renderer) ([]unstructured.Unstructured, error),
+) ([]unstructured.Unstructured, error) {
+	objects := make([]unstructured.Unstructured, 0)
+
+	for i, child := range r.renderers {
+		if err := types.ValidateRenderer(child); err != nil {
+			return nil, fmt.Errorf("%s: invalid renderer at index %d: %w", r.name, i, err)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("%s render canceled: %w", r.name, err)
+		}
+
+		childObjects, err := process(child)
+		if err != nil {
+			return nil, &types.RendererError{
+				Name: child.Name(),
+				Type: fmt.Sprintf("%T", child),
+				Err:  err,
+			}
+		}
+
+		objects = append(objects, childObjects...)
+	}
+
+	return objects, nil
+}
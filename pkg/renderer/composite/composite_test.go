@@ -0,0 +1,193 @@
+package composite_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/composite"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+var errRender = errors.New("chart not found")
+
+// staticRenderer renders a ConfigMap per name, or fails with err.
+type staticRenderer struct {
+	name    string
+	objects []string
+	err     error
+
+	values  map[string]any
+	version string
+}
+
+func (r *staticRenderer) Name() string {
+	return r.name
+}
+
+func (r *staticRenderer) Process(_ context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	r.values = values
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	objects := make([]unstructured.Unstructured, 0, len(r.objects))
+
+	for _, name := range r.objects {
+		obj := unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName(name)
+
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// versionRenderer is a staticRenderer implementing types.VersionAwareRenderer.
+type versionRenderer struct {
+	staticRenderer
+}
+
+func (r *versionRenderer) ProcessVersion(
+	ctx context.Context,
+	values map[string]any,
+	version string,
+) ([]unstructured.Unstructured, error) {
+	r.version = version
+
+	return r.Process(ctx, values)
+}
+
+func names(objects []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, obj.GetName())
+	}
+
+	return result
+}
+
+func TestNew(t *testing.T) {
+
+	t.Run("should concatenate the output of its children in order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		first := &staticRenderer{name: "first", objects: []string{"a", "b"}}
+		second := &staticRenderer{name: "second", objects: []string{"c"}}
+
+		r := composite.New("platform", first, second)
+		g.Expect(r.Name()).Should(Equal("platform"))
+
+		values := map[string]any{"env": "prod"}
+
+		objects, err := r.Process(t.Context(), values)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"a", "b", "c"}))
+		g.Expect(first.values).Should(Equal(values))
+		g.Expect(second.values).Should(Equal(values))
+	})
+
+	t.Run("should wrap child errors with the child name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		failing := &staticRenderer{name: "failing", err: errRender}
+		last := &staticRenderer{name: "last", objects: []string{"c"}}
+
+		_, err := composite.New("platform", &staticRenderer{name: "first"}, failing, last).Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(errRender))
+
+		var rendererErr *types.RendererError
+		g.Expect(errors.As(err, &rendererErr)).Should(BeTrue())
+		g.Expect(rendererErr.Name).Should(Equal("failing"))
+		g.Expect(last.values).Should(BeNil())
+	})
+
+	t.Run("should reject invalid children", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := composite.New("platform", &staticRenderer{name: "first"}, nil).Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(types.ErrRendererNil))
+		g.Expect(err.Error()).Should(ContainSubstring("index 1"))
+	})
+
+	t.Run("should pass the Kubernetes version to version-aware children", func(t *testing.T) {
+		g := NewWithT(t)
+
+		aware := &versionRenderer{staticRenderer{name: "aware", objects: []string{"a"}}}
+		plain := &staticRenderer{name: "plain", objects: []string{"b"}}
+
+		objects, err := composite.New("platform", aware, plain).(types.VersionAwareRenderer).
+			ProcessVersion(t.Context(), nil, "v1.31.0")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"a", "b"}))
+		g.Expect(aware.version).Should(Equal("v1.31.0"))
+	})
+
+	t.Run("should render as a single renderer of an engine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := composite.New("platform",
+			&staticRenderer{name: "first", objects: []string{"a"}},
+			&staticRenderer{name: "second", objects: []string{"b"}},
+		)
+
+		e, err := engine.New(
+			engine.WithRendererOptions(r, []types.Filter{
+				func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+					return obj.GetName() != "a", nil
+				},
+			}, nil),
+			engine.WithProvenance(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, report, err := e.RenderWithReport(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"b"}))
+		g.Expect(objects[0].GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceType, "platform"))
+		g.Expect(report.Renderers).Should(HaveLen(1))
+		g.Expect(report.Renderers[0].Name).Should(Equal("platform"))
+	})
+
+	t.Run("should name the group and the child once in engine errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(engine.WithRenderer(composite.New("platform",
+			&staticRenderer{name: "failing", err: errRender},
+		)))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(MatchError(errRender))
+		g.Expect(strings.Count(err.Error(), "rendering failed")).Should(Equal(1))
+		g.Expect(err.Error()).Should(ContainSubstring(`renderer "platform"`))
+		g.Expect(err.Error()).Should(ContainSubstring(`renderer "failing"`))
+	})
+
+	t.Run("should require output from a group without targeted children", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := composite.New("platform", &staticRenderer{name: "empty"})
+		g.Expect(types.Implements[types.TargetedRenderer](r)).Should(BeFalse())
+
+		e, err := engine.New(engine.WithRenderer(r), engine.WithRequireNonEmpty(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).Should(MatchError(engine.ErrRendererEmpty))
+
+		_, err = e.Render(t.Context(), engine.WithTarget(func(context.Context, unstructured.Unstructured) (bool, error) {
+			return true, nil
+		}))
+		g.Expect(err).Should(MatchError(engine.ErrRendererEmpty))
+	})
+}
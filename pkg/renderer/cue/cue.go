@@ -0,0 +1,224 @@
+// Package cue provides a renderer exporting CUE definitions as Kubernetes objects.
+package cue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/load"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+const rendererName = "cue"
+
+var (
+	// ErrDirEmpty is returned when the directory is empty.
+	ErrDirEmpty = errors.New("cue directory cannot be empty")
+
+	// ErrNotConcrete is returned when an exported object contains non-concrete values.
+	ErrNotConcrete = errors.New("cue value is not concrete")
+
+	// ErrUnexpectedOutput is returned when the exported values are not Kubernetes objects.
+	ErrUnexpectedOutput = errors.New("unexpected cue output")
+)
+
+// Renderer exports the Kubernetes objects defined by a CUE instance.
+type Renderer struct {
+	dir     string
+	options Options
+}
+
+// New returns a renderer loading the CUE instance in dir.
+//
+// On every render, the render-time values are unified with the instance at the values path
+// (see WithValuesPath), then objects are exported either from the list or struct at the objects
+// path (see WithObjectsPath) or, by default, from every top-level field having both an apiVersion
+// and a kind, in declaration order.
+//
+// Exported objects must be concrete: an error naming the first incomplete field is returned otherwise.
+func New(dir string, opts ...Option) (types.Renderer, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, ErrDirEmpty
+	}
+
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	r := Renderer{
+		dir:     dir,
+		options: options,
+	}
+
+	return &r, nil
+}
+
+// Name implements types.Renderer.
+func (r *Renderer) Name() string {
+	return rendererName
+}
+
+// Process implements types.Renderer.
+func (r *Renderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("cue render canceled: %w", err)
+	}
+
+	v, err := r.build(values)
+	if err != nil {
+		return nil, err
+	}
+
+	exported, err := r.exported(v)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]unstructured.Unstructured, 0, len(exported))
+
+	for _, value := range exported {
+		obj, err := decode(value)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, obj)
+	}
+
+	return pipeline.Apply(ctx, objects, r.options.Filters, r.options.Transformers)
+}
+
+// build loads the instance and unifies it with values.
+// A new context is created for every build since CUE contexts are not safe for concurrent use.
+func (r *Renderer) build(values map[string]any) (cue.Value, error) {
+	instances := load.Instances([]string{"."}, &load.Config{
+		Dir:     r.dir,
+		Package: r.options.Package,
+	})
+
+	if len(instances) == 0 {
+		return cue.Value{}, fmt.Errorf("no CUE instance found in %s", r.dir)
+	}
+
+	if err := instances[0].Err; err != nil {
+		return cue.Value{}, fmt.Errorf("failed to load CUE instance from %s: %w", r.dir, err)
+	}
+
+	v := cuecontext.New().BuildInstance(instances[0])
+	if err := v.Err(); err != nil {
+		return cue.Value{}, fmt.Errorf("failed to build CUE instance from %s: %w", r.dir, err)
+	}
+
+	if len(values) > 0 {
+		v = v.FillPath(cue.ParsePath(r.options.ValuesPath), values)
+	}
+
+	if err := v.Validate(); err != nil {
+		return cue.Value{}, fmt.Errorf("failed to unify values at %s: %w", r.options.ValuesPath, err)
+	}
+
+	return v, nil
+}
+
+// exported returns the values to export as objects.
+func (r *Renderer) exported(v cue.Value) ([]cue.Value, error) {
+	if r.options.ObjectsPath == "" {
+		fields, err := v.Fields()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list top-level fields: %w", err)
+		}
+
+		result := make([]cue.Value, 0)
+
+		for fields.Next() {
+			if looksLikeObject(fields.Value()) {
+				result = append(result, fields.Value())
+			}
+		}
+
+		return result, nil
+	}
+
+	root := v.LookupPath(cue.ParsePath(r.options.ObjectsPath))
+	if !root.Exists() {
+		return nil, fmt.Errorf("%w: objects path %q not found", ErrUnexpectedOutput, r.options.ObjectsPath)
+	}
+
+	var (
+		items *cue.Iterator
+		err   error
+	)
+
+	switch root.IncompleteKind() {
+	case cue.ListKind:
+		var list cue.Iterator
+		list, err = root.List()
+		items = &list
+	case cue.StructKind:
+		items, err = root.Fields()
+	default:
+		return nil, fmt.Errorf(
+			"%w: objects path %q is a %s, expected a list or struct",
+			ErrUnexpectedOutput,
+			r.options.ObjectsPath,
+			root.IncompleteKind(),
+		)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate objects at %q: %w", r.options.ObjectsPath, err)
+	}
+
+	result := make([]cue.Value, 0)
+	for items.Next() {
+		result = append(result, items.Value())
+	}
+
+	return result, nil
+}
+
+// decode exports a concrete CUE value as an unstructured object.
+func decode(value cue.Value) (unstructured.Unstructured, error) {
+	if err := value.Validate(cue.Concrete(true)); err != nil {
+		return unstructured.Unstructured{}, fmt.Errorf("%w: %w", ErrNotConcrete, err)
+	}
+
+	if !looksLikeObject(value) {
+		return unstructured.Unstructured{}, fmt.Errorf(
+			"%w: %s is not a Kubernetes object",
+			ErrUnexpectedOutput,
+			value.Path(),
+		)
+	}
+
+	data, err := value.MarshalJSON()
+	if err != nil {
+		return unstructured.Unstructured{}, fmt.Errorf("failed to export %s: %w", value.Path(), err)
+	}
+
+	obj := unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return unstructured.Unstructured{}, fmt.Errorf("failed to decode %s: %w", value.Path(), err)
+	}
+
+	return obj, nil
+}
+
+// looksLikeObject reports whether value is a struct with apiVersion and kind fields.
+func looksLikeObject(value cue.Value) bool {
+	if value.IncompleteKind() != cue.StructKind {
+		return false
+	}
+
+	return value.LookupPath(cue.ParsePath("apiVersion")).Exists() &&
+		value.LookupPath(cue.ParsePath("kind")).Exists()
+}
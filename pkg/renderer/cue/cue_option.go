@@ -0,0 +1,94 @@
+package cue
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// DefaultValuesPath is the path at which render-time values are injected when none is configured.
+const DefaultValuesPath = "values"
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the CUE renderer.
+type Options struct {
+	// Package is the name of the CUE package to load from the directory.
+	// If empty, the only package in the directory is loaded.
+	Package string
+
+	// ValuesPath is the CUE path at which render-time values are unified with the instance.
+	// Defaults to DefaultValuesPath.
+	ValuesPath string
+
+	// ObjectsPath is the CUE path of a list or struct holding the objects to export.
+	// If empty, every top-level field that looks like a Kubernetes object is exported.
+	ObjectsPath string
+
+	// Filters are renderer-specific filters applied to the exported objects.
+	Filters []types.Filter
+
+	// Transformers are renderer-specific transformers applied to the exported objects.
+	Transformers []types.Transformer
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.Filters = append(target.Filters, opts.Filters...)
+	target.Transformers = append(target.Transformers, opts.Transformers...)
+
+	if opts.Package != "" {
+		target.Package = opts.Package
+	}
+
+	if opts.ValuesPath != "" {
+		target.ValuesPath = opts.ValuesPath
+	}
+
+	if opts.ObjectsPath != "" {
+		target.ObjectsPath = opts.ObjectsPath
+	}
+}
+
+// WithPackage sets the name of the CUE package to load.
+func WithPackage(name string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Package = name
+	})
+}
+
+// WithValuesPath sets the CUE path (e.g. "config.values") at which render-time values are injected.
+func WithValuesPath(path string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.ValuesPath = path
+	})
+}
+
+// WithObjectsPath exports the objects found in the list or struct at the CUE path (e.g. "objects")
+// instead of the top-level fields that look like Kubernetes objects.
+func WithObjectsPath(path string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.ObjectsPath = path
+	})
+}
+
+// WithFilter adds a renderer-specific filter applied to the exported objects.
+func WithFilter(f types.Filter) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Filters = append(o.Filters, f)
+	})
+}
+
+// WithTransformer adds a renderer-specific transformer applied to the exported objects.
+func WithTransformer(t types.Transformer) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Transformers = append(o.Transformers, t)
+	})
+}
+
+func defaultOptions() Options {
+	return Options{
+		ValuesPath: DefaultValuesPath,
+	}
+}
@@ -0,0 +1,231 @@
+package cue_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/cue"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	topLevel = `package app
+
+values: {
+	name:     string | *"web"
+	replicas: int | *1
+}
+
+service: {
+	apiVersion: "v1"
+	kind:       "Service"
+	metadata: name: values.name
+}
+
+deployment: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	metadata: name: values.name
+	spec: replicas: values.replicas
+}
+
+settings: {
+	debug: true
+}
+`
+
+	objectsList = `package app
+
+config: name: string
+
+objects: [
+	{apiVersion: "v1", kind: "ConfigMap", metadata: name: config.name},
+	{apiVersion: "v1", kind: "Secret", metadata: name: config.name},
+]
+`
+
+	objectsStruct = `package app
+
+objects: {
+	first: {apiVersion: "v1", kind: "ConfigMap", metadata: name: "first"}
+	second: {apiVersion: "v1", kind: "ConfigMap", metadata: name: "second"}
+}
+`
+
+	incomplete = `package app
+
+values: replicas: int
+
+deployment: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	metadata: name: "web"
+	spec: replicas: values.replicas
+}
+`
+
+	notAnObject = `package app
+
+objects: ["not an object"]
+`
+)
+
+func TestNew(t *testing.T) {
+
+	t.Run("should reject an empty directory", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := cue.New("")
+		g.Expect(err).Should(MatchError(cue.ErrDirEmpty))
+	})
+
+	t.Run("should be named cue", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := cue.New(".")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(r.Name()).Should(Equal("cue"))
+	})
+}
+
+func TestProcess(t *testing.T) {
+
+	t.Run("should export top-level objects in declaration order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := cue.New(writeDir(t, topLevel))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetKind()).Should(Equal("Service"))
+		g.Expect(objects[1].GetKind()).Should(Equal("Deployment"))
+		g.Expect(objects[1].GetName()).Should(Equal("web"))
+	})
+
+	t.Run("should unify values at the values path", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := cue.New(writeDir(t, topLevel))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), map[string]any{"name": "api", "replicas": 3})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[1].GetName()).Should(Equal("api"))
+
+		replicas, _, _ := unstructured.NestedInt64(objects[1].Object, "spec", "replicas")
+		g.Expect(replicas).Should(Equal(int64(3)))
+	})
+
+	t.Run("should report values conflicting with the instance", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := cue.New(writeDir(t, topLevel))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), map[string]any{"replicas": "three"})
+		g.Expect(err).Should(MatchError(ContainSubstring("values.replicas")))
+	})
+
+	t.Run("should export objects from a list at the objects path", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := cue.New(
+			writeDir(t, objectsList),
+			cue.WithObjectsPath("objects"),
+			cue.WithValuesPath("config"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), map[string]any{"name": "settings"})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetKind()).Should(Equal("ConfigMap"))
+		g.Expect(objects[1].GetKind()).Should(Equal("Secret"))
+		g.Expect(objects[1].GetName()).Should(Equal("settings"))
+	})
+
+	t.Run("should export objects from a struct at the objects path", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := cue.New(writeDir(t, objectsStruct), cue.WithObjectsPath("objects"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetName()).Should(Equal("first"))
+		g.Expect(objects[1].GetName()).Should(Equal("second"))
+	})
+
+	t.Run("should name the incomplete field", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := cue.New(writeDir(t, incomplete))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(cue.ErrNotConcrete))
+		g.Expect(err.Error()).Should(ContainSubstring("deployment.spec.replicas"))
+	})
+
+	t.Run("should reject a missing objects path", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := cue.New(writeDir(t, topLevel), cue.WithObjectsPath("objects"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(cue.ErrUnexpectedOutput))
+	})
+
+	t.Run("should reject values that are not objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := cue.New(writeDir(t, notAnObject), cue.WithObjectsPath("objects"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(cue.ErrUnexpectedOutput))
+	})
+
+	t.Run("should apply renderer-specific filters and transformers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := cue.New(
+			writeDir(t, topLevel),
+			cue.WithFilter(func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				return obj.GetKind() == "Deployment", nil
+			}),
+			cue.WithTransformer(func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				obj.SetNamespace("rendered")
+
+				return obj, nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetNamespace()).Should(Equal("rendered"))
+	})
+}
+
+func writeDir(t *testing.T, content string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.cue"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
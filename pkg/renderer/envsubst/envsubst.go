@@ -0,0 +1,211 @@
+// Package envsubst provides a renderer substituting ${key} variables in YAML files with render-time
+// values before decoding them, for manifests needing simple substitution rather than a template engine.
+package envsubst
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/k8s-manifest-kit/pkg/util"
+	pkgerrors "github.com/k8s-manifest-kit/pkg/util/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/decode"
+)
+
+const (
+	rendererName = "envsubst"
+
+	// defaultSeparator separates the key of a variable from its default value.
+	defaultSeparator = ":-"
+)
+
+var (
+	// ErrMissingValue is returned when a variable without default value has no value.
+	ErrMissingValue = errors.New("missing value")
+
+	// ErrInvalidVariable is returned for a malformed variable, e.g. without closing brace.
+	ErrInvalidVariable = errors.New("invalid variable")
+)
+
+// keyPattern matches the keys of variables: dot-separated names, e.g. "image.tag".
+//
+//nolint:gochecknoglobals
+var keyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*(\.[A-Za-z_][A-Za-z0-9_-]*)*$`)
+
+// Renderer decodes YAML files after substituting their variables with values.
+type Renderer struct {
+	paths   []string
+	options Options
+}
+
+// New returns a renderer reading the files at paths, in order, and substituting their variables with
+// the render-time values, deep merged over the values set with WithValues, before decoding them.
+//
+// A variable is written ${key}, where key is a value name or a dot-separated path into nested values,
+// e.g. ${image.tag}. It can carry a default, ${key:-default}, used when the value is missing or empty;
+// a variable without default whose value is missing fails the render with an error wrapping
+// ErrMissingValue and naming the file and line. $${ is an escape for a literal ${. String values are
+// inserted as they are, other values as JSON, which YAML accepts as flow style; values are not quoted,
+// so a string value that is not a valid YAML scalar where it is inserted must be quoted in the file.
+//
+// Files may contain multiple YAML documents; documents without apiVersion or kind are skipped, and so
+// are empty ones unless set otherwise with WithDecodeOptions.
+func New(paths []string, opts ...Option) (types.Renderer, error) {
+	if len(paths) == 0 {
+		return nil, pkgerrors.ErrPathEmpty
+	}
+
+	for _, path := range paths {
+		if strings.TrimSpace(path) == "" {
+			return nil, pkgerrors.ErrPathEmpty
+		}
+	}
+
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	r := Renderer{
+		paths:   slices.Clone(paths),
+		options: options,
+	}
+
+	return &r, nil
+}
+
+// Name implements types.Renderer.
+func (r *Renderer) Name() string {
+	return rendererName
+}
+
+// Process implements types.Renderer.
+func (r *Renderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	values = util.DeepMerge(r.options.Values, values)
+	objects := make([]unstructured.Unstructured, 0)
+
+	for _, path := range r.paths {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("envsubst render canceled: %w", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		content, err = substitute(content, values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to substitute %s: %w", path, err)
+		}
+
+		decoded, err := decode.YAML(content, r.options.Decode...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+
+		objects = append(objects, decoded...)
+	}
+
+	return pipeline.Apply(ctx, objects, r.options.Filters, r.options.Transformers)
+}
+
+// substitute replaces the variables of content with values.
+func substitute(content []byte, values map[string]any) ([]byte, error) {
+	var out bytes.Buffer
+
+	out.Grow(len(content))
+
+	for i := 0; i < len(content); {
+		switch {
+		case bytes.HasPrefix(content[i:], []byte("$${")):
+			out.WriteString("${")
+			i += len("$${")
+
+		case bytes.HasPrefix(content[i:], []byte("${")):
+			line := bytes.Count(content[:i], []byte("\n")) + 1
+
+			end := bytes.IndexByte(content[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("%w at line %d: missing closing brace", ErrInvalidVariable, line)
+			}
+
+			value, err := resolve(string(content[i+len("${"):i+end]), values)
+			if err != nil {
+				return nil, fmt.Errorf("%w at line %d", err, line)
+			}
+
+			out.WriteString(value)
+			i += end + 1
+
+		default:
+			out.WriteByte(content[i])
+			i++
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+// resolve returns the value of the variable expr, the content of ${...}.
+func resolve(expr string, values map[string]any) (string, error) {
+	key, def, hasDefault := strings.Cut(expr, defaultSeparator)
+
+	if !keyPattern.MatchString(key) {
+		return "", fmt.Errorf("%w: ${%s}", ErrInvalidVariable, expr)
+	}
+
+	value, found := lookup(values, key)
+
+	switch {
+	case found && value != "":
+		return value, nil
+	case hasDefault:
+		return def, nil
+	case found:
+		return "", nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrMissingValue, key)
+	}
+}
+
+// lookup returns the value at the dot-separated path key of values, formatted for substitution.
+func lookup(values map[string]any, key string) (string, bool) {
+	var current any = values
+
+	for part := range strings.SplitSeq(key, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return "", false
+		}
+
+		if current, ok = m[part]; !ok {
+			return "", false
+		}
+	}
+
+	switch v := current.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v), true
+		}
+
+		return string(data), true
+	}
+}
@@ -0,0 +1,67 @@
+package envsubst
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/decode"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the envsubst renderer.
+type Options struct {
+	// Filters are renderer-specific filters applied to the decoded objects.
+	Filters []types.Filter
+
+	// Transformers are renderer-specific transformers applied to the decoded objects.
+	Transformers []types.Transformer
+
+	// Decode are the options of the decoding of the substituted files, such as decode.WithEmptyError.
+	Decode []decode.Option
+
+	// Values are default values, deep merged with the render-time values, which take precedence.
+	Values map[string]any
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.Filters = append(target.Filters, opts.Filters...)
+	target.Transformers = append(target.Transformers, opts.Transformers...)
+	target.Decode = append(target.Decode, opts.Decode...)
+
+	if opts.Values != nil {
+		target.Values = util.DeepMerge(target.Values, opts.Values)
+	}
+}
+
+// WithFilter adds a renderer-specific filter applied to the decoded objects.
+func WithFilter(f types.Filter) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Filters = append(o.Filters, f)
+	})
+}
+
+// WithTransformer adds a renderer-specific transformer applied to the decoded objects.
+func WithTransformer(t types.Transformer) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Transformers = append(o.Transformers, t)
+	})
+}
+
+// WithDecodeOptions sets options of the decoding of the substituted files, e.g. decode.WithEmptyError(true)
+// to fail on empty documents instead of skipping them.
+func WithDecodeOptions(opts ...decode.Option) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Decode = append(o.Decode, opts...)
+	})
+}
+
+// WithValues sets default values for the variables, deep merged with the render-time values,
+// which take precedence.
+func WithValues(values map[string]any) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Values = util.DeepMerge(o.Values, values)
+	})
+}
@@ -0,0 +1,151 @@
+package envsubst_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k8s-manifest-kit/pkg/util/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/envsubst"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ${name}
+  labels:
+    env: ${env:-dev}
+    literal: $${name}
+spec:
+  replicas: ${replicas}
+  template:
+    spec:
+      containers:
+      - name: app
+        image: "${image.repository}:${image.tag:-latest}"
+        args: ${args}
+`
+
+	service = `apiVersion: v1
+kind: Service
+metadata:
+  name: ${name}
+`
+)
+
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	NewWithT(t).Expect(os.WriteFile(path, []byte(content), 0o600)).Should(Succeed())
+
+	return path
+}
+
+func TestNew(t *testing.T) {
+
+	t.Run("should substitute values before decoding", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := envsubst.New([]string{writeFile(t, "deployment.yaml", deployment), writeFile(t, "service.yaml", service)})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(r.Name()).Should(Equal("envsubst"))
+
+		objects, err := r.Process(t.Context(), map[string]any{
+			"name":     "web",
+			"replicas": 3,
+			"image":    map[string]any{"repository": "nginx"},
+			"args":     []any{"--port", "8080"},
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+
+		obj := objects[0]
+		g.Expect(obj.GetName()).Should(Equal("web"))
+		g.Expect(obj.GetLabels()).Should(Equal(map[string]string{"env": "dev", "literal": "${name}"}))
+
+		replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		g.Expect(replicas).Should(Equal(int64(3)))
+
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		g.Expect(containers[0]).Should(HaveKeyWithValue("image", "nginx:latest"))
+		g.Expect(containers[0]).Should(HaveKeyWithValue("args", []any{"--port", "8080"}))
+
+		g.Expect(objects[1].GetKind()).Should(Equal("Service"))
+		g.Expect(objects[1].GetName()).Should(Equal("web"))
+	})
+
+	t.Run("should let render-time values override default values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := envsubst.New(
+			[]string{writeFile(t, "service.yaml", service)},
+			envsubst.WithValues(map[string]any{"name": "default"}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects[0].GetName()).Should(Equal("default"))
+
+		objects, err = r.Process(t.Context(), map[string]any{"name": "web"})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects[0].GetName()).Should(Equal("web"))
+	})
+
+	t.Run("should fail on a missing value naming the file and line", func(t *testing.T) {
+		g := NewWithT(t)
+
+		path := writeFile(t, "deployment.yaml", deployment)
+
+		r, err := envsubst.New([]string{path})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), map[string]any{"name": "web"})
+		g.Expect(err).Should(MatchError(envsubst.ErrMissingValue))
+		g.Expect(err).Should(MatchError(ContainSubstring("replicas at line 9")))
+		g.Expect(err).Should(MatchError(ContainSubstring(path)))
+	})
+
+	t.Run("should fail on malformed variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, content := range []string{"name: ${name", "name: ${not valid}", "name: ${}"} {
+			r, err := envsubst.New([]string{writeFile(t, "invalid.yaml", content)})
+			g.Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = r.Process(t.Context(), map[string]any{"name": "web"})
+			g.Expect(err).Should(MatchError(envsubst.ErrInvalidVariable), content)
+		}
+	})
+
+	t.Run("should fail on a canceled context", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := envsubst.New([]string{writeFile(t, "service.yaml", service)})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err = r.Process(ctx, map[string]any{"name": "web"})
+		g.Expect(err).Should(MatchError(context.Canceled))
+	})
+
+	t.Run("should require paths", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := envsubst.New(nil)
+		g.Expect(err).Should(MatchError(errors.ErrPathEmpty))
+
+		_, err = envsubst.New([]string{" "})
+		g.Expect(err).Should(MatchError(errors.ErrPathEmpty))
+	})
+}
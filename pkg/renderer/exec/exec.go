@@ -0,0 +1,112 @@
+// Package exec provides a renderer running an external command that prints Kubernetes objects,
+// an escape hatch for generators without a native renderer.
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/decode"
+)
+
+const rendererName = "exec"
+
+var (
+	// ErrCommandEmpty is returned when the command is empty.
+	ErrCommandEmpty = errors.New("exec command cannot be empty")
+
+	// ErrCommandFailed is returned when the command cannot be started or exits with a non-zero code.
+	ErrCommandFailed = errors.New("exec command failed")
+)
+
+// Renderer runs an external command and decodes the Kubernetes objects it prints.
+type Renderer struct {
+	command string
+	args    []string
+	options Options
+}
+
+// New returns a renderer running command with args.
+//
+// On each render, the command receives the render-time values as a JSON object on its stdin and must
+// print the objects as a multi-document YAML (or JSON) stream on its stdout. A command exiting with a
+// non-zero code fails the render with an error wrapping ErrCommandFailed and including its stderr.
+// The command is resolved through PATH unless it contains a path separator, and is killed when the
+// render context is canceled.
+func New(command string, args []string, opts ...Option) (types.Renderer, error) {
+	if strings.TrimSpace(command) == "" {
+		return nil, ErrCommandEmpty
+	}
+
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	r := Renderer{
+		command: command,
+		args:    slices.Clone(args),
+		options: options,
+	}
+
+	return &r, nil
+}
+
+// Name implements types.Renderer.
+func (r *Renderer) Name() string {
+	return rendererName
+}
+
+// Process implements types.Renderer.
+func (r *Renderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("exec render canceled: %w", err)
+	}
+
+	if values == nil {
+		values = map[string]any{}
+	}
+
+	input, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode values for %s: %w", r.command, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := osexec.CommandContext(ctx, r.command, r.args...)
+	cmd.Dir = r.options.Dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if len(r.options.Env) > 0 {
+		cmd.Env = append(os.Environ(), r.options.Env...)
+	}
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("exec render canceled: %w", ctxErr)
+		}
+
+		return nil, fmt.Errorf("%w: %s: %w: %s", ErrCommandFailed, r.command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	objects, err := decode.YAML(stdout.Bytes(), r.options.Decode...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode output of %s: %w", r.command, err)
+	}
+
+	return pipeline.Apply(ctx, objects, r.options.Filters, r.options.Transformers)
+}
@@ -0,0 +1,79 @@
+package exec
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/decode"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the exec renderer.
+type Options struct {
+	// Dir is the working directory of the command. If empty, the command runs in the current directory.
+	Dir string
+
+	// Env are environment variables, in "key=value" form, added to the environment of the current
+	// process for the command. Later entries take precedence over earlier ones.
+	Env []string
+
+	// Filters are renderer-specific filters applied to the decoded objects.
+	Filters []types.Filter
+
+	// Transformers are renderer-specific transformers applied to the decoded objects.
+	Transformers []types.Transformer
+
+	// Decode are the options of the decoding of the command output, such as decode.WithEmptyError.
+	Decode []decode.Option
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.Env = append(target.Env, opts.Env...)
+	target.Filters = append(target.Filters, opts.Filters...)
+	target.Transformers = append(target.Transformers, opts.Transformers...)
+	target.Decode = append(target.Decode, opts.Decode...)
+
+	if opts.Dir != "" {
+		target.Dir = opts.Dir
+	}
+}
+
+// WithDir sets the working directory of the command.
+func WithDir(dir string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Dir = dir
+	})
+}
+
+// WithEnv sets the environment variable key to value for the command, on top of the environment
+// of the current process.
+func WithEnv(key string, value string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Env = append(o.Env, key+"="+value)
+	})
+}
+
+// WithFilter adds a renderer-specific filter applied to the decoded objects.
+func WithFilter(f types.Filter) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Filters = append(o.Filters, f)
+	})
+}
+
+// WithTransformer adds a renderer-specific transformer applied to the decoded objects.
+func WithTransformer(t types.Transformer) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Transformers = append(o.Transformers, t)
+	})
+}
+
+// WithDecodeOptions sets options of the decoding of the command output, e.g. decode.WithEmptyError(true)
+// to fail on empty documents instead of skipping them.
+func WithDecodeOptions(opts ...decode.Option) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Decode = append(o.Decode, opts...)
+	})
+}
@@ -0,0 +1,213 @@
+package exec_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/exec"
+	"github.com/k8s-manifest-kit/engine/pkg/util/decode"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	twoObjects = `cat > /dev/null
+cat <<'EOF'
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+EOF`
+
+	list = `cat > /dev/null
+cat <<'EOF'
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: listed
+EOF`
+
+	echoValues = `printf 'apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: values\ndata:\n  values: %s\n' "'$(cat)'"`
+
+	echoEnv = `printf 'apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n' "$CONFIG_NAME"`
+
+	emptyDocuments = `cat > /dev/null; printf -- '---\n---\n'`
+
+	failing = `echo "generator exploded" >&2; exit 3`
+)
+
+func TestNew(t *testing.T) {
+
+	t.Run("should reject an empty command", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := exec.New(" ", nil)
+		g.Expect(err).Should(MatchError(exec.ErrCommandEmpty))
+	})
+
+	t.Run("should be named exec", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := exec.New("generator", nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(r.Name()).Should(Equal("exec"))
+	})
+}
+
+func TestProcess(t *testing.T) {
+
+	t.Run("should decode multi-document output", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := exec.New("sh", []string{"-c", twoObjects})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"first", "second"}))
+	})
+
+	t.Run("should flatten list objects into their items", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := exec.New("sh", []string{"-c", list})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"listed"}))
+	})
+
+	t.Run("should skip empty documents unless configured otherwise", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := exec.New("sh", []string{"-c", emptyDocuments})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(BeEmpty())
+
+		r, err = exec.New("sh", []string{"-c", emptyDocuments}, exec.WithDecodeOptions(decode.WithEmptyError(true)))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(decode.ErrEmptyDocument))
+	})
+
+	t.Run("should write values as JSON to stdin", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := exec.New("sh", []string{"-c", echoValues})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), map[string]any{"image": map[string]any{"tag": "v1"}})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+
+		data, _, _ := unstructured.NestedString(objects[0].Object, "data", "values")
+		g.Expect(data).Should(MatchJSON(`{"image": {"tag": "v1"}}`))
+
+		objects, err = r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		data, _, _ = unstructured.NestedString(objects[0].Object, "data", "values")
+		g.Expect(data).Should(MatchJSON(`{}`))
+	})
+
+	t.Run("should run in the configured directory with the configured environment", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "generate.sh"), []byte(echoEnv), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := exec.New("sh", []string{"generate.sh"},
+			exec.WithDir(dir),
+			exec.WithEnv("CONFIG_NAME", "from-env"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"from-env"}))
+	})
+
+	t.Run("should include stderr when the command fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := exec.New("sh", []string{"-c", failing})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(exec.ErrCommandFailed))
+		g.Expect(err.Error()).Should(ContainSubstring("exit status 3"))
+		g.Expect(err.Error()).Should(ContainSubstring("generator exploded"))
+	})
+
+	t.Run("should report a command that cannot be started", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := exec.New(filepath.Join(t.TempDir(), "missing"), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(exec.ErrCommandFailed))
+	})
+
+	t.Run("should apply renderer-specific filters and transformers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := exec.New("sh", []string{"-c", twoObjects},
+			exec.WithFilter(func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				return obj.GetName() == "second", nil
+			}),
+			exec.WithTransformer(func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				obj.SetNamespace("rendered")
+
+				return obj, nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"second"}))
+		g.Expect(objects[0].GetNamespace()).Should(Equal("rendered"))
+	})
+
+	t.Run("should stop on a canceled context", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := exec.New("sh", []string{"-c", twoObjects})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err = r.Process(ctx, nil)
+		g.Expect(err).Should(MatchError(context.Canceled))
+	})
+}
+
+func names(objects []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, obj.GetName())
+	}
+
+	return result
+}
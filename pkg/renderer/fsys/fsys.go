@@ -0,0 +1,162 @@
+// Package fsys provides a renderer decoding manifests read from an fs.FS, e.g. an embed.FS.
+package fsys
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/k8s-manifest-kit/pkg/util/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/decode"
+)
+
+const (
+	rendererName = "fsys"
+
+	// anyDirs is the glob segment matching zero or more directories.
+	anyDirs = "**"
+)
+
+// Renderer decodes the YAML and JSON documents of the files of an fs.FS matching a glob.
+type Renderer struct {
+	fsys    fs.FS
+	glob    string
+	options Options
+}
+
+// New returns a renderer reading the files of fsys matching glob.
+//
+// The glob uses path.Match syntax against slash-separated paths relative to the root of fsys,
+// with the addition of "**" segments matching any number of directories: "manifests/*.yaml"
+// only matches files directly in manifests, while "manifests/**/*.yaml" also matches nested ones.
+// Files are read in sorted path order and may contain multiple YAML documents; documents without
+// apiVersion or kind are skipped, and so are empty ones unless set otherwise with WithDecodeOptions.
+// Render-time values are ignored.
+func New(fsys fs.FS, glob string, opts ...Option) (types.Renderer, error) {
+	if fsys == nil {
+		return nil, errors.ErrFsRequired
+	}
+
+	if strings.TrimSpace(glob) == "" {
+		return nil, errors.ErrPathEmpty
+	}
+
+	if _, err := path.Match(glob, ""); err != nil {
+		return nil, fmt.Errorf("invalid glob %q: %w", glob, err)
+	}
+
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	r := Renderer{
+		fsys:    fsys,
+		glob:    glob,
+		options: options,
+	}
+
+	return &r, nil
+}
+
+// Name implements types.Renderer.
+func (r *Renderer) Name() string {
+	return rendererName
+}
+
+// Process implements types.Renderer.
+func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	names, err := r.files()
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]unstructured.Unstructured, 0)
+	decodeOpts := append(slices.Clone(r.options.Decode), decode.WithComments(r.options.Comments))
+
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("fsys render canceled: %w", err)
+		}
+
+		content, err := fs.ReadFile(r.fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		decoded, err := decode.YAML(content, decodeOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+		}
+
+		objects = append(objects, decoded...)
+	}
+
+	return pipeline.Apply(ctx, objects, r.options.Filters, r.options.Transformers)
+}
+
+// files returns the sorted paths of the files matching the glob.
+func (r *Renderer) files() ([]string, error) {
+	names := make([]string, 0)
+
+	err := fs.WalkDir(r.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && match(r.glob, name) {
+			names = append(names, name)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files matching %q: %w", r.glob, err)
+	}
+
+	// WalkDir visits "a/b.yaml" before "a.yaml"; sort so the order only depends on the paths.
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// match reports whether name matches glob, where "**" segments match any number of directories.
+func match(glob string, name string) bool {
+	return matchSegments(strings.Split(glob, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern []string, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == anyDirs {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+
+	return len(name) == 0
+}
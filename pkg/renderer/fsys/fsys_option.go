@@ -0,0 +1,72 @@
+package fsys
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/decode"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the filesystem renderer.
+type Options struct {
+	// Filters are renderer-specific filters applied to the decoded objects.
+	Filters []types.Filter
+
+	// Transformers are renderer-specific transformers applied to the decoded objects.
+	Transformers []types.Transformer
+
+	// Comments records the commented source document of each object, see WithComments.
+	Comments bool
+
+	// Decode are the options of the decoding of the files, such as decode.WithEmptyError.
+	Decode []decode.Option
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.Filters = append(target.Filters, opts.Filters...)
+	target.Transformers = append(target.Transformers, opts.Transformers...)
+
+	target.Decode = append(target.Decode, opts.Decode...)
+
+	if opts.Comments {
+		target.Comments = true
+	}
+}
+
+// WithFilter adds a renderer-specific filter applied to the decoded objects.
+func WithFilter(f types.Filter) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Filters = append(o.Filters, f)
+	})
+}
+
+// WithTransformer adds a renderer-specific transformer applied to the decoded objects.
+func WithTransformer(t types.Transformer) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Transformers = append(o.Transformers, t)
+	})
+}
+
+// WithComments enables or disables preserving the comments of the decoded documents. Each object decoded
+// from a document with comments carries that document in the types.AnnotationSourceComments annotation,
+// which the YAML writer removes and, with its own WithComments option, uses to re-emit the comments.
+// Documents with anchors are recorded as well, for the YAML writer WithAnchors option.
+// Preservation is best-effort and only complete for untransformed objects; objects flattened from a
+// List do not carry comments.
+func WithComments(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Comments = enabled
+	})
+}
+
+// WithDecodeOptions sets options of the decoding of the files, e.g. decode.WithEmptyError(true)
+// to fail on empty documents instead of skipping them.
+func WithDecodeOptions(opts ...decode.Option) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Decode = append(o.Decode, opts...)
+	})
+}
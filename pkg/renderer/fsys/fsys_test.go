@@ -0,0 +1,208 @@
+package fsys_test
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/k8s-manifest-kit/pkg/util/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/fsys"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	configMaps = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`
+
+	service = `apiVersion: v1
+kind: Service
+metadata:
+  name: service
+`
+
+	deployment = `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "deployment"}}`
+
+	nested = `apiVersion: v1
+kind: Secret
+metadata:
+  name: nested
+`
+
+	values = `replicas: 3
+`
+
+	configMapList = `apiVersion: v1
+kind: ConfigMapList
+items:
+- metadata:
+    name: listed
+`
+
+	invalid = `apiVersion: v1
+kind: ConfigMap
+metadata: [unterminated
+`
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"manifests/service.yaml":            {Data: []byte(service)},
+		"manifests/configmaps.yaml":         {Data: []byte(configMaps)},
+		"manifests/deployment.json":         {Data: []byte(deployment)},
+		"manifests/values.yaml":             {Data: []byte(values)},
+		"manifests/nested/deep/secret.yaml": {Data: []byte(nested)},
+		"manifests.yaml":                    {Data: []byte(service)},
+		"README.md":                         {Data: []byte("# Manifests")},
+	}
+}
+
+func TestNew(t *testing.T) {
+
+	t.Run("should require a filesystem", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := fsys.New(nil, "*.yaml")
+		g.Expect(err).Should(MatchError(errors.ErrFsRequired))
+	})
+
+	t.Run("should require a glob", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := fsys.New(testFS(), " ")
+		g.Expect(err).Should(MatchError(errors.ErrPathEmpty))
+	})
+
+	t.Run("should reject an invalid glob", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := fsys.New(testFS(), "manifests/[")
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
+
+func TestProcess(t *testing.T) {
+
+	t.Run("should render matching files in sorted path order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := fsys.New(testFS(), "manifests/*.yaml")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"first", "second", "service"}))
+	})
+
+	t.Run("should match nested directories with **", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := fsys.New(testFS(), "manifests/**/*.yaml")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"first", "second", "nested", "service"}))
+	})
+
+	t.Run("should decode JSON files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := fsys.New(testFS(), "**/*.json")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"deployment"}))
+		g.Expect(objects[0].GetKind()).Should(Equal("Deployment"))
+	})
+
+	t.Run("should sort files by path across directories", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// a directory is walked before a file sharing its prefix, but "a.yaml" sorts before "a/b.yaml"
+		fs := fstest.MapFS{
+			"a/b.yaml": {Data: []byte(nested)},
+			"a.yaml":   {Data: []byte(service)},
+		}
+
+		r, err := fsys.New(fs, "**/*.yaml")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"service", "nested"}))
+	})
+
+	t.Run("should flatten list objects into their items", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fs := fstest.MapFS{
+			"list.yaml": {Data: []byte(configMapList)},
+		}
+
+		r, err := fsys.New(fs, "*.yaml")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"listed"}))
+		g.Expect(objects[0].GetKind()).Should(Equal("ConfigMap"))
+	})
+
+	t.Run("should report the file that cannot be decoded", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fs := testFS()
+		fs["manifests/invalid.yaml"] = &fstest.MapFile{Data: []byte(invalid)}
+
+		r, err := fsys.New(fs, "manifests/*.yaml")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(ContainSubstring("manifests/invalid.yaml")))
+	})
+
+	t.Run("should apply renderer-specific filters and transformers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := fsys.New(
+			testFS(),
+			"manifests/*.yaml",
+			fsys.WithFilter(func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				return obj.GetKind() == "Service", nil
+			}),
+			fsys.WithTransformer(func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				obj.SetNamespace("rendered")
+
+				return obj, nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"service"}))
+		g.Expect(objects[0].GetNamespace()).Should(Equal("rendered"))
+	})
+}
+
+func names(objects []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, obj.GetName())
+	}
+
+	return result
+}
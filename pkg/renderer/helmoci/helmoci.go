@@ -0,0 +1,474 @@
+// Package helmoci provides a renderer pulling Helm charts from OCI registries.
+package helmoci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/renderer"
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/auto"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+const (
+	rendererName = "helmoci"
+
+	// Scheme is the optional prefix of chart references, as in "oci://ghcr.io/org/charts/app".
+	Scheme = "oci://"
+
+	// MediaTypeChart is the media type of the layer holding the packaged chart.
+	MediaTypeChart = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+	// DefaultMaxChartSize is the maximum size, in bytes, of a chart unless set with WithMaxChartSize:
+	// both its packaged archive and its extracted files, in total, must fit. It matches the limit
+	// Helm puts on decompressed charts.
+	DefaultMaxChartSize int64 = 100 << 20
+
+	// chartFile is the file every chart has at its root.
+	chartFile = "Chart.yaml"
+)
+
+var (
+	// ErrPull is returned when the chart cannot be pulled, e.g. because of network or authentication
+	// failures, because no version matches, or because the artifact is not a valid chart.
+	ErrPull = errors.New("failed to pull Helm chart")
+
+	// ErrRender is returned when the pulled chart cannot be rendered.
+	ErrRender = errors.New("failed to render Helm chart")
+
+	// ErrVersionNotFound is returned, wrapped in ErrPull, when no version of the chart matches.
+	ErrVersionNotFound = errors.New("no chart version matches")
+
+	// ErrDigestMismatch is returned, wrapped in ErrPull, when the chart does not have the pinned digest.
+	ErrDigestMismatch = errors.New("chart digest mismatch")
+
+	// ErrNotChart is returned, wrapped in ErrPull, when the artifact has no chart layer.
+	ErrNotChart = errors.New("artifact is not a Helm chart")
+
+	// ErrChartTooLarge is returned, wrapped in ErrPull, when the packaged chart or its extracted files
+	// exceed the maximum chart size.
+	ErrChartTooLarge = errors.New("chart exceeds the maximum size")
+)
+
+// Renderer pulls a Helm chart from an OCI registry and renders it.
+type Renderer struct {
+	repo       name.Repository
+	version    string
+	tag        string
+	constraint *semver.Constraints
+	digest     string
+	remoteOpts []remote.Option
+	options    Options
+
+	mu    sync.Mutex
+	chart *chart
+}
+
+// chart is a pulled chart.
+type chart struct {
+	ref     name.Reference
+	archive []byte
+}
+
+// New returns a renderer pulling the chart at ref, e.g. "oci://ghcr.io/org/charts/app", in the
+// given version.
+//
+// The version is either an exact chart version ("1.2.3"), a constraint (">=1.2.0 <2.0.0", "~1.2",
+// "^1") resolved to the highest matching version among the tags of the repository, or empty for the
+// highest stable version. The chart can be pinned to a manifest digest with a "@sha256:..." suffix
+// of ref or with WithDigest; the version is then optional and, if given, must resolve to that digest.
+//
+// The chart is pulled on the first render and reused by the following ones. Each render extracts it
+// to a temporary directory and renders it, with the render-time values, using the renderer created
+// by the factory set with WithFactory or, by default, by the renderer registered under auto.KindHelm
+// in the renderer registry (see renderer.Register) with the configuration {"path": dir}.
+//
+// Errors wrap ErrPull for registry and content failures and ErrRender for rendering failures,
+// so the two can be told apart with errors.Is.
+func New(ref string, version string, opts ...Option) (types.Renderer, error) {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	if options.MaxChartSize <= 0 {
+		options.MaxChartSize = DefaultMaxChartSize
+	}
+
+	var nameOpts []name.Option
+	if options.Insecure {
+		nameOpts = append(nameOpts, name.Insecure)
+	}
+
+	trimmed := strings.TrimPrefix(ref, Scheme)
+
+	parsed, err := name.ParseReference(trimmed, nameOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI reference %q: %w", ref, err)
+	}
+
+	r := Renderer{
+		repo:    parsed.Context(),
+		version: version,
+		digest:  options.Digest,
+		options: options,
+	}
+
+	switch p := parsed.(type) {
+	case name.Digest:
+		if r.digest != "" && r.digest != p.DigestStr() {
+			return nil, fmt.Errorf("%w: reference %q is pinned to %s", ErrDigestMismatch, ref, r.digest)
+		}
+
+		r.digest = p.DigestStr()
+	case name.Tag:
+		// ParseReference defaults to "latest"; only use tags written in ref.
+		if strings.HasSuffix(trimmed, ":"+p.TagStr()) {
+			if r.version != "" && r.version != p.TagStr() {
+				return nil, fmt.Errorf("invalid OCI reference %q: tag conflicts with version %q", ref, version)
+			}
+
+			r.version = p.TagStr()
+		}
+	}
+
+	if err := r.parseVersion(); err != nil {
+		return nil, err
+	}
+
+	if options.TLSConfig != nil {
+		//nolint:forcetypeassert // net/http documents DefaultTransport as a *http.Transport
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = options.TLSConfig
+
+		r.remoteOpts = append(r.remoteOpts, remote.WithTransport(transport))
+	}
+
+	r.remoteOpts = append(r.remoteOpts, options.RemoteOptions...)
+
+	return &r, nil
+}
+
+// parseVersion sets the tag or the constraint the version resolves with.
+func (r *Renderer) parseVersion() error {
+	switch {
+	case r.version == "" && r.digest != "":
+		return nil
+	case r.version == "":
+		r.constraint, _ = semver.NewConstraint("*")
+
+		return nil
+	}
+
+	if _, err := semver.StrictNewVersion(r.version); err == nil {
+		// OCI tags cannot contain "+": Helm pushes build metadata with "_" instead.
+		r.tag = strings.ReplaceAll(r.version, "+", "_")
+
+		return nil
+	}
+
+	constraint, err := semver.NewConstraint(r.version)
+	if err != nil {
+		return fmt.Errorf("invalid chart version %q: %w", r.version, err)
+	}
+
+	r.constraint = constraint
+
+	return nil
+}
+
+// Name implements types.Renderer.
+func (r *Renderer) Name() string {
+	return rendererName
+}
+
+// Process implements types.Renderer.
+func (r *Renderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	return r.render(ctx, func(delegate types.Renderer) ([]unstructured.Unstructured, error) {
+		return delegate.Process(ctx, values)
+	})
+}
+
+// ProcessVersion implements types.VersionAwareRenderer. The version is passed on to the chart
+// renderer if it is version-aware too.
+func (r *Renderer) ProcessVersion(
+	ctx context.Context,
+	values map[string]any,
+	version string,
+) ([]unstructured.Unstructured, error) {
+	return r.render(ctx, func(delegate types.Renderer) ([]unstructured.Unstructured, error) {
+		if versionAware, ok := delegate.(types.VersionAwareRenderer); ok {
+			return versionAware.ProcessVersion(ctx, values, version)
+		}
+
+		return delegate.Process(ctx, values)
+	})
+}
+
+// render pulls the chart, extracts it and renders it with process.
+func (r *Renderer) render(
+	ctx context.Context,
+	process func(delegate types.Renderer) ([]unstructured.Unstructured, error),
+) ([]unstructured.Unstructured, error) {
+	c, err := r.pull(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "helmoci-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chart directory: %w", err)
+	}
+
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	chartDir, err := extract(c.archive, dir, r.options.MaxChartSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrPull, c.ref, err)
+	}
+
+	delegate, err := r.factory(chartDir)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrRender, c.ref, err)
+	}
+
+	objects, err := process(delegate)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrRender, c.ref, err)
+	}
+
+	return pipeline.Apply(ctx, objects, r.options.Filters, r.options.Transformers)
+}
+
+// factory creates the renderer of the chart extracted to dir.
+func (r *Renderer) factory(dir string) (types.Renderer, error) {
+	if r.options.Factory != nil {
+		return r.options.Factory(dir)
+	}
+
+	return renderer.New(auto.KindHelm, map[string]any{"path": dir})
+}
+
+// pull returns the chart, pulling it from the registry the first time.
+func (r *Renderer) pull(ctx context.Context) (*chart, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.chart != nil {
+		return r.chart, nil
+	}
+
+	remoteOpts := append(slices.Clone(r.remoteOpts), remote.WithContext(ctx))
+
+	ref, err := r.resolve(remoteOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := remote.Image(ref, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrPull, ref, err)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrPull, ref, err)
+	}
+
+	if r.digest != "" && digest.String() != r.digest {
+		return nil, fmt.Errorf("%w %s: %w: got %s, want %s", ErrPull, ref, ErrDigestMismatch, digest, r.digest)
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrPull, ref, err)
+	}
+
+	for _, layer := range layers {
+		mediaType, err := layer.MediaType()
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrPull, ref, err)
+		}
+
+		if mediaType != MediaTypeChart {
+			continue
+		}
+
+		archive, err := readLayer(layer.Compressed, r.options.MaxChartSize)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrPull, ref, err)
+		}
+
+		r.chart = &chart{ref: ref, archive: archive}
+
+		return r.chart, nil
+	}
+
+	return nil, fmt.Errorf("%w %s: %w", ErrPull, ref, ErrNotChart)
+}
+
+// resolve returns the reference of the manifest matching the version and the digest.
+func (r *Renderer) resolve(remoteOpts []remote.Option) (name.Reference, error) {
+	switch {
+	case r.tag != "":
+		return r.repo.Tag(r.tag), nil
+	case r.constraint == nil:
+		return r.repo.Digest(r.digest), nil
+	}
+
+	tags, err := remote.List(r.repo, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrPull, r.repo, err)
+	}
+
+	var best *semver.Version
+	var bestTag string
+
+	for _, tag := range tags {
+		v, err := semver.NewVersion(strings.ReplaceAll(tag, "_", "+"))
+		if err != nil || !r.constraint.Check(v) {
+			continue
+		}
+
+		if best == nil || v.GreaterThan(best) {
+			best = v
+			bestTag = tag
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("%w %s: %w %q", ErrPull, r.repo, ErrVersionNotFound, r.version)
+	}
+
+	return r.repo.Tag(bestTag), nil
+}
+
+// readLayer reads the content returned by open, failing if it exceeds limit bytes.
+func readLayer(open func() (io.ReadCloser, error), limit int64) ([]byte, error) {
+	rc, err := open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rc.Close() }()
+
+	content, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("%w: chart archive exceeds %d bytes", ErrChartTooLarge, limit)
+	}
+
+	return content, nil
+}
+
+// extract writes the files of a packaged chart to dir and returns the chart directory,
+// i.e. the single top-level directory of the archive. It fails if the files exceed limit bytes
+// in total, so a small archive cannot fill the disk.
+func extract(archive []byte, dir string, limit int64) (string, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return "", fmt.Errorf("failed to read chart archive: %w", err)
+	}
+
+	tr := tar.NewReader(gr)
+	root := ""
+	remaining := limit
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("failed to read chart archive: %w", err)
+		}
+
+		// Directories are created along with the files they contain; links are not followed.
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		if !fs.ValidPath(name) {
+			return "", fmt.Errorf("invalid path %q in chart archive", header.Name)
+		}
+
+		top, _, ok := strings.Cut(name, "/")
+
+		switch {
+		case !ok:
+			return "", fmt.Errorf("file %q is outside of the chart directory", header.Name)
+		case root == "":
+			root = top
+		case top != root:
+			return "", fmt.Errorf("chart archive has several top-level directories: %q and %q", root, top)
+		}
+
+		written, err := writeFile(filepath.Join(dir, filepath.FromSlash(name)), tr, remaining)
+		if err != nil {
+			return "", err
+		}
+
+		remaining -= written
+	}
+
+	chartDir := filepath.Join(dir, root)
+
+	if _, err := os.Stat(filepath.Join(chartDir, chartFile)); root == "" || err != nil {
+		return "", fmt.Errorf("chart archive has no %s", chartFile)
+	}
+
+	return chartDir, nil
+}
+
+// writeFile streams the content of r to the file at target, creating its parent directories, and
+// returns the number of bytes written. It fails if the content exceeds limit bytes.
+func writeFile(target string, r io.Reader, limit int64) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return 0, fmt.Errorf("failed to create directory for %s: %w", target, err)
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", target, err)
+	}
+
+	written, err := io.Copy(f, io.LimitReader(r, limit+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return written, fmt.Errorf("failed to write %s: %w", target, err)
+	}
+
+	if written > limit {
+		return written, fmt.Errorf("%w: chart files exceed %d bytes", ErrChartTooLarge, limit)
+	}
+
+	return written, nil
+}
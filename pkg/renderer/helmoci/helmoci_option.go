@@ -0,0 +1,144 @@
+package helmoci
+
+import (
+	"crypto/tls"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Factory creates the renderer of a chart extracted to dir.
+type Factory func(dir string) (types.Renderer, error)
+
+// Options represents the configuration of the Helm OCI renderer.
+type Options struct {
+	// RemoteOptions are passed to the registry client, e.g. to configure authentication or transport.
+	RemoteOptions []remote.Option
+
+	// TLSConfig is the TLS configuration used to connect to the registry, e.g. to trust a private
+	// certificate authority or to present a client certificate. If nil, the system defaults are used.
+	TLSConfig *tls.Config
+
+	// Insecure allows pulling from registries over plain HTTP.
+	Insecure bool
+
+	// Digest pins the chart to a manifest digest, e.g. "sha256:...".
+	Digest string
+
+	// MaxChartSize is the maximum size, in bytes, of the packaged chart and of its extracted files
+	// in total. If not positive, DefaultMaxChartSize is used.
+	MaxChartSize int64
+
+	// Factory creates the renderer of the pulled chart. If nil, the renderer registered under
+	// auto.KindHelm in the renderer registry is used.
+	Factory Factory
+
+	// Filters are renderer-specific filters applied to the rendered objects.
+	Filters []types.Filter
+
+	// Transformers are renderer-specific transformers applied to the rendered objects.
+	Transformers []types.Transformer
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.RemoteOptions = append(target.RemoteOptions, opts.RemoteOptions...)
+	target.Filters = append(target.Filters, opts.Filters...)
+	target.Transformers = append(target.Transformers, opts.Transformers...)
+	target.Insecure = opts.Insecure
+
+	if opts.TLSConfig != nil {
+		target.TLSConfig = opts.TLSConfig
+	}
+
+	if opts.Digest != "" {
+		target.Digest = opts.Digest
+	}
+
+	if opts.MaxChartSize != 0 {
+		target.MaxChartSize = opts.MaxChartSize
+	}
+
+	if opts.Factory != nil {
+		target.Factory = opts.Factory
+	}
+}
+
+// WithRemoteOptions adds options passed to the registry client.
+func WithRemoteOptions(opts ...remote.Option) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.RemoteOptions = append(o.RemoteOptions, opts...)
+	})
+}
+
+// WithKeychain authenticates to the registry with credentials resolved from keychain,
+// e.g. authn.DefaultKeychain to use the Docker configuration written by "helm registry login".
+func WithKeychain(keychain authn.Keychain) Option {
+	return WithRemoteOptions(remote.WithAuthFromKeychain(keychain))
+}
+
+// WithBasicAuth authenticates to the registry with a username and a password or token.
+func WithBasicAuth(username string, password string) Option {
+	return WithRemoteOptions(remote.WithAuth(&authn.Basic{
+		Username: username,
+		Password: password,
+	}))
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to the registry.
+func WithTLSConfig(config *tls.Config) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.TLSConfig = config
+	})
+}
+
+// WithInsecure allows pulling from registries over plain HTTP.
+func WithInsecure(insecure bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Insecure = insecure
+	})
+}
+
+// WithDigest pins the chart to a manifest digest, e.g. "sha256:...". When a version is also given,
+// it must resolve to the same digest.
+func WithDigest(digest string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Digest = digest
+	})
+}
+
+// WithMaxChartSize sets the maximum size, in bytes, of the packaged chart and of its extracted files
+// in total, DefaultMaxChartSize by default. Larger charts fail to pull with ErrChartTooLarge.
+func WithMaxChartSize(size int64) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.MaxChartSize = size
+	})
+}
+
+// WithFactory sets the factory creating the renderer of the pulled chart, e.g. to use a configured
+// Helm renderer instead of the one in the renderer registry.
+func WithFactory(factory Factory) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Factory = factory
+	})
+}
+
+// WithFilter adds a renderer-specific filter applied to the rendered objects.
+func WithFilter(f types.Filter) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Filters = append(o.Filters, f)
+	})
+}
+
+// WithTransformer adds a renderer-specific transformer applied to the rendered objects.
+func WithTransformer(t types.Transformer) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Transformers = append(o.Transformers, t)
+	})
+}
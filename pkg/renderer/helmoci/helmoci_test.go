@@ -0,0 +1,581 @@
+package helmoci_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/k8s-manifest-kit/engine/pkg/renderer"
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/helmoci"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+const mediaTypeConfig = "application/vnd.cncf.helm.config.v1+json"
+
+func TestNew(t *testing.T) {
+
+	t.Run("should reject an invalid reference", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := helmoci.New("oci://INVALID::ref", "")
+		g.Expect(err).Should(HaveOccurred())
+	})
+
+	t.Run("should reject an invalid version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := helmoci.New("oci://example.com/charts/app", "not a version")
+		g.Expect(err).Should(MatchError(ContainSubstring("invalid chart version")))
+	})
+
+	t.Run("should reject a tag conflicting with the version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := helmoci.New("oci://example.com/charts/app:1.0.0", "2.0.0")
+		g.Expect(err).Should(MatchError(ContainSubstring("conflicts with version")))
+	})
+
+	t.Run("should reject a digest conflicting with the pinned one", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := helmoci.New(
+			"oci://example.com/charts/app@sha256:"+strings.Repeat("a", 64),
+			"",
+			helmoci.WithDigest("sha256:"+strings.Repeat("b", 64)),
+		)
+		g.Expect(err).Should(MatchError(helmoci.ErrDigestMismatch))
+	})
+
+	t.Run("should be named helmoci", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := helmoci.New("oci://example.com/charts/app", "1.0.0")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(r.Name()).Should(Equal("helmoci"))
+	})
+}
+
+func TestProcess(t *testing.T) {
+
+	t.Run("should render the chart with the render-time values", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, nil)
+
+		reg.pushChart(t, "charts/app", "1.0.0")
+
+		r, err := helmoci.New("oci://"+reg.host+"/charts/app", "1.0.0", helmoci.WithFactory(newChartRenderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), map[string]any{"name": "web"})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("web"))
+		g.Expect(objects[0].GetLabels()).Should(HaveKeyWithValue("chart-version", "1.0.0"))
+	})
+
+	t.Run("should resolve versions", func(t *testing.T) {
+		reg := startRegistry(t, nil)
+
+		for _, version := range []string{"1.0.0", "1.2.0", "1.3.0-rc.1", "2.0.0", "2.1.0+build.1"} {
+			reg.pushChart(t, "charts/app", version)
+		}
+
+		tests := []struct {
+			version  string
+			expected string
+		}{
+			{version: "", expected: "2.1.0+build.1"},
+			{version: "^1", expected: "1.2.0"},
+			{version: ">=1.0.0 <1.2.0", expected: "1.0.0"},
+			{version: "1.3.0-rc.1", expected: "1.3.0-rc.1"},
+			{version: "2.1.0+build.1", expected: "2.1.0+build.1"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.version, func(t *testing.T) {
+				g := NewWithT(t)
+
+				r, err := helmoci.New(reg.host+"/charts/app", tt.version, helmoci.WithFactory(newChartRenderer))
+				g.Expect(err).ShouldNot(HaveOccurred())
+
+				objects, err := r.Process(t.Context(), nil)
+				g.Expect(err).ShouldNot(HaveOccurred())
+				g.Expect(objects[0].GetLabels()).Should(HaveKeyWithValue("chart-version", tt.expected))
+			})
+		}
+	})
+
+	t.Run("should report unmatched versions as pull errors", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, nil)
+
+		reg.pushChart(t, "charts/app", "1.0.0")
+
+		r, err := helmoci.New(reg.host+"/charts/app", "^2", helmoci.WithFactory(newChartRenderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(helmoci.ErrPull))
+		g.Expect(err).Should(MatchError(helmoci.ErrVersionNotFound))
+	})
+
+	t.Run("should pin the chart to a digest", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, nil)
+
+		digest := reg.pushChart(t, "charts/app", "1.0.0")
+		reg.pushChart(t, "charts/app", "1.1.0")
+
+		r, err := helmoci.New(reg.host+"/charts/app@"+digest, "", helmoci.WithFactory(newChartRenderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects[0].GetLabels()).Should(HaveKeyWithValue("chart-version", "1.0.0"))
+	})
+
+	t.Run("should fail when the version does not have the pinned digest", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, nil)
+
+		digest := reg.pushChart(t, "charts/app", "1.0.0")
+		reg.pushChart(t, "charts/app", "1.1.0")
+
+		r, err := helmoci.New(reg.host+"/charts/app", "1.1.0",
+			helmoci.WithDigest(digest),
+			helmoci.WithFactory(newChartRenderer),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(helmoci.ErrPull))
+		g.Expect(err).Should(MatchError(helmoci.ErrDigestMismatch))
+	})
+
+	t.Run("should authenticate with basic auth", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, &authn.Basic{Username: "user", Password: "secret"})
+
+		reg.pushChart(t, "charts/app", "1.0.0")
+
+		r, err := helmoci.New(reg.host+"/charts/app", "1.0.0",
+			helmoci.WithBasicAuth("user", "secret"),
+			helmoci.WithFactory(newChartRenderer),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	t.Run("should report authentication failures as pull errors", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, &authn.Basic{Username: "user", Password: "secret"})
+
+		reg.pushChart(t, "charts/app", "1.0.0")
+
+		r, err := helmoci.New(reg.host+"/charts/app", "1.0.0", helmoci.WithFactory(newChartRenderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(helmoci.ErrPull))
+		g.Expect(err).ShouldNot(MatchError(helmoci.ErrRender))
+	})
+
+	t.Run("should connect with the TLS configuration", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startTLSRegistry(t)
+
+		reg.pushChart(t, "charts/app", "1.0.0")
+
+		r, err := helmoci.New(reg.host+"/charts/app", "1.0.0", helmoci.WithFactory(newChartRenderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(helmoci.ErrPull))
+
+		r, err = helmoci.New(reg.host+"/charts/app", "1.0.0",
+			helmoci.WithTLSConfig(&tls.Config{RootCAs: reg.roots, MinVersion: tls.VersionTLS12}),
+			helmoci.WithFactory(newChartRenderer),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	t.Run("should report artifacts without chart as pull errors", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, nil)
+
+		reg.push(t, "charts/app:1.0.0", static.NewLayer([]byte("kind: List"), "application/yaml"))
+
+		r, err := helmoci.New(reg.host+"/charts/app", "1.0.0", helmoci.WithFactory(newChartRenderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(helmoci.ErrNotChart))
+	})
+
+	t.Run("should reject a chart archive larger than the maximum size", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, nil)
+
+		reg.pushChart(t, "charts/app", "1.0.0")
+
+		r, err := helmoci.New(reg.host+"/charts/app", "1.0.0",
+			helmoci.WithFactory(newChartRenderer),
+			helmoci.WithMaxChartSize(16),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(helmoci.ErrChartTooLarge))
+		g.Expect(err).Should(MatchError(helmoci.ErrPull))
+	})
+
+	t.Run("should reject chart files larger than the maximum size", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, nil)
+
+		// the archive is small, but its files are not
+		archive := tarGzip(t, map[string]string{
+			"app/Chart.yaml":  "apiVersion: v2\nname: app\nversion: 1.0.0\n",
+			"app/values.yaml": "padding: " + strings.Repeat("a", 64<<10) + "\n",
+		})
+		reg.push(t, "charts/app:1.0.0", static.NewLayer(archive, helmoci.MediaTypeChart))
+
+		r, err := helmoci.New(reg.host+"/charts/app", "1.0.0",
+			helmoci.WithFactory(newChartRenderer),
+			helmoci.WithMaxChartSize(8<<10),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(helmoci.ErrChartTooLarge))
+		g.Expect(err).Should(MatchError(helmoci.ErrPull))
+		g.Expect(err).Should(MatchError(ContainSubstring("chart files exceed")))
+	})
+
+	t.Run("should report rendering failures as render errors", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, nil)
+
+		reg.pushChart(t, "charts/app", "1.0.0")
+
+		r, err := helmoci.New(reg.host+"/charts/app", "1.0.0", helmoci.WithFactory(func(dir string) (types.Renderer, error) {
+			return &chartRenderer{dir: dir, err: errors.New("template error")}, nil
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(helmoci.ErrRender))
+		g.Expect(err).Should(MatchError(ContainSubstring("template error")))
+		g.Expect(err).ShouldNot(MatchError(helmoci.ErrPull))
+	})
+
+	t.Run("should use the registered helm renderer by default", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, nil)
+
+		reg.pushChart(t, "charts/app", "1.0.0")
+
+		r, err := helmoci.New(reg.host+"/charts/app", "1.0.0")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(helmoci.ErrRender))
+		g.Expect(err).Should(MatchError(renderer.ErrUnknownKind))
+	})
+
+	t.Run("should pull the chart once", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, nil)
+
+		reg.pushChart(t, "charts/app", "1.0.0")
+
+		r, err := helmoci.New(reg.host+"/charts/app", "", helmoci.WithFactory(newChartRenderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		requests := reg.requests.Load()
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(reg.requests.Load()).Should(Equal(requests))
+	})
+
+	t.Run("should apply renderer-specific filters and transformers", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, nil)
+
+		reg.pushChart(t, "charts/app", "1.0.0")
+
+		r, err := helmoci.New(reg.host+"/charts/app", "1.0.0",
+			helmoci.WithFactory(newChartRenderer),
+			helmoci.WithTransformer(func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				obj.SetNamespace("rendered")
+
+				return obj, nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects[0].GetNamespace()).Should(Equal("rendered"))
+	})
+}
+
+func TestProcessVersion(t *testing.T) {
+
+	t.Run("should pass the Kubernetes version to the chart renderer", func(t *testing.T) {
+		g := NewWithT(t)
+		reg := startRegistry(t, nil)
+
+		reg.pushChart(t, "charts/app", "1.0.0")
+
+		r, err := helmoci.New(reg.host+"/charts/app", "1.0.0", helmoci.WithFactory(newChartRenderer))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		versionAware, ok := r.(types.VersionAwareRenderer)
+		g.Expect(ok).Should(BeTrue())
+
+		objects, err := versionAware.ProcessVersion(t.Context(), nil, "v1.31.0")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects[0].GetLabels()).Should(HaveKeyWithValue("kube-version", "v1.31.0"))
+	})
+}
+
+// chartRenderer stands in for a Helm renderer: it returns a ConfigMap named after the "name" value
+// and labeled with the version of the chart in dir.
+type chartRenderer struct {
+	dir string
+	err error
+}
+
+func newChartRenderer(dir string) (types.Renderer, error) {
+	return &chartRenderer{dir: dir}, nil
+}
+
+func (r *chartRenderer) Name() string {
+	return "chart"
+}
+
+func (r *chartRenderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	return r.ProcessVersion(ctx, values, "")
+}
+
+func (r *chartRenderer) ProcessVersion(
+	_ context.Context,
+	values map[string]any,
+	version string,
+) ([]unstructured.Unstructured, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	content, err := os.ReadFile(filepath.Join(r.dir, "Chart.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var chart struct {
+		Version string `json:"version"`
+	}
+
+	if err := yaml.Unmarshal(content, &chart); err != nil {
+		return nil, err
+	}
+
+	name, _ := values["name"].(string)
+	if name == "" {
+		name = "default"
+	}
+
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName(name)
+	obj.SetLabels(map[string]string{"chart-version": chart.Version, "kube-version": version})
+
+	return []unstructured.Unstructured{obj}, nil
+}
+
+type testRegistry struct {
+	host      string
+	transport http.RoundTripper
+	roots     *x509.CertPool
+	requests  atomic.Int32
+}
+
+// startRegistry starts an in-memory registry, optionally requiring basic authentication.
+func startRegistry(t *testing.T, auth *authn.Basic) *testRegistry {
+	t.Helper()
+
+	reg := testRegistry{transport: http.DefaultTransport}
+
+	server := httptest.NewServer(reg.handler(auth))
+	t.Cleanup(server.Close)
+
+	reg.host = strings.TrimPrefix(server.URL, "http://")
+
+	return &reg
+}
+
+// startTLSRegistry starts an in-memory registry served over TLS with a self-signed certificate.
+func startTLSRegistry(t *testing.T) *testRegistry {
+	t.Helper()
+
+	reg := testRegistry{roots: x509.NewCertPool()}
+
+	server := httptest.NewTLSServer(reg.handler(nil))
+	t.Cleanup(server.Close)
+
+	reg.host = strings.TrimPrefix(server.URL, "https://")
+	reg.transport = server.Client().Transport
+	reg.roots.AddCert(server.Certificate())
+
+	return &reg
+}
+
+func (reg *testRegistry) handler(auth *authn.Basic) http.Handler {
+	next := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if auth != nil {
+			user, password, ok := req.BasicAuth()
+			if !ok || user != auth.Username || password != auth.Password {
+				w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+		}
+
+		if req.Method == http.MethodGet {
+			reg.requests.Add(1)
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// pushChart uploads a chart in the given version, the way Helm does, and returns its digest.
+func (reg *testRegistry) pushChart(t *testing.T, repository string, version string) string {
+	t.Helper()
+
+	archive := tarGzip(t, map[string]string{
+		"app/Chart.yaml":            "apiVersion: v2\nname: app\nversion: " + version + "\n",
+		"app/values.yaml":           "name: default\n",
+		"app/templates/config.yaml": "apiVersion: v1\nkind: ConfigMap\n",
+	})
+
+	return reg.push(t,
+		repository+":"+strings.ReplaceAll(version, "+", "_"),
+		static.NewLayer(archive, helmoci.MediaTypeChart),
+	)
+}
+
+// push uploads an artifact with a Helm config and the given layer, and returns its digest.
+func (reg *testRegistry) push(t *testing.T, ref string, layer v1.Layer) string {
+	t.Helper()
+
+	img, err := mutate.Append(empty.Image, mutate.Addendum{Layer: layer, MediaType: mustMediaType(t, layer)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img = mutate.ConfigMediaType(img, mediaTypeConfig)
+
+	tag, err := name.ParseReference(reg.host + "/" + ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = remote.Write(tag, img,
+		remote.WithAuth(&authn.Basic{Username: "user", Password: "secret"}),
+		remote.WithTransport(reg.transport),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return digest.String()
+}
+
+func mustMediaType(t *testing.T, layer v1.Layer) ggcrtypes.MediaType {
+	t.Helper()
+
+	mediaType, err := layer.MediaType()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return mediaType
+}
+
+func tarGzip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for name, content := range files {
+		header := tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(&header); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
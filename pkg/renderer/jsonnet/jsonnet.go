@@ -0,0 +1,191 @@
+// Package jsonnet provides a renderer evaluating Jsonnet programs into Kubernetes objects.
+package jsonnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	gojsonnet "github.com/google/go-jsonnet"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sjson "k8s.io/apimachinery/pkg/util/json"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+const rendererName = "jsonnet"
+
+var (
+	// ErrEntrypointEmpty is returned when the entrypoint is empty.
+	ErrEntrypointEmpty = errors.New("jsonnet entrypoint cannot be empty")
+
+	// ErrUnexpectedOutput is returned when the program does not evaluate to Kubernetes objects.
+	ErrUnexpectedOutput = errors.New("unexpected jsonnet output")
+)
+
+// Renderer evaluates a Jsonnet file into Kubernetes objects.
+type Renderer struct {
+	entrypoint string
+	options    Options
+}
+
+// New returns a renderer evaluating the Jsonnet file at entrypoint.
+//
+// The program must evaluate to one of:
+//   - a Kubernetes object (an object with apiVersion and kind)
+//   - an array of Kubernetes objects; nested arrays are flattened
+//   - an object whose fields are Kubernetes objects, or such objects nested further;
+//     fields are visited in sorted order so the output is deterministic
+//
+// Render-time values are exposed to the program as external variables, see WithExtStr and WithExtCode.
+// Evaluation errors include the Jsonnet stack trace.
+func New(entrypoint string, opts ...Option) (types.Renderer, error) {
+	if strings.TrimSpace(entrypoint) == "" {
+		return nil, ErrEntrypointEmpty
+	}
+
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	r := Renderer{
+		entrypoint: entrypoint,
+		options:    options,
+	}
+
+	return &r, nil
+}
+
+// Name implements types.Renderer.
+func (r *Renderer) Name() string {
+	return rendererName
+}
+
+// Process implements types.Renderer.
+func (r *Renderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("jsonnet render canceled: %w", err)
+	}
+
+	vm, err := r.vm(values)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := vm.EvaluateFile(r.entrypoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate %s: %w", r.entrypoint, err)
+	}
+
+	// Decode with the apimachinery decoder so that integers are kept as int64, as in
+	// objects decoded by client-go, instead of being converted to float64.
+	var result any
+	if err := k8sjson.Unmarshal([]byte(out), &result); err != nil {
+		return nil, fmt.Errorf("failed to decode output of %s: %w", r.entrypoint, err)
+	}
+
+	objects := make([]unstructured.Unstructured, 0)
+	if err := collect(result, "$", &objects); err != nil {
+		return nil, fmt.Errorf("%s: %w", r.entrypoint, err)
+	}
+
+	return pipeline.Apply(ctx, objects, r.options.Filters, r.options.Transformers)
+}
+
+// vm returns a Jsonnet VM configured with the import paths and external variables.
+// A new VM is created for every evaluation since VMs are not safe for concurrent use.
+func (r *Renderer) vm(values map[string]any) (*gojsonnet.VM, error) {
+	vm := gojsonnet.MakeVM()
+	vm.Importer(&gojsonnet.FileImporter{JPaths: slices.Clone(r.options.ImportPaths)})
+
+	for name, key := range r.options.ExtStr {
+		value, ok := lookup(values, key)
+		if !ok {
+			continue
+		}
+
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("value %q for ext-str %q must be a string, got %T", key, name, value)
+		}
+
+		vm.ExtVar(name, s)
+	}
+
+	for name, key := range r.options.ExtCode {
+		value, ok := lookup(values, key)
+		if !ok {
+			continue
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode value %q for ext-code %q: %w", key, name, err)
+		}
+
+		vm.ExtCode(name, string(data))
+	}
+
+	return vm, nil
+}
+
+// lookup returns the value at the dot-separated key.
+// Missing keys are left unbound, so Jsonnet reports them when the program references them.
+func lookup(values map[string]any, key string) (any, bool) {
+	value, found, err := unstructured.NestedFieldNoCopy(values, strings.Split(key, ".")...)
+	if err != nil || !found {
+		return nil, false
+	}
+
+	return value, true
+}
+
+// collect appends the Kubernetes objects found in v to objects.
+func collect(v any, path string, objects *[]unstructured.Unstructured) error {
+	switch value := v.(type) {
+	case []any:
+		for i, item := range value {
+			if err := collect(item, fmt.Sprintf("%s[%d]", path, i), objects); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case map[string]any:
+		if isObject(value) {
+			*objects = append(*objects, unstructured.Unstructured{Object: value})
+
+			return nil
+		}
+
+		for _, k := range slices.Sorted(maps.Keys(value)) {
+			if err := collect(value[k], path+"."+k, objects); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		return fmt.Errorf(
+			"%w: %s is a %T, expected a Kubernetes object or an array of objects",
+			ErrUnexpectedOutput,
+			path,
+			v,
+		)
+	}
+}
+
+// isObject reports whether m has the apiVersion and kind of a Kubernetes object.
+func isObject(m map[string]any) bool {
+	apiVersion, _ := m["apiVersion"].(string)
+	kind, _ := m["kind"].(string)
+
+	return apiVersion != "" && kind != ""
+}
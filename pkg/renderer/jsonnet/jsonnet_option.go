@@ -0,0 +1,102 @@
+package jsonnet
+
+import (
+	"maps"
+
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the Jsonnet renderer.
+type Options struct {
+	// ImportPaths are the library search paths used to resolve imports (the --jpath flag).
+	ImportPaths []string
+
+	// ExtStr maps external variable names to render-time value keys. Each variable receives
+	// the value, which must be a string, as with --ext-str.
+	ExtStr map[string]string
+
+	// ExtCode maps external variable names to render-time value keys. Each variable receives
+	// the JSON encoding of the value, as with --ext-code.
+	ExtCode map[string]string
+
+	// Filters are renderer-specific filters applied to the evaluated objects.
+	Filters []types.Filter
+
+	// Transformers are renderer-specific transformers applied to the evaluated objects.
+	Transformers []types.Transformer
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.ImportPaths = append(target.ImportPaths, opts.ImportPaths...)
+	target.Filters = append(target.Filters, opts.Filters...)
+	target.Transformers = append(target.Transformers, opts.Transformers...)
+
+	if len(opts.ExtStr) > 0 {
+		if target.ExtStr == nil {
+			target.ExtStr = make(map[string]string, len(opts.ExtStr))
+		}
+
+		maps.Copy(target.ExtStr, opts.ExtStr)
+	}
+
+	if len(opts.ExtCode) > 0 {
+		if target.ExtCode == nil {
+			target.ExtCode = make(map[string]string, len(opts.ExtCode))
+		}
+
+		maps.Copy(target.ExtCode, opts.ExtCode)
+	}
+}
+
+// WithImportPaths appends library search paths used to resolve imports.
+// Paths are searched in order, after the directory of the importing file.
+func WithImportPaths(paths ...string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.ImportPaths = append(o.ImportPaths, paths...)
+	})
+}
+
+// WithExtStr binds the external variable name to the render-time value at key, passed as a string.
+// Nested values are addressed with dot-separated keys (e.g. "image.tag").
+func WithExtStr(name string, key string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if o.ExtStr == nil {
+			o.ExtStr = make(map[string]string)
+		}
+
+		o.ExtStr[name] = key
+	})
+}
+
+// WithExtCode binds the external variable name to the render-time value at key, passed as Jsonnet code.
+// The value is JSON encoded, so maps, slices, numbers and booleans keep their type.
+// Nested values are addressed with dot-separated keys (e.g. "image.tag").
+func WithExtCode(name string, key string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if o.ExtCode == nil {
+			o.ExtCode = make(map[string]string)
+		}
+
+		o.ExtCode[name] = key
+	})
+}
+
+// WithFilter adds a renderer-specific filter applied to the evaluated objects.
+func WithFilter(f types.Filter) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Filters = append(o.Filters, f)
+	})
+}
+
+// WithTransformer adds a renderer-specific transformer applied to the evaluated objects.
+func WithTransformer(t types.Transformer) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Transformers = append(o.Transformers, t)
+	})
+}
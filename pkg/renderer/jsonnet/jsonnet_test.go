@@ -0,0 +1,261 @@
+package jsonnet_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/jsonnet"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	singleObject = `{
+  apiVersion: 'v1',
+  kind: 'ConfigMap',
+  metadata: { name: 'single' },
+}`
+
+	objectArray = `[
+  { apiVersion: 'v1', kind: 'ConfigMap', metadata: { name: 'first' } },
+  [
+    { apiVersion: 'v1', kind: 'ConfigMap', metadata: { name: 'second' } },
+  ],
+]`
+
+	objectTree = `{
+  zeta: { apiVersion: 'v1', kind: 'ConfigMap', metadata: { name: 'zeta' } },
+  alpha: {
+    service: { apiVersion: 'v1', kind: 'Service', metadata: { name: 'alpha' } },
+  },
+}`
+
+	extVars = `{
+  apiVersion: 'apps/v1',
+  kind: 'Deployment',
+  metadata: { name: std.extVar('name') },
+  spec: { replicas: std.extVar('replicas'), template: std.extVar('template') },
+}`
+
+	importing = `local lib = import 'lib.libsonnet';
+lib.configMap('imported')`
+
+	library = `{
+  configMap(name):: { apiVersion: 'v1', kind: 'ConfigMap', metadata: { name: name } },
+}`
+
+	failing = `local check(x) = if x > 1 then error 'too many replicas' else x;
+{ apiVersion: 'v1', kind: 'ConfigMap', metadata: { name: 'x' }, data: { n: check(2) } }`
+
+	notAnObject = `[{ apiVersion: 'v1', kind: 'ConfigMap', metadata: { name: 'x' } }, 'not an object']`
+)
+
+func TestNew(t *testing.T) {
+
+	t.Run("should reject an empty entrypoint", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := jsonnet.New(" ")
+		g.Expect(err).Should(MatchError(jsonnet.ErrEntrypointEmpty))
+	})
+
+	t.Run("should be named jsonnet", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := jsonnet.New("main.jsonnet")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(r.Name()).Should(Equal("jsonnet"))
+	})
+}
+
+func TestProcess(t *testing.T) {
+
+	t.Run("should render a single object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := jsonnet.New(writeFile(t, t.TempDir(), "main.jsonnet", singleObject))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"single"}))
+	})
+
+	t.Run("should flatten arrays of objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := jsonnet.New(writeFile(t, t.TempDir(), "main.jsonnet", objectArray))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"first", "second"}))
+	})
+
+	t.Run("should collect nested objects in sorted field order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := jsonnet.New(writeFile(t, t.TempDir(), "main.jsonnet", objectTree))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"alpha", "zeta"}))
+		g.Expect(objects[0].GetKind()).Should(Equal("Service"))
+	})
+
+	t.Run("should bind values to external variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := jsonnet.New(
+			writeFile(t, t.TempDir(), "main.jsonnet", extVars),
+			jsonnet.WithExtStr("name", "app.name"),
+			jsonnet.WithExtCode("replicas", "replicas"),
+			jsonnet.WithExtCode("template", "template"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), map[string]any{
+			"app":      map[string]any{"name": "web"},
+			"replicas": 3,
+			"template": map[string]any{"metadata": map[string]any{"labels": map[string]any{"app": "web"}}},
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("web"))
+
+		replicas, _, _ := unstructured.NestedInt64(objects[0].Object, "spec", "replicas")
+		g.Expect(replicas).Should(Equal(int64(3)))
+
+		app, _, _ := unstructured.NestedString(objects[0].Object, "spec", "template", "metadata", "labels", "app")
+		g.Expect(app).Should(Equal("web"))
+	})
+
+	t.Run("should reject non-string ext-str values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := jsonnet.New(
+			writeFile(t, t.TempDir(), "main.jsonnet", extVars),
+			jsonnet.WithExtStr("name", "name"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), map[string]any{"name": 1})
+		g.Expect(err).Should(MatchError(ContainSubstring(`ext-str "name" must be a string`)))
+	})
+
+	t.Run("should report unbound external variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := jsonnet.New(
+			writeFile(t, t.TempDir(), "main.jsonnet", extVars),
+			jsonnet.WithExtStr("name", "name"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), map[string]any{})
+		g.Expect(err).Should(MatchError(ContainSubstring("Undefined external variable: name")))
+	})
+
+	t.Run("should resolve imports from import paths", func(t *testing.T) {
+		g := NewWithT(t)
+
+		lib := t.TempDir()
+		writeFile(t, lib, "lib.libsonnet", library)
+
+		r, err := jsonnet.New(
+			writeFile(t, t.TempDir(), "main.jsonnet", importing),
+			jsonnet.WithImportPaths(lib),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"imported"}))
+	})
+
+	t.Run("should include the stack trace in evaluation errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		entrypoint := writeFile(t, t.TempDir(), "main.jsonnet", failing)
+
+		r, err := jsonnet.New(entrypoint)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(HaveOccurred())
+		g.Expect(err.Error()).Should(ContainSubstring("too many replicas"))
+		g.Expect(err.Error()).Should(ContainSubstring(entrypoint + ":1:"))
+		g.Expect(err.Error()).Should(ContainSubstring("function <check>"))
+	})
+
+	t.Run("should reject output that is not a Kubernetes object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := jsonnet.New(writeFile(t, t.TempDir(), "main.jsonnet", notAnObject))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(errors.Is(err, jsonnet.ErrUnexpectedOutput)).Should(BeTrue())
+	})
+
+	t.Run("should apply renderer-specific filters and transformers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := jsonnet.New(
+			writeFile(t, t.TempDir(), "main.jsonnet", objectArray),
+			jsonnet.WithFilter(func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				return obj.GetName() == "second", nil
+			}),
+			jsonnet.WithTransformer(func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				obj.SetNamespace("rendered")
+
+				return obj, nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"second"}))
+		g.Expect(objects[0].GetNamespace()).Should(Equal("rendered"))
+	})
+
+	t.Run("should stop on a canceled context", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := jsonnet.New(writeFile(t, t.TempDir(), "main.jsonnet", singleObject))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err = r.Process(ctx, nil)
+		g.Expect(err).Should(MatchError(context.Canceled))
+	})
+}
+
+func writeFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func names(objects []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, obj.GetName())
+	}
+
+	return result
+}
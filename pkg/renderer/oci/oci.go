@@ -0,0 +1,210 @@
+// Package oci provides a renderer extracting Kubernetes manifests bundled in OCI artifacts.
+package oci
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/decode"
+)
+
+const rendererName = "oci"
+
+var (
+	// ErrFetch is returned when the artifact cannot be pulled, e.g. because of network or
+	// authentication failures, or because the reference does not exist.
+	ErrFetch = errors.New("failed to fetch OCI artifact")
+
+	// ErrParse is returned when the content of a layer cannot be decoded into Kubernetes objects.
+	ErrParse = errors.New("failed to parse OCI artifact content")
+)
+
+// Renderer extracts the Kubernetes objects bundled in an OCI artifact.
+type Renderer struct {
+	ref     name.Reference
+	options Options
+}
+
+// New returns a renderer pulling the artifact at ref (e.g. "ghcr.io/org/bundle:v1.0.0").
+//
+// Each layer, in manifest order, is read either as a YAML or JSON stream or, when its media type
+// denotes a tar archive (optionally compressed), as an archive whose .yaml, .yml and .json files
+// are decoded in sorted path order. Render-time values are ignored: bundles are static.
+//
+// Errors wrap ErrFetch for registry failures and ErrParse for content failures,
+// so the two can be told apart with errors.Is.
+func New(ref string, opts ...Option) (types.Renderer, error) {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	var nameOpts []name.Option
+	if options.Insecure {
+		nameOpts = append(nameOpts, name.Insecure)
+	}
+
+	parsed, err := name.ParseReference(ref, nameOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI reference %q: %w", ref, err)
+	}
+
+	r := Renderer{
+		ref:     parsed,
+		options: options,
+	}
+
+	return &r, nil
+}
+
+// Name implements types.Renderer.
+func (r *Renderer) Name() string {
+	return rendererName
+}
+
+// Process implements types.Renderer.
+func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	remoteOpts := append(slices.Clone(r.options.RemoteOptions), remote.WithContext(ctx))
+
+	img, err := remote.Image(r.ref, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrFetch, r.ref, err)
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrFetch, r.ref, err)
+	}
+
+	objects := make([]unstructured.Unstructured, 0)
+
+	for _, layer := range layers {
+		mediaType, err := layer.MediaType()
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrFetch, r.ref, err)
+		}
+
+		if len(r.options.MediaTypes) > 0 && !slices.Contains(r.options.MediaTypes, string(mediaType)) {
+			continue
+		}
+
+		decoded, err := r.decodeLayer(layer, string(mediaType))
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, decoded...)
+	}
+
+	return pipeline.Apply(ctx, objects, r.options.Filters, r.options.Transformers)
+}
+
+// decodeLayer returns the objects contained in a layer.
+func (r *Renderer) decodeLayer(layer v1.Layer, mediaType string) ([]unstructured.Unstructured, error) {
+	digest, err := layer.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrFetch, r.ref, err)
+	}
+
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: layer %s: %w", ErrFetch, r.ref, digest, err)
+	}
+
+	defer func() { _ = rc.Close() }()
+
+	if !strings.Contains(mediaType, "tar") {
+		content, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: layer %s: %w", ErrFetch, r.ref, digest, err)
+		}
+
+		objects, err := decode.YAML(content, r.options.Decode...)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: layer %s: %w", ErrParse, r.ref, digest, err)
+		}
+
+		return objects, nil
+	}
+
+	files, err := readArchive(rc)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: layer %s: %w", ErrParse, r.ref, digest, err)
+	}
+
+	objects := make([]unstructured.Unstructured, 0)
+
+	for _, file := range files {
+		decoded, err := decode.YAML(file.content, r.options.Decode...)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: layer %s: %s: %w", ErrParse, r.ref, digest, file.name, err)
+		}
+
+		objects = append(objects, decoded...)
+	}
+
+	return objects, nil
+}
+
+type archiveFile struct {
+	name    string
+	content []byte
+}
+
+// readArchive returns the manifest files of a tar archive, sorted by path.
+func readArchive(r io.Reader) ([]archiveFile, error) {
+	files := make([]archiveFile, 0)
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg || !isManifest(header.Name) {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+
+		files = append(files, archiveFile{name: header.Name, content: content})
+	}
+
+	slices.SortFunc(files, func(a archiveFile, b archiveFile) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	return files, nil
+}
+
+// isManifest reports whether the file name has a YAML or JSON extension.
+func isManifest(file string) bool {
+	switch strings.ToLower(path.Ext(file)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,95 @@
+package oci
+
+import (
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/decode"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the OCI renderer.
+type Options struct {
+	// RemoteOptions are passed to the registry client, e.g. to configure authentication or transport.
+	RemoteOptions []remote.Option
+
+	// MediaTypes restricts the layers read to those with one of the given media types.
+	// If empty, every layer is read.
+	MediaTypes []string
+
+	// Insecure allows pulling from registries over plain HTTP.
+	Insecure bool
+
+	// Filters are renderer-specific filters applied to the extracted objects.
+	Filters []types.Filter
+
+	// Transformers are renderer-specific transformers applied to the extracted objects.
+	Transformers []types.Transformer
+
+	// Decode are the options of the decoding of the layers, such as decode.WithEmptyError.
+	Decode []decode.Option
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.RemoteOptions = append(target.RemoteOptions, opts.RemoteOptions...)
+	target.MediaTypes = append(target.MediaTypes, opts.MediaTypes...)
+	target.Filters = append(target.Filters, opts.Filters...)
+	target.Transformers = append(target.Transformers, opts.Transformers...)
+	target.Decode = append(target.Decode, opts.Decode...)
+	target.Insecure = opts.Insecure
+}
+
+// WithRemoteOptions adds options passed to the registry client.
+func WithRemoteOptions(opts ...remote.Option) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.RemoteOptions = append(o.RemoteOptions, opts...)
+	})
+}
+
+// WithKeychain authenticates to the registry with credentials resolved from keychain,
+// e.g. authn.DefaultKeychain to use the Docker configuration.
+func WithKeychain(keychain authn.Keychain) Option {
+	return WithRemoteOptions(remote.WithAuthFromKeychain(keychain))
+}
+
+// WithMediaTypes restricts the layers read to those with one of the given media types,
+// so that e.g. configuration or documentation layers bundled in the artifact are ignored.
+func WithMediaTypes(mediaTypes ...string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.MediaTypes = append(o.MediaTypes, mediaTypes...)
+	})
+}
+
+// WithInsecure allows pulling from registries over plain HTTP.
+func WithInsecure(insecure bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Insecure = insecure
+	})
+}
+
+// WithFilter adds a renderer-specific filter applied to the extracted objects.
+func WithFilter(f types.Filter) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Filters = append(o.Filters, f)
+	})
+}
+
+// WithTransformer adds a renderer-specific transformer applied to the extracted objects.
+func WithTransformer(t types.Transformer) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Transformers = append(o.Transformers, t)
+	})
+}
+
+// WithDecodeOptions sets options of the decoding of the layers, e.g. decode.WithEmptyError(true)
+// to fail on empty documents instead of skipping them.
+func WithDecodeOptions(opts ...decode.Option) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Decode = append(o.Decode, opts...)
+	})
+}
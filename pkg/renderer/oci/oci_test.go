@@ -0,0 +1,310 @@
+package oci_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/oci"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	mediaTypeYAML    = "application/vnd.example.manifests.v1+yaml"
+	mediaTypeTarGzip = "application/vnd.example.manifests.v1.tar+gzip"
+	mediaTypeDocs    = "text/markdown"
+
+	manifests = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`
+
+	service = `apiVersion: v1
+kind: Service
+metadata:
+  name: service
+`
+
+	deployment = `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "deployment"}}`
+
+	readme = `# Bundle`
+
+	invalid = `apiVersion: v1
+kind: ConfigMap
+metadata: [unterminated
+`
+)
+
+func TestNew(t *testing.T) {
+
+	t.Run("should reject an invalid reference", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := oci.New("INVALID::ref")
+		g.Expect(err).Should(HaveOccurred())
+	})
+
+	t.Run("should be named oci", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := oci.New("example.com/bundle:v1")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(r.Name()).Should(Equal("oci"))
+	})
+}
+
+func TestProcess(t *testing.T) {
+
+	t.Run("should render YAML layers", func(t *testing.T) {
+		g := NewWithT(t)
+		host := startRegistry(t, nil)
+
+		ref := push(t, host, "bundle:v1", layer(manifests, mediaTypeYAML))
+
+		r, err := oci.New(ref)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"first", "second"}))
+	})
+
+	t.Run("should render tar archives in sorted path order", func(t *testing.T) {
+		g := NewWithT(t)
+		host := startRegistry(t, nil)
+
+		archive := tarGzip(t, map[string]string{
+			"z/service.yaml":       service,
+			"a/deployment.json":    deployment,
+			"a/README.md":          readme,
+			"m/configmaps.yml":     manifests,
+			"m/nested/ignored.txt": readme,
+		})
+
+		ref := push(t, host, "bundle:v1", layer(archive, mediaTypeTarGzip))
+
+		r, err := oci.New(ref)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"deployment", "first", "second", "service"}))
+	})
+
+	t.Run("should only read layers with the configured media types", func(t *testing.T) {
+		g := NewWithT(t)
+		host := startRegistry(t, nil)
+
+		ref := push(t, host, "bundle:v1",
+			layer(readme, mediaTypeDocs),
+			layer(service, mediaTypeYAML),
+		)
+
+		r, err := oci.New(ref, oci.WithMediaTypes(mediaTypeYAML))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"service"}))
+	})
+
+	t.Run("should authenticate with remote options", func(t *testing.T) {
+		g := NewWithT(t)
+		host := startRegistry(t, &authn.Basic{Username: "user", Password: "secret"})
+
+		ref := push(t, host, "bundle:v1", layer(service, mediaTypeYAML))
+
+		r, err := oci.New(ref, oci.WithRemoteOptions(remote.WithAuth(&authn.Basic{
+			Username: "user",
+			Password: "secret",
+		})))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"service"}))
+	})
+
+	t.Run("should report authentication failures as fetch errors", func(t *testing.T) {
+		g := NewWithT(t)
+		host := startRegistry(t, &authn.Basic{Username: "user", Password: "secret"})
+
+		ref := push(t, host, "bundle:v1", layer(service, mediaTypeYAML))
+
+		r, err := oci.New(ref)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(oci.ErrFetch))
+		g.Expect(err).ShouldNot(MatchError(oci.ErrParse))
+	})
+
+	t.Run("should report missing artifacts as fetch errors", func(t *testing.T) {
+		g := NewWithT(t)
+		host := startRegistry(t, nil)
+
+		r, err := oci.New(host + "/bundle:missing")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(oci.ErrFetch))
+	})
+
+	t.Run("should report invalid content as parse errors", func(t *testing.T) {
+		g := NewWithT(t)
+		host := startRegistry(t, nil)
+
+		ref := push(t, host, "bundle:v1", layer(invalid, mediaTypeYAML))
+
+		r, err := oci.New(ref)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(oci.ErrParse))
+		g.Expect(err).ShouldNot(MatchError(oci.ErrFetch))
+	})
+
+	t.Run("should apply renderer-specific filters and transformers", func(t *testing.T) {
+		g := NewWithT(t)
+		host := startRegistry(t, nil)
+
+		ref := push(t, host, "bundle:v1", layer(manifests, mediaTypeYAML))
+
+		r, err := oci.New(
+			ref,
+			oci.WithFilter(func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				return obj.GetName() == "second", nil
+			}),
+			oci.WithTransformer(func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				obj.SetNamespace("rendered")
+
+				return obj, nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"second"}))
+		g.Expect(objects[0].GetNamespace()).Should(Equal("rendered"))
+	})
+}
+
+// startRegistry starts an in-memory registry, optionally requiring basic authentication,
+// and returns its host.
+func startRegistry(t *testing.T, auth *authn.Basic) string {
+	t.Helper()
+
+	var handler http.Handler = registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	if auth != nil {
+		next := handler
+		handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			user, password, ok := req.BasicAuth()
+			if !ok || user != auth.Username || password != auth.Password {
+				w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+// push uploads an artifact made of layers and returns its reference.
+func push(t *testing.T, host string, repository string, layers ...mutate.Addendum) string {
+	t.Helper()
+
+	img, err := mutate.Append(empty.Image, layers...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := name.ParseReference(host + "/" + repository)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := remote.Write(ref, img, remote.WithAuth(&authn.Basic{Username: "user", Password: "secret"})); err != nil {
+		t.Fatal(err)
+	}
+
+	return ref.String()
+}
+
+func layer(content string, mediaType string) mutate.Addendum {
+	return mutate.Addendum{
+		Layer:     static.NewLayer([]byte(content), ggcrtypes.MediaType(mediaType)),
+		MediaType: ggcrtypes.MediaType(mediaType),
+	}
+}
+
+func tarGzip(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for name, content := range files {
+		header := tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(&header); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func names(objects []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, obj.GetName())
+	}
+
+	return result
+}
@@ -0,0 +1,122 @@
+// Package renderer provides a registry creating renderers by kind, for engines assembled from
+// configuration. Renderer implementations live in sub-packages.
+package renderer
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+var (
+	// ErrKindEmpty is returned when registering a factory with an empty kind.
+	ErrKindEmpty = errors.New("renderer kind cannot be empty")
+
+	// ErrFactoryNil is returned when registering a nil factory.
+	ErrFactoryNil = errors.New("renderer factory cannot be nil")
+
+	// ErrDuplicateKind is returned when registering a kind that is already registered.
+	ErrDuplicateKind = errors.New("renderer kind already registered")
+
+	// ErrUnknownKind is returned when creating a renderer of a kind that is not registered.
+	ErrUnknownKind = errors.New("unknown renderer kind")
+)
+
+// Factory creates a renderer from a configuration block, typically decoded from YAML or JSON.
+type Factory func(config map[string]any) (types.Renderer, error)
+
+// Registry maps renderer kinds to factories. It is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[string]Factory),
+	}
+}
+
+// Register associates kind with factory. It fails if kind is empty, factory is nil,
+// or kind is already registered.
+func (r *Registry) Register(kind string, factory Factory) error {
+	if strings.TrimSpace(kind) == "" {
+		return ErrKindEmpty
+	}
+
+	if factory == nil {
+		return fmt.Errorf("%w: %q", ErrFactoryNil, kind)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.factories[kind]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateKind, kind)
+	}
+
+	r.factories[kind] = factory
+
+	return nil
+}
+
+// New creates a renderer of the given kind from config, and validates it with types.ValidateRenderer.
+func (r *Registry) New(kind string, config map[string]any) (types.Renderer, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[kind]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+
+	renderer, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %q renderer: %w", kind, err)
+	}
+
+	if err := types.ValidateRenderer(renderer); err != nil {
+		return nil, fmt.Errorf("invalid %q renderer: %w", kind, err)
+	}
+
+	return renderer, nil
+}
+
+// Kinds returns the registered kinds, sorted.
+func (r *Registry) Kinds() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	kinds := make([]string, 0, len(r.factories))
+	for kind := range r.factories {
+		kinds = append(kinds, kind)
+	}
+
+	slices.Sort(kinds)
+
+	return kinds
+}
+
+// defaultRegistry is the registry used by the package-level functions.
+var defaultRegistry = NewRegistry()
+
+// Register associates kind with factory in the default registry, see Registry.Register.
+// Renderer packages typically call it from an init function.
+func Register(kind string, factory Factory) error {
+	return defaultRegistry.Register(kind, factory)
+}
+
+// New creates a renderer of the given kind from the default registry, see Registry.New.
+func New(kind string, config map[string]any) (types.Renderer, error) {
+	return defaultRegistry.New(kind, config)
+}
+
+// Kinds returns the kinds registered in the default registry, sorted.
+func Kinds() []string {
+	return defaultRegistry.Kinds()
+}
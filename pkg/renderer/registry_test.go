@@ -0,0 +1,117 @@
+package renderer_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/renderer"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+type fakeRenderer struct {
+	name string
+}
+
+func (r *fakeRenderer) Name() string {
+	return r.name
+}
+
+func (r *fakeRenderer) Process(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	return nil, nil
+}
+
+func fakeFactory(config map[string]any) (types.Renderer, error) {
+	name, _ := config["name"].(string)
+
+	return &fakeRenderer{name: name}, nil
+}
+
+func TestRegistry(t *testing.T) {
+
+	t.Run("should create registered renderers from config", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := renderer.NewRegistry()
+		g.Expect(r.Register("fake", fakeFactory)).Should(Succeed())
+
+		created, err := r.New("fake", map[string]any{"name": "from-config"})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(created.Name()).Should(Equal("from-config"))
+	})
+
+	t.Run("should reject invalid registrations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := renderer.NewRegistry()
+		g.Expect(r.Register("fake", fakeFactory)).Should(Succeed())
+
+		g.Expect(r.Register("fake", fakeFactory)).Should(MatchError(renderer.ErrDuplicateKind))
+		g.Expect(r.Register(" ", fakeFactory)).Should(MatchError(renderer.ErrKindEmpty))
+		g.Expect(r.Register("nil", nil)).Should(MatchError(renderer.ErrFactoryNil))
+	})
+
+	t.Run("should fail for unknown kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := renderer.NewRegistry().New("helm", nil)
+		g.Expect(err).Should(MatchError(renderer.ErrUnknownKind))
+	})
+
+	t.Run("should report factory errors and invalid renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		factoryErr := errors.New("missing chart")
+
+		r := renderer.NewRegistry()
+		g.Expect(r.Register("failing", func(_ map[string]any) (types.Renderer, error) {
+			return nil, factoryErr
+		})).Should(Succeed())
+		g.Expect(r.Register("fake", fakeFactory)).Should(Succeed())
+
+		_, err := r.New("failing", nil)
+		g.Expect(err).Should(MatchError(factoryErr))
+
+		_, err = r.New("fake", nil)
+		g.Expect(err).Should(MatchError(types.ErrRendererNameEmpty))
+	})
+
+	t.Run("should be safe for concurrent use", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := renderer.NewRegistry()
+
+		var wg sync.WaitGroup
+		for i := range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				kind := fmt.Sprintf("kind-%d", i%10)
+				_ = r.Register(kind, fakeFactory)
+				_, _ = r.New(kind, map[string]any{"name": kind})
+			}()
+		}
+
+		wg.Wait()
+
+		g.Expect(r.Kinds()).Should(HaveLen(10))
+	})
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(renderer.Register("default-fake", fakeFactory)).Should(Succeed())
+	g.Expect(renderer.Kinds()).Should(ContainElement("default-fake"))
+
+	created, err := renderer.New("default-fake", map[string]any{"name": "fake"})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(created.Name()).Should(Equal("fake"))
+}
@@ -0,0 +1,275 @@
+// Package retry provides a renderer wrapper retrying renderers that fail transiently, e.g. because
+// of network errors or registry throttling.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Renderer calls a wrapped renderer until it succeeds or the attempts are exhausted.
+type Renderer struct {
+	renderer types.Renderer
+	options  Options
+}
+
+// StreamingRenderer is a Renderer for a types.StreamingRenderer.
+type StreamingRenderer struct {
+	*Renderer
+
+	stream types.StreamingRenderer
+}
+
+// Wrap returns a renderer retrying the Process calls of r that fail, waiting between attempts
+// with an exponential backoff. By default, every error is retried, up to DefaultAttempts calls,
+// with delays starting at DefaultDelay and capped at DefaultMaxDelay.
+//
+// Errors not matching WithRetryable are returned as is. Once the attempts are exhausted, the
+// returned error wraps the error of the last attempt. The context is checked before every attempt
+// and while waiting: a canceled render is not retried and fails with an error wrapping both the
+// context error and that of the last attempt.
+//
+// Name delegates to r, so that the engine and its per-renderer options see the wrapped renderer.
+// ProcessVersion and ProcessTarget are retried the same way, and forwarded to r when it implements
+// types.VersionAwareRenderer or types.TargetedRenderer, falling back to Process otherwise. If r
+// implements types.StreamingRenderer, so does the returned renderer: a stream failing before it
+// delivered any object is retried, while later errors are returned as is, as the objects already
+// delivered cannot be taken back.
+func Wrap(r types.Renderer, opts ...Option) types.Renderer {
+	options := Options{
+		Attempts: DefaultAttempts,
+		Delay:    DefaultDelay,
+		MaxDelay: DefaultMaxDelay,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	rr := &Renderer{
+		renderer: r,
+		options:  options,
+	}
+
+	if stream, ok := r.(types.StreamingRenderer); ok {
+		return &StreamingRenderer{Renderer: rr, stream: stream}
+	}
+
+	return rr
+}
+
+// Name implements types.Renderer.
+func (r *Renderer) Name() string {
+	return r.renderer.Name()
+}
+
+// Unwrap returns the wrapped renderer, see types.Implements.
+func (r *Renderer) Unwrap() types.Renderer {
+	return r.renderer
+}
+
+// Process implements types.Renderer.
+func (r *Renderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	return r.process(ctx, func() ([]unstructured.Unstructured, error) {
+		return r.renderer.Process(ctx, values)
+	})
+}
+
+// ProcessVersion implements types.VersionAwareRenderer.
+func (r *Renderer) ProcessVersion(
+	ctx context.Context,
+	values map[string]any,
+	version string,
+) ([]unstructured.Unstructured, error) {
+	return r.process(ctx, func() ([]unstructured.Unstructured, error) {
+		if vr, ok := r.renderer.(types.VersionAwareRenderer); ok {
+			return vr.ProcessVersion(ctx, values, version)
+		}
+
+		return r.renderer.Process(ctx, values)
+	})
+}
+
+// ProcessTarget implements types.TargetedRenderer.
+func (r *Renderer) ProcessTarget(
+	ctx context.Context,
+	values map[string]any,
+	target types.Filter,
+) ([]unstructured.Unstructured, error) {
+	return r.process(ctx, func() ([]unstructured.Unstructured, error) {
+		if tr, ok := r.renderer.(types.TargetedRenderer); ok {
+			return tr.ProcessTarget(ctx, values, target)
+		}
+
+		return r.renderer.Process(ctx, values)
+	})
+}
+
+// process retries process and returns the objects of the first successful attempt.
+func (r *Renderer) process(
+	ctx context.Context,
+	process func() ([]unstructured.Unstructured, error),
+) ([]unstructured.Unstructured, error) {
+	var objects []unstructured.Unstructured
+
+	err := r.retry(ctx, func() (bool, error) {
+		var err error
+		objects, err = process()
+
+		return true, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// ProcessStream implements types.StreamingRenderer.
+func (r *StreamingRenderer) ProcessStream(
+	ctx context.Context,
+	values map[string]any,
+) (<-chan unstructured.Unstructured, <-chan error) {
+	out := make(chan unstructured.Unstructured)
+	outErrs := make(chan error, 1)
+
+	go func() {
+		defer close(outErrs)
+		defer close(out)
+
+		err := r.retry(ctx, func() (bool, error) {
+			sent, err := r.forward(ctx, values, out)
+
+			return !sent, err
+		})
+		if err != nil {
+			outErrs <- err
+		}
+	}()
+
+	return out, outErrs
+}
+
+// forward runs one attempt of the wrapped stream, sending its objects to out, and reports whether
+// any object was sent.
+func (r *StreamingRenderer) forward(
+	ctx context.Context,
+	values map[string]any,
+	out chan<- unstructured.Unstructured,
+) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	in, inErrs := r.stream.ProcessStream(ctx, values)
+
+	sent := false
+
+	// Objects and errors are received together, as the wrapped renderer may block sending an
+	// error on an unbuffered channel while its object stream is still open.
+	for in != nil || inErrs != nil {
+		select {
+		case <-ctx.Done():
+			return sent, ctx.Err()
+
+		case err, ok := <-inErrs:
+			if !ok {
+				inErrs = nil
+
+				continue
+			}
+
+			if err != nil {
+				return sent, err
+			}
+
+		case obj, ok := <-in:
+			if !ok {
+				in = nil
+
+				continue
+			}
+
+			select {
+			case out <- obj:
+				sent = true
+			case <-ctx.Done():
+				return sent, ctx.Err()
+			}
+		}
+	}
+
+	return sent, nil
+}
+
+// retry calls attempt until it succeeds, the attempts are exhausted, or it fails with an error
+// that is not retried, either because attempt reports it may not be or because of WithRetryable.
+func (r *Renderer) retry(ctx context.Context, attempt func() (bool, error)) error {
+	delay := r.options.Delay
+
+	var lastErr error
+
+	for n := 1; ; n++ {
+		if err := ctx.Err(); err != nil {
+			return r.canceled(err, n-1, lastErr)
+		}
+
+		retryable, err := attempt()
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return r.canceled(ctxErr, n, lastErr)
+		}
+
+		if !retryable || (r.options.Retryable != nil && !r.options.Retryable(err)) {
+			return err
+		}
+
+		if n >= r.options.Attempts {
+			return fmt.Errorf("%s render failed after %d attempts: %w", r.Name(), n, lastErr)
+		}
+
+		if err := wait(ctx, delay); err != nil {
+			return r.canceled(err, n, lastErr)
+		}
+
+		delay *= 2
+		if r.options.MaxDelay > 0 {
+			delay = min(delay, r.options.MaxDelay)
+		}
+	}
+}
+
+// canceled returns the error of a render canceled after attempts failed attempts.
+func (r *Renderer) canceled(ctxErr error, attempts int, lastErr error) error {
+	if lastErr == nil {
+		return fmt.Errorf("%s render canceled: %w", r.Name(), ctxErr)
+	}
+
+	return fmt.Errorf("%s render canceled after %d attempts: %w: %w", r.Name(), attempts, ctxErr, lastErr)
+}
+
+// wait blocks for d or until ctx is done, whichever comes first.
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"time"
+
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+const (
+	// DefaultAttempts is the default maximum number of calls to the wrapped renderer.
+	DefaultAttempts = 3
+
+	// DefaultDelay is the default delay before the first retry.
+	DefaultDelay = 200 * time.Millisecond
+
+	// DefaultMaxDelay is the default upper bound of the delay between two attempts.
+	DefaultMaxDelay = 5 * time.Second
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the retry wrapper.
+type Options struct {
+	// Attempts is the maximum number of calls to the wrapped renderer, including the first one.
+	Attempts int
+
+	// Delay is the delay before the first retry. It doubles after each retry, up to MaxDelay.
+	Delay time.Duration
+
+	// MaxDelay is the upper bound of the delay between two attempts, unbounded if zero.
+	MaxDelay time.Duration
+
+	// Retryable reports whether a failed attempt is retried. If nil, every error is retried.
+	Retryable func(err error) bool
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Attempts > 0 {
+		target.Attempts = opts.Attempts
+	}
+
+	if opts.Delay > 0 {
+		target.Delay = opts.Delay
+	}
+
+	if opts.MaxDelay > 0 {
+		target.MaxDelay = opts.MaxDelay
+	}
+
+	if opts.Retryable != nil {
+		target.Retryable = opts.Retryable
+	}
+}
+
+// WithAttempts sets the maximum number of calls to the wrapped renderer, including the first one.
+// Values lower than 1 are treated as 1, i.e. no retry.
+func WithAttempts(attempts int) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Attempts = attempts
+	})
+}
+
+// WithBackoff sets the delay before the first retry and the upper bound it doubles up to
+// after each retry. A zero delay retries immediately, a zero maxDelay leaves the delay unbounded.
+func WithBackoff(delay time.Duration, maxDelay time.Duration) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Delay = delay
+		o.MaxDelay = maxDelay
+	})
+}
+
+// WithRetryable restricts retries to the errors for which fn returns true, e.g. to only retry
+// network errors. Other errors are returned right away.
+func WithRetryable(fn func(err error) bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Retryable = fn
+	})
+}
@@ -0,0 +1,300 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/retry"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+var (
+	errTransient = errors.New("registry throttled")
+	errPermanent = errors.New("manifest not found")
+)
+
+// flakyRenderer fails with the errors of errs, in order, then succeeds.
+type flakyRenderer struct {
+	errs  []error
+	calls int
+}
+
+func (r *flakyRenderer) Name() string {
+	return "flaky"
+}
+
+func (r *flakyRenderer) Process(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	r.calls++
+
+	if r.calls <= len(r.errs) {
+		return nil, r.errs[r.calls-1]
+	}
+
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName("config")
+
+	return []unstructured.Unstructured{obj}, nil
+}
+
+// versionRenderer is a version-aware flakyRenderer recording the versions it is rendered for.
+type versionRenderer struct {
+	flakyRenderer
+
+	versions []string
+}
+
+func (r *versionRenderer) ProcessVersion(
+	ctx context.Context,
+	values map[string]any,
+	version string,
+) ([]unstructured.Unstructured, error) {
+	r.versions = append(r.versions, version)
+
+	return r.Process(ctx, values)
+}
+
+// targetRenderer is a targeted flakyRenderer counting its targeted calls.
+type targetRenderer struct {
+	flakyRenderer
+
+	targeted int
+}
+
+func (r *targetRenderer) ProcessTarget(
+	ctx context.Context,
+	values map[string]any,
+	_ types.Filter,
+) ([]unstructured.Unstructured, error) {
+	r.targeted++
+
+	return r.Process(ctx, values)
+}
+
+// streamRenderer is a streaming flakyRenderer. Failing streams send an object before their error
+// if partial is set.
+type streamRenderer struct {
+	flakyRenderer
+
+	partial bool
+}
+
+func (r *streamRenderer) ProcessStream(
+	ctx context.Context,
+	values map[string]any,
+) (<-chan unstructured.Unstructured, <-chan error) {
+	objects := make(chan unstructured.Unstructured)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(objects)
+
+		result, err := r.Process(ctx, values)
+		if err != nil && r.partial {
+			result = []unstructured.Unstructured{{}}
+		}
+
+		for _, obj := range result {
+			select {
+			case objects <- obj:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return objects, errs
+}
+
+// collect drains a stream and returns its objects and error.
+func collect(objects <-chan unstructured.Unstructured, errs <-chan error) ([]unstructured.Unstructured, error) {
+	var result []unstructured.Unstructured
+	for obj := range objects {
+		result = append(result, obj)
+	}
+
+	return result, <-errs
+}
+
+func TestWrap(t *testing.T) {
+
+	t.Run("should delegate the name to the wrapped renderer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(retry.Wrap(&flakyRenderer{}).Name()).Should(Equal("flaky"))
+	})
+
+	t.Run("should retry until the renderer succeeds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		flaky := &flakyRenderer{errs: []error{errTransient, errTransient}}
+		r := retry.Wrap(flaky, retry.WithBackoff(time.Millisecond, 2*time.Millisecond))
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(flaky.calls).Should(Equal(3))
+	})
+
+	t.Run("should return the last error once attempts are exhausted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		flaky := &flakyRenderer{errs: []error{errPermanent, errTransient, errTransient}}
+		r := retry.Wrap(flaky, retry.WithAttempts(2), retry.WithBackoff(0, 0))
+
+		_, err := r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(errTransient))
+		g.Expect(err.Error()).Should(ContainSubstring("after 2 attempts"))
+		g.Expect(flaky.calls).Should(Equal(2))
+	})
+
+	t.Run("should not retry errors rejected by the predicate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		flaky := &flakyRenderer{errs: []error{errTransient, errPermanent}}
+		r := retry.Wrap(flaky,
+			retry.WithBackoff(0, 0),
+			retry.WithRetryable(func(err error) bool { return errors.Is(err, errTransient) }),
+		)
+
+		_, err := r.Process(t.Context(), nil)
+		g.Expect(err).Should(Equal(errPermanent))
+		g.Expect(flaky.calls).Should(Equal(2))
+	})
+
+	t.Run("should call the renderer once with a single attempt", func(t *testing.T) {
+		g := NewWithT(t)
+
+		flaky := &flakyRenderer{errs: []error{errTransient}}
+		r := retry.Wrap(flaky, retry.WithAttempts(0))
+
+		_, err := r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(errTransient))
+		g.Expect(flaky.calls).Should(Equal(1))
+	})
+
+	t.Run("should stop waiting when the context is canceled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+
+		flaky := &flakyRenderer{errs: []error{errTransient, errTransient}}
+		r := retry.Wrap(flaky, retry.WithBackoff(time.Minute, time.Minute))
+
+		start := time.Now()
+		_, err := r.Process(ctx, nil)
+		g.Expect(err).Should(MatchError(context.DeadlineExceeded))
+		g.Expect(err).Should(MatchError(errTransient))
+		g.Expect(time.Since(start)).Should(BeNumerically("<", time.Minute))
+		g.Expect(flaky.calls).Should(Equal(1))
+	})
+
+	t.Run("should not call the renderer on a canceled context", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		flaky := &flakyRenderer{}
+		_, err := retry.Wrap(flaky).Process(ctx, nil)
+		g.Expect(err).Should(MatchError(context.Canceled))
+		g.Expect(flaky.calls).Should(BeZero())
+	})
+
+	t.Run("should retry ProcessVersion with the version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		inner := &versionRenderer{flakyRenderer: flakyRenderer{errs: []error{errTransient}}}
+		r := retry.Wrap(inner, retry.WithBackoff(0, 0))
+		g.Expect(types.Implements[types.VersionAwareRenderer](r)).Should(BeTrue())
+
+		vr, ok := r.(types.VersionAwareRenderer)
+		g.Expect(ok).Should(BeTrue())
+
+		objects, err := vr.ProcessVersion(t.Context(), nil, "v1.31.0")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(inner.versions).Should(Equal([]string{"v1.31.0", "v1.31.0"}))
+	})
+
+	t.Run("should retry ProcessTarget with the target", func(t *testing.T) {
+		g := NewWithT(t)
+
+		inner := &targetRenderer{flakyRenderer: flakyRenderer{errs: []error{errTransient}}}
+		r := retry.Wrap(inner, retry.WithBackoff(0, 0))
+		g.Expect(types.Implements[types.TargetedRenderer](r)).Should(BeTrue())
+
+		tr, ok := r.(types.TargetedRenderer)
+		g.Expect(ok).Should(BeTrue())
+
+		objects, err := tr.ProcessTarget(t.Context(), nil, func(context.Context, unstructured.Unstructured) (bool, error) {
+			return true, nil
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(inner.targeted).Should(Equal(2))
+	})
+
+	t.Run("should fall back to Process for renderers lacking an optional interface", func(t *testing.T) {
+		g := NewWithT(t)
+
+		flaky := &flakyRenderer{errs: []error{errTransient}}
+		r := retry.Wrap(flaky, retry.WithBackoff(0, 0))
+		g.Expect(types.Implements[types.VersionAwareRenderer](r)).Should(BeFalse())
+		g.Expect(types.Implements[types.TargetedRenderer](r)).Should(BeFalse())
+
+		_, ok := r.(types.StreamingRenderer)
+		g.Expect(ok).Should(BeFalse())
+
+		vr, ok := r.(types.VersionAwareRenderer)
+		g.Expect(ok).Should(BeTrue())
+
+		objects, err := vr.ProcessVersion(t.Context(), nil, "v1.31.0")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(flaky.calls).Should(Equal(2))
+	})
+
+	t.Run("should retry streams failing before any object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		inner := &streamRenderer{flakyRenderer: flakyRenderer{errs: []error{errTransient, errTransient}}}
+		r := retry.Wrap(inner, retry.WithBackoff(0, 0))
+
+		sr, ok := r.(types.StreamingRenderer)
+		g.Expect(ok).Should(BeTrue())
+
+		objects, err := collect(sr.ProcessStream(t.Context(), nil))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(inner.calls).Should(Equal(3))
+	})
+
+	t.Run("should not retry streams failing after an object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		inner := &streamRenderer{flakyRenderer: flakyRenderer{errs: []error{errTransient}}, partial: true}
+		r := retry.Wrap(inner, retry.WithBackoff(0, 0))
+
+		sr, ok := r.(types.StreamingRenderer)
+		g.Expect(ok).Should(BeTrue())
+
+		objects, err := collect(sr.ProcessStream(t.Context(), nil))
+		g.Expect(err).Should(Equal(errTransient))
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(inner.calls).Should(Equal(1))
+	})
+}
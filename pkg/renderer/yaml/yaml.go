@@ -0,0 +1,167 @@
+// Package yaml provides a renderer decoding a multi-document YAML or JSON stream read from an
+// io.Reader one document at a time, e.g. the output of a subprocess.
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/decode"
+)
+
+const rendererName = "yaml"
+
+var (
+	// ErrReaderRequired is returned when the reader is nil.
+	ErrReaderRequired = errors.New("yaml reader cannot be nil")
+
+	// ErrReaderConsumed is returned when rendering again a renderer whose reader has already been read.
+	ErrReaderConsumed = errors.New("yaml reader already consumed")
+)
+
+// Renderer decodes the documents of a YAML or JSON stream as they are read.
+type Renderer struct {
+	reader   io.Reader
+	consumed atomic.Bool
+	options  Options
+}
+
+// NewFromReader returns a renderer decoding the documents read from r.
+//
+// Documents are decoded one at a time, so a stream larger than memory can be rendered as long as
+// each of its documents fits: the renderer implements types.StreamingRenderer, and the engine runs
+// each object through the pipeline as soon as it is decoded. Documents without apiVersion or kind are
+// skipped, and so are empty ones unless set otherwise with WithDecodeOptions. Decoding errors name the
+// index of the failing document and, when it could be parsed, its line. Render-time values are ignored.
+//
+// A reader can only be read once: the renderer renders once, and any later render fails with
+// ErrReaderConsumed. The reader is not closed.
+func NewFromReader(r io.Reader, opts ...Option) (types.Renderer, error) {
+	if r == nil {
+		return nil, ErrReaderRequired
+	}
+
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return &Renderer{
+		reader:  r,
+		options: options,
+	}, nil
+}
+
+// Name implements types.Renderer.
+func (r *Renderer) Name() string {
+	return rendererName
+}
+
+// Process implements types.Renderer. It decodes the whole stream before returning.
+func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	dec, err := r.decoder(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]unstructured.Unstructured, 0)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("yaml render canceled: %w", err)
+		}
+
+		decoded, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode stream: %w", err)
+		}
+
+		objects = append(objects, decoded...)
+	}
+
+	return pipeline.Apply(ctx, objects, r.options.Filters, r.options.Transformers)
+}
+
+// ProcessStream implements types.StreamingRenderer. Renderer-specific filters and transformers
+// are applied to the objects of each document before they are sent.
+func (r *Renderer) ProcessStream(
+	ctx context.Context,
+	_ map[string]any,
+) (<-chan unstructured.Unstructured, <-chan error) {
+	objects := make(chan unstructured.Unstructured)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(objects)
+		defer close(errs)
+
+		if err := r.stream(ctx, objects); err != nil {
+			errs <- err
+		}
+	}()
+
+	return objects, errs
+}
+
+// stream decodes the documents of the reader and sends their objects to objects.
+func (r *Renderer) stream(ctx context.Context, objects chan<- unstructured.Unstructured) error {
+	dec, err := r.decoder(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("yaml render canceled: %w", err)
+		}
+
+		decoded, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to decode stream: %w", err)
+		}
+
+		decoded, err = pipeline.Apply(ctx, decoded, r.options.Filters, r.options.Transformers)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range decoded {
+			select {
+			case objects <- obj:
+			case <-ctx.Done():
+				return fmt.Errorf("yaml render canceled: %w", ctx.Err())
+			}
+		}
+	}
+}
+
+// decoder returns the decoder of the reader, failing if it has already been returned.
+func (r *Renderer) decoder(ctx context.Context) (*decode.Decoder, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("yaml render canceled: %w", err)
+	}
+
+	if !r.consumed.CompareAndSwap(false, true) {
+		return nil, ErrReaderConsumed
+	}
+
+	decodeOpts := append(slices.Clone(r.options.Decode), decode.WithComments(r.options.Comments))
+
+	return decode.NewDecoder(r.reader, decodeOpts...), nil
+}
@@ -0,0 +1,167 @@
+package yaml_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/renderer/yaml"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	first = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+`
+
+	second = `apiVersion: v1
+kind: Secret
+metadata:
+  name: second
+`
+
+	configMapList = `apiVersion: v1
+kind: ConfigMapList
+items:
+- metadata:
+    name: listed
+`
+
+	invalid = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: [unterminated
+`
+)
+
+func stream(documents ...string) io.Reader {
+	return strings.NewReader(strings.Join(documents, "---\n"))
+}
+
+func names(objects []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, obj.GetName())
+	}
+
+	return result
+}
+
+func TestNewFromReader(t *testing.T) {
+
+	t.Run("should decode every document of the stream", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := yaml.NewFromReader(stream(first, "", "foo: bar\n", configMapList, second))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(r.Name()).Should(Equal("yaml"))
+
+		objects, err := r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"first", "listed", "second"}))
+	})
+
+	t.Run("should stream objects as documents are read", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pr, pw := io.Pipe()
+
+		r, err := yaml.NewFromReader(pr)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		sr, ok := r.(types.StreamingRenderer)
+		g.Expect(ok).Should(BeTrue())
+
+		objects, errs := sr.ProcessStream(t.Context(), nil)
+
+		// The end of a document is only known once the next one starts.
+		go func() {
+			_, _ = io.WriteString(pw, first+"---\napiVersion: v1\n")
+		}()
+
+		var obj unstructured.Unstructured
+		g.Eventually(objects).Should(Receive(&obj))
+		g.Expect(obj.GetName()).Should(Equal("first"))
+
+		go func() {
+			_, _ = io.WriteString(pw, strings.TrimPrefix(second, "apiVersion: v1\n"))
+			_ = pw.Close()
+		}()
+
+		g.Eventually(objects).Should(Receive(&obj))
+		g.Expect(obj.GetName()).Should(Equal("second"))
+		g.Eventually(objects).Should(BeClosed())
+		g.Eventually(errs).Should(BeClosed())
+	})
+
+	t.Run("should render through the engine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := yaml.NewFromReader(
+			stream(first, second),
+			yaml.WithFilter(func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				return obj.GetKind() == "Secret", nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		e, err := engine.New(engine.WithRenderer(r))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"second"}))
+	})
+
+	t.Run("should name the failing document", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := yaml.NewFromReader(stream(first, second, invalid))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(ContainSubstring("document[2]")))
+		g.Expect(err).Should(MatchError(ContainSubstring("line 13")))
+	})
+
+	t.Run("should read the stream only once", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := yaml.NewFromReader(stream(first))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = r.Process(t.Context(), nil)
+		g.Expect(err).Should(MatchError(yaml.ErrReaderConsumed))
+	})
+
+	t.Run("should fail on a canceled context", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, err := yaml.NewFromReader(stream(first))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err = r.Process(ctx, nil)
+		g.Expect(err).Should(MatchError(context.Canceled))
+	})
+
+	t.Run("should require a reader", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.NewFromReader(nil)
+		g.Expect(err).Should(MatchError(yaml.ErrReaderRequired))
+	})
+}
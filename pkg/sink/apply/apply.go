@@ -0,0 +1,154 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// Status describes the outcome of applying a single object.
+type Status string
+
+const (
+	// StatusCreated means the object did not exist and was created.
+	StatusCreated Status = "created"
+
+	// StatusConfigured means the object existed and was changed.
+	StatusConfigured Status = "configured"
+
+	// StatusUnchanged means the object existed and applying it did not change it.
+	StatusUnchanged Status = "unchanged"
+
+	// StatusError means the object could not be applied, see Result.Err.
+	StatusError Status = "error"
+)
+
+// Result is the outcome of applying a single object.
+type Result struct {
+	// Object is the object as returned by the API server, or the input object on error.
+	Object unstructured.Unstructured
+
+	// Status is the outcome of the apply.
+	Status Status
+
+	// Err is the error that occurred, if Status is StatusError.
+	Err error
+}
+
+// Applier applies rendered objects to a cluster using server-side apply.
+// It is a consumer of the objects returned by Engine.Render and is not part of the render pipeline.
+type Applier struct {
+	client  dynamic.Interface
+	mapper  meta.RESTMapper
+	options Options
+}
+
+// New creates an Applier that uses mapper to resolve object kinds to API resources.
+func New(client dynamic.Interface, mapper meta.RESTMapper, opts ...Option) *Applier {
+	options := Options{
+		FieldManager: DefaultFieldManager,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return &Applier{
+		client:  client,
+		mapper:  mapper,
+		options: options,
+	}
+}
+
+// Apply server-side applies objects in the given order and returns one Result per object.
+// A failure does not stop the remaining objects from being applied; all failures are also
+// returned, joined, as the error. Callers that need dependencies (e.g. namespaces, CRDs)
+// applied first should sort the objects beforehand, e.g. with order.Sort.
+func (a *Applier) Apply(ctx context.Context, objects []unstructured.Unstructured) ([]Result, error) {
+	results := make([]Result, 0, len(objects))
+
+	var errs []error
+
+	for _, obj := range objects {
+		result := a.apply(ctx, obj)
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func (a *Applier) apply(ctx context.Context, obj unstructured.Unstructured) Result {
+	ri, err := a.resourceFor(obj)
+	if err != nil {
+		return failed(obj, err)
+	}
+
+	existing, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return failed(obj, fmt.Errorf("failed to get current state: %w", err))
+	}
+
+	applied, err := ri.Apply(ctx, obj.GetName(), &obj, metav1.ApplyOptions{
+		FieldManager: a.options.FieldManager,
+		Force:        a.options.Force,
+	})
+	if err != nil {
+		return failed(obj, err)
+	}
+
+	var status Status
+
+	switch {
+	case existing == nil:
+		status = StatusCreated
+	case existing.GetResourceVersion() == applied.GetResourceVersion():
+		status = StatusUnchanged
+	default:
+		status = StatusConfigured
+	}
+
+	return Result{
+		Object: *applied,
+		Status: status,
+	}
+}
+
+// resourceFor returns the dynamic resource client for the object's kind and scope.
+func (a *Applier) resourceFor(obj unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+
+	mapping, err := a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to map %s to a resource: %w", gvk, err)
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return a.client.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+	}
+
+	return a.client.Resource(mapping.Resource), nil
+}
+
+func failed(obj unstructured.Unstructured, err error) Result {
+	return Result{
+		Object: obj,
+		Status: StatusError,
+		Err: fmt.Errorf(
+			"failed to apply %s %s (namespace: %s): %w",
+			obj.GroupVersionKind(),
+			obj.GetName(),
+			obj.GetNamespace(),
+			err,
+		),
+	}
+}
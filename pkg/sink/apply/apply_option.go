@@ -0,0 +1,44 @@
+package apply
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// DefaultFieldManager is the field manager used for server-side apply when none is configured.
+const DefaultFieldManager = "k8s-manifest-kit"
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the Applier.
+type Options struct {
+	// FieldManager is the field manager that owns the applied fields.
+	FieldManager string
+
+	// Force takes ownership of fields owned by other field managers on conflict.
+	Force bool
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.FieldManager != "" {
+		target.FieldManager = opts.FieldManager
+	}
+
+	target.Force = opts.Force
+}
+
+// WithFieldManager sets the field manager that owns the applied fields.
+func WithFieldManager(fieldManager string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.FieldManager = fieldManager
+	})
+}
+
+// WithForce enables or disables forcing ownership of conflicting fields.
+// When disabled (default), applying a field owned by another manager fails with a conflict.
+func WithForce(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Force = enabled
+	})
+}
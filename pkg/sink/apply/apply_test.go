@@ -0,0 +1,128 @@
+package apply_test
+
+import (
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/k8s-manifest-kit/engine/pkg/sink/apply"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testNamespace = "default"
+)
+
+func TestApply(t *testing.T) {
+
+	t.Run("should report created, configured and unchanged objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+			withResourceVersion(makeObject("ConfigMap", "same", testNamespace), "1"),
+			withResourceVersion(makeObject("ConfigMap", "changed", testNamespace), "1"),
+		)
+
+		var patches []k8stesting.PatchAction
+		client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			patch := action.(k8stesting.PatchAction)
+			patches = append(patches, patch)
+
+			var rv string
+			switch patch.GetName() {
+			case "same":
+				rv = "1"
+			default:
+				rv = "2"
+			}
+
+			obj := makeObject("ConfigMap", patch.GetName(), patch.GetNamespace())
+			if patch.GetResource().Resource == "namespaces" {
+				obj = makeObject("Namespace", patch.GetName(), "")
+			}
+
+			return true, withResourceVersion(obj, rv), nil
+		})
+
+		applier := apply.New(client, newMapper())
+
+		results, err := applier.Apply(t.Context(), []unstructured.Unstructured{
+			*makeObject("Namespace", "team-a", ""),
+			*makeObject("ConfigMap", "same", testNamespace),
+			*makeObject("ConfigMap", "changed", testNamespace),
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(results).Should(HaveLen(3))
+		g.Expect(results[0].Status).Should(Equal(apply.StatusCreated))
+		g.Expect(results[1].Status).Should(Equal(apply.StatusUnchanged))
+		g.Expect(results[2].Status).Should(Equal(apply.StatusConfigured))
+		g.Expect(results[2].Object.GetResourceVersion()).Should(Equal("2"))
+
+		g.Expect(patches).Should(HaveLen(3))
+		g.Expect(patches[0].GetNamespace()).Should(BeEmpty())
+		g.Expect(patches[1].GetNamespace()).Should(Equal(testNamespace))
+	})
+
+	t.Run("should continue after a failure and report it", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			patch := action.(k8stesting.PatchAction)
+			if patch.GetName() == "bad" {
+				return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "bad", nil)
+			}
+
+			return true, makeObject("ConfigMap", patch.GetName(), patch.GetNamespace()), nil
+		})
+
+		applier := apply.New(client, newMapper(), apply.WithFieldManager("test"), apply.WithForce(false))
+
+		results, err := applier.Apply(t.Context(), []unstructured.Unstructured{
+			*makeObject("ConfigMap", "bad", testNamespace),
+			*makeObject("Widget", "unknown", testNamespace),
+			*makeObject("ConfigMap", "good", testNamespace),
+		})
+		g.Expect(err).Should(HaveOccurred())
+		g.Expect(apierrors.IsConflict(err)).Should(BeTrue())
+		g.Expect(meta.IsNoMatchError(err)).Should(BeTrue())
+
+		g.Expect(results).Should(HaveLen(3))
+		g.Expect(results[0].Status).Should(Equal(apply.StatusError))
+		g.Expect(results[0].Err).Should(MatchError(ContainSubstring("bad")))
+		g.Expect(results[1].Status).Should(Equal(apply.StatusError))
+		g.Expect(results[1].Object.GetName()).Should(Equal("unknown"))
+		g.Expect(results[2].Status).Should(Equal(apply.StatusCreated))
+	})
+}
+
+func newMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+
+	return mapper
+}
+
+func withResourceVersion(obj *unstructured.Unstructured, rv string) *unstructured.Unstructured {
+	obj.SetResourceVersion(rv)
+
+	return obj
+}
+
+func makeObject(kind string, name string, namespace string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+
+	return obj
+}
@@ -0,0 +1,102 @@
+// Package apiversion provides a transformer rewriting the apiVersion of deprecated or
+// not-yet-available kinds, to smooth over cluster version skew.
+package apiversion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Move renames a field, e.g. from "spec.backend" to "spec.defaultBackend".
+// Paths are dot-separated; a move whose source field is missing is skipped.
+type Move struct {
+	From string
+	To   string
+}
+
+// Rule rewrites objects of one GroupVersionKind to another.
+type Rule struct {
+	// From is the GroupVersionKind of the objects to rewrite.
+	From schema.GroupVersionKind
+
+	// To is the GroupVersionKind to rewrite them to. An empty Kind keeps the original kind.
+	To schema.GroupVersionKind
+
+	// Moves are field renames applied, in order, after the apiVersion is rewritten.
+	Moves []Move
+
+	// Migrate is an optional transformer for migrations that cannot be expressed as moves.
+	// It runs last, on the rewritten object.
+	Migrate types.Transformer
+}
+
+// Rewrite returns a transformer applying the first rule matching the GroupVersionKind of each object.
+// Objects not matching any rule pass through untouched.
+func Rewrite(rules []Rule) types.Transformer {
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		gvk := obj.GroupVersionKind()
+
+		for _, rule := range rules {
+			if rule.From != gvk {
+				continue
+			}
+
+			return rule.apply(ctx, obj)
+		}
+
+		return obj, nil
+	}
+}
+
+func (r Rule) apply(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+	to := r.To
+	if to.Kind == "" {
+		to.Kind = r.From.Kind
+	}
+
+	obj.SetGroupVersionKind(to)
+
+	for _, move := range r.Moves {
+		if err := moveField(obj.Object, move); err != nil {
+			return obj, fmt.Errorf("failed to migrate %s to %s: %w", r.From, to, err)
+		}
+	}
+
+	if r.Migrate == nil {
+		return obj, nil
+	}
+
+	result, err := r.Migrate(ctx, obj)
+	if err != nil {
+		return obj, fmt.Errorf("failed to migrate %s to %s: %w", r.From, to, err)
+	}
+
+	return result, nil
+}
+
+func moveField(content map[string]any, move Move) error {
+	from := strings.Split(move.From, ".")
+
+	value, found, err := unstructured.NestedFieldNoCopy(content, from...)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", move.From, err)
+	}
+
+	if !found {
+		return nil
+	}
+
+	unstructured.RemoveNestedField(content, from...)
+
+	if err := unstructured.SetNestedField(content, value, strings.Split(move.To, ".")...); err != nil {
+		return fmt.Errorf("cannot move %s to %s: %w", move.From, move.To, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package apiversion_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/apiversion"
+
+	. "github.com/onsi/gomega"
+)
+
+var (
+	ingressV1beta1 = schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}
+	ingressV1      = schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
+)
+
+func makeIngress() unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "web"},
+		"spec": map[string]any{
+			"backend": map[string]any{"serviceName": "web", "servicePort": int64(80)},
+		},
+	}}
+	obj.SetGroupVersionKind(ingressV1beta1)
+
+	return obj
+}
+
+func TestRewrite(t *testing.T) {
+
+	t.Run("should rewrite the apiVersion and move fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transform := apiversion.Rewrite([]apiversion.Rule{{
+			From: ingressV1beta1,
+			To:   ingressV1,
+			Moves: []apiversion.Move{
+				{From: "spec.backend.serviceName", To: "spec.defaultBackend.service.name"},
+				{From: "spec.backend.servicePort", To: "spec.defaultBackend.service.port.number"},
+				{From: "spec.tls", To: "spec.tlsConfig"},
+			},
+		}})
+
+		result, err := transform(t.Context(), makeIngress())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.GroupVersionKind()).Should(Equal(ingressV1))
+
+		name, _, _ := unstructured.NestedString(result.Object, "spec", "defaultBackend", "service", "name")
+		g.Expect(name).Should(Equal("web"))
+
+		port, _, _ := unstructured.NestedInt64(result.Object, "spec", "defaultBackend", "service", "port", "number")
+		g.Expect(port).Should(Equal(int64(80)))
+
+		// the emptied source map is left in place, missing sources are skipped
+		g.Expect(result.Object["spec"]).Should(HaveKeyWithValue("backend", BeEmpty()))
+		g.Expect(result.Object["spec"]).ShouldNot(HaveKey("tlsConfig"))
+	})
+
+	t.Run("should keep the kind when the target kind is empty", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transform := apiversion.Rewrite([]apiversion.Rule{{
+			From: ingressV1beta1,
+			To:   schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1"},
+		}})
+
+		result, err := transform(t.Context(), makeIngress())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.GroupVersionKind()).Should(Equal(ingressV1))
+	})
+
+	t.Run("should run the custom migration last", func(t *testing.T) {
+		g := NewWithT(t)
+
+		migrateErr := errors.New("unsupported backend")
+
+		transform := apiversion.Rewrite([]apiversion.Rule{{
+			From: ingressV1beta1,
+			To:   ingressV1,
+			Migrate: func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				if obj.GetAPIVersion() != "networking.k8s.io/v1" {
+					return obj, errors.New("migration ran before the rewrite")
+				}
+
+				return obj, migrateErr
+			},
+		}})
+
+		_, err := transform(t.Context(), makeIngress())
+		g.Expect(err).Should(MatchError(migrateErr))
+	})
+
+	t.Run("should pass through objects matching no rule", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transform := apiversion.Rewrite([]apiversion.Rule{{
+			From: schema.GroupVersionKind{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"},
+			To:   schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"},
+		}})
+
+		input := makeIngress()
+		result, err := transform(t.Context(), input)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(makeIngress().Object))
+	})
+}
@@ -0,0 +1,215 @@
+// Package checksum provides a hook rolling workloads when the ConfigMaps and Secrets they use change.
+package checksum
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/podspec"
+)
+
+// ref identifies a ConfigMap or Secret.
+type ref struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// Hook returns a types.Hook whose AfterRender stamps the pod template of workloads with a checksum
+// of the ConfigMaps and Secrets they reference, so that a change of their content rolls the workload.
+// Register it with engine.WithHook; it needs the whole rendered set, which a per-object transformer
+// does not see.
+//
+// References are read from the pod spec of Deployments, StatefulSets, DaemonSets, ReplicaSets,
+// ReplicationControllers, Jobs and CronJobs: ConfigMap and Secret volumes, including projected
+// sources, and the envFrom and env valueFrom references of containers and init containers.
+// Only references to ConfigMaps and Secrets of the rendered set, in the workload's namespace, are
+// taken into account; workloads without any such reference are left untouched. The checksum covers
+// the data, binaryData and stringData of the referenced objects.
+func Hook(opts ...Option) types.Hook {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return &hook{options: options}
+}
+
+type hook struct {
+	options Options
+}
+
+// BeforeRender returns values unchanged.
+func (h *hook) BeforeRender(_ context.Context, values map[string]any) (map[string]any, error) {
+	return values, nil
+}
+
+// AfterRender stamps the workloads of objects with the checksum of their configuration.
+// Annotated workloads are copies; the input objects are not modified.
+func (h *hook) AfterRender(
+	_ context.Context,
+	objects []unstructured.Unstructured,
+) ([]unstructured.Unstructured, error) {
+	digests := make(map[ref]string)
+
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		if gvk.Group != "" || (gvk.Kind != "ConfigMap" && gvk.Kind != "Secret") {
+			continue
+		}
+
+		digest, err := contentDigest(obj)
+		if err != nil {
+			return nil, fmt.Errorf("unable to hash %s %s/%s: %w", gvk.Kind, obj.GetNamespace(), obj.GetName(), err)
+		}
+
+		digests[ref{kind: gvk.Kind, namespace: obj.GetNamespace(), name: obj.GetName()}] = digest
+	}
+
+	if len(digests) == 0 {
+		return objects, nil
+	}
+
+	result := slices.Clone(objects)
+
+	for i, obj := range result {
+		templatePath, ok := podspec.TemplatePath(obj.GroupVersionKind().GroupKind())
+		if !ok {
+			continue
+		}
+
+		template, found, err := unstructured.NestedMap(obj.Object, templatePath...)
+		if err != nil || !found {
+			continue
+		}
+
+		sum := sha256.New()
+		matched := false
+
+		for _, r := range references(template, obj.GetNamespace()) {
+			if digest, ok := digests[r]; ok {
+				sum.Write([]byte(r.kind + "/" + r.name + "=" + digest + "\n"))
+
+				matched = true
+			}
+		}
+
+		if !matched {
+			continue
+		}
+
+		annotated := obj.DeepCopy()
+		annotationsPath := append(slices.Clone(templatePath), "metadata", "annotations")
+
+		if err := unstructured.SetNestedField(
+			annotated.Object,
+			hex.EncodeToString(sum.Sum(nil)),
+			append(annotationsPath, h.options.Annotation)...,
+		); err != nil {
+			return nil, fmt.Errorf(
+				"unable to annotate %s %s/%s: %w",
+				obj.GetKind(),
+				obj.GetNamespace(),
+				obj.GetName(),
+				err,
+			)
+		}
+
+		result[i] = *annotated
+	}
+
+	return result, nil
+}
+
+// contentDigest returns the hex-encoded SHA-256 of the content of a ConfigMap or Secret.
+// The JSON encoding sorts map keys, which makes the digest stable.
+func contentDigest(obj unstructured.Unstructured) (string, error) {
+	content := make(map[string]any)
+
+	for _, field := range []string{"data", "binaryData", "stringData"} {
+		if value, ok := obj.Object[field]; ok {
+			content[field] = value
+		}
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// references returns the ConfigMaps and Secrets referenced by a pod template, sorted and deduplicated.
+func references(template map[string]any, namespace string) []ref {
+	var refs []ref
+
+	add := func(kind string, name string) {
+		if name != "" {
+			refs = append(refs, ref{kind: kind, namespace: namespace, name: name})
+		}
+	}
+
+	volumes, _, _ := unstructured.NestedSlice(template, "spec", "volumes")
+	for _, volume := range maps(volumes) {
+		add("ConfigMap", nestedString(volume, "configMap", "name"))
+		add("Secret", nestedString(volume, "secret", "secretName"))
+
+		sources, _, _ := unstructured.NestedSlice(volume, "projected", "sources")
+		for _, source := range maps(sources) {
+			add("ConfigMap", nestedString(source, "configMap", "name"))
+			add("Secret", nestedString(source, "secret", "name"))
+		}
+	}
+
+	for _, field := range []string{"containers", "initContainers"} {
+		containers, _, _ := unstructured.NestedSlice(template, "spec", field)
+		for _, container := range maps(containers) {
+			envFrom, _, _ := unstructured.NestedSlice(container, "envFrom")
+			for _, source := range maps(envFrom) {
+				add("ConfigMap", nestedString(source, "configMapRef", "name"))
+				add("Secret", nestedString(source, "secretRef", "name"))
+			}
+
+			env, _, _ := unstructured.NestedSlice(container, "env")
+			for _, variable := range maps(env) {
+				add("ConfigMap", nestedString(variable, "valueFrom", "configMapKeyRef", "name"))
+				add("Secret", nestedString(variable, "valueFrom", "secretKeyRef", "name"))
+			}
+		}
+	}
+
+	slices.SortFunc(refs, func(a ref, b ref) int {
+		return cmp.Or(cmp.Compare(a.kind, b.kind), cmp.Compare(a.name, b.name))
+	})
+
+	return slices.Compact(refs)
+}
+
+func maps(items []any) []map[string]any {
+	result := make([]map[string]any, 0, len(items))
+
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			result = append(result, m)
+		}
+	}
+
+	return result
+}
+
+func nestedString(obj map[string]any, fields ...string) string {
+	value, _, _ := unstructured.NestedString(obj, fields...)
+
+	return value
+}
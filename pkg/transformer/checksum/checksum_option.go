@@ -0,0 +1,38 @@
+package checksum
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// DefaultAnnotation is the pod template annotation receiving the checksum.
+const DefaultAnnotation = "checksum/config"
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the checksum hook.
+type Options struct {
+	// Annotation is the pod template annotation receiving the checksum.
+	// Defaults to DefaultAnnotation.
+	Annotation string
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Annotation != "" {
+		target.Annotation = opts.Annotation
+	}
+}
+
+// WithAnnotation sets the pod template annotation receiving the checksum.
+func WithAnnotation(key string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Annotation = key
+	})
+}
+
+func defaultOptions() Options {
+	return Options{
+		Annotation: DefaultAnnotation,
+	}
+}
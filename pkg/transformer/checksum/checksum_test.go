@@ -0,0 +1,180 @@
+package checksum_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+	"github.com/k8s-manifest-kit/engine/pkg/enginetest"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/checksum"
+
+	. "github.com/onsi/gomega"
+)
+
+const workloads = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: apps
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        envFrom:
+        - secretRef:
+            name: creds
+      volumes:
+      - name: config
+        configMap:
+          name: config
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: standalone
+  namespace: apps
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: external
+  namespace: apps
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        envFrom:
+        - configMapRef:
+            name: not-rendered
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: report
+  namespace: apps
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: report
+            env:
+            - name: LEVEL
+              valueFrom:
+                configMapKeyRef:
+                  name: config
+                  key: level
+`
+
+func configuration(level string) string {
+	return `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: apps
+data:
+  level: ` + level + `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: apps
+stringData:
+  password: secret
+`
+}
+
+func render(g *WithT, t *testing.T, config string, opts ...checksum.Option) map[string]map[string]string {
+	t.Helper()
+
+	e, err := engine.New(
+		engine.WithRenderer(enginetest.FromYAML("workloads", workloads)),
+		engine.WithRenderer(enginetest.FromYAML("config", config)),
+		engine.WithHook(checksum.Hook(opts...)),
+	)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	objects, err := e.Render(t.Context())
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(objects).Should(HaveLen(6))
+
+	result := make(map[string]map[string]string)
+
+	for _, obj := range objects {
+		path := []string{"spec", "template", "metadata", "annotations"}
+		if obj.GetKind() == "CronJob" {
+			path = []string{"spec", "jobTemplate", "spec", "template", "metadata", "annotations"}
+		}
+
+		annotations, _, _ := unstructured.NestedStringMap(obj.Object, path...)
+		result[obj.GetName()] = annotations
+	}
+
+	return result
+}
+
+func TestHook(t *testing.T) {
+
+	t.Run("should annotate workloads referencing rendered configuration only", func(t *testing.T) {
+		g := NewWithT(t)
+
+		annotations := render(g, t, configuration("info"))
+		g.Expect(annotations["web"]).Should(HaveKey(checksum.DefaultAnnotation))
+		g.Expect(annotations["report"]).Should(HaveKey(checksum.DefaultAnnotation))
+		g.Expect(annotations["standalone"]).Should(BeEmpty())
+		g.Expect(annotations["external"]).Should(BeEmpty())
+	})
+
+	t.Run("should change the checksum when the configuration changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		before := render(g, t, configuration("info"))
+		same := render(g, t, configuration("info"))
+		after := render(g, t, configuration("debug"))
+
+		g.Expect(same["web"]).Should(Equal(before["web"]))
+		g.Expect(after["web"][checksum.DefaultAnnotation]).ShouldNot(Equal(before["web"][checksum.DefaultAnnotation]))
+		g.Expect(after["report"][checksum.DefaultAnnotation]).ShouldNot(Equal(before["report"][checksum.DefaultAnnotation]))
+	})
+
+	t.Run("should use the configured annotation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		annotations := render(g, t, configuration("info"), checksum.WithAnnotation("example.com/config-hash"))
+		g.Expect(annotations["web"]).Should(HaveKey("example.com/config-hash"))
+		g.Expect(annotations["web"]).ShouldNot(HaveKey(checksum.DefaultAnnotation))
+	})
+
+	t.Run("should ignore configuration of other namespaces", func(t *testing.T) {
+		g := NewWithT(t)
+
+		other := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: other
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: other
+`
+
+		annotations := render(g, t, other)
+		g.Expect(annotations["web"]).Should(BeEmpty())
+	})
+}
@@ -0,0 +1,244 @@
+// Package companion provides hooks generating companion objects, such as a HorizontalPodAutoscaler
+// or a PodDisruptionBudget, for the workloads of a render.
+package companion
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// workloadKinds are the kinds receiving companion objects.
+//
+//nolint:gochecknoglobals
+var workloadKinds = []schema.GroupKind{
+	{Group: "apps", Kind: "Deployment"},
+	{Group: "apps", Kind: "StatefulSet"},
+}
+
+// HPA returns a types.Hook whose AfterRender adds, right after each Deployment and StatefulSet,
+// a HorizontalPodAutoscaler in the same namespace with the given spec and a scaleTargetRef targeting
+// the workload. Register it with engine.WithHook: it creates objects, which a per-object transformer
+// cannot do.
+//
+// Workloads already targeted by a HorizontalPodAutoscaler of the rendered set, and workloads rejected
+// by the WithSelector filter, are skipped. The scaleTargetRef of spec is ignored.
+func HPA(spec autoscalingv2.HorizontalPodAutoscalerSpec, opts ...Option) types.Hook {
+	return newHook(opts, func(workload unstructured.Unstructured, name string) ([]unstructured.Unstructured, error) {
+		hpaSpec := *spec.DeepCopy()
+		hpaSpec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
+			APIVersion: workload.GetAPIVersion(),
+			Kind:       workload.GetKind(),
+			Name:       workload.GetName(),
+		}
+
+		return newObject(autoscalingv2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"), workload, name, &hpaSpec)
+	}, hasHPA)
+}
+
+// PDB returns a types.Hook whose AfterRender adds, right after each Deployment and StatefulSet,
+// a PodDisruptionBudget in the same namespace with the given spec and the selector of the workload.
+// Register it with engine.WithHook: it creates objects, which a per-object transformer cannot do.
+//
+// Workloads whose pods are already selected by a PodDisruptionBudget of the rendered set, workloads
+// without a selector, and workloads rejected by the WithSelector filter are skipped. The selector of
+// spec is ignored.
+func PDB(spec policyv1.PodDisruptionBudgetSpec, opts ...Option) types.Hook {
+	return newHook(opts, func(workload unstructured.Unstructured, name string) ([]unstructured.Unstructured, error) {
+		var selector metav1.LabelSelector
+
+		content, found, err := unstructured.NestedMap(workload.Object, "spec", "selector")
+		if err != nil || !found {
+			return nil, err
+		}
+
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &selector); err != nil {
+			return nil, fmt.Errorf("failed to read selector: %w", err)
+		}
+
+		pdbSpec := *spec.DeepCopy()
+		pdbSpec.Selector = &selector
+
+		return newObject(policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"), workload, name, &pdbSpec)
+	}, hasPDB)
+}
+
+// generator returns the companion object of workload, named name, if it has one.
+type generator func(workload unstructured.Unstructured, name string) ([]unstructured.Unstructured, error)
+
+// coverage reports whether workload already has a companion object among objects.
+type coverage func(objects []unstructured.Unstructured, workload unstructured.Unstructured) (bool, error)
+
+func newHook(opts []Option, generate generator, covered coverage) types.Hook {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return &hook{
+		options:  options,
+		generate: generate,
+		covered:  covered,
+	}
+}
+
+type hook struct {
+	options  Options
+	generate generator
+	covered  coverage
+}
+
+// BeforeRender returns values unchanged.
+func (h *hook) BeforeRender(_ context.Context, values map[string]any) (map[string]any, error) {
+	return values, nil
+}
+
+// AfterRender adds the companion objects of the workloads of objects.
+// The input objects are not modified.
+func (h *hook) AfterRender(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+) ([]unstructured.Unstructured, error) {
+	result := make([]unstructured.Unstructured, 0, len(objects))
+
+	for _, obj := range objects {
+		result = append(result, obj)
+
+		companions, err := h.companions(ctx, objects, obj)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"unable to generate the companion of %s %s/%s: %w",
+				obj.GetKind(),
+				obj.GetNamespace(),
+				obj.GetName(),
+				err,
+			)
+		}
+
+		result = append(result, companions...)
+	}
+
+	return result, nil
+}
+
+// companions returns the companion object of obj, if it is a selected workload without one.
+func (h *hook) companions(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+	obj unstructured.Unstructured,
+) ([]unstructured.Unstructured, error) {
+	if !slices.Contains(workloadKinds, obj.GroupVersionKind().GroupKind()) {
+		return nil, nil
+	}
+
+	if h.options.Selector != nil {
+		ok, err := h.options.Selector(ctx, obj)
+		if err != nil || !ok {
+			return nil, err
+		}
+	}
+
+	covered, err := h.covered(objects, obj)
+	if err != nil || covered {
+		return nil, err
+	}
+
+	return h.generate(obj, obj.GetName()+h.options.NameSuffix)
+}
+
+// newObject returns an object of kind gvk named name, in the namespace of workload, with spec.
+func newObject(
+	gvk schema.GroupVersionKind,
+	workload unstructured.Unstructured,
+	name string,
+	spec any,
+) ([]unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s spec: %w", gvk.Kind, err)
+	}
+
+	obj := unstructured.Unstructured{Object: map[string]any{"spec": content}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(name)
+
+	if namespace := workload.GetNamespace(); namespace != "" {
+		obj.SetNamespace(namespace)
+	}
+
+	return []unstructured.Unstructured{obj}, nil
+}
+
+// hasHPA reports whether a HorizontalPodAutoscaler of objects targets workload.
+func hasHPA(objects []unstructured.Unstructured, workload unstructured.Unstructured) (bool, error) {
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		if gvk.Group != autoscalingv2.GroupName || gvk.Kind != "HorizontalPodAutoscaler" {
+			continue
+		}
+
+		if obj.GetNamespace() != workload.GetNamespace() {
+			continue
+		}
+
+		ref, _, err := unstructured.NestedStringMap(obj.Object, "spec", "scaleTargetRef")
+		if err != nil {
+			return false, fmt.Errorf("failed to read scaleTargetRef of HorizontalPodAutoscaler %s: %w", obj.GetName(), err)
+		}
+
+		if ref["kind"] == workload.GetKind() && ref["name"] == workload.GetName() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// hasPDB reports whether a PodDisruptionBudget of objects selects the pods of workload.
+func hasPDB(objects []unstructured.Unstructured, workload unstructured.Unstructured) (bool, error) {
+	podLabels, _, err := unstructured.NestedStringMap(workload.Object, "spec", "template", "metadata", "labels")
+	if err != nil {
+		return false, fmt.Errorf("failed to read pod template labels: %w", err)
+	}
+
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		if gvk.Group != policyv1.GroupName || gvk.Kind != "PodDisruptionBudget" {
+			continue
+		}
+
+		if obj.GetNamespace() != workload.GetNamespace() {
+			continue
+		}
+
+		var pdb policyv1.PodDisruptionBudget
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &pdb); err != nil {
+			return false, fmt.Errorf("failed to read PodDisruptionBudget %s: %w", obj.GetName(), err)
+		}
+
+		if pdb.Spec.Selector == nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return false, fmt.Errorf("failed to read selector of PodDisruptionBudget %s: %w", obj.GetName(), err)
+		}
+
+		if !selector.Empty() && selector.Matches(labels.Set(podLabels)) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
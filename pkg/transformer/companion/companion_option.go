@@ -0,0 +1,47 @@
+package companion
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the companion hooks.
+type Options struct {
+	// Selector restricts the workloads receiving a companion object. If nil, every workload does.
+	Selector types.Filter
+
+	// NameSuffix is appended to the name of a workload to form the name of its companion object.
+	// If empty, the companion object has the name of the workload.
+	NameSuffix string
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Selector != nil {
+		target.Selector = opts.Selector
+	}
+
+	if opts.NameSuffix != "" {
+		target.NameSuffix = opts.NameSuffix
+	}
+}
+
+// WithSelector restricts the workloads receiving a companion object to those accepted by selector,
+// e.g. labels.HasLabel("autoscaling").
+func WithSelector(selector types.Filter) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Selector = selector
+	})
+}
+
+// WithNameSuffix sets the suffix appended to the name of a workload to form the name of its
+// companion object, e.g. "-hpa".
+func WithNameSuffix(suffix string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.NameSuffix = suffix
+	})
+}
@@ -0,0 +1,195 @@
+package companion_test
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter/meta/name"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/companion"
+
+	. "github.com/onsi/gomega"
+)
+
+func toUnstructured(t *testing.T, obj runtime.Object) unstructured.Unstructured {
+	t.Helper()
+
+	unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return unstructured.Unstructured{Object: unstr}
+}
+
+func fromUnstructured[T any](t *testing.T, obj unstructured.Unstructured) *T {
+	t.Helper()
+
+	var result T
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &result)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return &result
+}
+
+func deployment(t *testing.T, name string) unstructured.Unstructured {
+	t.Helper()
+
+	podLabels := map[string]string{"app": name}
+
+	return toUnstructured(t, &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: podLabels}},
+		},
+	})
+}
+
+func kinds(objects []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, obj.GetKind()+"/"+obj.GetName())
+	}
+
+	return result
+}
+
+func TestHPA(t *testing.T) {
+
+	spec := autoscalingv2.HorizontalPodAutoscalerSpec{
+		MinReplicas: ptr.To[int32](2),
+		MaxReplicas: 10,
+	}
+
+	t.Run("should add an autoscaler targeting each workload after it", func(t *testing.T) {
+		g := NewWithT(t)
+
+		service := toUnstructured(t, &corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+		})
+
+		objects := []unstructured.Unstructured{deployment(t, "web"), service, deployment(t, "api")}
+
+		result, err := companion.HPA(spec).AfterRender(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(kinds(result)).Should(Equal([]string{
+			"Deployment/web",
+			"HorizontalPodAutoscaler/web",
+			"Service/web",
+			"Deployment/api",
+			"HorizontalPodAutoscaler/api",
+		}))
+
+		hpa := fromUnstructured[autoscalingv2.HorizontalPodAutoscaler](t, result[1])
+		g.Expect(hpa.APIVersion).Should(Equal("autoscaling/v2"))
+		g.Expect(hpa.Namespace).Should(Equal("apps"))
+		g.Expect(hpa.Spec.MinReplicas).Should(Equal(ptr.To[int32](2)))
+		g.Expect(hpa.Spec.MaxReplicas).Should(Equal(int32(10)))
+		g.Expect(hpa.Spec.ScaleTargetRef).Should(Equal(autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       "web",
+		}))
+		g.Expect(result[1].Object).ShouldNot(HaveKey("status"))
+	})
+
+	t.Run("should skip workloads that already have an autoscaler", func(t *testing.T) {
+		g := NewWithT(t)
+
+		existing := toUnstructured(t, &autoscalingv2.HorizontalPodAutoscaler{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "autoscaling/v2", Kind: "HorizontalPodAutoscaler"},
+			ObjectMeta: metav1.ObjectMeta{Name: "custom", Namespace: "apps"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+				MaxReplicas:    3,
+			},
+		})
+
+		objects := []unstructured.Unstructured{deployment(t, "web"), existing}
+
+		result, err := companion.HPA(spec).AfterRender(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(kinds(result)).Should(Equal([]string{"Deployment/web", "HorizontalPodAutoscaler/custom"}))
+	})
+
+	t.Run("should only consider selected workloads", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := []unstructured.Unstructured{deployment(t, "web"), deployment(t, "api")}
+
+		result, err := companion.HPA(spec,
+			companion.WithSelector(name.Exact("api")),
+			companion.WithNameSuffix("-hpa"),
+		).AfterRender(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(kinds(result)).Should(Equal([]string{
+			"Deployment/web",
+			"Deployment/api",
+			"HorizontalPodAutoscaler/api-hpa",
+		}))
+	})
+}
+
+func TestPDB(t *testing.T) {
+
+	spec := policyv1.PodDisruptionBudgetSpec{
+		MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+	}
+
+	t.Run("should add a disruption budget selecting the pods of each workload", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := companion.PDB(spec).AfterRender(t.Context(), []unstructured.Unstructured{deployment(t, "web")})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(kinds(result)).Should(Equal([]string{"Deployment/web", "PodDisruptionBudget/web"}))
+
+		pdb := fromUnstructured[policyv1.PodDisruptionBudget](t, result[1])
+		g.Expect(pdb.APIVersion).Should(Equal("policy/v1"))
+		g.Expect(pdb.Namespace).Should(Equal("apps"))
+		g.Expect(pdb.Spec.MaxUnavailable).Should(Equal(ptr.To(intstr.FromInt32(1))))
+		g.Expect(pdb.Spec.Selector).Should(Equal(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}))
+	})
+
+	t.Run("should skip workloads whose pods are already selected", func(t *testing.T) {
+		g := NewWithT(t)
+
+		existing := toUnstructured(t, &policyv1.PodDisruptionBudget{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "policy/v1", Kind: "PodDisruptionBudget"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web-budget", Namespace: "apps"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+		})
+
+		objects := []unstructured.Unstructured{existing, deployment(t, "web"), deployment(t, "api")}
+
+		result, err := companion.PDB(spec).AfterRender(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(kinds(result)).Should(Equal([]string{
+			"PodDisruptionBudget/web-budget",
+			"Deployment/web",
+			"Deployment/api",
+			"PodDisruptionBudget/api",
+		}))
+	})
+
+	t.Run("should skip workloads without a selector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		workload := deployment(t, "web")
+		unstructured.RemoveNestedField(workload.Object, "spec", "selector")
+
+		result, err := companion.PDB(spec).AfterRender(t.Context(), []unstructured.Unstructured{workload})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(kinds(result)).Should(Equal([]string{"Deployment/web"}))
+	})
+}
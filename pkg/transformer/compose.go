@@ -12,7 +12,8 @@ import (
 // Chain explicitly chains transformers in sequence.
 // Each transformer receives the output of the previous transformer.
 // If no transformers are provided, returns the object unchanged.
-// If any transformer returns an error, the error is returned immediately.
+// If any transformer returns an error, it is returned immediately as an Error
+// identifying the object passed to the chain.
 func Chain(transformers ...types.Transformer) types.Transformer {
 	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
 		result := obj
@@ -21,7 +22,7 @@ func Chain(transformers ...types.Transformer) types.Transformer {
 			var err error
 			result, err = transformer(ctx, result)
 			if err != nil {
-				return unstructured.Unstructured{}, err
+				return unstructured.Unstructured{}, Wrap(obj, err)
 			}
 		}
 
@@ -32,20 +33,41 @@ func Chain(transformers ...types.Transformer) types.Transformer {
 // If applies a transformer conditionally based on a filter.
 // If the filter passes, the transformer is applied.
 // If the filter fails, the object is returned unchanged.
-// If the filter returns an error, the error is returned.
+// Errors from the filter or the transformer are returned as an Error identifying the object.
 func If(condition types.Filter, transformer types.Transformer) types.Transformer {
 	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
 		ok, err := condition(ctx, obj)
 		if err != nil {
-			return unstructured.Unstructured{}, err
+			return unstructured.Unstructured{}, Wrap(obj, err)
 		}
 
 		if !ok {
 			return obj, nil
 		}
 
-		return transformer(ctx, obj)
+		result, err := transformer(ctx, obj)
+		if err != nil {
+			return unstructured.Unstructured{}, Wrap(obj, err)
+		}
+
+		return result, nil
+	}
+}
+
+// ForSource applies the transformer only to objects produced by a renderer of the given type, i.e. whose
+// types.AnnotationSourceType annotation equals sourceType, passing all other objects through unchanged.
+//
+// It relies on provenance annotations: objects lacking types.AnnotationSourceType are never transformed.
+// Renderers may set the annotation themselves; otherwise enable engine.WithProvenance, which stamps it
+// before engine-level and render-time transformers run.
+func ForSource(sourceType string, transformer types.Transformer) types.Transformer {
+	fromSource := func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		value, ok := obj.GetAnnotations()[types.AnnotationSourceType]
+
+		return ok && value == sourceType, nil
 	}
+
+	return If(fromSource, transformer)
 }
 
 // Case represents a conditional branch in a Switch.
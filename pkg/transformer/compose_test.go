@@ -72,6 +72,22 @@ func TestChain(t *testing.T) {
 		g.Expect(err).ShouldNot(HaveOccurred())
 		g.Expect(obj.GetLabels()).Should(HaveKeyWithValue("count", "2"))
 	})
+
+	t.Run("should attach the input object identity to errors", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := transformer.Chain(
+			setLabel("step", "1"),
+			transformer.Chain(errorTransformer()),
+		)
+
+		_, err := tr(t.Context(), makePod("test"))
+		g.Expect(err).Should(MatchError(ContainSubstring("transformer error")))
+
+		var trErr *transformer.Error
+		g.Expect(errors.As(err, &trErr)).Should(BeTrue())
+		g.Expect(trErr.Object.GetName()).Should(Equal("test"))
+		g.Expect(trErr.Error()).Should(Equal("transformer error for v1:Pod test (namespace: ): transformer error"))
+	})
 }
 
 func TestIf(t *testing.T) {
@@ -139,6 +155,61 @@ func TestIf(t *testing.T) {
 		g.Expect(err).ShouldNot(HaveOccurred())
 		g.Expect(obj.GetLabels()).Should(HaveKeyWithValue("is-pod", "true"))
 	})
+
+	t.Run("should attach object identity to errors", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := transformer.If(alwaysTrue(), errorTransformer())
+
+		_, err := tr(t.Context(), makePod("test"))
+		g.Expect(err).Should(MatchError(ContainSubstring("transformer error")))
+
+		var trErr *transformer.Error
+		g.Expect(errors.As(err, &trErr)).Should(BeTrue())
+		g.Expect(trErr.Object.GetName()).Should(Equal("test"))
+	})
+}
+
+func TestForSource(t *testing.T) {
+
+	fromSource := func(name string, sourceType string) unstructured.Unstructured {
+		obj := makePod(name)
+		obj.SetAnnotations(map[string]string{types.AnnotationSourceType: sourceType})
+
+		return obj
+	}
+
+	t.Run("should transform only objects from the source", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := transformer.ForSource("helm", setLabel("sidecar", "injected"))
+
+		helm, err := tr(t.Context(), fromSource("helm", "helm"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(helm.GetLabels()).Should(HaveKeyWithValue("sidecar", "injected"))
+
+		kustomize, err := tr(t.Context(), fromSource("kustomize", "kustomize"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(kustomize.GetLabels()).Should(BeEmpty())
+	})
+
+	t.Run("should pass objects without provenance through", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := transformer.ForSource("helm", errorTransformer())
+
+		obj, err := tr(t.Context(), makePod("test"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetName()).Should(Equal("test"))
+	})
+
+	t.Run("should attach object identity to errors", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := transformer.ForSource("helm", errorTransformer())
+
+		_, err := tr(t.Context(), fromSource("test", "helm"))
+
+		var trErr *transformer.Error
+		g.Expect(errors.As(err, &trErr)).Should(BeTrue())
+		g.Expect(trErr.Object.GetName()).Should(Equal("test"))
+	})
 }
 
 func TestSwitch(t *testing.T) {
@@ -0,0 +1,120 @@
+// Package env provides a transformer setting the environment variables of workload containers.
+package env
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/podspec"
+)
+
+// Set returns a transformer merging vars into the env of the container named containerName of Pods,
+// Deployments, StatefulSets, DaemonSets, ReplicaSets, ReplicationControllers, Jobs and CronJobs.
+// A variable replaces the one with the same name, in place, and is appended otherwise.
+//
+// Use WithAllContainers to target all containers, WithInitContainers to include init containers,
+// and WithEnvFrom to inject envFrom sources as well. Objects of other kinds, or without a matching
+// container, pass through untouched.
+func Set(containerName string, vars []corev1.EnvVar, opts ...Option) types.Transformer {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	fields := []string{"containers"}
+	if options.InitContainers {
+		fields = append(fields, "initContainers")
+	}
+
+	// Converted once; a conversion error is reported on the first object it would apply to.
+	env, envErr := toUnstructured(vars)
+	envFrom, envFromErr := toUnstructured(options.EnvFrom)
+
+	return podspec.Transformer(func(spec map[string]any) (bool, error) {
+		if envErr != nil {
+			return false, envErr
+		}
+
+		if envFromErr != nil {
+			return false, envFromErr
+		}
+
+		return podspec.UpdateContainers(spec, fields, func(container map[string]any) error {
+			name, _, _ := unstructured.NestedString(container, "name")
+			if !options.AllContainers && name != containerName {
+				return nil
+			}
+
+			if err := merge(container, "env", env, sameName); err != nil {
+				return fmt.Errorf("container %q: %w", name, err)
+			}
+
+			if err := merge(container, "envFrom", envFrom, reflect.DeepEqual); err != nil {
+				return fmt.Errorf("container %q: %w", name, err)
+			}
+
+			return nil
+		})
+	})
+}
+
+// merge merges items into the list field of container: an item replaces the first existing one
+// it matches, and is appended otherwise.
+func merge(container map[string]any, field string, items []any, match func(a any, b any) bool) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	list, _, err := unstructured.NestedSlice(container, field)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", field, err)
+	}
+
+	for _, item := range items {
+		i := slices.IndexFunc(list, func(existing any) bool {
+			return match(existing, item)
+		})
+
+		if i >= 0 {
+			list[i] = runtime.DeepCopyJSONValue(item)
+		} else {
+			list = append(list, runtime.DeepCopyJSONValue(item))
+		}
+	}
+
+	if err := unstructured.SetNestedSlice(container, list, field); err != nil {
+		return fmt.Errorf("failed to set %s: %w", field, err)
+	}
+
+	return nil
+}
+
+// sameName reports whether two env entries define the same variable.
+func sameName(a any, b any) bool {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+
+	return aok && bok && am["name"] == bm["name"]
+}
+
+// toUnstructured converts typed items to their unstructured form.
+func toUnstructured[T any](items []T) ([]any, error) {
+	result := make([]any, 0, len(items))
+
+	for i := range items {
+		item, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&items[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %T: %w", items[i], err)
+		}
+
+		result = append(result, item)
+	}
+
+	return result, nil
+}
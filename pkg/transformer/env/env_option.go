@@ -0,0 +1,51 @@
+package env
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the Set transformer.
+type Options struct {
+	// AllContainers sets the variables in all containers instead of the named one.
+	AllContainers bool
+
+	// InitContainers sets the variables in init containers as well.
+	InitContainers bool
+
+	// EnvFrom are the sources of variables appended to the envFrom of the containers.
+	EnvFrom []corev1.EnvFromSource
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.AllContainers = opts.AllContainers
+	target.InitContainers = opts.InitContainers
+	target.EnvFrom = append(target.EnvFrom, opts.EnvFrom...)
+}
+
+// WithAllContainers enables or disables setting the variables in all containers, whatever their name.
+func WithAllContainers(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.AllContainers = enabled
+	})
+}
+
+// WithInitContainers enables or disables setting the variables in init containers as well.
+func WithInitContainers(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.InitContainers = enabled
+	})
+}
+
+// WithEnvFrom appends sources of variables, such as ConfigMaps or Secrets, to the envFrom of the
+// containers. Sources already present are not added twice.
+func WithEnvFrom(sources ...corev1.EnvFromSource) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.EnvFrom = append(o.EnvFrom, sources...)
+	})
+}
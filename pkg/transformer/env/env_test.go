@@ -0,0 +1,150 @@
+package env_test
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/env"
+
+	. "github.com/onsi/gomega"
+)
+
+func toUnstructured(t *testing.T, obj runtime.Object) unstructured.Unstructured {
+	t.Helper()
+
+	unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return unstructured.Unstructured{Object: unstr}
+}
+
+func fromUnstructured[T any](t *testing.T, obj unstructured.Unstructured) *T {
+	t.Helper()
+
+	var result T
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &result)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return &result
+}
+
+func podSpec() corev1.PodSpec {
+	return corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "init"},
+		},
+		Containers: []corev1.Container{
+			{
+				Name: "app",
+				Env: []corev1.EnvVar{
+					{Name: "LOG_LEVEL", Value: "info"},
+					{Name: "PORT", Value: "8080"},
+				},
+			},
+			{Name: "sidecar"},
+		},
+	}
+}
+
+func deployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{Spec: podSpec()},
+		},
+	}
+}
+
+func TestSet(t *testing.T) {
+
+	vars := []corev1.EnvVar{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "REGION", Value: "eu-west-1"},
+	}
+
+	t.Run("should merge variables into the named container by name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := env.Set("app", vars)(t.Context(), toUnstructured(t, deployment()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		spec := fromUnstructured[appsv1.Deployment](t, result).Spec.Template.Spec
+		g.Expect(spec.Containers[0].Env).Should(Equal([]corev1.EnvVar{
+			{Name: "LOG_LEVEL", Value: "debug"},
+			{Name: "PORT", Value: "8080"},
+			{Name: "REGION", Value: "eu-west-1"},
+		}))
+		g.Expect(spec.Containers[1].Env).Should(BeEmpty())
+		g.Expect(spec.InitContainers[0].Env).Should(BeEmpty())
+	})
+
+	t.Run("should set variables in all containers and init containers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cronJob := &batchv1.CronJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+			ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+			Spec: batchv1.CronJobSpec{
+				JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{Spec: podSpec()},
+				}},
+			},
+		}
+
+		transform := env.Set("", vars[1:], env.WithAllContainers(true), env.WithInitContainers(true))
+
+		result, err := transform(t.Context(), toUnstructured(t, cronJob))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		spec := fromUnstructured[batchv1.CronJob](t, result).Spec.JobTemplate.Spec.Template.Spec
+		for _, c := range append(spec.InitContainers, spec.Containers...) {
+			g.Expect(c.Env).Should(ContainElement(corev1.EnvVar{Name: "REGION", Value: "eu-west-1"}), c.Name)
+		}
+	})
+
+	t.Run("should inject envFrom sources once", func(t *testing.T) {
+		g := NewWithT(t)
+
+		source := corev1.EnvFromSource{
+			ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "shared"}},
+		}
+		transform := env.Set("app", nil, env.WithEnvFrom(source))
+
+		result, err := transform(t.Context(), toUnstructured(t, deployment()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err = transform(t.Context(), result)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		spec := fromUnstructured[appsv1.Deployment](t, result).Spec.Template.Spec
+		g.Expect(spec.Containers[0].EnvFrom).Should(Equal([]corev1.EnvFromSource{source}))
+		g.Expect(spec.Containers[0].Env).Should(HaveLen(2))
+	})
+
+	t.Run("should pass through objects without the named container", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, deployment())
+
+		result, err := env.Set("missing", vars)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(Equal(toUnstructured(t, deployment())))
+
+		cm := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": "config"},
+		}}
+
+		result, err = env.Set("app", vars)(t.Context(), cm)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(Equal(cm))
+	})
+}
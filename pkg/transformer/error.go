@@ -2,32 +2,15 @@ package transformer
 
 import (
 	"errors"
-	"fmt"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
 )
 
 // Error represents an error that occurred during transformer application.
-// It provides context about which object failed and the underlying error.
-type Error struct {
-	Object unstructured.Unstructured
-	Err    error
-}
-
-func (e *Error) Error() string {
-	return fmt.Sprintf(
-		"transformer error for %s:%s %s (namespace: %s): %v",
-		e.Object.GroupVersionKind().GroupVersion(),
-		e.Object.GroupVersionKind().Kind,
-		e.Object.GetName(),
-		e.Object.GetNamespace(),
-		e.Err,
-	)
-}
-
-func (e *Error) Unwrap() error {
-	return e.Err
-}
+// It is an alias of types.TransformerError, so either can be used with errors.As.
+type Error = types.TransformerError
 
 // Wrap wraps an error with transformer context.
 // If err is already an Error, it returns it as-is to avoid double-wrapping.
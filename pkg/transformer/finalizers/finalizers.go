@@ -0,0 +1,53 @@
+// Package finalizers provides transformers removing finalizers, so that exported objects do not hang
+// on deletion once applied to a cluster running none of the controllers owning them.
+package finalizers
+
+import (
+	"context"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Remove returns a transformer clearing metadata.finalizers, except for the finalizers listed with
+// WithKeep. The field is removed once no finalizer is left. Objects without finalizers, and objects
+// whose GroupVersionKind is not listed with WithGVKs when it is used, are returned unchanged.
+func Remove(opts ...Option) types.Transformer {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if len(options.GVKs) > 0 && !slices.Contains(options.GVKs, obj.GroupVersionKind()) {
+			return obj, nil
+		}
+
+		finalizers := obj.GetFinalizers()
+		if len(finalizers) == 0 {
+			return obj, nil
+		}
+
+		kept := slices.DeleteFunc(finalizers, func(name string) bool {
+			return !slices.Contains(options.Keep, name)
+		})
+
+		if len(kept) == 0 {
+			unstructured.RemoveNestedField(obj.Object, "metadata", "finalizers")
+
+			return obj, nil
+		}
+
+		obj.SetFinalizers(kept)
+
+		return obj, nil
+	}
+}
+
+// Keep returns a transformer retaining only the finalizers named names, removing the others.
+// It is a shorthand for Remove(WithKeep(names...)).
+func Keep(names ...string) types.Transformer {
+	return Remove(WithKeep(names...))
+}
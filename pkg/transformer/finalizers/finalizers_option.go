@@ -0,0 +1,41 @@
+package finalizers
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the Remove transformer.
+type Options struct {
+	// Keep are the names of the finalizers to retain.
+	Keep []string
+
+	// GVKs restricts the transformer to objects of these GroupVersionKinds.
+	// If empty, all objects are transformed.
+	GVKs []schema.GroupVersionKind
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.Keep = append(target.Keep, opts.Keep...)
+	target.GVKs = append(target.GVKs, opts.GVKs...)
+}
+
+// WithKeep retains the finalizers with the given names, e.g. "kubernetes.io/pvc-protection".
+func WithKeep(names ...string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Keep = append(o.Keep, names...)
+	})
+}
+
+// WithGVKs restricts the transformer to objects of the given GroupVersionKinds, leaving other
+// objects untouched.
+func WithGVKs(gvks ...schema.GroupVersionKind) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.GVKs = append(o.GVKs, gvks...)
+	})
+}
@@ -0,0 +1,97 @@
+package finalizers_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/finalizers"
+
+	. "github.com/onsi/gomega"
+)
+
+const pvcProtection = "kubernetes.io/pvc-protection"
+
+func makeObject(apiVersion string, kind string, finalizers ...string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName("test")
+	obj.SetNamespace("default")
+	obj.SetFinalizers(finalizers)
+
+	return obj
+}
+
+func TestRemove(t *testing.T) {
+
+	t.Run("should remove all finalizers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := finalizers.Remove()(t.Context(), makeObject("v1", "ConfigMap", "example.com/cleanup", pvcProtection))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetFinalizers()).Should(BeEmpty())
+
+		_, found, err := unstructured.NestedFieldNoCopy(obj.Object, "metadata", "finalizers")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(found).Should(BeFalse())
+	})
+
+	t.Run("should pass objects without finalizers through", func(t *testing.T) {
+		g := NewWithT(t)
+
+		in := makeObject("v1", "ConfigMap")
+
+		obj, err := finalizers.Remove()(t.Context(), in)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj).Should(Equal(in))
+	})
+
+	t.Run("should retain the finalizers to keep", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := finalizers.Remove(finalizers.WithKeep(pvcProtection))(
+			t.Context(),
+			makeObject("v1", "PersistentVolumeClaim", "example.com/cleanup", pvcProtection),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetFinalizers()).Should(Equal([]string{pvcProtection}))
+	})
+
+	t.Run("should only transform the selected GVKs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transformer := finalizers.Remove(finalizers.WithGVKs(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}))
+
+		obj, err := transformer(t.Context(), makeObject("v1", "ConfigMap", "example.com/cleanup"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetFinalizers()).Should(BeEmpty())
+
+		obj, err = transformer(t.Context(), makeObject("v1", "Secret", "example.com/cleanup"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetFinalizers()).Should(Equal([]string{"example.com/cleanup"}))
+	})
+}
+
+func TestKeep(t *testing.T) {
+
+	t.Run("should retain only the listed finalizers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := finalizers.Keep(pvcProtection, "example.com/other")(
+			t.Context(),
+			makeObject("v1", "PersistentVolumeClaim", "example.com/cleanup", pvcProtection),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetFinalizers()).Should(Equal([]string{pvcProtection}))
+	})
+
+	t.Run("should remove every finalizer when none is listed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := finalizers.Keep()(t.Context(), makeObject("v1", "ConfigMap", "example.com/cleanup"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetFinalizers()).Should(BeEmpty())
+	})
+}
@@ -20,6 +20,7 @@ var (
 )
 
 // Transform creates a new JQ transformer with the given expression and options.
+// The expression must return a single object, which replaces the content of the input object.
 func Transform(expression string, opts ...jq.Option) (types.Transformer, error) {
 	// Create a new JQ engine
 	engine, err := jq.NewEngine(expression, opts...)
@@ -28,11 +29,12 @@ func Transform(expression string, opts ...jq.Option) (types.Transformer, error)
 	}
 
 	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
-		v, err := engine.Run(obj.Object)
+		// The JQ engine normalizes numbers in place, so run it on a copy to leave the input untouched
+		v, err := engine.Run(obj.DeepCopy().Object)
 		if err != nil {
 			return unstructured.Unstructured{}, &transformer.Error{
 				Object: obj,
-				Err:    fmt.Errorf("error execuring jq expression: %w", err),
+				Err:    fmt.Errorf("error executing jq expression: %w", err),
 			}
 		}
 
@@ -270,3 +270,45 @@ func TestTransformer(t *testing.T) {
 		})
 	}
 }
+
+func TestTransformerRoundTrip(t *testing.T) {
+
+	t.Run("should produce an object usable as unstructured", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transformer, err := jq.Transform(`.spec.replicas = 3 | .spec.ports = [{"port": 80}]`)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		input := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": "app"},
+			"spec":       map[string]any{"replicas": int64(1)},
+		}}
+
+		transformed, err := transformer(t.Context(), input)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(transformed.GetName()).Should(Equal("app"))
+		g.Expect(transformed.GetKind()).Should(Equal("Deployment"))
+
+		replicas, found, err := unstructured.NestedInt64(transformed.Object, "spec", "replicas")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(found).Should(BeTrue())
+		g.Expect(replicas).Should(Equal(int64(3)))
+
+		g.Expect(func() { transformed.DeepCopy() }).ShouldNot(Panic())
+		g.Expect(input.Object["spec"]).Should(Equal(map[string]any{"replicas": int64(1)}))
+	})
+
+	t.Run("should reject results that are not objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transformer, err := jq.Transform(`.metadata.name`)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = transformer(t.Context(), unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{"name": "app"},
+		}})
+		g.Expect(err).Should(MatchError(jq.ErrJqMustReturnObject))
+	})
+}
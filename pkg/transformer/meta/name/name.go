@@ -2,9 +2,11 @@ package name
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/k8s-manifest-kit/engine/pkg/types"
 )
@@ -27,6 +29,63 @@ func SetSuffix(suffix string) types.Transformer {
 	}
 }
 
+// Prefix returns a transformer that adds a prefix to resource names, like kustomize's namePrefix.
+// Objects without a name, e.g. relying on generateName, are left unchanged, and a name exceeding
+// the 253 character limit of Kubernetes names fails the transformation.
+//
+// WithReferences(true) also prefixes the references an object holds to other objects, so that
+// they keep pointing to the renamed objects. Only the following reference fields are renamed:
+//   - in the pod spec of Pods, Deployments, StatefulSets, DaemonSets, ReplicaSets,
+//     ReplicationControllers, Jobs and CronJobs: ConfigMap and Secret volumes, including projected
+//     sources, and the envFrom and env valueFrom ConfigMap and Secret references of containers and
+//     init containers
+//   - in networking.k8s.io Ingresses: the Service names of the default and rule backends, and the
+//     TLS Secret names
+//
+// References are renamed whatever the object they point to, so the referenced objects are expected
+// to be renamed by the same transformer.
+func Prefix(prefix string, opts ...Option) types.Transformer {
+	return rename(func(name string) string { return prefix + name }, opts)
+}
+
+// Suffix returns a transformer that adds a suffix to resource names, like kustomize's nameSuffix.
+// It behaves like Prefix, including the renaming of references enabled by WithReferences.
+func Suffix(suffix string, opts ...Option) types.Transformer {
+	return rename(func(name string) string { return name + suffix }, opts)
+}
+
+func rename(fn func(string) string, opts []Option) types.Transformer {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if obj.GetName() == "" {
+			return obj, nil
+		}
+
+		name := fn(obj.GetName())
+		if len(name) > validation.DNS1123SubdomainMaxLength {
+			return obj, fmt.Errorf(
+				"name %q exceeds the maximum length of %d characters",
+				name,
+				validation.DNS1123SubdomainMaxLength,
+			)
+		}
+
+		obj.SetName(name)
+
+		if options.References {
+			for _, path := range referencePaths[obj.GroupVersionKind().GroupKind()] {
+				renameReferences(obj.Object, path, fn)
+			}
+		}
+
+		return obj, nil
+	}
+}
+
 // Replace returns a transformer that replaces all occurrences of a substring in resource names.
 func Replace(from string, to string) types.Transformer {
 	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
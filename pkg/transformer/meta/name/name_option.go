@@ -0,0 +1,26 @@
+package name
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the Prefix and Suffix transformers.
+type Options struct {
+	// References enables renaming the references to other objects, see Prefix.
+	References bool
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.References = opts.References
+}
+
+// WithReferences enables or disables renaming the references to other objects.
+func WithReferences(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.References = enabled
+	})
+}
@@ -1,6 +1,7 @@
 package name_test
 
 import (
+	"strings"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -79,7 +80,157 @@ func TestReplace(t *testing.T) {
 	})
 }
 
-// Helper function
+func TestPrefix(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should add prefix to name only by default", func(t *testing.T) {
+		transformer := name.Prefix("prod-")
+
+		obj, err := transformer(t.Context(), makeDeployment("web"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetName()).Should(Equal("prod-web"))
+
+		ref, _, _ := unstructured.NestedString(volumes(obj)[0].(map[string]any), "configMap", "name")
+		g.Expect(ref).Should(Equal("config"))
+	})
+
+	t.Run("should rename the references of pod specs", func(t *testing.T) {
+		transformer := name.Prefix("prod-", name.WithReferences(true))
+
+		obj, err := transformer(t.Context(), makeDeployment("web"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		vols := volumes(obj)
+		g.Expect(vols[0]).Should(HaveKeyWithValue("configMap", HaveKeyWithValue("name", "prod-config")))
+		g.Expect(vols[1]).Should(HaveKeyWithValue("secret", HaveKeyWithValue("secretName", "prod-tls")))
+
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		container, _ := containers[0].(map[string]any)
+
+		envFrom, _, _ := unstructured.NestedSlice(container, "envFrom")
+		g.Expect(envFrom[0]).Should(HaveKeyWithValue("secretRef", HaveKeyWithValue("name", "prod-creds")))
+
+		env, _, _ := unstructured.NestedSlice(container, "env")
+		ref, _, _ := unstructured.NestedString(env[0].(map[string]any), "valueFrom", "configMapKeyRef", "name")
+		g.Expect(ref).Should(Equal("prod-settings"))
+	})
+
+	t.Run("should rename the backends and TLS secrets of ingresses", func(t *testing.T) {
+		transformer := name.Prefix("prod-", name.WithReferences(true))
+
+		obj, err := transformer(t.Context(), makeIngress("web"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetName()).Should(Equal("prod-web"))
+
+		backend, _, _ := unstructured.NestedString(obj.Object, "spec", "defaultBackend", "service", "name")
+		g.Expect(backend).Should(Equal("prod-default"))
+
+		rules, _, _ := unstructured.NestedSlice(obj.Object, "spec", "rules")
+		paths, _, _ := unstructured.NestedSlice(rules[0].(map[string]any), "http", "paths")
+		service, _, _ := unstructured.NestedString(paths[0].(map[string]any), "backend", "service", "name")
+		g.Expect(service).Should(Equal("prod-web"))
+
+		tls, _, _ := unstructured.NestedSlice(obj.Object, "spec", "tls")
+		g.Expect(tls[0]).Should(HaveKeyWithValue("secretName", "prod-web-tls"))
+	})
+
+	t.Run("should leave objects without a name unchanged", func(t *testing.T) {
+		transformer := name.Prefix("prod-")
+
+		obj, err := transformer(t.Context(), makePod(""))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetName()).Should(BeEmpty())
+	})
+
+	t.Run("should fail when the name exceeds the maximum length", func(t *testing.T) {
+		transformer := name.Prefix(strings.Repeat("a", 250))
+
+		_, err := transformer(t.Context(), makePod("nginx"))
+		g.Expect(err).Should(MatchError(ContainSubstring("exceeds the maximum length of 253 characters")))
+	})
+}
+
+func TestSuffix(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should add suffix to name and references", func(t *testing.T) {
+		transformer := name.Suffix("-blue", name.WithReferences(true))
+
+		obj, err := transformer(t.Context(), makeDeployment("web"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetName()).Should(Equal("web-blue"))
+		g.Expect(volumes(obj)[0]).Should(HaveKeyWithValue("configMap", HaveKeyWithValue("name", "config-blue")))
+	})
+}
+
+// Helper functions
+
+func makeDeployment(deploymentName string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": deploymentName},
+			"spec": map[string]any{
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{
+								"name": "app",
+								"envFrom": []any{
+									map[string]any{"secretRef": map[string]any{"name": "creds"}},
+								},
+								"env": []any{
+									map[string]any{
+										"name": "LEVEL",
+										"valueFrom": map[string]any{
+											"configMapKeyRef": map[string]any{"name": "settings", "key": "level"},
+										},
+									},
+								},
+							},
+						},
+						"volumes": []any{
+							map[string]any{"name": "config", "configMap": map[string]any{"name": "config"}},
+							map[string]any{"name": "tls", "secret": map[string]any{"secretName": "tls"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func makeIngress(ingressName string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "Ingress",
+			"metadata":   map[string]any{"name": ingressName},
+			"spec": map[string]any{
+				"defaultBackend": map[string]any{"service": map[string]any{"name": "default"}},
+				"rules": []any{
+					map[string]any{
+						"http": map[string]any{
+							"paths": []any{
+								map[string]any{"backend": map[string]any{"service": map[string]any{"name": "web"}}},
+							},
+						},
+					},
+				},
+				"tls": []any{
+					map[string]any{"secretName": "web-tls"},
+				},
+			},
+		},
+	}
+}
+
+func volumes(obj unstructured.Unstructured) []any {
+	vols, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "volumes")
+
+	return vols
+}
 
 func makePod(podName string) unstructured.Unstructured {
 	obj := unstructured.Unstructured{
@@ -0,0 +1,88 @@
+package name
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/k8s-manifest-kit/engine/pkg/util/podspec"
+)
+
+// each marks a path element iterating over the items of a list.
+const each = "*"
+
+// podSpecReferences lists the ConfigMap and Secret references of a pod spec, relative to it.
+//
+//nolint:gochecknoglobals
+var podSpecReferences = [][]string{
+	{"volumes", each, "configMap", "name"},
+	{"volumes", each, "secret", "secretName"},
+	{"volumes", each, "projected", "sources", each, "configMap", "name"},
+	{"volumes", each, "projected", "sources", each, "secret", "name"},
+	{"containers", each, "envFrom", each, "configMapRef", "name"},
+	{"containers", each, "envFrom", each, "secretRef", "name"},
+	{"containers", each, "env", each, "valueFrom", "configMapKeyRef", "name"},
+	{"containers", each, "env", each, "valueFrom", "secretKeyRef", "name"},
+	{"initContainers", each, "envFrom", each, "configMapRef", "name"},
+	{"initContainers", each, "envFrom", each, "secretRef", "name"},
+	{"initContainers", each, "env", each, "valueFrom", "configMapKeyRef", "name"},
+	{"initContainers", each, "env", each, "valueFrom", "secretKeyRef", "name"},
+}
+
+// referencePaths maps kinds to the paths of the references to other objects they hold.
+//
+//nolint:gochecknoglobals
+var referencePaths = func() map[schema.GroupKind][][]string {
+	paths := map[schema.GroupKind][][]string{
+		{Group: "networking.k8s.io", Kind: "Ingress"}: {
+			{"spec", "defaultBackend", "service", "name"},
+			{"spec", "rules", each, "http", "paths", each, "backend", "service", "name"},
+			{"spec", "tls", each, "secretName"},
+		},
+	}
+
+	for _, gk := range podspec.Kinds() {
+		specPath, _ := podspec.Path(gk)
+
+		for _, ref := range podSpecReferences {
+			paths[gk] = append(paths[gk], append(slices.Clone(specPath), ref...))
+		}
+	}
+
+	return paths
+}()
+
+// renameReferences renames, in place, the non-empty strings found at path within value.
+func renameReferences(value any, path []string, rename func(string) string) {
+	if len(path) == 0 {
+		return
+	}
+
+	if path[0] == each {
+		items, ok := value.([]any)
+		if !ok {
+			return
+		}
+
+		for _, item := range items {
+			renameReferences(item, path[1:], rename)
+		}
+
+		return
+	}
+
+	fields, ok := value.(map[string]any)
+	if !ok {
+		return
+	}
+
+	if len(path) > 1 {
+		renameReferences(fields[path[0]], path[1:], rename)
+
+		return
+	}
+
+	if ref, ok := fields[path[0]].(string); ok && ref != "" {
+		fields[path[0]] = rename(ref)
+	}
+}
@@ -0,0 +1,39 @@
+// Package normalize provides a transformer removing server-populated and other noisy fields.
+package normalize
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// New returns a transformer that removes the configured fields (DefaultFields() unless
+// WithFields is used), so that objects rendered by different tools, or read back from a
+// cluster, produce clean and diffable output.
+// Fields missing from an object are ignored.
+func New(opts ...Option) types.Transformer {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	if options.Fields == nil {
+		options.Fields = DefaultFields()
+	}
+
+	paths := make([][]string, 0, len(options.Fields))
+	for _, field := range options.Fields {
+		paths = append(paths, strings.Split(field, "."))
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		for _, path := range paths {
+			unstructured.RemoveNestedField(obj.Object, path...)
+		}
+
+		return obj, nil
+	}
+}
@@ -0,0 +1,47 @@
+package normalize
+
+import (
+	"slices"
+
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the normalization transformer.
+type Options struct {
+	// Fields are the dot-separated paths of the fields to remove (e.g. "metadata.uid").
+	// If nil, DefaultFields() is used.
+	Fields []string
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Fields != nil {
+		target.Fields = slices.Clone(opts.Fields)
+	}
+}
+
+// WithFields sets the dot-separated paths of the fields to remove, replacing the defaults.
+// To extend the defaults, pass them explicitly:
+//
+//	normalize.WithFields(append(normalize.DefaultFields(), "metadata.annotations")...)
+func WithFields(fields ...string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Fields = fields
+	})
+}
+
+// DefaultFields returns the fields removed by default: status and the metadata
+// populated by the API server.
+func DefaultFields() []string {
+	return []string{
+		"status",
+		"metadata.creationTimestamp",
+		"metadata.resourceVersion",
+		"metadata.uid",
+		"metadata.generation",
+		"metadata.managedFields",
+	}
+}
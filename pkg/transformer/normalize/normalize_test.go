@@ -0,0 +1,115 @@
+package normalize_test
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/normalize"
+
+	. "github.com/onsi/gomega"
+)
+
+func toUnstructured(t *testing.T, obj runtime.Object) unstructured.Unstructured {
+	t.Helper()
+
+	unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return unstructured.Unstructured{Object: unstr}
+}
+
+func liveDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "web",
+			Namespace:         "default",
+			UID:               "6f1c0f0e-0000-0000-0000-000000000000",
+			ResourceVersion:   "12345",
+			Generation:        3,
+			CreationTimestamp: metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			Labels:            map[string]string{"app": "web"},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply},
+			},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: 1},
+	}
+}
+
+func TestNew(t *testing.T) {
+
+	t.Run("should strip server-populated fields by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := normalize.New()(t.Context(), toUnstructured(t, liveDeployment()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(obj.Object).ShouldNot(HaveKey("status"))
+		g.Expect(obj.Object["metadata"]).Should(Equal(map[string]any{
+			"name":      "web",
+			"namespace": "default",
+			"labels":    map[string]any{"app": "web"},
+		}))
+	})
+
+	t.Run("should strip a null creationTimestamp", func(t *testing.T) {
+		g := NewWithT(t)
+
+		input := toUnstructured(t, liveDeployment())
+		g.Expect(unstructured.SetNestedField(input.Object, nil, "metadata", "creationTimestamp")).Should(Succeed())
+
+		obj, err := normalize.New()(t.Context(), input)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.Object["metadata"]).ShouldNot(HaveKey("creationTimestamp"))
+	})
+
+	t.Run("should only strip the configured fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := normalize.New(
+			normalize.WithFields("status", "metadata.labels"),
+		)(t.Context(), toUnstructured(t, liveDeployment()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(obj.Object).ShouldNot(HaveKey("status"))
+		g.Expect(obj.GetLabels()).Should(BeEmpty())
+		g.Expect(obj.GetUID()).ShouldNot(BeEmpty())
+		g.Expect(obj.GetResourceVersion()).Should(Equal("12345"))
+	})
+
+	t.Run("should extend the default fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := normalize.New(
+			normalize.WithFields(append(normalize.DefaultFields(), "metadata.labels")...),
+		)(t.Context(), toUnstructured(t, liveDeployment()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(obj.Object["metadata"]).Should(Equal(map[string]any{
+			"name":      "web",
+			"namespace": "default",
+		}))
+	})
+
+	t.Run("should be safe on objects lacking the fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		input := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+		}}
+
+		obj, err := normalize.New()(t.Context(), input)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.Object).Should(Equal(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+		}))
+	})
+}
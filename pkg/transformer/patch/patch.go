@@ -0,0 +1,141 @@
+// Package patch provides transformers applying kustomize-style patches to selected objects.
+package patch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Selector selects the objects a patch applies to.
+// Empty fields match any value.
+type Selector struct {
+	Group     string
+	Version   string
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+// Matches reports whether obj is selected.
+func (s Selector) Matches(obj unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+
+	return matches(s.Group, gvk.Group) &&
+		matches(s.Version, gvk.Version) &&
+		matches(s.Kind, gvk.Kind) &&
+		matches(s.Name, obj.GetName()) &&
+		matches(s.Namespace, obj.GetNamespace())
+}
+
+func matches(expected string, actual string) bool {
+	return expected == "" || expected == actual
+}
+
+// Operation is a single RFC 6902 JSON patch operation.
+type Operation struct {
+	// Op is one of add, remove, replace, move, copy or test.
+	Op string `json:"op"`
+
+	// Path is the JSON pointer to the target location.
+	Path string `json:"path"`
+
+	// Value is the value used by add, replace and test.
+	Value any `json:"value,omitempty"`
+
+	// From is the JSON pointer to the source location used by move and copy.
+	From string `json:"from,omitempty"`
+}
+
+// JSON6902 returns a transformer that applies the RFC 6902 operations to objects matching target.
+// Non-matching objects pass through unchanged. If the patch cannot be applied (e.g. a test
+// operation fails or a path does not exist), an error identifying the object is returned.
+func JSON6902(target Selector, ops []Operation) types.Transformer {
+	data, err := json.Marshal(ops)
+
+	var patch jsonpatch.Patch
+	if err == nil {
+		patch, err = jsonpatch.DecodePatch(data)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if !target.Matches(obj) {
+			return obj, nil
+		}
+
+		if err != nil {
+			return unstructured.Unstructured{}, transformer.Wrap(obj, fmt.Errorf("invalid JSON patch: %w", err))
+		}
+
+		return apply(obj, func(original []byte) ([]byte, error) {
+			return patch.Apply(original)
+		})
+	}
+}
+
+// StrategicMerge returns a transformer that applies a strategic merge patch to objects matching target.
+// The patch may be YAML or JSON. For kinds known to the client-go scheme, the patch follows the
+// strategic merge rules of the Go types (e.g. containers are merged by name); for other kinds,
+// such as custom resources, it is applied as an RFC 7386 JSON merge patch.
+// Non-matching objects pass through unchanged.
+func StrategicMerge(target Selector, patch []byte) types.Transformer {
+	data, err := yaml.YAMLToJSON(patch)
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if !target.Matches(obj) {
+			return obj, nil
+		}
+
+		if err != nil {
+			return unstructured.Unstructured{}, transformer.Wrap(obj, fmt.Errorf("invalid strategic merge patch: %w", err))
+		}
+
+		typed, schemeErr := scheme.Scheme.New(obj.GroupVersionKind())
+
+		return apply(obj, func(original []byte) ([]byte, error) {
+			if schemeErr != nil {
+				if !runtime.IsNotRegisteredError(schemeErr) {
+					return nil, schemeErr
+				}
+
+				return jsonpatch.MergePatch(original, data)
+			}
+
+			return strategicpatch.StrategicMergePatch(original, data, typed)
+		})
+	}
+}
+
+// apply runs fn on the JSON encoding of obj and decodes the result.
+func apply(
+	obj unstructured.Unstructured,
+	fn func(original []byte) ([]byte, error),
+) (unstructured.Unstructured, error) {
+	original, err := obj.MarshalJSON()
+	if err != nil {
+		return unstructured.Unstructured{}, transformer.Wrap(obj, fmt.Errorf("failed to encode object: %w", err))
+	}
+
+	patched, err := fn(original)
+	if err != nil {
+		return unstructured.Unstructured{}, transformer.Wrap(obj, fmt.Errorf("failed to apply patch: %w", err))
+	}
+
+	result := unstructured.Unstructured{}
+	if err := result.UnmarshalJSON(patched); err != nil {
+		return unstructured.Unstructured{}, transformer.Wrap(obj, fmt.Errorf("failed to decode patched object: %w", err))
+	}
+
+	return result, nil
+}
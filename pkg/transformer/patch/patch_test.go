@@ -0,0 +1,180 @@
+package patch_test
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/patch"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	kindDeployment = "Deployment"
+)
+
+func TestSelector(t *testing.T) {
+	g := NewWithT(t)
+
+	deployment := makeDeployment("web", "default")
+
+	tests := []struct {
+		name     string
+		selector patch.Selector
+		expected bool
+	}{
+		{name: "empty selector matches everything", selector: patch.Selector{}, expected: true},
+		{name: "matching kind", selector: patch.Selector{Kind: kindDeployment}, expected: true},
+		{name: "matching gvk and name", selector: patch.Selector{Group: "apps", Version: "v1", Kind: kindDeployment, Name: "web"}, expected: true},
+		{name: "other kind", selector: patch.Selector{Kind: "Service"}, expected: false},
+		{name: "other group", selector: patch.Selector{Group: "extensions", Kind: kindDeployment}, expected: false},
+		{name: "other name", selector: patch.Selector{Kind: kindDeployment, Name: "api"}, expected: false},
+		{name: "other namespace", selector: patch.Selector{Kind: kindDeployment, Namespace: "prod"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.Expect(tt.selector.Matches(deployment)).Should(Equal(tt.expected))
+		})
+	}
+}
+
+func TestJSON6902(t *testing.T) {
+
+	t.Run("should apply operations to matching objects", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := patch.JSON6902(patch.Selector{Kind: kindDeployment, Name: "web"}, []patch.Operation{
+			{Op: "replace", Path: "/spec/replicas", Value: 3},
+			{Op: "add", Path: "/metadata/labels", Value: map[string]any{"tier": "frontend"}},
+		})
+
+		obj, err := tr(t.Context(), makeDeployment("web", "default"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		g.Expect(replicas).Should(Equal(int64(3)))
+		g.Expect(obj.GetLabels()).Should(HaveKeyWithValue("tier", "frontend"))
+	})
+
+	t.Run("should pass non-matching objects through", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := patch.JSON6902(patch.Selector{Kind: kindDeployment, Name: "web"}, []patch.Operation{
+			{Op: "remove", Path: "/spec/does-not-exist"},
+		})
+
+		in := makeDeployment("api", "default")
+		obj, err := tr(t.Context(), in)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj).Should(Equal(in))
+	})
+
+	t.Run("should fail with object identity when a test operation fails", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := patch.JSON6902(patch.Selector{Kind: kindDeployment}, []patch.Operation{
+			{Op: "test", Path: "/spec/replicas", Value: 5},
+		})
+
+		_, err := tr(t.Context(), makeDeployment("web", "default"))
+		g.Expect(err).Should(HaveOccurred())
+
+		var trErr *transformer.Error
+		g.Expect(errors.As(err, &trErr)).Should(BeTrue())
+		g.Expect(trErr.Object.GetName()).Should(Equal("web"))
+	})
+
+	t.Run("should fail when a path is missing", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := patch.JSON6902(patch.Selector{Kind: kindDeployment}, []patch.Operation{
+			{Op: "replace", Path: "/spec/missing/field", Value: 1},
+		})
+
+		_, err := tr(t.Context(), makeDeployment("web", "default"))
+		g.Expect(err).Should(MatchError(ContainSubstring("web")))
+	})
+}
+
+func TestStrategicMerge(t *testing.T) {
+
+	t.Run("should merge containers by name for built-in kinds", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := patch.StrategicMerge(patch.Selector{Kind: kindDeployment}, []byte(`
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: nginx:1.26
+`))
+
+		obj, err := tr(t.Context(), makeDeployment("web", "default"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		g.Expect(containers).Should(HaveLen(2))
+		g.Expect(containers).Should(ContainElement(HaveKeyWithValue("image", "nginx:1.26")))
+		g.Expect(containers).Should(ContainElement(HaveKeyWithValue("name", "sidecar")))
+	})
+
+	t.Run("should fall back to JSON merge patch for unknown kinds", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := patch.StrategicMerge(patch.Selector{Kind: "Widget"}, []byte(`{"spec":{"size":"large","color":null}}`))
+
+		widget := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]any{"name": "w1"},
+			"spec":       map[string]any{"size": "small", "color": "red", "shape": "round"},
+		}}
+
+		obj, err := tr(t.Context(), widget)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.Object["spec"]).Should(Equal(map[string]any{"size": "large", "shape": "round"}))
+	})
+
+	t.Run("should pass non-matching objects through", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := patch.StrategicMerge(patch.Selector{Kind: "Service"}, []byte(`spec: {replicas: 10}`))
+
+		in := makeDeployment("web", "default")
+		obj, err := tr(t.Context(), in)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj).Should(Equal(in))
+	})
+
+	t.Run("should fail with object identity on an invalid patch", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := patch.StrategicMerge(patch.Selector{Kind: kindDeployment}, []byte(`spec: [`))
+
+		_, err := tr(t.Context(), makeDeployment("web", "default"))
+		g.Expect(err).Should(HaveOccurred())
+
+		var trErr *transformer.Error
+		g.Expect(errors.As(err, &trErr)).Should(BeTrue())
+		g.Expect(trErr.Object.GetName()).Should(Equal("web"))
+	})
+}
+
+func makeDeployment(name string, namespace string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       kindDeployment,
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": map[string]any{
+			"replicas": int64(1),
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{"name": "app", "image": "nginx:1.25"},
+						map[string]any{"name": "sidecar", "image": "envoy:1.0"},
+					},
+				},
+			},
+		},
+	}}
+}
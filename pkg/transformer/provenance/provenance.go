@@ -0,0 +1,49 @@
+// Package provenance provides a transformer recording which renderer produced an object.
+package provenance
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Stamp returns a transformer that annotates objects with the renderer that produced them:
+// types.AnnotationSourceType and the name annotation (types.AnnotationSourceName by default)
+// are both set to the renderer name.
+//
+// Annotations already present on the object, e.g. set by the renderer itself, are never overwritten.
+// Objects are returned unchanged when no renderer name is configured or found in the context.
+func Stamp(opts ...Option) types.Transformer {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		name := options.Name
+		if name == "" {
+			name, _ = types.RendererName(ctx)
+		}
+
+		if name == "" {
+			return obj, nil
+		}
+
+		values := obj.GetAnnotations()
+		if values == nil {
+			values = make(map[string]string)
+		}
+
+		for _, key := range []string{types.AnnotationSourceType, options.NameAnnotation} {
+			if _, ok := values[key]; !ok {
+				values[key] = name
+			}
+		}
+
+		obj.SetAnnotations(values)
+
+		return obj, nil
+	}
+}
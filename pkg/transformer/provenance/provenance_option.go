@@ -0,0 +1,52 @@
+package provenance
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the Stamp transformer.
+type Options struct {
+	// NameAnnotation is the annotation key receiving the renderer name.
+	// Defaults to types.AnnotationSourceName.
+	NameAnnotation string
+
+	// Name is the renderer name to stamp. If empty, the name is read from the context
+	// (see types.RendererName), which the engine populates for each renderer.
+	Name string
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.NameAnnotation != "" {
+		target.NameAnnotation = opts.NameAnnotation
+	}
+
+	if opts.Name != "" {
+		target.Name = opts.Name
+	}
+}
+
+// WithNameAnnotation sets the annotation key receiving the renderer name.
+func WithNameAnnotation(key string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.NameAnnotation = key
+	})
+}
+
+// WithName sets the renderer name to stamp instead of reading it from the context.
+func WithName(name string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Name = name
+	})
+}
+
+func defaultOptions() Options {
+	return Options{
+		NameAnnotation: types.AnnotationSourceName,
+	}
+}
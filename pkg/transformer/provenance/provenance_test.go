@@ -0,0 +1,69 @@
+package provenance_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/provenance"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestStamp(t *testing.T) {
+
+	t.Run("should stamp the renderer name from the context", func(t *testing.T) {
+		g := NewWithT(t)
+		ctx := types.WithRendererName(t.Context(), "helm")
+
+		obj, err := provenance.Stamp()(ctx, makePod("test"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceType, "helm"))
+		g.Expect(obj.GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceName, "helm"))
+	})
+
+	t.Run("should use a configured name and annotation key", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := provenance.Stamp(
+			provenance.WithName("kustomize"),
+			provenance.WithNameAnnotation("example.com/source"),
+		)
+
+		obj, err := tr(t.Context(), makePod("test"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceType, "kustomize"))
+		g.Expect(obj.GetAnnotations()).Should(HaveKeyWithValue("example.com/source", "kustomize"))
+		g.Expect(obj.GetAnnotations()).ShouldNot(HaveKey(types.AnnotationSourceName))
+	})
+
+	t.Run("should not overwrite existing annotations", func(t *testing.T) {
+		g := NewWithT(t)
+		ctx := types.WithRendererName(t.Context(), "helm")
+
+		pod := makePod("test")
+		pod.SetAnnotations(map[string]string{types.AnnotationSourceType: "chart"})
+
+		obj, err := provenance.Stamp()(ctx, pod)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceType, "chart"))
+		g.Expect(obj.GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceName, "helm"))
+	})
+
+	t.Run("should leave objects unchanged without a renderer name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := provenance.Stamp()(t.Context(), makePod("test"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetAnnotations()).Should(BeEmpty())
+	})
+}
+
+func makePod(name string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	obj.SetName(name)
+
+	return obj
+}
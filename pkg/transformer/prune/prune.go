@@ -0,0 +1,137 @@
+// Package prune provides a transformer removing empty and null fields.
+package prune
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// node is a map or slice of the object being pruned.
+type node struct {
+	value any
+
+	// path is the dot-separated path of the node, only tracked down to the depth of the
+	// deepest preserved path, as deeper fields can never be preserved.
+	path  string
+	depth int
+}
+
+// Empty returns a transformer that recursively removes null map values, empty maps and empty
+// slices, e.g. "spec: {}" or "labels: null". Maps left empty once their own fields are pruned are
+// removed as well. Slice elements are pruned but never removed, so positions stay meaningful.
+// Fields listed with WithPreserve are kept even when empty.
+//
+// The traversal is iterative, so arbitrarily deep objects cannot overflow the stack.
+func Empty(opts ...Option) types.Transformer {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	preserve := make(map[string]struct{}, len(options.Preserve))
+	maxDepth := 0
+
+	for _, path := range options.Preserve {
+		preserve[path] = struct{}{}
+		maxDepth = max(maxDepth, strings.Count(path, ".")+1)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		// Collect containers parent first, then prune them children first, so that a map
+		// emptied by pruning is itself pruned from its parent.
+		nodes := collect(obj.Object, maxDepth)
+
+		for _, n := range slices.Backward(nodes) {
+			m, ok := n.value.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			for key, value := range m {
+				if n.depth < maxDepth {
+					if _, ok := preserve[join(n.path, key)]; ok {
+						continue
+					}
+				}
+
+				if isEmpty(value) {
+					delete(m, key)
+				}
+			}
+		}
+
+		return obj, nil
+	}
+}
+
+// collect returns all maps and slices reachable from root, each one after its parent.
+// Paths are tracked down to maxDepth.
+func collect(root map[string]any, maxDepth int) []node {
+	var nodes []node
+
+	stack := []node{{value: root}}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		nodes = append(nodes, n)
+
+		switch v := n.value.(type) {
+		case map[string]any:
+			for key, child := range v {
+				if !isContainer(child) {
+					continue
+				}
+
+				path := ""
+				if n.depth < maxDepth {
+					path = join(n.path, key)
+				}
+
+				stack = append(stack, node{value: child, path: path, depth: n.depth + 1})
+			}
+		case []any:
+			for _, child := range v {
+				if isContainer(child) {
+					stack = append(stack, node{value: child, path: n.path, depth: n.depth})
+				}
+			}
+		}
+	}
+
+	return nodes
+}
+
+func isContainer(value any) bool {
+	switch value.(type) {
+	case map[string]any, []any:
+		return true
+	default:
+		return false
+	}
+}
+
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+func join(path string, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
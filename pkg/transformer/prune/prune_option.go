@@ -0,0 +1,27 @@
+package prune
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the Empty transformer.
+type Options struct {
+	// Preserve are the dot-separated paths of the fields never pruned, e.g. "spec.selector.matchLabels".
+	// List elements do not add a path segment, so "spec.containers.args" designates the args of every container.
+	Preserve []string
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.Preserve = append(target.Preserve, opts.Preserve...)
+}
+
+// WithPreserve adds dot-separated paths of fields that must never be pruned, even when empty.
+func WithPreserve(paths ...string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Preserve = append(o.Preserve, paths...)
+	})
+}
@@ -0,0 +1,116 @@
+package prune_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/prune"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestEmpty(t *testing.T) {
+
+	t.Run("should remove null values, empty maps and empty slices", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]any{
+				"name":        "web",
+				"labels":      nil,
+				"annotations": map[string]any{},
+			},
+			"spec": map[string]any{
+				"ports":    []any{},
+				"selector": map[string]any{"app": "web"},
+				"type":     "",
+			},
+			"status": map[string]any{
+				"loadBalancer": map[string]any{},
+			},
+		}}
+
+		result, err := prune.Empty()(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]any{
+				"name": "web",
+			},
+			"spec": map[string]any{
+				"selector": map[string]any{"app": "web"},
+				"type":     "",
+			},
+		}))
+	})
+
+	t.Run("should prune list elements without removing them", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{
+				"containers": []any{
+					map[string]any{"name": "web", "args": []any{}, "env": nil},
+					map[string]any{"resources": map[string]any{}},
+				},
+			},
+		}}
+
+		result, err := prune.Empty()(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(map[string]any{
+			"spec": map[string]any{
+				"containers": []any{
+					map[string]any{"name": "web"},
+					map[string]any{},
+				},
+			},
+		}))
+	})
+
+	t.Run("should keep preserved paths", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{
+				"selector": map[string]any{
+					"matchLabels": map[string]any{},
+				},
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{"name": "web", "args": []any{}},
+						},
+					},
+				},
+			},
+		}}
+
+		result, err := prune.Empty(
+			prune.WithPreserve("spec.selector.matchLabels"),
+			prune.WithPreserve("spec.template.spec.containers.args"),
+		)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(obj.Object))
+	})
+
+	t.Run("should handle deeply nested objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		root := map[string]any{}
+		current := root
+		for range 100000 {
+			next := map[string]any{"empty": nil}
+			current["nested"] = next
+			current = next
+		}
+
+		result, err := prune.Empty()(t.Context(), unstructured.Unstructured{Object: root})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(BeEmpty())
+	})
+}
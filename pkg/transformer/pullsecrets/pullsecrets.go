@@ -0,0 +1,79 @@
+// Package pullsecrets provides a transformer adding imagePullSecrets to workloads and ServiceAccounts,
+// so that every pod of a bundle can pull from private registries.
+package pullsecrets
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/podspec"
+)
+
+const field = "imagePullSecrets"
+
+// serviceAccount is the kind holding imagePullSecrets at its root rather than in a pod spec.
+//
+//nolint:gochecknoglobals
+var serviceAccount = schema.GroupKind{Group: "", Kind: "ServiceAccount"}
+
+// Add returns a transformer appending a reference to each of the secrets names to the imagePullSecrets
+// of Pods, Deployments, StatefulSets, DaemonSets, ReplicaSets, ReplicationControllers, Jobs, CronJobs
+// and ServiceAccounts. Names already referenced are skipped, so applying it again leaves objects
+// unchanged; empty names are ignored. Objects of other kinds pass through untouched.
+func Add(names ...string) types.Transformer {
+	names = slices.DeleteFunc(slices.Clone(names), func(name string) bool { return name == "" })
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		path, ok := holderPath(obj.GroupVersionKind().GroupKind())
+		if !ok || len(names) == 0 {
+			return obj, nil
+		}
+
+		secrets, _, err := unstructured.NestedSlice(obj.Object, append(slices.Clone(path), field)...)
+		if err != nil {
+			return obj, fmt.Errorf("failed to read %s: %w", field, err)
+		}
+
+		count := len(secrets)
+
+		for _, name := range names {
+			if !contains(secrets, name) {
+				secrets = append(secrets, map[string]any{"name": name})
+			}
+		}
+
+		if len(secrets) == count {
+			return obj, nil
+		}
+
+		if err := unstructured.SetNestedSlice(obj.Object, secrets, append(slices.Clone(path), field)...); err != nil {
+			return obj, fmt.Errorf("failed to set %s: %w", field, err)
+		}
+
+		return obj, nil
+	}
+}
+
+// holderPath returns the path of the object holding the imagePullSecrets of the objects of kind gk:
+// the pod spec of workloads, the root of ServiceAccounts.
+func holderPath(gk schema.GroupKind) ([]string, bool) {
+	if gk == serviceAccount {
+		return []string{}, true
+	}
+
+	return podspec.Path(gk)
+}
+
+// contains reports whether secrets holds a reference named name.
+func contains(secrets []any, name string) bool {
+	return slices.ContainsFunc(secrets, func(secret any) bool {
+		ref, ok := secret.(map[string]any)
+
+		return ok && ref["name"] == name
+	})
+}
@@ -0,0 +1,154 @@
+package pullsecrets_test
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/pullsecrets"
+
+	. "github.com/onsi/gomega"
+)
+
+func toUnstructured(t *testing.T, obj runtime.Object) unstructured.Unstructured {
+	t.Helper()
+
+	unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return unstructured.Unstructured{Object: unstr}
+}
+
+func fromUnstructured[T any](t *testing.T, obj unstructured.Unstructured) *T {
+	t.Helper()
+
+	var result T
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &result)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return &result
+}
+
+func refs(names ...string) []corev1.LocalObjectReference {
+	result := make([]corev1.LocalObjectReference, 0, len(names))
+	for _, name := range names {
+		result = append(result, corev1.LocalObjectReference{Name: name})
+	}
+
+	return result
+}
+
+func TestAdd(t *testing.T) {
+
+	t.Run("should add pull secrets to the pod template of deployments", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		})
+
+		result, err := pullsecrets.Add("registry", "mirror")(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		d := fromUnstructured[appsv1.Deployment](t, result)
+		g.Expect(d.Spec.Template.Spec.ImagePullSecrets).Should(Equal(refs("registry", "mirror")))
+	})
+
+	t.Run("should keep existing pull secrets and skip duplicates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Spec:       corev1.PodSpec{ImagePullSecrets: refs("existing", "registry")},
+		})
+
+		result, err := pullsecrets.Add("registry", "mirror", "mirror", "")(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		p := fromUnstructured[corev1.Pod](t, result)
+		g.Expect(p.Spec.ImagePullSecrets).Should(Equal(refs("existing", "registry", "mirror")))
+	})
+
+	t.Run("should be idempotent", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transformer := pullsecrets.Add("registry")
+		obj := toUnstructured(t, &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		})
+
+		once, err := transformer(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		twice, err := transformer(t.Context(), *once.DeepCopy())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(twice.Object).Should(Equal(once.Object))
+	})
+
+	t.Run("should support the nested pod template of cron jobs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &batchv1.CronJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly"},
+		})
+
+		result, err := pullsecrets.Add("registry")(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		c := fromUnstructured[batchv1.CronJob](t, result)
+		g.Expect(c.Spec.JobTemplate.Spec.Template.Spec.ImagePullSecrets).Should(Equal(refs("registry")))
+	})
+
+	t.Run("should add pull secrets to service accounts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &corev1.ServiceAccount{
+			TypeMeta:         metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta:       metav1.ObjectMeta{Name: "app"},
+			ImagePullSecrets: refs("existing"),
+		})
+
+		result, err := pullsecrets.Add("registry")(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		sa := fromUnstructured[corev1.ServiceAccount](t, result)
+		g.Expect(sa.ImagePullSecrets).Should(Equal(refs("existing", "registry")))
+	})
+
+	t.Run("should leave objects without a pod spec untouched", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: "config"},
+		})
+		original := obj.DeepCopy()
+
+		result, err := pullsecrets.Add("registry")(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(original.Object))
+	})
+
+	t.Run("should fail on a malformed imagePullSecrets field", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion":       "v1",
+			"kind":             "ServiceAccount",
+			"metadata":         map[string]any{"name": "app"},
+			"imagePullSecrets": "registry",
+		}}
+
+		_, err := pullsecrets.Add("registry")(t.Context(), obj)
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
@@ -0,0 +1,124 @@
+// Package recommended provides a transformer setting the Kubernetes recommended labels
+// (app.kubernetes.io/*), see https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/.
+package recommended
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/podspec"
+)
+
+// Keys of the recommended labels.
+const (
+	LabelName      = "app.kubernetes.io/name"
+	LabelInstance  = "app.kubernetes.io/instance"
+	LabelVersion   = "app.kubernetes.io/version"
+	LabelComponent = "app.kubernetes.io/component"
+	LabelPartOf    = "app.kubernetes.io/part-of"
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+)
+
+// RecommendedLabels are the values of the recommended labels of an application.
+// Empty values are not set.
+//
+//nolint:revive // The name mirrors the Kubernetes documentation.
+type RecommendedLabels struct {
+	// Name is the name of the application, e.g. "mysql" (app.kubernetes.io/name).
+	Name string
+
+	// Instance identifies the instance of the application, e.g. "mysql-abcxyz" (app.kubernetes.io/instance).
+	Instance string
+
+	// Version is the version of the application, e.g. "5.7.21" (app.kubernetes.io/version).
+	Version string
+
+	// Component is the component within the architecture, e.g. "database" (app.kubernetes.io/component).
+	Component string
+
+	// PartOf is the name of the higher level application this one is part of, e.g. "wordpress"
+	// (app.kubernetes.io/part-of).
+	PartOf string
+
+	// ManagedBy is the tool managing the application, e.g. "helm" (app.kubernetes.io/managed-by).
+	ManagedBy string
+
+	// Overwrite replaces the values of labels already set on objects, which are kept otherwise.
+	Overwrite bool
+}
+
+// labels returns the non-empty recommended labels.
+func (l RecommendedLabels) labels() map[string]string {
+	labels := make(map[string]string)
+
+	for key, value := range map[string]string{
+		LabelName:      l.Name,
+		LabelInstance:  l.Instance,
+		LabelVersion:   l.Version,
+		LabelComponent: l.Component,
+		LabelPartOf:    l.PartOf,
+		LabelManagedBy: l.ManagedBy,
+	} {
+		if value != "" {
+			labels[key] = value
+		}
+	}
+
+	return labels
+}
+
+// Set returns a transformer setting the recommended labels of app on metadata.labels and, for
+// Deployments, StatefulSets, DaemonSets, ReplicaSets, ReplicationControllers, Jobs and CronJobs, on
+// the labels of the pod template, so that pods carry them too. Selectors are never modified.
+// Labels already present keep their value unless app.Overwrite is set.
+func Set(app RecommendedLabels) types.Transformer {
+	labels := app.labels()
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if len(labels) == 0 {
+			return obj, nil
+		}
+
+		paths := [][]string{{"metadata", "labels"}}
+		if path, ok := podspec.TemplatePath(obj.GroupVersionKind().GroupKind()); ok {
+			paths = append(paths, append(path, "metadata", "labels"))
+		}
+
+		for _, path := range paths {
+			if err := merge(obj, path, labels, app.Overwrite); err != nil {
+				return obj, err
+			}
+		}
+
+		return obj, nil
+	}
+}
+
+// merge sets labels on the string map at path of obj, keeping its existing values unless overwrite is set.
+func merge(obj unstructured.Unstructured, path []string, labels map[string]string, overwrite bool) error {
+	current, _, err := unstructured.NestedStringMap(obj.Object, path...)
+	if err != nil {
+		return fmt.Errorf("failed to read labels: %w", err)
+	}
+
+	if current == nil {
+		current = make(map[string]string, len(labels))
+	}
+
+	for key, value := range labels {
+		if _, ok := current[key]; ok && !overwrite {
+			continue
+		}
+
+		current[key] = value
+	}
+
+	if err := unstructured.SetNestedStringMap(obj.Object, current, path...); err != nil {
+		return fmt.Errorf("failed to set labels: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package recommended_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/recommended"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeDeployment(labels map[string]any) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "web", "namespace": "default", "labels": labels},
+		"spec": map[string]any{
+			"selector": map[string]any{"matchLabels": map[string]any{"app": "web"}},
+			"template": map[string]any{
+				"metadata": map[string]any{"labels": map[string]any{"app": "web"}},
+			},
+		},
+	}}
+}
+
+func TestSet(t *testing.T) {
+
+	app := recommended.RecommendedLabels{
+		Name:      "web",
+		Instance:  "web-prod",
+		Version:   "1.2.3",
+		Component: "frontend",
+		PartOf:    "shop",
+		ManagedBy: "manifest-engine",
+	}
+
+	expected := map[string]string{
+		recommended.LabelName:      "web",
+		recommended.LabelInstance:  "web-prod",
+		recommended.LabelVersion:   "1.2.3",
+		recommended.LabelComponent: "frontend",
+		recommended.LabelPartOf:    "shop",
+		recommended.LabelManagedBy: "manifest-engine",
+	}
+
+	t.Run("should set the labels on objects and pod templates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := recommended.Set(app)(t.Context(), makeDeployment(nil))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetLabels()).Should(Equal(expected))
+
+		templateLabels, _, err := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(templateLabels).Should(HaveKeyWithValue("app", "web"))
+		g.Expect(templateLabels).Should(HaveKeyWithValue(recommended.LabelName, "web"))
+		g.Expect(templateLabels).Should(HaveLen(7))
+
+		selector, _, err := unstructured.NestedStringMap(obj.Object, "spec", "selector", "matchLabels")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(selector).Should(Equal(map[string]string{"app": "web"}))
+	})
+
+	t.Run("should keep labels already present", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := recommended.Set(app)(t.Context(), makeDeployment(map[string]any{recommended.LabelVersion: "1.0.0"}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetLabels()).Should(HaveKeyWithValue(recommended.LabelVersion, "1.0.0"))
+		g.Expect(obj.GetLabels()).Should(HaveKeyWithValue(recommended.LabelName, "web"))
+	})
+
+	t.Run("should overwrite labels when set to", func(t *testing.T) {
+		g := NewWithT(t)
+
+		overwrite := app
+		overwrite.Overwrite = true
+
+		obj, err := recommended.Set(overwrite)(t.Context(), makeDeployment(map[string]any{recommended.LabelVersion: "1.0.0"}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetLabels()).Should(Equal(expected))
+	})
+
+	t.Run("should skip empty values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cm := unstructured.Unstructured{Object: map[string]any{}}
+		cm.SetAPIVersion("v1")
+		cm.SetKind("ConfigMap")
+		cm.SetName("config")
+
+		obj, err := recommended.Set(recommended.RecommendedLabels{Name: "web"})(t.Context(), cm)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetLabels()).Should(Equal(map[string]string{recommended.LabelName: "web"}))
+
+		_, found, err := unstructured.NestedFieldNoCopy(obj.Object, "spec")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(found).Should(BeFalse())
+	})
+
+	t.Run("should leave objects unchanged without labels", func(t *testing.T) {
+		g := NewWithT(t)
+
+		in := makeDeployment(nil)
+
+		obj, err := recommended.Set(recommended.RecommendedLabels{Overwrite: true})(t.Context(), in)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj).Should(Equal(makeDeployment(nil)))
+	})
+
+	t.Run("should fail on malformed labels", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := recommended.Set(app)(t.Context(), makeDeployment(map[string]any{"app": 1}))
+		g.Expect(err).Should(MatchError(ContainSubstring("failed to read labels")))
+	})
+}
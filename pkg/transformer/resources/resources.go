@@ -0,0 +1,121 @@
+// Package resources provides a transformer enforcing default container resource requests and limits.
+package resources
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/podspec"
+)
+
+// EnsureDefaults returns a transformer that fills in the resource requests and limits missing from
+// the containers and init containers of Pods, Deployments, StatefulSets, DaemonSets, ReplicaSets,
+// ReplicationControllers, Jobs and CronJobs. Explicitly set values are never overwritten.
+//
+// To keep containers valid, a default request is capped at the container's limit for the same
+// resource, and a default limit is raised to the container's request.
+// Objects of other kinds, or without containers, pass through untouched.
+func EnsureDefaults(reqs corev1.ResourceList, limits corev1.ResourceList, opts ...Option) types.Transformer {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	fields := []string{"containers", "initContainers"}
+	if options.SkipInitContainers {
+		fields = fields[:1]
+	}
+
+	return podspec.Transformer(func(spec map[string]any) (bool, error) {
+		return podspec.UpdateContainers(spec, fields, func(container map[string]any) error {
+			name, _, _ := unstructured.NestedString(container, "name")
+			if !options.selects(name) {
+				return nil
+			}
+
+			if err := ensureDefaults(container, reqs, limits); err != nil {
+				return fmt.Errorf("container %q: %w", name, err)
+			}
+
+			return nil
+		})
+	})
+}
+
+// ensureDefaults fills in the missing requests and limits of a container.
+func ensureDefaults(container map[string]any, reqs corev1.ResourceList, limits corev1.ResourceList) error {
+	current, err := currentResources(container)
+	if err != nil {
+		return err
+	}
+
+	for name, quantity := range reqs {
+		if _, ok := current.Requests[name]; ok {
+			continue
+		}
+
+		if limit, ok := current.Limits[name]; ok && quantity.Cmp(limit) > 0 {
+			quantity = limit
+		}
+
+		if err := setQuantity(container, "requests", name, quantity); err != nil {
+			return err
+		}
+
+		current.Requests[name] = quantity
+	}
+
+	for name, quantity := range limits {
+		if _, ok := current.Limits[name]; ok {
+			continue
+		}
+
+		if request, ok := current.Requests[name]; ok && quantity.Cmp(request) < 0 {
+			quantity = request
+		}
+
+		if err := setQuantity(container, "limits", name, quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// currentResources parses the requests and limits already set on a container.
+func currentResources(container map[string]any) (corev1.ResourceRequirements, error) {
+	result := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
+	}
+
+	for field, list := range map[string]corev1.ResourceList{"requests": result.Requests, "limits": result.Limits} {
+		values, _, err := unstructured.NestedMap(container, "resources", field)
+		if err != nil {
+			return result, fmt.Errorf("failed to read resources.%s: %w", field, err)
+		}
+
+		for name, value := range values {
+			quantity, err := resource.ParseQuantity(fmt.Sprint(value))
+			if err != nil {
+				return result, fmt.Errorf("invalid resources.%s.%s %v: %w", field, name, value, err)
+			}
+
+			list[corev1.ResourceName(name)] = quantity
+		}
+	}
+
+	return result, nil
+}
+
+func setQuantity(container map[string]any, field string, name corev1.ResourceName, quantity resource.Quantity) error {
+	if err := unstructured.SetNestedField(container, quantity.String(), "resources", field, string(name)); err != nil {
+		return fmt.Errorf("failed to set resources.%s.%s: %w", field, name, err)
+	}
+
+	return nil
+}
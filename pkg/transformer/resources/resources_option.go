@@ -0,0 +1,44 @@
+package resources
+
+import (
+	"slices"
+
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the EnsureDefaults transformer.
+type Options struct {
+	// Containers restricts defaulting to the containers with these names.
+	// If empty, all containers are defaulted.
+	Containers []string
+
+	// SkipInitContainers leaves init containers untouched.
+	SkipInitContainers bool
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.Containers = append(target.Containers, opts.Containers...)
+	target.SkipInitContainers = opts.SkipInitContainers
+}
+
+// WithContainers restricts defaulting to the containers with the given names.
+func WithContainers(names ...string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Containers = append(o.Containers, names...)
+	})
+}
+
+// WithSkipInitContainers enables or disables leaving init containers untouched.
+func WithSkipInitContainers(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.SkipInitContainers = enabled
+	})
+}
+
+func (opts Options) selects(name string) bool {
+	return len(opts.Containers) == 0 || slices.Contains(opts.Containers, name)
+}
@@ -0,0 +1,212 @@
+package resources_test
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/resources"
+
+	. "github.com/onsi/gomega"
+)
+
+func toUnstructured(t *testing.T, obj runtime.Object) unstructured.Unstructured {
+	t.Helper()
+
+	unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return unstructured.Unstructured{Object: unstr}
+}
+
+func fromUnstructured[T any](t *testing.T, obj unstructured.Unstructured) *T {
+	t.Helper()
+
+	var result T
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &result)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return &result
+}
+
+func podSpec() corev1.PodSpec {
+	return corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "init"},
+		},
+		Containers: []corev1.Container{
+			{Name: "app"},
+			{
+				Name: "sidecar",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+				},
+			},
+		},
+	}
+}
+
+func deployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{Spec: podSpec()},
+		},
+	}
+}
+
+func defaults() (corev1.ResourceList, corev1.ResourceList) {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("100m"),
+		corev1.ResourceMemory: resource.MustParse("128Mi"),
+	}, corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+}
+
+func TestEnsureDefaults(t *testing.T) {
+
+	t.Run("should fill in missing requests and limits", func(t *testing.T) {
+		g := NewWithT(t)
+
+		reqs, limits := defaults()
+		transform := resources.EnsureDefaults(reqs, limits)
+
+		result, err := transform(t.Context(), toUnstructured(t, deployment()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		spec := fromUnstructured[appsv1.Deployment](t, result).Spec.Template.Spec
+
+		app := spec.Containers[0].Resources
+		g.Expect(app.Requests.Cpu().String()).Should(Equal("100m"))
+		g.Expect(app.Requests.Memory().String()).Should(Equal("128Mi"))
+		g.Expect(app.Limits.Memory().String()).Should(Equal("256Mi"))
+
+		init := spec.InitContainers[0].Resources
+		g.Expect(init.Requests.Cpu().String()).Should(Equal("100m"))
+		g.Expect(init.Limits.Memory().String()).Should(Equal("256Mi"))
+	})
+
+	t.Run("should not overwrite explicit values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		reqs, limits := defaults()
+		transform := resources.EnsureDefaults(reqs, limits)
+
+		result, err := transform(t.Context(), toUnstructured(t, deployment()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		sidecar := fromUnstructured[appsv1.Deployment](t, result).Spec.Template.Spec.Containers[1].Resources
+		g.Expect(sidecar.Requests.Cpu().String()).Should(Equal("50m"))
+		g.Expect(sidecar.Limits.Memory().String()).Should(Equal("64Mi"))
+
+		// the default memory request is capped at the explicit limit
+		g.Expect(sidecar.Requests.Memory().String()).Should(Equal("64Mi"))
+	})
+
+	t.Run("should raise a default limit to an explicit request", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pod := &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: "pod"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+					},
+				}},
+			},
+		}
+
+		reqs, limits := defaults()
+		result, err := resources.EnsureDefaults(reqs, limits)(t.Context(), toUnstructured(t, pod))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		app := fromUnstructured[corev1.Pod](t, result).Spec.Containers[0].Resources
+		g.Expect(app.Limits.Memory().String()).Should(Equal("1Gi"))
+	})
+
+	t.Run("should handle CronJobs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cronJob := &batchv1.CronJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+			ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+			Spec: batchv1.CronJobSpec{
+				JobTemplate: batchv1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{Spec: podSpec()},
+					},
+				},
+			},
+		}
+
+		reqs, limits := defaults()
+		result, err := resources.EnsureDefaults(reqs, limits)(t.Context(), toUnstructured(t, cronJob))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		spec := fromUnstructured[batchv1.CronJob](t, result).Spec.JobTemplate.Spec.Template.Spec
+		g.Expect(spec.Containers[0].Resources.Requests.Cpu().String()).Should(Equal("100m"))
+	})
+
+	t.Run("should restrict defaulting to the selected containers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		reqs, limits := defaults()
+		transform := resources.EnsureDefaults(
+			reqs,
+			limits,
+			resources.WithContainers("app"),
+			resources.WithSkipInitContainers(true),
+		)
+
+		result, err := transform(t.Context(), toUnstructured(t, deployment()))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		spec := fromUnstructured[appsv1.Deployment](t, result).Spec.Template.Spec
+		g.Expect(spec.Containers[0].Resources.Requests).ShouldNot(BeEmpty())
+		g.Expect(spec.Containers[1].Resources.Requests).ShouldNot(HaveKey(corev1.ResourceMemory))
+		g.Expect(spec.InitContainers[0].Resources.Requests).Should(BeEmpty())
+	})
+
+	t.Run("should pass through other objects untouched", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cm := toUnstructured(t, &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: "config"},
+		})
+		expected := cm.DeepCopy()
+
+		reqs, limits := defaults()
+		result, err := resources.EnsureDefaults(reqs, limits)(t.Context(), cm)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(expected.Object))
+	})
+
+	t.Run("should reject invalid quantities", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, deployment())
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		containers[0].(map[string]any)["resources"] = map[string]any{
+			"requests": map[string]any{"cpu": "lots"},
+		}
+		g.Expect(unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")).
+			Should(Succeed())
+
+		reqs, limits := defaults()
+		_, err := resources.EnsureDefaults(reqs, limits)(t.Context(), obj)
+		g.Expect(err).Should(MatchError(ContainSubstring(`container "app"`)))
+	})
+}
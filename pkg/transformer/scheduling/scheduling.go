@@ -0,0 +1,235 @@
+// Package scheduling provides transformers merging scheduling constraints (node selector, tolerations
+// and affinity) into the pod template of workloads.
+package scheduling
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/podspec"
+)
+
+// NodeSelector returns a transformer merging selector into the node selector of the pods of
+// workloads. Keys already set on a workload are kept with their value.
+//
+// Like all transformers of this package, it applies to Pods, Deployments, StatefulSets, DaemonSets,
+// ReplicaSets, ReplicationControllers, Jobs and CronJobs; objects of other kinds pass through untouched.
+func NodeSelector(selector map[string]string) types.Transformer {
+	return podspec.Transformer(func(spec map[string]any) (bool, error) {
+		current, _, err := unstructured.NestedStringMap(spec, "nodeSelector")
+		if err != nil {
+			return false, fmt.Errorf("failed to read nodeSelector: %w", err)
+		}
+
+		if current == nil {
+			current = make(map[string]string, len(selector))
+		}
+
+		count := len(current)
+
+		for key, value := range selector {
+			if _, ok := current[key]; !ok {
+				current[key] = value
+			}
+		}
+
+		if len(current) == count {
+			return false, nil
+		}
+
+		return true, unstructured.SetNestedStringMap(spec, current, "nodeSelector")
+	})
+}
+
+// Tolerations returns a transformer appending tolerations to the pods of workloads. A toleration is
+// skipped when the pod already has one with the same key, operator, value and effect, so existing
+// tolerations, including their tolerationSeconds, are never modified.
+func Tolerations(tolerations []corev1.Toleration) types.Transformer {
+	return podspec.Transformer(func(spec map[string]any) (bool, error) {
+		c, err := readConstraints(spec)
+		if err != nil {
+			return false, err
+		}
+
+		count := len(c.Tolerations)
+
+		for _, toleration := range tolerations {
+			matches := slices.ContainsFunc(c.Tolerations, func(existing corev1.Toleration) bool {
+				return existing.MatchToleration(&toleration)
+			})
+
+			if !matches {
+				c.Tolerations = append(c.Tolerations, toleration)
+			}
+		}
+
+		if len(c.Tolerations) == count {
+			return false, nil
+		}
+
+		return true, writeConstraints(spec, c, "tolerations")
+	})
+}
+
+// Affinity returns a transformer merging affinity into the pods of workloads, so that both the
+// existing constraints and those of affinity apply:
+//   - required node selector terms are combined with the existing ones: since terms are ORed, each
+//     existing term is ANDed with each term of affinity
+//   - required pod (anti-)affinity terms and preferred terms are appended, skipping duplicates
+//
+// A nil affinity, or one adding no constraint, leaves objects unchanged.
+func Affinity(affinity *corev1.Affinity) types.Transformer {
+	return podspec.Transformer(func(spec map[string]any) (bool, error) {
+		if affinity == nil {
+			return false, nil
+		}
+
+		c, err := readConstraints(spec)
+		if err != nil {
+			return false, err
+		}
+
+		if c.Affinity == nil {
+			c.Affinity = &corev1.Affinity{}
+		}
+
+		current := c.Affinity.DeepCopy()
+		c.Affinity = mergeAffinity(c.Affinity, affinity)
+
+		if equality.Semantic.DeepEqual(current, c.Affinity) {
+			return false, nil
+		}
+
+		return true, writeConstraints(spec, c, "affinity")
+	})
+}
+
+// constraints holds the typed scheduling fields of a pod spec.
+type constraints struct {
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	Affinity    *corev1.Affinity    `json:"affinity,omitempty"`
+}
+
+// readConstraints decodes the tolerations and affinity of spec.
+func readConstraints(spec map[string]any) (constraints, error) {
+	content := make(map[string]any, 2)
+	for _, field := range []string{"tolerations", "affinity"} {
+		if value, ok := spec[field]; ok && value != nil {
+			content[field] = value
+		}
+	}
+
+	var result constraints
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &result); err != nil {
+		return result, fmt.Errorf("failed to read scheduling constraints: %w", err)
+	}
+
+	return result, nil
+}
+
+// writeConstraints encodes field of c into spec.
+func writeConstraints(spec map[string]any, c constraints, field string) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&c)
+	if err != nil {
+		return fmt.Errorf("failed to set %s: %w", field, err)
+	}
+
+	spec[field] = content[field]
+
+	return nil
+}
+
+// mergeAffinity returns current with the constraints of policy added.
+func mergeAffinity(current *corev1.Affinity, policy *corev1.Affinity) *corev1.Affinity {
+	if policy.NodeAffinity != nil {
+		if current.NodeAffinity == nil {
+			current.NodeAffinity = &corev1.NodeAffinity{}
+		}
+
+		current.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = mergeNodeSelector(
+			current.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			policy.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		)
+		current.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = appendMissing(
+			current.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			policy.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		)
+	}
+
+	if policy.PodAffinity != nil {
+		if current.PodAffinity == nil {
+			current.PodAffinity = &corev1.PodAffinity{}
+		}
+
+		current.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = appendMissing(
+			current.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			policy.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		)
+		current.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution = appendMissing(
+			current.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			policy.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		)
+	}
+
+	if policy.PodAntiAffinity != nil {
+		if current.PodAntiAffinity == nil {
+			current.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+
+		current.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = appendMissing(
+			current.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			policy.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		)
+		current.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = appendMissing(
+			current.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			policy.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		)
+	}
+
+	return current
+}
+
+// mergeNodeSelector returns a node selector matching the nodes matched by both current and policy.
+func mergeNodeSelector(current *corev1.NodeSelector, policy *corev1.NodeSelector) *corev1.NodeSelector {
+	if policy == nil || len(policy.NodeSelectorTerms) == 0 {
+		return current
+	}
+
+	if current == nil || len(current.NodeSelectorTerms) == 0 {
+		return policy.DeepCopy()
+	}
+
+	terms := make([]corev1.NodeSelectorTerm, 0, len(current.NodeSelectorTerms)*len(policy.NodeSelectorTerms))
+
+	for _, c := range current.NodeSelectorTerms {
+		for _, p := range policy.NodeSelectorTerms {
+			terms = append(terms, corev1.NodeSelectorTerm{
+				MatchExpressions: appendMissing(slices.Clone(c.MatchExpressions), p.MatchExpressions),
+				MatchFields:      appendMissing(slices.Clone(c.MatchFields), p.MatchFields),
+			})
+		}
+	}
+
+	return &corev1.NodeSelector{NodeSelectorTerms: terms}
+}
+
+// appendMissing appends to current the items of additions it does not already contain.
+func appendMissing[T any](current []T, additions []T) []T {
+	for _, item := range additions {
+		contains := slices.ContainsFunc(current, func(existing T) bool {
+			return equality.Semantic.DeepEqual(existing, item)
+		})
+
+		if !contains {
+			current = append(current, item)
+		}
+	}
+
+	return current
+}
@@ -0,0 +1,271 @@
+package scheduling_test
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/scheduling"
+
+	. "github.com/onsi/gomega"
+)
+
+func toUnstructured(t *testing.T, obj runtime.Object) unstructured.Unstructured {
+	t.Helper()
+
+	unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return unstructured.Unstructured{Object: unstr}
+}
+
+func fromUnstructured[T any](t *testing.T, obj unstructured.Unstructured) *T {
+	t.Helper()
+
+	var result T
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &result)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return &result
+}
+
+func deployment(spec corev1.PodSpec) unstructured.Unstructured {
+	obj, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(&appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{Spec: spec},
+		},
+	})
+
+	return unstructured.Unstructured{Object: obj}
+}
+
+func TestNodeSelector(t *testing.T) {
+
+	t.Run("should merge keys without overriding existing ones", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := deployment(corev1.PodSpec{NodeSelector: map[string]string{"zone": "a"}})
+
+		result, err := scheduling.NodeSelector(map[string]string{"zone": "b", "pool": "apps"})(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		d := fromUnstructured[appsv1.Deployment](t, result)
+		g.Expect(d.Spec.Template.Spec.NodeSelector).Should(Equal(map[string]string{"zone": "a", "pool": "apps"}))
+	})
+
+	t.Run("should handle the CronJob job template", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &batchv1.CronJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+			ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+			Spec:       batchv1.CronJobSpec{Schedule: "@daily"},
+		})
+
+		result, err := scheduling.NodeSelector(map[string]string{"pool": "batch"})(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		c := fromUnstructured[batchv1.CronJob](t, result)
+		g.Expect(c.Spec.JobTemplate.Spec.Template.Spec.NodeSelector).Should(HaveKeyWithValue("pool", "batch"))
+	})
+
+	t.Run("should pass other kinds through", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		})
+		original := obj.DeepCopy()
+
+		result, err := scheduling.NodeSelector(map[string]string{"pool": "apps"})(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(original.Object))
+	})
+}
+
+func TestTolerations(t *testing.T) {
+
+	t.Run("should append missing tolerations and keep existing ones", func(t *testing.T) {
+		g := NewWithT(t)
+
+		seconds := int64(60)
+		existing := corev1.Toleration{
+			Key:               "dedicated",
+			Operator:          corev1.TolerationOpEqual,
+			Value:             "apps",
+			Effect:            corev1.TaintEffectNoExecute,
+			TolerationSeconds: &seconds,
+		}
+		added := corev1.Toleration{
+			Key:      "spot",
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		}
+
+		obj := deployment(corev1.PodSpec{Tolerations: []corev1.Toleration{existing}})
+
+		duplicate := existing
+		duplicate.TolerationSeconds = nil
+
+		tr := scheduling.Tolerations([]corev1.Toleration{duplicate, added})
+
+		result, err := tr(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		d := fromUnstructured[appsv1.Deployment](t, result)
+		g.Expect(d.Spec.Template.Spec.Tolerations).Should(Equal([]corev1.Toleration{existing, added}))
+
+		// applying twice is idempotent
+		result, err = tr(t.Context(), result)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		d = fromUnstructured[appsv1.Deployment](t, result)
+		g.Expect(d.Spec.Template.Spec.Tolerations).Should(HaveLen(2))
+	})
+
+	t.Run("should add tolerations to a Pod", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		})
+
+		result, err := scheduling.Tolerations([]corev1.Toleration{{Key: "spot", Operator: corev1.TolerationOpExists}})(
+			t.Context(), obj,
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		p := fromUnstructured[corev1.Pod](t, result)
+		g.Expect(p.Spec.Tolerations).Should(HaveLen(1))
+	})
+}
+
+func TestAffinity(t *testing.T) {
+
+	requirement := func(key string, values ...string) corev1.NodeSelectorRequirement {
+		return corev1.NodeSelectorRequirement{Key: key, Operator: corev1.NodeSelectorOpIn, Values: values}
+	}
+
+	nodeAffinity := func(terms ...corev1.NodeSelectorTerm) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}}
+	}
+
+	t.Run("should set the affinity of workloads without one", func(t *testing.T) {
+		g := NewWithT(t)
+
+		policy := nodeAffinity(corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{requirement("pool", "apps")},
+		})
+
+		result, err := scheduling.Affinity(policy)(t.Context(), deployment(corev1.PodSpec{}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		d := fromUnstructured[appsv1.Deployment](t, result)
+		g.Expect(d.Spec.Template.Spec.Affinity).Should(Equal(policy))
+	})
+
+	t.Run("should AND required node selector terms with existing ones", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := deployment(corev1.PodSpec{Affinity: nodeAffinity(
+			corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{requirement("zone", "a")}},
+			corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{requirement("zone", "b")}},
+		)})
+
+		policy := nodeAffinity(corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{requirement("pool", "apps")},
+		})
+
+		result, err := scheduling.Affinity(policy)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		d := fromUnstructured[appsv1.Deployment](t, result)
+		g.Expect(d.Spec.Template.Spec.Affinity).Should(Equal(nodeAffinity(
+			corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+				requirement("zone", "a"), requirement("pool", "apps"),
+			}},
+			corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+				requirement("zone", "b"), requirement("pool", "apps"),
+			}},
+		)))
+	})
+
+	t.Run("should append pod anti-affinity terms without duplicates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		term := func(app string) corev1.PodAffinityTerm {
+			return corev1.PodAffinityTerm{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+				TopologyKey:   "kubernetes.io/hostname",
+			}
+		}
+
+		obj := deployment(corev1.PodSpec{Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term("web")},
+		}}})
+
+		policy := &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term("web"), term("db")},
+		}}
+
+		result, err := scheduling.Affinity(policy)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		d := fromUnstructured[appsv1.Deployment](t, result)
+		g.Expect(d.Spec.Template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).Should(
+			Equal([]corev1.PodAffinityTerm{term("web"), term("db")}),
+		)
+	})
+
+	t.Run("should not create a pod spec when nothing changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &batchv1.CronJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+			ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+			Spec:       batchv1.CronJobSpec{Schedule: "@daily"},
+		})
+		original := obj.DeepCopy()
+
+		for _, transformer := range []func(unstructured.Unstructured) (unstructured.Unstructured, error){
+			func(obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				return scheduling.Affinity(nil)(t.Context(), obj)
+			},
+			func(obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				return scheduling.Affinity(&corev1.Affinity{})(t.Context(), obj)
+			},
+			func(obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				return scheduling.NodeSelector(nil)(t.Context(), obj)
+			},
+			func(obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				return scheduling.Tolerations(nil)(t.Context(), obj)
+			},
+		} {
+			result, err := transformer(obj)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(result.Object).Should(Equal(original.Object))
+		}
+	})
+
+	t.Run("should leave objects unchanged for a nil affinity", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := deployment(corev1.PodSpec{})
+		original := obj.DeepCopy()
+
+		result, err := scheduling.Affinity(nil)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(original.Object))
+	})
+}
@@ -0,0 +1,143 @@
+// Package secretnormalize provides transformers converting Secrets between their data and stringData
+// representations, so that Secrets compare and apply the same whatever the renderer that produced them.
+package secretnormalize
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"slices"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+const (
+	fieldData       = "data"
+	fieldStringData = "stringData"
+)
+
+// ToData returns a transformer moving the stringData of Secrets into data: each value is base64
+// encoded and stringData is removed. As with the API server, a stringData value takes precedence over
+// the data value of the same key. Objects of other kinds pass through untouched.
+func ToData() types.Transformer {
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if !isSecret(obj) {
+			return obj, nil
+		}
+
+		stringData, found, err := unstructured.NestedStringMap(obj.Object, fieldStringData)
+		if err != nil {
+			return obj, fmt.Errorf("failed to read %s: %w", fieldStringData, err)
+		}
+
+		if !found {
+			return obj, nil
+		}
+
+		data, _, err := unstructured.NestedStringMap(obj.Object, fieldData)
+		if err != nil {
+			return obj, fmt.Errorf("failed to read %s: %w", fieldData, err)
+		}
+
+		if data == nil {
+			data = make(map[string]string, len(stringData))
+		}
+
+		for key, value := range stringData {
+			data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+		}
+
+		if err := setValues(obj, fieldData, data); err != nil {
+			return obj, err
+		}
+
+		unstructured.RemoveNestedField(obj.Object, fieldStringData)
+
+		return obj, nil
+	}
+}
+
+// ToStringData returns a transformer moving the data of Secrets into stringData: each value is
+// base64 decoded and data is removed. As with the API server, an existing stringData value takes
+// precedence over the data value of the same key. Values that are not valid UTF-8 once decoded,
+// such as binary files, cannot be represented as strings and are left in data. Objects of other
+// kinds pass through untouched.
+//
+// A data value that is not valid base64 fails the transformation with an error naming its key.
+func ToStringData() types.Transformer {
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if !isSecret(obj) {
+			return obj, nil
+		}
+
+		data, found, err := unstructured.NestedStringMap(obj.Object, fieldData)
+		if err != nil {
+			return obj, fmt.Errorf("failed to read %s: %w", fieldData, err)
+		}
+
+		if !found {
+			return obj, nil
+		}
+
+		stringData, _, err := unstructured.NestedStringMap(obj.Object, fieldStringData)
+		if err != nil {
+			return obj, fmt.Errorf("failed to read %s: %w", fieldStringData, err)
+		}
+
+		if stringData == nil {
+			stringData = make(map[string]string, len(data))
+		}
+
+		binary := make(map[string]string)
+
+		// Sorted so that the first invalid key reported does not depend on map iteration order.
+		for _, key := range slices.Sorted(maps.Keys(data)) {
+			decoded, err := base64.StdEncoding.DecodeString(data[key])
+			if err != nil {
+				return obj, fmt.Errorf("failed to decode %s %q: %w", fieldData, key, err)
+			}
+
+			if !utf8.Valid(decoded) {
+				binary[key] = data[key]
+
+				continue
+			}
+
+			if _, ok := stringData[key]; !ok {
+				stringData[key] = string(decoded)
+			}
+		}
+
+		if err := setValues(obj, fieldStringData, stringData); err != nil {
+			return obj, err
+		}
+
+		if len(binary) == 0 {
+			unstructured.RemoveNestedField(obj.Object, fieldData)
+
+			return obj, nil
+		}
+
+		return obj, setValues(obj, fieldData, binary)
+	}
+}
+
+// isSecret reports whether obj is a core Secret.
+func isSecret(obj unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+
+	return gvk.Group == "" && gvk.Kind == "Secret"
+}
+
+// setValues sets the field map of obj to values.
+func setValues(obj unstructured.Unstructured, field string, values map[string]string) error {
+	if err := unstructured.SetNestedStringMap(obj.Object, values, field); err != nil {
+		return fmt.Errorf("failed to set %s: %w", field, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package secretnormalize_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/secretnormalize"
+
+	. "github.com/onsi/gomega"
+)
+
+func encoded(value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+func secret(data map[string]any, stringData map[string]any) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "credentials"},
+	}}
+
+	if data != nil {
+		obj.Object["data"] = data
+	}
+
+	if stringData != nil {
+		obj.Object["stringData"] = stringData
+	}
+
+	return obj
+}
+
+func TestToData(t *testing.T) {
+
+	t.Run("should encode stringData into data", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(
+			map[string]any{"username": encoded("admin"), "password": encoded("old")},
+			map[string]any{"password": "s3cr3t", "token": "abc"},
+		)
+
+		result, err := secretnormalize.ToData()(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).ShouldNot(HaveKey("stringData"))
+		g.Expect(result.Object["data"]).Should(Equal(map[string]any{
+			"username": encoded("admin"),
+			"password": encoded("s3cr3t"),
+			"token":    encoded("abc"),
+		}))
+	})
+
+	t.Run("should leave secrets without stringData untouched", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(map[string]any{"username": encoded("admin")}, nil)
+
+		result, err := secretnormalize.ToData()(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object["data"]).Should(Equal(map[string]any{"username": encoded("admin")}))
+	})
+
+	t.Run("should ignore other kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(nil, map[string]any{"key": "value"})
+		obj.SetKind("ConfigMap")
+
+		result, err := secretnormalize.ToData()(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object["stringData"]).Should(Equal(map[string]any{"key": "value"}))
+		g.Expect(result.Object).ShouldNot(HaveKey("data"))
+	})
+}
+
+func TestToStringData(t *testing.T) {
+
+	t.Run("should decode data into stringData", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(
+			map[string]any{"username": encoded("admin"), "password": encoded("old")},
+			map[string]any{"password": "s3cr3t"},
+		)
+
+		result, err := secretnormalize.ToStringData()(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).ShouldNot(HaveKey("data"))
+		g.Expect(result.Object["stringData"]).Should(Equal(map[string]any{
+			"username": "admin",
+			"password": "s3cr3t",
+		}))
+	})
+
+	t.Run("should keep binary values in data", func(t *testing.T) {
+		g := NewWithT(t)
+
+		binary := base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe, 0x00})
+		obj := secret(map[string]any{"username": encoded("admin"), "keystore": binary}, nil)
+
+		result, err := secretnormalize.ToStringData()(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object["data"]).Should(Equal(map[string]any{"keystore": binary}))
+		g.Expect(result.Object["stringData"]).Should(Equal(map[string]any{"username": "admin"}))
+	})
+
+	t.Run("should fail on invalid base64 naming the key", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(map[string]any{"username": encoded("admin"), "password": "not base64!"}, nil)
+
+		_, err := secretnormalize.ToStringData()(t.Context(), obj)
+		g.Expect(err).Should(MatchError(ContainSubstring(`"password"`)))
+	})
+
+	t.Run("should round trip with ToData", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(map[string]any{"username": encoded("admin")}, nil)
+
+		result, err := secretnormalize.ToStringData()(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err = secretnormalize.ToData()(t.Context(), result)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(secret(map[string]any{"username": encoded("admin")}, nil).Object))
+	})
+}
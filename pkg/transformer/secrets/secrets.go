@@ -0,0 +1,150 @@
+// Package secrets provides transformers for the data of Secrets: decryption of values kept encrypted
+// in source, e.g. in SOPS-style workflows, and redaction for output that is safe to log or diff.
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+const (
+	// AnnotationEncrypted marks a Secret whose data and stringData values are encrypted. Only Secrets
+	// where it is set to "true" are decrypted by DecryptWith, which removes it.
+	AnnotationEncrypted = "manifests.k8s-manifests-lib/secret.encrypted"
+
+	// Redacted is the value RedactSecrets replaces secret values with.
+	Redacted = "***"
+
+	// annotationLastApplied holds a copy of the object, secret values included, set by kubectl apply.
+	annotationLastApplied = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// ErrDecryptorRequired is returned by DecryptWith when no decryptor is set.
+var ErrDecryptorRequired = errors.New("secrets decryptor cannot be nil")
+
+// DecryptWith returns a transformer decrypting with decryptor the values of the Secrets annotated with
+// AnnotationEncrypted. The values of data are base64 decoded before being decrypted and encoded again
+// afterwards; the values of stringData are decrypted as they are. The annotation is removed from
+// decrypted Secrets, and objects of other kinds or without the annotation pass through untouched.
+//
+// A value that cannot be decrypted fails the transformation with an error naming its key.
+func DecryptWith(decryptor func([]byte) ([]byte, error)) types.Transformer {
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if !isSecret(obj) || obj.GetAnnotations()[AnnotationEncrypted] != "true" {
+			return obj, nil
+		}
+
+		if decryptor == nil {
+			return obj, ErrDecryptorRequired
+		}
+
+		err := mapValues(obj, "data", func(key string, value string) (string, error) {
+			ciphertext, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return "", fmt.Errorf("failed to decode data %q: %w", key, err)
+			}
+
+			plaintext, err := decryptor(ciphertext)
+			if err != nil {
+				return "", fmt.Errorf("failed to decrypt data %q: %w", key, err)
+			}
+
+			return base64.StdEncoding.EncodeToString(plaintext), nil
+		})
+		if err != nil {
+			return obj, err
+		}
+
+		err = mapValues(obj, "stringData", func(key string, value string) (string, error) {
+			plaintext, err := decryptor([]byte(value))
+			if err != nil {
+				return "", fmt.Errorf("failed to decrypt stringData %q: %w", key, err)
+			}
+
+			return string(plaintext), nil
+		})
+		if err != nil {
+			return obj, err
+		}
+
+		removeAnnotation(&obj, AnnotationEncrypted)
+
+		return obj, nil
+	}
+}
+
+// RedactSecrets returns a transformer replacing every data and stringData value of Secrets with
+// Redacted, so that rendered output can be logged or diffed without leaking secrets. Keys are kept,
+// so diffs still show which entries are added or removed. The kubectl last-applied-configuration
+// annotation, which holds a copy of the values, is removed. Objects of other kinds pass through untouched.
+//
+// Redacted data values are not valid base64: the output is meant for display, not to be applied.
+func RedactSecrets() types.Transformer {
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if !isSecret(obj) {
+			return obj, nil
+		}
+
+		redact := func(string, string) (string, error) {
+			return Redacted, nil
+		}
+
+		for _, field := range []string{"data", "stringData"} {
+			if err := mapValues(obj, field, redact); err != nil {
+				return obj, err
+			}
+		}
+
+		removeAnnotation(&obj, annotationLastApplied)
+
+		return obj, nil
+	}
+}
+
+// isSecret reports whether obj is a core Secret.
+func isSecret(obj unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+
+	return gvk.Group == "" && gvk.Kind == "Secret"
+}
+
+// mapValues replaces each value of the field map of obj with the result of fn.
+func mapValues(obj unstructured.Unstructured, field string, fn func(key string, value string) (string, error)) error {
+	values, found, err := unstructured.NestedStringMap(obj.Object, field)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", field, err)
+	}
+
+	if !found {
+		return nil
+	}
+
+	for key, value := range values {
+		if values[key], err = fn(key, value); err != nil {
+			return err
+		}
+	}
+
+	if err := unstructured.SetNestedStringMap(obj.Object, values, field); err != nil {
+		return fmt.Errorf("failed to set %s: %w", field, err)
+	}
+
+	return nil
+}
+
+// removeAnnotation deletes the key annotation of obj, if any.
+func removeAnnotation(obj *unstructured.Unstructured, key string) {
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[key]; !ok {
+		return
+	}
+
+	delete(annotations, key)
+	obj.SetAnnotations(annotations)
+}
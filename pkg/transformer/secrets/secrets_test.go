@@ -0,0 +1,155 @@
+package secrets_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/secrets"
+
+	. "github.com/onsi/gomega"
+)
+
+var errNotEncrypted = errors.New("value is not encrypted")
+
+// decrypt "decrypts" values by stripping their "enc:" prefix.
+func decrypt(ciphertext []byte) ([]byte, error) {
+	plaintext, ok := bytes.CutPrefix(ciphertext, []byte("enc:"))
+	if !ok {
+		return nil, errNotEncrypted
+	}
+
+	return plaintext, nil
+}
+
+func encoded(value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+func secret(annotations map[string]any, data map[string]any, stringData map[string]any) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]any{
+			"name":        "credentials",
+			"annotations": annotations,
+		},
+	}}
+
+	if data != nil {
+		obj.Object["data"] = data
+	}
+
+	if stringData != nil {
+		obj.Object["stringData"] = stringData
+	}
+
+	return obj
+}
+
+func TestDecryptWith(t *testing.T) {
+
+	t.Run("should decrypt data and stringData of annotated secrets", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(
+			map[string]any{secrets.AnnotationEncrypted: "true", "team": "payments"},
+			map[string]any{"password": encoded("enc:s3cr3t")},
+			map[string]any{"token": "enc:abc"},
+		)
+
+		result, err := secrets.DecryptWith(decrypt)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(HaveKeyWithValue("data", map[string]any{"password": encoded("s3cr3t")}))
+		g.Expect(result.Object).Should(HaveKeyWithValue("stringData", map[string]any{"token": "abc"}))
+		g.Expect(result.GetAnnotations()).Should(Equal(map[string]string{"team": "payments"}))
+	})
+
+	t.Run("should leave secrets without the marker untouched", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(nil, map[string]any{"password": encoded("plain")}, nil)
+		original := obj.DeepCopy()
+
+		result, err := secrets.DecryptWith(decrypt)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(original.Object))
+	})
+
+	t.Run("should leave other kinds untouched", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(map[string]any{secrets.AnnotationEncrypted: "true"}, map[string]any{"key": "enc:value"}, nil)
+		obj.SetKind("ConfigMap")
+		original := obj.DeepCopy()
+
+		result, err := secrets.DecryptWith(decrypt)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(original.Object))
+	})
+
+	t.Run("should name the key that cannot be decrypted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(map[string]any{secrets.AnnotationEncrypted: "true"}, nil, map[string]any{"token": "plain"})
+
+		_, err := secrets.DecryptWith(decrypt)(t.Context(), obj)
+		g.Expect(err).Should(MatchError(errNotEncrypted))
+		g.Expect(err.Error()).Should(ContainSubstring(`stringData "token"`))
+	})
+
+	t.Run("should fail on data that is not base64", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(map[string]any{secrets.AnnotationEncrypted: "true"}, map[string]any{"password": "!!"}, nil)
+
+		_, err := secrets.DecryptWith(decrypt)(t.Context(), obj)
+		g.Expect(err).Should(MatchError(ContainSubstring(`failed to decode data "password"`)))
+	})
+
+	t.Run("should fail without a decryptor", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(map[string]any{secrets.AnnotationEncrypted: "true"}, nil, nil)
+
+		_, err := secrets.DecryptWith(nil)(t.Context(), obj)
+		g.Expect(err).Should(MatchError(secrets.ErrDecryptorRequired))
+	})
+}
+
+func TestRedactSecrets(t *testing.T) {
+
+	t.Run("should replace every value and keep the keys", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(
+			map[string]any{"kubectl.kubernetes.io/last-applied-configuration": `{"data":{"password":"czNjcjN0"}}`},
+			map[string]any{"password": encoded("s3cr3t"), "user": encoded("admin")},
+			map[string]any{"token": "abc"},
+		)
+
+		result, err := secrets.RedactSecrets()(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(HaveKeyWithValue("data", map[string]any{
+			"password": secrets.Redacted,
+			"user":     secrets.Redacted,
+		}))
+		g.Expect(result.Object).Should(HaveKeyWithValue("stringData", map[string]any{"token": secrets.Redacted}))
+		g.Expect(result.GetAnnotations()).Should(BeEmpty())
+	})
+
+	t.Run("should leave other kinds untouched", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := secret(nil, map[string]any{"key": "value"}, nil)
+		obj.SetKind("ConfigMap")
+		original := obj.DeepCopy()
+
+		result, err := secrets.RedactSecrets()(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(original.Object))
+	})
+}
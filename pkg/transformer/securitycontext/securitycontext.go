@@ -0,0 +1,120 @@
+// Package securitycontext provides a transformer enforcing default pod and container security contexts,
+// e.g. to meet the baseline or restricted Pod Security Standards at render time.
+package securitycontext
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/podspec"
+)
+
+const fieldSecurityContext = "securityContext"
+
+// EnsureDefaults returns a transformer that fills in the fields of pod missing from the pod security
+// context, and the fields of container missing from the security context of each container, init
+// container and ephemeral container, of Pods, Deployments, StatefulSets, DaemonSets, ReplicaSets,
+// ReplicationControllers, Jobs and CronJobs. Explicitly set values are never overwritten.
+//
+// Nested fields are filled one by one, e.g. capabilities.drop is set on a container that only adds
+// capabilities, while lists such as capabilities.drop are kept as they are when set. A container
+// default is not applied for a field the manifest sets on the pod security context, such as runAsUser,
+// since it would override the explicit pod-level value. Objects of other kinds pass through untouched.
+func EnsureDefaults(
+	pod corev1.PodSecurityContext,
+	container corev1.SecurityContext,
+	opts ...Option,
+) types.Transformer {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	fields := []string{"containers", "initContainers", "ephemeralContainers"}
+	if options.SkipInitContainers {
+		fields = slices.DeleteFunc(fields, func(field string) bool { return field == "initContainers" })
+	}
+
+	podDefaults, podErr := runtime.DefaultUnstructuredConverter.ToUnstructured(&pod)
+	containerDefaults, containerErr := runtime.DefaultUnstructuredConverter.ToUnstructured(&container)
+
+	return podspec.Transformer(func(spec map[string]any) (bool, error) {
+		if podErr != nil {
+			return false, fmt.Errorf("invalid pod security context defaults: %w", podErr)
+		}
+
+		if containerErr != nil {
+			return false, fmt.Errorf("invalid container security context defaults: %w", containerErr)
+		}
+
+		podContext, _, err := unstructured.NestedMap(spec, fieldSecurityContext)
+		if err != nil {
+			return false, fmt.Errorf("failed to read %s: %w", fieldSecurityContext, err)
+		}
+
+		// Fields set on the pod apply to all its containers and must not be shadowed by container defaults.
+		defaults := runtime.DeepCopyJSON(containerDefaults)
+		for key := range podContext {
+			delete(defaults, key)
+		}
+
+		changed := false
+
+		if merged := fill(podContext, podDefaults); len(merged) > 0 {
+			spec[fieldSecurityContext] = merged
+			changed = true
+		}
+
+		found, err := podspec.UpdateContainers(spec, fields, func(container map[string]any) error {
+			name, _, _ := unstructured.NestedString(container, "name")
+			if !options.selects(name) {
+				return nil
+			}
+
+			current, _, err := unstructured.NestedMap(container, fieldSecurityContext)
+			if err != nil {
+				return fmt.Errorf("container %q: failed to read %s: %w", name, fieldSecurityContext, err)
+			}
+
+			if merged := fill(current, defaults); len(merged) > 0 {
+				container[fieldSecurityContext] = merged
+			}
+
+			return nil
+		})
+
+		return changed || found, err
+	})
+}
+
+// fill returns current with the fields of defaults it is missing, recursing into nested objects.
+// Neither argument is modified.
+func fill(current map[string]any, defaults map[string]any) map[string]any {
+	result := runtime.DeepCopyJSON(current)
+	if result == nil {
+		result = make(map[string]any, len(defaults))
+	}
+
+	for key, value := range defaults {
+		existing, ok := result[key]
+		if !ok {
+			result[key] = runtime.DeepCopyJSONValue(value)
+
+			continue
+		}
+
+		existingMap, existingIsMap := existing.(map[string]any)
+		defaultMap, defaultIsMap := value.(map[string]any)
+
+		if existingIsMap && defaultIsMap {
+			result[key] = fill(existingMap, defaultMap)
+		}
+	}
+
+	return result
+}
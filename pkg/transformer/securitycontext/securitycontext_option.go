@@ -0,0 +1,44 @@
+package securitycontext
+
+import (
+	"slices"
+
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the EnsureDefaults transformer.
+type Options struct {
+	// Containers restricts container defaults to the containers with these names.
+	// If empty, all containers are defaulted. The pod security context is defaulted regardless.
+	Containers []string
+
+	// SkipInitContainers leaves the security context of init containers untouched.
+	SkipInitContainers bool
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.Containers = append(target.Containers, opts.Containers...)
+	target.SkipInitContainers = opts.SkipInitContainers
+}
+
+// WithContainers restricts container defaults to the containers with the given names.
+func WithContainers(names ...string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Containers = append(o.Containers, names...)
+	})
+}
+
+// WithSkipInitContainers enables or disables leaving the security context of init containers untouched.
+func WithSkipInitContainers(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.SkipInitContainers = enabled
+	})
+}
+
+func (opts Options) selects(name string) bool {
+	return len(opts.Containers) == 0 || slices.Contains(opts.Containers, name)
+}
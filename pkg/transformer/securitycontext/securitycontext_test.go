@@ -0,0 +1,213 @@
+package securitycontext_test
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/securitycontext"
+
+	. "github.com/onsi/gomega"
+)
+
+func toUnstructured(t *testing.T, obj runtime.Object) unstructured.Unstructured {
+	t.Helper()
+
+	unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return unstructured.Unstructured{Object: unstr}
+}
+
+func fromUnstructured[T any](t *testing.T, obj unstructured.Unstructured) *T {
+	t.Helper()
+
+	var result T
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &result)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return &result
+}
+
+func restricted() (corev1.PodSecurityContext, corev1.SecurityContext) {
+	pod := corev1.PodSecurityContext{
+		RunAsNonRoot:   ptr.To(true),
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+
+	container := corev1.SecurityContext{
+		RunAsNonRoot:             ptr.To(true),
+		ReadOnlyRootFilesystem:   ptr.To(true),
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+
+	return pod, container
+}
+
+func TestEnsureDefaults(t *testing.T) {
+
+	t.Run("should fill missing pod and container fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &appsv1.Deployment{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						InitContainers: []corev1.Container{{Name: "init"}},
+						Containers: []corev1.Container{
+							{Name: "app"},
+							{
+								Name: "sidecar",
+								SecurityContext: &corev1.SecurityContext{
+									ReadOnlyRootFilesystem: ptr.To(false),
+									Capabilities:           &corev1.Capabilities{Add: []corev1.Capability{"NET_BIND_SERVICE"}},
+								},
+							},
+						},
+						EphemeralContainers: []corev1.EphemeralContainer{
+							{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debug"}},
+						},
+					},
+				},
+			},
+		})
+
+		pod, container := restricted()
+
+		result, err := securitycontext.EnsureDefaults(pod, container)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		spec := fromUnstructured[appsv1.Deployment](t, result).Spec.Template.Spec
+		g.Expect(spec.SecurityContext).Should(Equal(&pod))
+		g.Expect(spec.InitContainers[0].SecurityContext).Should(Equal(&container))
+		g.Expect(spec.Containers[0].SecurityContext).Should(Equal(&container))
+		g.Expect(spec.EphemeralContainers[0].SecurityContext).Should(Equal(&container))
+
+		g.Expect(spec.Containers[1].SecurityContext).Should(Equal(&corev1.SecurityContext{
+			RunAsNonRoot:             ptr.To(true),
+			ReadOnlyRootFilesystem:   ptr.To(false),
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Add:  []corev1.Capability{"NET_BIND_SERVICE"},
+				Drop: []corev1.Capability{"ALL"},
+			},
+		}))
+	})
+
+	t.Run("should not shadow fields set on the pod", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &corev1.Pod{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			Spec: corev1.PodSpec{
+				SecurityContext: &corev1.PodSecurityContext{RunAsUser: ptr.To[int64](1000)},
+				Containers:      []corev1.Container{{Name: "app"}},
+			},
+		})
+
+		result, err := securitycontext.EnsureDefaults(
+			corev1.PodSecurityContext{RunAsUser: ptr.To[int64](2000), RunAsNonRoot: ptr.To(true)},
+			corev1.SecurityContext{RunAsUser: ptr.To[int64](2000), ReadOnlyRootFilesystem: ptr.To(true)},
+		)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		spec := fromUnstructured[corev1.Pod](t, result).Spec
+		g.Expect(spec.SecurityContext).Should(Equal(&corev1.PodSecurityContext{
+			RunAsUser:    ptr.To[int64](1000),
+			RunAsNonRoot: ptr.To(true),
+		}))
+		g.Expect(spec.Containers[0].SecurityContext).Should(Equal(&corev1.SecurityContext{
+			ReadOnlyRootFilesystem: ptr.To(true),
+		}))
+	})
+
+	t.Run("should handle the nested template of CronJobs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &batchv1.CronJob{
+			TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+			Spec: batchv1.CronJobSpec{
+				JobTemplate: batchv1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "job"}}},
+						},
+					},
+				},
+			},
+		})
+
+		pod, container := restricted()
+
+		result, err := securitycontext.EnsureDefaults(pod, container)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		spec := fromUnstructured[batchv1.CronJob](t, result).Spec.JobTemplate.Spec.Template.Spec
+		g.Expect(spec.SecurityContext).Should(Equal(&pod))
+		g.Expect(spec.Containers[0].SecurityContext).Should(Equal(&container))
+	})
+
+	t.Run("should honor container options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &corev1.Pod{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init"}},
+				Containers:     []corev1.Container{{Name: "app"}, {Name: "sidecar"}},
+			},
+		})
+
+		pod, container := restricted()
+
+		result, err := securitycontext.EnsureDefaults(
+			pod,
+			container,
+			securitycontext.WithContainers("app", "init"),
+			securitycontext.WithSkipInitContainers(true),
+		)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		spec := fromUnstructured[corev1.Pod](t, result).Spec
+		g.Expect(spec.SecurityContext).Should(Equal(&pod))
+		g.Expect(spec.Containers[0].SecurityContext).Should(Equal(&container))
+		g.Expect(spec.Containers[1].SecurityContext).Should(BeNil())
+		g.Expect(spec.InitContainers[0].SecurityContext).Should(BeNil())
+	})
+
+	t.Run("should ignore other kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := toUnstructured(t, &corev1.ConfigMap{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}})
+		original := obj.DeepCopy()
+
+		pod, container := restricted()
+
+		result, err := securitycontext.EnsureDefaults(pod, container)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(original.Object))
+	})
+
+	t.Run("should fail on a malformed security context", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"spec":       map[string]any{"securityContext": "restricted"},
+		}}
+
+		pod, container := restricted()
+
+		_, err := securitycontext.EnsureDefaults(pod, container)(t.Context(), obj)
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
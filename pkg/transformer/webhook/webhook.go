@@ -0,0 +1,194 @@
+// Package webhook provides a transformer and a hook sending rendered objects to an external mutating
+// webhook, e.g. a centralized policy service, and replacing them with the objects it returns.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// maxErrorBody is the maximum number of bytes of a response body reported in errors.
+const maxErrorBody = 4096
+
+var (
+	// ErrUnexpectedStatus is returned when the webhook responds with a non-2xx status.
+	ErrUnexpectedStatus = errors.New("webhook returned an unexpected status")
+
+	// ErrInvalidResponse is returned when the webhook response cannot be decoded into the expected objects.
+	ErrInvalidResponse = errors.New("invalid webhook response")
+)
+
+// New returns a transformer POSTing each object as a JSON document to url and replacing it with the
+// object of the JSON response, which must be a complete object rather than a patch. Each object is
+// sent in its own request; to amortize requests over many objects, use Hook.
+//
+// Requests have a Content-Type of application/json and the headers set with WithHeader, and use
+// the TLS configuration set with WithTLSConfig. Each request is bounded by the timeout set with
+// WithTimeout (DefaultTimeout by default) and by the context. A response with a non-2xx status fails
+// with an error wrapping ErrUnexpectedStatus and including the response body.
+func New(url string, opts ...Option) types.Transformer {
+	c := newClient(url, opts)
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		data, err := c.post(ctx, obj.Object)
+		if err != nil {
+			return obj, err
+		}
+
+		var result unstructured.Unstructured
+		if err := result.UnmarshalJSON(data); err != nil {
+			return obj, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+		}
+
+		return result, nil
+	}
+}
+
+// Hook returns a types.Hook whose AfterRender POSTs the final objects to url in batches of up to
+// WithBatchSize objects (DefaultBatchSize by default), each as a JSON array, and replaces them with
+// the objects of the JSON array of the response, which must hold as many objects, in the same order.
+// Register it with engine.WithHook. Requests are made as described for New, one batch at a time.
+func Hook(url string, opts ...Option) types.Hook {
+	return &hook{client: newClient(url, opts)}
+}
+
+type hook struct {
+	client *client
+}
+
+// BeforeRender returns values unchanged.
+func (h *hook) BeforeRender(_ context.Context, values map[string]any) (map[string]any, error) {
+	return values, nil
+}
+
+// AfterRender replaces objects with the objects returned by the webhook, batch by batch.
+func (h *hook) AfterRender(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+) ([]unstructured.Unstructured, error) {
+	result := make([]unstructured.Unstructured, 0, len(objects))
+	size := h.client.options.BatchSize
+
+	for start := 0; start < len(objects); start += size {
+		batch := objects[start:min(start+size, len(objects))]
+
+		mutated, err := h.client.postBatch(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("webhook batch of objects %d to %d: %w", start, start+len(batch)-1, err)
+		}
+
+		result = append(result, mutated...)
+	}
+
+	return result, nil
+}
+
+// client sends objects to a webhook.
+type client struct {
+	url     string
+	http    *http.Client
+	options Options
+}
+
+func newClient(url string, opts []Option) *client {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return &client{
+		url: url,
+		http: &http.Client{
+			Timeout: options.Timeout,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: options.TLSConfig,
+			},
+		},
+		options: options,
+	}
+}
+
+// postBatch sends objects as a JSON array and returns the objects of the response.
+func (c *client) postBatch(ctx context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	payload := make([]map[string]any, 0, len(objects))
+	for _, obj := range objects {
+		payload = append(payload, obj.Object)
+	}
+
+	data, err := c.post(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+
+	if len(items) != len(objects) {
+		return nil, fmt.Errorf("%w: expected %d objects, got %d", ErrInvalidResponse, len(objects), len(items))
+	}
+
+	result := make([]unstructured.Unstructured, len(items))
+
+	for i, item := range items {
+		if err := result[i].UnmarshalJSON(item); err != nil {
+			return nil, fmt.Errorf("%w: object %d: %w", ErrInvalidResponse, i, err)
+		}
+	}
+
+	return result, nil
+}
+
+// post sends payload as JSON and returns the body of a 2xx response.
+func (c *client) post(ctx context.Context, payload any) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	for key, value := range c.options.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webhook request failed: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+		return nil, fmt.Errorf("%w %s: %s", ErrUnexpectedStatus, resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook response: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"maps"
+	"time"
+
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+const (
+	// DefaultTimeout is the default time limit of a webhook request.
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultBatchSize is the default maximum number of objects sent in a request by Hook.
+	DefaultBatchSize = 50
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the webhook transformer and hook.
+type Options struct {
+	// TLSConfig is the TLS configuration of the requests, e.g. with the CA of the webhook
+	// or a client certificate. If nil, the default configuration is used.
+	TLSConfig *tls.Config
+
+	// Timeout bounds each request. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// BatchSize is the maximum number of objects sent in a request by Hook. Defaults to DefaultBatchSize.
+	BatchSize int
+
+	// Headers are set on every request, e.g. for authentication.
+	Headers map[string]string
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.TLSConfig != nil {
+		target.TLSConfig = opts.TLSConfig
+	}
+
+	if opts.Timeout > 0 {
+		target.Timeout = opts.Timeout
+	}
+
+	if opts.BatchSize > 0 {
+		target.BatchSize = opts.BatchSize
+	}
+
+	if len(opts.Headers) > 0 {
+		if target.Headers == nil {
+			target.Headers = make(map[string]string, len(opts.Headers))
+		}
+
+		maps.Copy(target.Headers, opts.Headers)
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the requests.
+func WithTLSConfig(config *tls.Config) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.TLSConfig = config
+	})
+}
+
+// WithTimeout bounds each request to d. Non-positive durations are ignored.
+func WithTimeout(d time.Duration) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if d > 0 {
+			o.Timeout = d
+		}
+	})
+}
+
+// WithBatchSize sets the maximum number of objects sent in a request by Hook. Non-positive sizes are ignored.
+// New always sends one object per request.
+func WithBatchSize(n int) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if n > 0 {
+			o.BatchSize = n
+		}
+	})
+}
+
+// WithHeader sets a header on every request, e.g. "Authorization".
+func WithHeader(key string, value string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if o.Headers == nil {
+			o.Headers = make(map[string]string)
+		}
+
+		o.Headers[key] = value
+	})
+}
+
+func defaultOptions() Options {
+	return Options{
+		Timeout:   DefaultTimeout,
+		BatchSize: DefaultBatchSize,
+	}
+}
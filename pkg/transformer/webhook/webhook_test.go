@@ -0,0 +1,197 @@
+package webhook_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/webhook"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeConfigMap(name string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName(name)
+	obj.SetNamespace("default")
+
+	return obj
+}
+
+// label mutates an object or a batch of objects by adding the "policy" label.
+func label(w http.ResponseWriter, r *http.Request) {
+	var payload any
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	mutate := func(obj any) {
+		metadata, _ := obj.(map[string]any)["metadata"].(map[string]any)
+		metadata["labels"] = map[string]any{"policy": "checked"}
+	}
+
+	if items, ok := payload.([]any); ok {
+		for _, item := range items {
+			mutate(item)
+		}
+	} else {
+		mutate(payload)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func TestNew(t *testing.T) {
+
+	t.Run("should replace objects with the webhook response", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var header string
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header.Get("Authorization")
+
+			label(w, r)
+		}))
+		defer srv.Close()
+
+		obj, err := webhook.New(srv.URL, webhook.WithHeader("Authorization", "Bearer token"))(
+			t.Context(),
+			makeConfigMap("config"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetName()).Should(Equal("config"))
+		g.Expect(obj.GetLabels()).Should(Equal(map[string]string{"policy": "checked"}))
+		g.Expect(header).Should(Equal("Bearer token"))
+	})
+
+	t.Run("should fail with the body of non-2xx responses", func(t *testing.T) {
+		g := NewWithT(t)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "denied by policy require-owner", http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		_, err := webhook.New(srv.URL)(t.Context(), makeConfigMap("config"))
+		g.Expect(err).Should(MatchError(webhook.ErrUnexpectedStatus))
+		g.Expect(err.Error()).Should(ContainSubstring("403 Forbidden"))
+		g.Expect(err.Error()).Should(ContainSubstring("denied by policy require-owner"))
+	})
+
+	t.Run("should reject responses that are not objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"patch": []}`))
+		}))
+		defer srv.Close()
+
+		_, err := webhook.New(srv.URL)(t.Context(), makeConfigMap("config"))
+		g.Expect(err).Should(MatchError(webhook.ErrInvalidResponse))
+	})
+
+	t.Run("should time out slow webhooks", func(t *testing.T) {
+		g := NewWithT(t)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+
+			label(w, r)
+		}))
+		defer srv.Close()
+
+		_, err := webhook.New(srv.URL, webhook.WithTimeout(10*time.Millisecond))(t.Context(), makeConfigMap("config"))
+		g.Expect(err).Should(MatchError(ContainSubstring("webhook request failed")))
+	})
+
+	t.Run("should use the TLS configuration", func(t *testing.T) {
+		g := NewWithT(t)
+
+		srv := httptest.NewTLSServer(http.HandlerFunc(label))
+		defer srv.Close()
+
+		_, err := webhook.New(srv.URL)(t.Context(), makeConfigMap("config"))
+		g.Expect(err).Should(HaveOccurred())
+
+		pool := x509.NewCertPool()
+		pool.AddCert(srv.Certificate())
+
+		obj, err := webhook.New(srv.URL, webhook.WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}))(
+			t.Context(),
+			makeConfigMap("config"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetLabels()).Should(HaveKeyWithValue("policy", "checked"))
+	})
+}
+
+func TestHook(t *testing.T) {
+
+	t.Run("should send objects in batches", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var requests atomic.Int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+
+			label(w, r)
+		}))
+		defer srv.Close()
+
+		objects := []unstructured.Unstructured{
+			makeConfigMap("a"), makeConfigMap("b"), makeConfigMap("c"), makeConfigMap("d"), makeConfigMap("e"),
+		}
+
+		result, err := webhook.Hook(srv.URL, webhook.WithBatchSize(2)).AfterRender(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(requests.Load()).Should(BeEquivalentTo(3))
+		g.Expect(result).Should(HaveLen(5))
+
+		for i, obj := range result {
+			g.Expect(obj.GetName()).Should(Equal(objects[i].GetName()))
+			g.Expect(obj.GetLabels()).Should(HaveKeyWithValue("policy", "checked"))
+		}
+
+		g.Expect(objects[0].GetLabels()).Should(BeEmpty())
+	})
+
+	t.Run("should reject responses with a different number of objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`[]`))
+		}))
+		defer srv.Close()
+
+		_, err := webhook.Hook(srv.URL).AfterRender(t.Context(), []unstructured.Unstructured{makeConfigMap("a")})
+		g.Expect(err).Should(MatchError(webhook.ErrInvalidResponse))
+		g.Expect(err.Error()).Should(ContainSubstring("expected 1 objects, got 0"))
+	})
+
+	t.Run("should leave values unchanged", func(t *testing.T) {
+		g := NewWithT(t)
+
+		values := map[string]any{"env": "prod"}
+
+		result, err := webhook.Hook("http://127.0.0.1:0").BeforeRender(t.Context(), values)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(Equal(values))
+	})
+}
@@ -9,4 +9,11 @@ const (
 
 	// AnnotationSourceFile is the annotation key for the specific template file.
 	AnnotationSourceFile = "manifests.k8s-manifests-lib/source.file"
+
+	// AnnotationSourceName is the annotation key for the name of the renderer that produced an object.
+	AnnotationSourceName = "manifests.k8s-manifests-lib/source.name"
+
+	// AnnotationSourceComments is the annotation key holding the commented source document of an object,
+	// set by renderers preserving comments and removed by the YAML writer.
+	AnnotationSourceComments = "manifests.k8s-manifests-lib/source.comments"
 )
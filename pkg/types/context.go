@@ -0,0 +1,70 @@
+package types
+
+import (
+	"context"
+	"sync"
+)
+
+type (
+	rendererNameKey struct{}
+	renderStateKey  struct{}
+	kubeVersionKey  struct{}
+)
+
+// WithRendererName returns a copy of ctx carrying the name of the renderer being executed.
+// The engine sets it on the context passed to Renderer.Process and to per-renderer processing.
+func WithRendererName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, rendererNameKey{}, name)
+}
+
+// RendererName returns the name of the renderer being executed, if ctx carries one.
+func RendererName(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(rendererNameKey{}).(string)
+
+	return name, ok && name != ""
+}
+
+// RenderState holds state scoped to a single render and shared by the filters, transformers and
+// validators taking part in it, e.g. counters. It is safe for concurrent use.
+type RenderState struct {
+	values sync.Map
+}
+
+// Load returns the value stored for key, creating it with create if there is none yet.
+// Keys must be comparable and should be unexported types or pointers, as for context values.
+func (s *RenderState) Load(key any, create func() any) any {
+	if value, ok := s.values.Load(key); ok {
+		return value
+	}
+
+	value, _ := s.values.LoadOrStore(key, create())
+
+	return value
+}
+
+// WithRenderState returns a copy of ctx carrying a new, empty RenderState.
+// The engine sets it on the context of every Render() call.
+func WithRenderState(ctx context.Context) context.Context {
+	return context.WithValue(ctx, renderStateKey{}, &RenderState{})
+}
+
+// RenderStateFrom returns the RenderState of the render being executed, if ctx carries one.
+func RenderStateFrom(ctx context.Context) (*RenderState, bool) {
+	state, ok := ctx.Value(renderStateKey{}).(*RenderState)
+
+	return state, ok
+}
+
+// WithKubeVersion returns a copy of ctx carrying the Kubernetes version the render targets,
+// in the "v1.31.0" form. The engine sets it on the context of every Render() call configured with
+// a version, so renderers, filters, transformers, hooks and validators can adapt to the cluster.
+func WithKubeVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, kubeVersionKey{}, version)
+}
+
+// KubeVersion returns the Kubernetes version the render targets, if ctx carries one.
+func KubeVersion(ctx context.Context) (string, bool) {
+	version, ok := ctx.Value(kubeVersionKey{}).(string)
+
+	return version, ok && version != ""
+}
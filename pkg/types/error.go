@@ -0,0 +1,88 @@
+package types
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RendererError represents an error returned by a renderer, or reported for it by the engine
+// (e.g. a timeout or an empty output). It identifies the failing renderer by name.
+// Errors of engine-level filters and transformers are reported as FilterError and
+// TransformerError, which identify the offending object.
+// A RendererError directly wrapping another one, e.g. for a child of a composite renderer,
+// names both renderers after a single "rendering failed" prefix.
+type RendererError struct {
+	Name string
+	// Type is the Go type of the failing renderer, e.g. "*helm.Renderer", if known.
+	Type string
+	Err  error
+}
+
+func (e *RendererError) Error() string {
+	return "rendering failed: " + e.message()
+}
+
+// message returns the error message of e without the "rendering failed" prefix.
+func (e *RendererError) message() string {
+	cause := fmt.Sprint(e.Err)
+	if inner, ok := e.Err.(*RendererError); ok { //nolint:errorlint // Only direct nesting shares the prefix.
+		cause = inner.message()
+	}
+
+	if e.Type == "" {
+		return fmt.Sprintf("error processing renderer %q: %s", e.Name, cause)
+	}
+
+	return fmt.Sprintf("error processing renderer %q (%s): %s", e.Name, e.Type, cause)
+}
+
+func (e *RendererError) Unwrap() error {
+	return e.Err
+}
+
+// FilterError represents an error that occurred during filter application.
+// It provides context about which object failed and the underlying error.
+// It is also available as filter.Error.
+type FilterError struct {
+	Object unstructured.Unstructured
+	Err    error
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf(
+		"filter error for %s:%s %s (namespace: %s): %v",
+		e.Object.GroupVersionKind().GroupVersion(),
+		e.Object.GroupVersionKind().Kind,
+		e.Object.GetName(),
+		e.Object.GetNamespace(),
+		e.Err,
+	)
+}
+
+func (e *FilterError) Unwrap() error {
+	return e.Err
+}
+
+// TransformerError represents an error that occurred during transformer application.
+// It provides context about which object failed and the underlying error.
+// It is also available as transformer.Error.
+type TransformerError struct {
+	Object unstructured.Unstructured
+	Err    error
+}
+
+func (e *TransformerError) Error() string {
+	return fmt.Sprintf(
+		"transformer error for %s:%s %s (namespace: %s): %v",
+		e.Object.GroupVersionKind().GroupVersion(),
+		e.Object.GroupVersionKind().Kind,
+		e.Object.GetName(),
+		e.Object.GetNamespace(),
+		e.Err,
+	)
+}
+
+func (e *TransformerError) Unwrap() error {
+	return e.Err
+}
@@ -15,6 +15,9 @@ var (
 
 	// ErrRendererNameEmpty is returned when a renderer name is empty.
 	ErrRendererNameEmpty = errors.New("renderer must return a non-empty name")
+
+	// ErrStageInvalid is returned when a stage does not set exactly one of Filter and Transformer.
+	ErrStageInvalid = errors.New("stage must set exactly one of Filter and Transformer")
 )
 
 // Filter is a function type that processes a single unstructured.Unstructured object
@@ -25,6 +28,22 @@ type Filter func(ctx context.Context, object unstructured.Unstructured) (bool, e
 // and returns the transformed object.
 type Transformer func(ctx context.Context, object unstructured.Unstructured) (unstructured.Unstructured, error)
 
+// Validator is a function type that checks a single unstructured.Unstructured object
+// and returns a non-nil error if the object is invalid.
+type Validator func(ctx context.Context, object unstructured.Unstructured) error
+
+// Stage is a step of an ordered pipeline, either a filter or a transformer: exactly one of Filter
+// and Transformer must be set. Stages run in the order they are added, so a transformer can prepare
+// objects for a later filter; engine-level filters and transformers registered on their own are
+// stages too, and run interleaved with the others in registration order.
+type Stage struct {
+	// Filter drops the objects it rejects.
+	Filter Filter
+
+	// Transformer transforms the objects reaching the stage.
+	Transformer Transformer
+}
+
 // Renderer is a non-generic interface that concrete renderer types implement.
 // This allows the Engine to manage them heterogeneously.
 type Renderer interface {
@@ -39,6 +58,84 @@ type Renderer interface {
 	Name() string
 }
 
+// StreamingRenderer is a Renderer able to emit its objects one at a time, for outputs too large
+// to be held in memory twice. The engine prefers ProcessStream over Process when a renderer
+// implements it, runs each object through the engine-level and render-time pipeline as soon as
+// it is received, and never caches streamed results.
+type StreamingRenderer interface {
+	Renderer
+
+	// ProcessStream starts rendering and returns a channel of objects and a channel of errors.
+	// The implementation must send at most one error, close both channels when it is done,
+	// and stop sending as soon as ctx is done.
+	ProcessStream(ctx context.Context, values map[string]any) (<-chan unstructured.Unstructured, <-chan error)
+}
+
+// TargetedRenderer is a Renderer able to skip work for objects that are not the target of a partial
+// render (see engine.WithTarget). The engine prefers ProcessTarget over Process when a target is set,
+// except for renderers implementing StreamingRenderer, and never caches targeted results.
+type TargetedRenderer interface {
+	Renderer
+
+	// ProcessTarget behaves like Process but may omit objects that do not match target, evaluated on the
+	// objects as the renderer produces them. It must return every matching object; objects not matching
+	// target may be returned as well, as the engine filters the result with target anyway.
+	ProcessTarget(ctx context.Context, values map[string]any, target Filter) ([]unstructured.Unstructured, error)
+}
+
+// VersionAwareRenderer is implemented by renderers whose output depends on the Kubernetes version
+// of the target cluster, e.g. Helm charts using .Capabilities.KubeVersion. When a render targets
+// a version (see engine.WithKubeVersion), the engine calls ProcessVersion instead of Process, except
+// for streaming renderers and for targeted renders of TargetedRenderer implementations, which read
+// the version from the context with KubeVersion. Results are cached per version.
+type VersionAwareRenderer interface {
+	Renderer
+
+	// ProcessVersion behaves like Process for a cluster running the given Kubernetes version,
+	// in the "v1.31.0" form.
+	ProcessVersion(ctx context.Context, values map[string]any, version string) ([]unstructured.Unstructured, error)
+}
+
+// Implements reports whether r implements the optional renderer interface T, e.g. VersionAwareRenderer.
+//
+// Renderers wrapping other renderers, e.g. to retry them, implement optional interfaces whatever the
+// renderers they wrap, and forward them when the wrapped renderers implement them, falling back to
+// Process otherwise. Such wrappers expose the renderers they wrap with an Unwrap() Renderer or an
+// Unwrap() []Renderer method: r then implements T only if one of the renderers it wraps does.
+func Implements[T Renderer](r Renderer) bool {
+	if _, ok := r.(T); !ok {
+		return false
+	}
+
+	switch w := r.(type) {
+	case interface{ Unwrap() Renderer }:
+		return Implements[T](w.Unwrap())
+	case interface{ Unwrap() []Renderer }:
+		for _, inner := range w.Unwrap() {
+			if Implements[T](inner) {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return true
+	}
+}
+
+// Hook is an extension point running custom logic around a render, such as seeding values or
+// auditing the output. Unlike filters and transformers, hooks see the values and the objects of
+// the whole render rather than one object at a time.
+type Hook interface {
+	// BeforeRender is called before any renderer runs with the render-time values, and returns the
+	// values to render with. It may modify and return the given map or return a new one.
+	BeforeRender(ctx context.Context, values map[string]any) (map[string]any, error)
+
+	// AfterRender is called with the final objects of the render, after all filters and transformers,
+	// and returns the objects to keep.
+	AfterRender(ctx context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error)
+}
+
 // ValidateRenderer checks if a Renderer implementation is valid.
 // Returns an error if the renderer is nil or if Name() returns an empty string.
 func ValidateRenderer(r Renderer) error {
@@ -53,3 +150,12 @@ func ValidateRenderer(r Renderer) error {
 
 	return nil
 }
+
+// ValidateStage checks that exactly one of the Filter and Transformer of a Stage is set.
+func ValidateStage(s Stage) error {
+	if (s.Filter == nil) == (s.Transformer == nil) {
+		return ErrStageInvalid
+	}
+
+	return nil
+}
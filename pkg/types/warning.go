@@ -0,0 +1,86 @@
+package types
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Warning is a non-fatal issue found during a render, such as a deprecated apiVersion or a missing
+// recommended label. Unlike errors, warnings never fail the render.
+type Warning struct {
+	// Code is a short, machine-readable identifier of the issue, e.g. "DeprecatedAPIVersion".
+	Code string
+
+	// Message is a human-readable description of the issue.
+	Message string
+
+	// Renderer is the name of the renderer being executed when the warning was emitted, if any.
+	Renderer string
+
+	// Object is the object the warning is about, nil if it is not about a single object.
+	Object *unstructured.Unstructured
+}
+
+func (w Warning) String() string {
+	if w.Object == nil {
+		return fmt.Sprintf("%s: %s", w.Code, w.Message)
+	}
+
+	return fmt.Sprintf(
+		"%s: %s (%s:%s %s, namespace: %s)",
+		w.Code,
+		w.Message,
+		w.Object.GroupVersionKind().GroupVersion(),
+		w.Object.GroupVersionKind().Kind,
+		w.Object.GetName(),
+		w.Object.GetNamespace(),
+	)
+}
+
+// WarningHandler receives the warnings emitted during a render.
+type WarningHandler func(warning Warning)
+
+type warningHandlerKey struct{}
+
+// WithWarningHandler returns a copy of ctx carrying handler, which receives the warnings emitted
+// with Warn. The engine sets it on the context of every Render() call.
+func WithWarningHandler(ctx context.Context, handler WarningHandler) context.Context {
+	return context.WithValue(ctx, warningHandlerKey{}, handler)
+}
+
+// Warn emits a warning to the handler carried by ctx, if any; otherwise the warning is discarded.
+// Renderers, filters, transformers and validators use it to report issues that should not fail the
+// render. The Renderer field is filled from ctx when empty (see RendererName).
+func Warn(ctx context.Context, warning Warning) {
+	handler := warningHandler(ctx)
+	if handler == nil {
+		return
+	}
+
+	if warning.Renderer == "" {
+		warning.Renderer, _ = RendererName(ctx)
+	}
+
+	handler(warning)
+}
+
+// WarnObject emits a warning about a copy of obj, see Warn.
+func WarnObject(ctx context.Context, obj unstructured.Unstructured, code string, message string) {
+	if warningHandler(ctx) == nil {
+		return
+	}
+
+	Warn(ctx, Warning{
+		Code:    code,
+		Message: message,
+		Object:  obj.DeepCopy(),
+	})
+}
+
+func warningHandler(ctx context.Context) WarningHandler {
+	handler, _ := ctx.Value(warningHandlerKey{}).(WarningHandler)
+
+	return handler
+}
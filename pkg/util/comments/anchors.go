@@ -0,0 +1,122 @@
+package comments
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// RestoreAnchors restores, in node, the anchors and aliases of document found at the same path.
+// Node is typically the result of Restore, or the encoding of the rendered object.
+//
+// An alias is restored only where the value of node still equals the anchored value, and only when
+// the anchor comes first in node, whose keys may be ordered differently from document; any other
+// alias stays expanded. Anchors left without alias are dropped. Merge keys ("<<") are not restored.
+func RestoreAnchors(node *yaml.Node, document string) error {
+	var source yaml.Node
+	if err := yaml.Unmarshal([]byte(document), &source); err != nil {
+		return fmt.Errorf("unable to decode source document: %w", err)
+	}
+
+	if source.Kind != yaml.DocumentNode || len(source.Content) == 0 {
+		return nil
+	}
+
+	dst := node
+	if dst.Kind == yaml.DocumentNode && len(dst.Content) > 0 {
+		dst = dst.Content[0]
+	}
+
+	r := anchorRestorer{
+		sources: make(map[*yaml.Node]*yaml.Node),
+		defined: make(map[*yaml.Node]*yaml.Node),
+		names:   make(map[string]bool),
+		used:    make(map[*yaml.Node]bool),
+	}
+
+	r.match(dst, source.Content[0])
+	r.restore(dst)
+
+	for _, anchored := range r.defined {
+		if !r.used[anchored] {
+			anchored.Anchor = ""
+		}
+	}
+
+	return nil
+}
+
+type anchorRestorer struct {
+	// sources maps the nodes of the rendered object to the source nodes found at the same path.
+	sources map[*yaml.Node]*yaml.Node
+	// defined maps the anchored source nodes to the rendered nodes carrying their anchor.
+	defined map[*yaml.Node]*yaml.Node
+	// names holds the anchor names already set, so a name is never defined twice.
+	names map[string]bool
+	// used holds the anchored rendered nodes referred to by an alias.
+	used map[*yaml.Node]bool
+}
+
+// match records the source node of dst and, recursively, of its children, like copyComments.
+func (r *anchorRestorer) match(dst *yaml.Node, src *yaml.Node) {
+	r.sources[dst] = src
+
+	switch {
+	case dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			for j := 0; j+1 < len(dst.Content); j += 2 {
+				if dst.Content[j].Value == src.Content[i].Value {
+					r.match(dst.Content[j+1], src.Content[i+1])
+
+					break
+				}
+			}
+		}
+	case dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode:
+		for i := 0; i < len(src.Content) && i < len(dst.Content); i++ {
+			r.match(dst.Content[i], src.Content[i])
+		}
+	}
+}
+
+// restore walks dst in emission order, setting anchors and replacing values with aliases.
+func (r *anchorRestorer) restore(dst *yaml.Node) {
+	if src, ok := r.sources[dst]; ok {
+		if src.Anchor != "" && !r.names[src.Anchor] {
+			dst.Anchor = src.Anchor
+			r.names[src.Anchor] = true
+			r.defined[src] = dst
+		}
+
+		if src.Kind == yaml.AliasNode {
+			if anchored, ok := r.defined[src.Alias]; ok && sameValue(dst, anchored) {
+				*dst = yaml.Node{
+					Kind:        yaml.AliasNode,
+					Value:       anchored.Anchor,
+					Alias:       anchored,
+					HeadComment: dst.HeadComment,
+					LineComment: dst.LineComment,
+					FootComment: dst.FootComment,
+				}
+				r.used[anchored] = true
+
+				return
+			}
+		}
+	}
+
+	for _, child := range dst.Content {
+		r.restore(child)
+	}
+}
+
+// sameValue reports whether a and b decode to the same value.
+func sameValue(a *yaml.Node, b *yaml.Node) bool {
+	var va, vb any
+	if a.Decode(&va) != nil || b.Decode(&vb) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,140 @@
+// Package comments carries the comments and anchors of source YAML documents through rendering, so that
+// the YAML writer can re-emit them. Preservation is best-effort: comments are matched to the fields of
+// the rendered object by path, so they survive untouched fields but are lost on removed or renamed ones.
+package comments
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.yaml.in/yaml/v3"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// Split returns the documents of a multi-document YAML stream, each re-encoded on its own with its
+// comments. Documents that are not mappings, such as empty ones, are skipped.
+func Split(content []byte) ([][]byte, error) {
+	documents := make([][]byte, 0)
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+
+	for i := 0; ; i++ {
+		var node yaml.Node
+		if err := dec.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				return documents, nil
+			}
+
+			return nil, fmt.Errorf("unable to decode YAML document[%d]: %w", i, err)
+		}
+
+		if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+
+		data, err := yaml.Marshal(&node)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode YAML document[%d]: %w", i, err)
+		}
+
+		documents = append(documents, data)
+	}
+}
+
+// Attach records document as the source of obj, in the types.AnnotationSourceComments annotation,
+// when it may contain comments or anchors. Other documents are not recorded.
+func Attach(obj *unstructured.Unstructured, document []byte) {
+	if !bytes.ContainsAny(document, "#&") {
+		return
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+
+	annotations[types.AnnotationSourceComments] = string(document)
+	obj.SetAnnotations(annotations)
+}
+
+// Detach returns a copy of the content of obj without the types.AnnotationSourceComments annotation,
+// and the source document it held, if any. The object is not modified.
+func Detach(obj unstructured.Unstructured) (map[string]any, string) {
+	document, ok := obj.GetAnnotations()[types.AnnotationSourceComments]
+	if !ok {
+		return obj.Object, ""
+	}
+
+	stripped := obj.DeepCopy()
+	annotations := stripped.GetAnnotations()
+	delete(annotations, types.AnnotationSourceComments)
+
+	if len(annotations) == 0 {
+		unstructured.RemoveNestedField(stripped.Object, "metadata", "annotations")
+	} else {
+		stripped.SetAnnotations(annotations)
+	}
+
+	return stripped.Object, document
+}
+
+// Restore returns content as a YAML node carrying the comments of the fields of document found at the
+// same path in content. Mapping entries are matched by key and sequence items by index.
+func Restore(content map[string]any, document string) (*yaml.Node, error) {
+	var node yaml.Node
+	if err := node.Encode(content); err != nil {
+		return nil, fmt.Errorf("unable to encode object: %w", err)
+	}
+
+	var source yaml.Node
+	if err := yaml.Unmarshal([]byte(document), &source); err != nil {
+		return nil, fmt.Errorf("unable to decode source document: %w", err)
+	}
+
+	if source.Kind == yaml.DocumentNode {
+		copyComments(&node, &source)
+
+		if len(source.Content) > 0 {
+			copyComments(&node, source.Content[0])
+		}
+	}
+
+	return &node, nil
+}
+
+// copyComments copies the comments of src, and recursively those of its children, to dst.
+func copyComments(dst *yaml.Node, src *yaml.Node) {
+	if dst.HeadComment == "" {
+		dst.HeadComment = src.HeadComment
+	}
+
+	if dst.LineComment == "" {
+		dst.LineComment = src.LineComment
+	}
+
+	if dst.FootComment == "" {
+		dst.FootComment = src.FootComment
+	}
+
+	switch {
+	case dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			for j := 0; j+1 < len(dst.Content); j += 2 {
+				if dst.Content[j].Value == src.Content[i].Value {
+					copyComments(dst.Content[j], src.Content[i])
+					copyComments(dst.Content[j+1], src.Content[i+1])
+
+					break
+				}
+			}
+		}
+	case dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode:
+		for i := 0; i < len(src.Content) && i < len(dst.Content); i++ {
+			copyComments(dst.Content[i], src.Content[i])
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package comments_test
+
+import (
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/comments"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSplit(t *testing.T) {
+
+	t.Run("should split documents keeping their comments", func(t *testing.T) {
+		g := NewWithT(t)
+
+		documents, err := comments.Split([]byte("# first\na: 1\n---\n---\nb: 2 # second\n"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(documents).Should(HaveLen(2))
+		g.Expect(string(documents[0])).Should(Equal("# first\na: 1\n"))
+		g.Expect(string(documents[1])).Should(Equal("b: 2 # second\n"))
+	})
+
+	t.Run("should identify invalid documents", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := comments.Split([]byte("a: 1\n---\nb: [unterminated\n"))
+		g.Expect(err).Should(MatchError(ContainSubstring("document[1]")))
+	})
+}
+
+func TestAttachDetach(t *testing.T) {
+
+	newObject := func() unstructured.Unstructured {
+		obj := unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName("app")
+
+		return obj
+	}
+
+	t.Run("should only record documents with comments or anchors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := newObject()
+		comments.Attach(&obj, []byte("kind: ConfigMap\n"))
+		g.Expect(obj.GetAnnotations()).Should(BeEmpty())
+
+		comments.Attach(&obj, []byte("kind: ConfigMap # config\n"))
+		g.Expect(obj.GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceComments, "kind: ConfigMap # config\n"))
+
+		comments.Attach(&obj, []byte("a: &x 1\nb: *x\n"))
+		g.Expect(obj.GetAnnotations()).Should(HaveKeyWithValue(types.AnnotationSourceComments, "a: &x 1\nb: *x\n"))
+	})
+
+	t.Run("should detach the document without modifying the object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := newObject()
+		obj.SetAnnotations(map[string]string{"team": "web"})
+		comments.Attach(&obj, []byte("kind: ConfigMap # config\n"))
+
+		content, document := comments.Detach(obj)
+		g.Expect(document).Should(Equal("kind: ConfigMap # config\n"))
+		g.Expect(content["metadata"]).Should(HaveKeyWithValue("annotations", map[string]any{"team": "web"}))
+		g.Expect(obj.GetAnnotations()).Should(HaveKey(types.AnnotationSourceComments))
+	})
+}
+
+func TestRestoreAnchors(t *testing.T) {
+
+	restore := func(g *WithT, content map[string]any, document string) string {
+		var node yaml.Node
+		g.Expect(node.Encode(content)).Should(Succeed())
+		g.Expect(comments.RestoreAnchors(&node, document)).Should(Succeed())
+
+		data, err := yaml.Marshal(&node)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		return string(data)
+	}
+
+	t.Run("should restore an alias following its anchor", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out := restore(g, map[string]any{"a": 1, "b": 1}, "a: &x 1\nb: *x\n")
+		g.Expect(out).Should(Equal("a: &x 1\nb: *x\n"))
+	})
+
+	t.Run("should expand an alias preceding its anchor once sorted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out := restore(g, map[string]any{"a": 1, "b": 1}, "b: &x 1\na: *x\n")
+		g.Expect(out).Should(Equal("a: 1\nb: 1\n"))
+	})
+}
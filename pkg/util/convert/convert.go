@@ -0,0 +1,93 @@
+package convert
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// ErrKindMismatch is returned when the kind of an object is not the kind registered for the target type.
+var ErrKindMismatch = errors.New("object kind does not match the target type")
+
+// As converts obj to a typed object, e.g. As[appsv1.Deployment](obj).
+//
+// When T is registered in the client-go scheme, the GroupVersionKind of obj must be one of the kinds
+// registered for T, so that a Service is never silently converted to a Deployment. Types unknown to
+// the scheme, such as custom resource types, are converted without this check.
+// Errors identify the object that could not be converted.
+func As[T any](obj unstructured.Unstructured) (*T, error) {
+	target := new(T)
+
+	if err := checkKind(obj.GroupVersionKind(), target); err != nil {
+		return nil, wrap(obj, target, err)
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, target); err != nil {
+		return nil, wrap(obj, target, err)
+	}
+
+	return target, nil
+}
+
+// FindAs converts the objects of objs whose GroupVersionKind is gvk to typed objects, in order.
+// It returns the first conversion error encountered.
+func FindAs[T any](objs []unstructured.Unstructured, gvk schema.GroupVersionKind) ([]*T, error) {
+	result := make([]*T, 0)
+
+	for _, obj := range objs {
+		if obj.GroupVersionKind() != gvk {
+			continue
+		}
+
+		typed, err := As[T](obj)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, typed)
+	}
+
+	return result, nil
+}
+
+// checkKind verifies that gvk is one of the kinds registered in the scheme for target.
+func checkKind(gvk schema.GroupVersionKind, target any) error {
+	ro, ok := target.(runtime.Object)
+	if !ok {
+		return nil
+	}
+
+	kinds, _, err := scheme.Scheme.ObjectKinds(ro)
+	if err != nil {
+		if runtime.IsNotRegisteredError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, kind := range kinds {
+		if kind == gvk {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: expected %s", ErrKindMismatch, kinds[0])
+}
+
+// wrap annotates err with the identity of obj and the target type.
+func wrap(obj unstructured.Unstructured, target any, err error) error {
+	return fmt.Errorf(
+		"unable to convert %s:%s %s (namespace: %s) to %T: %w",
+		obj.GroupVersionKind().GroupVersion(),
+		obj.GroupVersionKind().Kind,
+		obj.GetName(),
+		obj.GetNamespace(),
+		target,
+		err,
+	)
+}
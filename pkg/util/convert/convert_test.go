@@ -0,0 +1,129 @@
+package convert_test
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/util/convert"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeDeployment(name string, replicas int64) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+		"spec":       map[string]any{"replicas": replicas},
+	}}
+}
+
+func makeService(name string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]any{"name": name},
+	}}
+}
+
+func TestAs(t *testing.T) {
+
+	t.Run("should convert to the registered type", func(t *testing.T) {
+		g := NewWithT(t)
+
+		deployment, err := convert.As[appsv1.Deployment](makeDeployment("app", 3))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(deployment.Name).Should(Equal("app"))
+		g.Expect(deployment.Namespace).Should(Equal("default"))
+		g.Expect(deployment.Spec.Replicas).ShouldNot(BeNil())
+		g.Expect(*deployment.Spec.Replicas).Should(Equal(int32(3)))
+	})
+
+	t.Run("should reject an object of another kind", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := convert.As[appsv1.Deployment](makeService("svc"))
+		g.Expect(err).Should(MatchError(convert.ErrKindMismatch))
+		g.Expect(err).Should(MatchError(ContainSubstring("v1:Service svc")))
+	})
+
+	t.Run("should identify the object when decoding fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := makeDeployment("app", 1)
+		obj.Object["spec"] = map[string]any{"replicas": "three"}
+
+		_, err := convert.As[appsv1.Deployment](obj)
+		g.Expect(err).Should(HaveOccurred())
+		g.Expect(err).Should(MatchError(ContainSubstring("apps/v1:Deployment app (namespace: default)")))
+	})
+
+	t.Run("should convert to a type unknown to the scheme", func(t *testing.T) {
+		g := NewWithT(t)
+
+		type widget struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		}
+
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]any{"name": "w1"},
+		}}
+
+		w, err := convert.As[widget](obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(w.Kind).Should(Equal("Widget"))
+		g.Expect(w.Metadata.Name).Should(Equal("w1"))
+	})
+}
+
+func TestFindAs(t *testing.T) {
+
+	t.Run("should convert the objects of the given kind in order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objs := []unstructured.Unstructured{
+			makeDeployment("app1", 1),
+			makeService("svc"),
+			makeDeployment("app2", 2),
+		}
+
+		deployments, err := convert.FindAs[appsv1.Deployment](objs, appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(deployments).Should(HaveLen(2))
+		g.Expect(deployments[0].Name).Should(Equal("app1"))
+		g.Expect(deployments[1].Name).Should(Equal("app2"))
+
+		services, err := convert.FindAs[corev1.Service](objs, corev1.SchemeGroupVersion.WithKind("Service"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(services).Should(HaveLen(1))
+	})
+
+	t.Run("should return an empty slice when nothing matches", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pods, err := convert.FindAs[corev1.Pod](
+			[]unstructured.Unstructured{makeService("svc")},
+			corev1.SchemeGroupVersion.WithKind("Pod"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(pods).Should(BeEmpty())
+	})
+
+	t.Run("should fail when the gvk does not match the target type", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := convert.FindAs[appsv1.Deployment](
+			[]unstructured.Unstructured{makeService("svc")},
+			corev1.SchemeGroupVersion.WithKind("Service"),
+		)
+		g.Expect(err).Should(MatchError(convert.ErrKindMismatch))
+	})
+}
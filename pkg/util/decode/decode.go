@@ -0,0 +1,139 @@
+// Package decode provides the YAML decoding shared by renderers reading manifests, so that they
+// handle empty documents, Lists and comments consistently.
+package decode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/k8s-manifest-kit/pkg/util/k8s"
+	"go.yaml.in/yaml/v3"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/util/comments"
+	"github.com/k8s-manifest-kit/engine/pkg/util/list"
+)
+
+// ErrEmptyDocument is returned, with WithEmptyError, for a document without content, e.g. between
+// two "---" separators, after a trailing one, or holding only comments.
+var ErrEmptyDocument = errors.New("empty YAML document")
+
+// YAML decodes the objects of a multi-document YAML or JSON stream, flattening List objects
+// (see list.Flatten).
+//
+// Empty documents, including "{}" and "null", are skipped unless WithEmptyError is set. Documents
+// without apiVersion or kind are always skipped, and a document that is not a mapping fails decoding.
+func YAML(content []byte, opts ...Option) ([]unstructured.Unstructured, error) {
+	dec := NewDecoder(bytes.NewReader(content), opts...)
+	objects := make([]unstructured.Unstructured, 0)
+
+	for {
+		decoded, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, decoded...)
+	}
+}
+
+// Decoder decodes the objects of a multi-document YAML or JSON stream one document at a time, so
+// that only the document being decoded is held in memory. It applies the same rules as YAML.
+type Decoder struct {
+	dec     *yaml.Decoder
+	options Options
+	index   int
+}
+
+// NewDecoder returns a decoder reading the documents of r.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	options := Options{
+		SkipEmpty: true,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return &Decoder{
+		dec:     yaml.NewDecoder(r),
+		options: options,
+	}
+}
+
+// Next returns the objects of the next document holding any, more than one for a List, skipping
+// the documents that hold none. It returns io.EOF once the stream is exhausted.
+//
+// Errors name the index of the failing document in the stream and, when the document could be
+// parsed, the line it starts at.
+func (d *Decoder) Next() ([]unstructured.Unstructured, error) {
+	for {
+		objects, err := d.decode(d.index)
+		d.index++
+
+		if err != nil || len(objects) > 0 {
+			return objects, err
+		}
+	}
+}
+
+// decode decodes the document i, the next one of the stream.
+func (d *Decoder) decode(i int) ([]unstructured.Unstructured, error) {
+	var node yaml.Node
+	if err := d.dec.Decode(&node); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+
+		return nil, fmt.Errorf("unable to decode YAML document[%d]: %w", i, err)
+	}
+
+	var content map[string]any
+	if err := node.Decode(&content); err != nil {
+		return nil, fmt.Errorf("unable to decode YAML document[%d] at line %d: %w", i, node.Line, err)
+	}
+
+	if len(content) == 0 {
+		if d.options.EmptyError || !d.options.SkipEmpty {
+			return nil, fmt.Errorf("%w: document[%d]", ErrEmptyDocument, i)
+		}
+
+		return nil, nil
+	}
+
+	if kind, ok := content["kind"].(string); !ok || kind == "" {
+		return nil, nil
+	}
+
+	if apiVersion, ok := content["apiVersion"].(string); !ok || apiVersion == "" {
+		return nil, nil
+	}
+
+	obj, err := k8s.ToUnstructured(&content)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode YAML document[%d] at line %d: %w", i, node.Line, err)
+	}
+
+	if d.options.Comments && !list.IsList(*obj) {
+		document, err := yaml.Marshal(&node)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode YAML document[%d]: %w", i, err)
+		}
+
+		comments.Attach(obj, document)
+	}
+
+	flattened, err := list.Flatten([]unstructured.Unstructured{*obj})
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode YAML document[%d] at line %d: %w", i, node.Line, err)
+	}
+
+	return flattened, nil
+}
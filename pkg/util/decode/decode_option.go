@@ -0,0 +1,51 @@
+package decode
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of YAML decoding.
+type Options struct {
+	// SkipEmpty silently skips empty documents. Defaults to true.
+	SkipEmpty bool
+
+	// EmptyError fails decoding on the first empty document. It takes precedence over SkipEmpty.
+	EmptyError bool
+
+	// Comments records the source document of each object, see WithComments.
+	Comments bool
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	target.SkipEmpty = opts.SkipEmpty
+	target.EmptyError = opts.EmptyError
+	target.Comments = opts.Comments
+}
+
+// WithSkipEmpty enables or disables silently skipping empty documents, such as those of conditionally
+// empty Helm templates. When disabled, an empty document fails decoding, as with WithEmptyError(true).
+func WithSkipEmpty(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.SkipEmpty = enabled
+	})
+}
+
+// WithEmptyError enables or disables failing decoding on the first empty document, with an error
+// wrapping ErrEmptyDocument, to catch a stray "---" or a template that unexpectedly renders nothing.
+func WithEmptyError(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.EmptyError = enabled
+	})
+}
+
+// WithComments enables or disables recording the source document of each object decoded from a document
+// with comments or anchors, see comments.Attach. Objects flattened from a List are not recorded.
+func WithComments(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Comments = enabled
+	})
+}
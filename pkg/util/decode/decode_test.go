@@ -0,0 +1,172 @@
+package decode_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/util/comments"
+	"github.com/k8s-manifest-kit/engine/pkg/util/decode"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	twoObjects = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+# the second one
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+---
+`
+
+	emptyDocuments = `---
+---
+# nothing to see
+---
+{}
+`
+
+	listDocument = `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: listed
+`
+)
+
+func names(objects []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, obj.GetName())
+	}
+
+	return result
+}
+
+func TestYAML(t *testing.T) {
+
+	t.Run("should decode multi-document streams", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := decode.YAML([]byte(twoObjects))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"first", "second"}))
+	})
+
+	t.Run("should skip a stream made only of empty documents", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := decode.YAML([]byte(emptyDocuments))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(BeEmpty())
+	})
+
+	t.Run("should fail on empty documents with WithEmptyError", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := decode.YAML([]byte(emptyDocuments), decode.WithEmptyError(true))
+		g.Expect(err).Should(MatchError(decode.ErrEmptyDocument))
+
+		_, err = decode.YAML([]byte(twoObjects), decode.WithEmptyError(true))
+		g.Expect(err).Should(MatchError(decode.ErrEmptyDocument))
+		g.Expect(err.Error()).Should(ContainSubstring("document[2]"))
+	})
+
+	t.Run("should fail on empty documents when not skipping them", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := decode.YAML([]byte(emptyDocuments), decode.WithSkipEmpty(false))
+		g.Expect(err).Should(MatchError(decode.ErrEmptyDocument))
+	})
+
+	t.Run("should accept empty input", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := decode.YAML(nil, decode.WithEmptyError(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(BeEmpty())
+	})
+
+	t.Run("should skip documents without kind or apiVersion", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := decode.YAML([]byte("foo: bar\n---\nkind: ConfigMap\n"), decode.WithEmptyError(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(BeEmpty())
+	})
+
+	t.Run("should flatten list objects into their items", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := decode.YAML([]byte(listDocument))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"listed"}))
+	})
+
+	t.Run("should record commented documents with WithComments", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := decode.YAML([]byte(twoObjects), decode.WithComments(true))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+
+		_, first := comments.Detach(objects[0])
+		g.Expect(first).Should(BeEmpty())
+
+		_, second := comments.Detach(objects[1])
+		g.Expect(second).Should(ContainSubstring("# the second one"))
+	})
+
+	t.Run("should fail on invalid YAML", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := decode.YAML([]byte("kind: [unterminated"))
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
+
+func TestDecoder(t *testing.T) {
+
+	t.Run("should return the objects one document at a time", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dec := decode.NewDecoder(strings.NewReader(twoObjects))
+
+		objects, err := dec.Next()
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"first"}))
+
+		objects, err = dec.Next()
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"second"}))
+
+		_, err = dec.Next()
+		g.Expect(err).Should(MatchError(io.EOF))
+	})
+
+	t.Run("should name the document and line of decoding errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dec := decode.NewDecoder(strings.NewReader(twoObjects + "- not\n- a mapping\n"))
+
+		for range 2 {
+			_, err := dec.Next()
+			g.Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		_, err := dec.Next()
+		g.Expect(err).Should(MatchError(ContainSubstring("document[2] at line 12")))
+	})
+}
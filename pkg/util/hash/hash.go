@@ -0,0 +1,93 @@
+// Package hash computes stable content hashes of rendered objects, for caching and change detection.
+package hash
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/normalize"
+)
+
+// entry is an object with its sort key and canonical encoding.
+type entry struct {
+	group     string
+	version   string
+	kind      string
+	namespace string
+	name      string
+	data      []byte
+}
+
+// Objects returns the hex-encoded SHA-256 hash of the logical content of objs.
+//
+// The hash does not depend on the order of objs, nor on the order of map keys: objects are sorted by
+// group, version, kind, namespace and name (then by content, should several share the same identity)
+// and each is encoded as JSON with sorted keys. Fields populated by the API server (see
+// normalize.DefaultFields) are ignored, so an object read back from a cluster hashes like the
+// rendered one. The input objects are not modified.
+func Objects(objs []unstructured.Unstructured) (string, error) {
+	paths := make([][]string, 0, len(normalize.DefaultFields()))
+	for _, field := range normalize.DefaultFields() {
+		paths = append(paths, strings.Split(field, "."))
+	}
+
+	entries := make([]entry, 0, len(objs))
+
+	for _, obj := range objs {
+		content := obj.DeepCopy().Object
+		for _, path := range paths {
+			unstructured.RemoveNestedField(content, path...)
+		}
+
+		data, err := json.Marshal(content)
+		if err != nil {
+			return "", fmt.Errorf(
+				"unable to encode %s:%s %s (namespace: %s): %w",
+				obj.GroupVersionKind().GroupVersion(),
+				obj.GroupVersionKind().Kind,
+				obj.GetName(),
+				obj.GetNamespace(),
+				err,
+			)
+		}
+
+		gvk := obj.GroupVersionKind()
+
+		entries = append(entries, entry{
+			group:     gvk.Group,
+			version:   gvk.Version,
+			kind:      gvk.Kind,
+			namespace: obj.GetNamespace(),
+			name:      obj.GetName(),
+			data:      data,
+		})
+	}
+
+	slices.SortFunc(entries, func(a entry, b entry) int {
+		return cmp.Or(
+			cmp.Compare(a.group, b.group),
+			cmp.Compare(a.version, b.version),
+			cmp.Compare(a.kind, b.kind),
+			cmp.Compare(a.namespace, b.namespace),
+			cmp.Compare(a.name, b.name),
+			bytes.Compare(a.data, b.data),
+		)
+	})
+
+	h := sha256.New()
+	for _, e := range entries {
+		// JSON documents never contain a raw newline, so it unambiguously separates objects.
+		h.Write(e.data)
+		h.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,114 @@
+package hash_test
+
+import (
+	"math"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/util/hash"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestObjects(t *testing.T) {
+
+	t.Run("should not depend on the order of objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		h1, err := hash.Objects([]unstructured.Unstructured{
+			makeConfigMap("a", "default", "1"),
+			makeConfigMap("b", "default", "2"),
+			makeConfigMap("a", "other", "3"),
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		h2, err := hash.Objects([]unstructured.Unstructured{
+			makeConfigMap("a", "other", "3"),
+			makeConfigMap("b", "default", "2"),
+			makeConfigMap("a", "default", "1"),
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(h1).Should(Equal(h2))
+		g.Expect(h1).Should(HaveLen(64))
+	})
+
+	t.Run("should not depend on the order of objects sharing an identity", func(t *testing.T) {
+		g := NewWithT(t)
+
+		h1, err := hash.Objects([]unstructured.Unstructured{makeConfigMap("a", "", "1"), makeConfigMap("a", "", "2")})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		h2, err := hash.Objects([]unstructured.Unstructured{makeConfigMap("a", "", "2"), makeConfigMap("a", "", "1")})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(h1).Should(Equal(h2))
+	})
+
+	t.Run("should ignore server-populated fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		rendered := makeConfigMap("a", "default", "1")
+
+		live := makeConfigMap("a", "default", "1")
+		live.SetUID("4f1d6c1e")
+		live.SetResourceVersion("42")
+		live.Object["status"] = map[string]any{"phase": "Active"}
+
+		h1, err := hash.Objects([]unstructured.Unstructured{rendered})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		h2, err := hash.Objects([]unstructured.Unstructured{live})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(h1).Should(Equal(h2))
+
+		g.Expect(live.GetUID()).Should(BeEquivalentTo("4f1d6c1e"))
+	})
+
+	t.Run("should change with the content", func(t *testing.T) {
+		g := NewWithT(t)
+
+		h1, err := hash.Objects([]unstructured.Unstructured{makeConfigMap("a", "default", "1")})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		h2, err := hash.Objects([]unstructured.Unstructured{makeConfigMap("a", "default", "2")})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(h1).ShouldNot(Equal(h2))
+
+		h3, err := hash.Objects([]unstructured.Unstructured{
+			makeConfigMap("a", "default", "1"),
+			makeConfigMap("a", "default", "1"),
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(h1).ShouldNot(Equal(h3))
+	})
+
+	t.Run("should hash an empty set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		h, err := hash.Objects(nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(h).Should(Equal("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"))
+	})
+
+	t.Run("should identify objects that cannot be encoded", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := makeConfigMap("a", "default", "1")
+		obj.Object["spec"] = map[string]any{"ratio": math.NaN()}
+
+		_, err := hash.Objects([]unstructured.Unstructured{obj})
+		g.Expect(err).Should(MatchError(ContainSubstring("v1:ConfigMap a (namespace: default)")))
+	})
+}
+
+func makeConfigMap(name string, namespace string, value string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"data": map[string]any{"value": value},
+	}}
+}
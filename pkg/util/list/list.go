@@ -0,0 +1,94 @@
+// Package list flattens Kubernetes list objects, such as v1/List or ConfigMapList, into their items.
+package list
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const listSuffix = "List"
+
+var (
+	// ErrInvalidItem is returned when an item of a list is not a Kubernetes object.
+	ErrInvalidItem = errors.New("invalid list item")
+)
+
+// IsList reports whether obj is a list object: its kind ends with "List" and it has an items array.
+func IsList(obj unstructured.Unstructured) bool {
+	if !strings.HasSuffix(obj.GetKind(), listSuffix) {
+		return false
+	}
+
+	_, ok := obj.Object["items"].([]any)
+
+	return ok
+}
+
+// Flatten returns objects with every list object replaced by its items, in order. Lists nested in a
+// list are flattened recursively, and other objects are returned unchanged.
+//
+// Items keep their own apiVersion and kind. Items of a typed list (e.g. ConfigMapList) may omit them,
+// as in API responses, in which case they are inferred from the list: the list's apiVersion and its
+// kind without the "List" suffix. An item of a generic v1/List must set its kind.
+func Flatten(objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	result := make([]unstructured.Unstructured, 0, len(objects))
+
+	for _, obj := range objects {
+		var err error
+
+		result, err = flatten(obj, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func flatten(obj unstructured.Unstructured, result []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	if !IsList(obj) {
+		return append(result, obj), nil
+	}
+
+	items, _ := obj.Object["items"].([]any)
+
+	itemKind := ""
+	if obj.GetKind() != listSuffix {
+		itemKind = strings.TrimSuffix(obj.GetKind(), listSuffix)
+	}
+
+	for i, item := range items {
+		content, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s %s items[%d] is a %T, expected an object",
+				ErrInvalidItem, obj.GetAPIVersion(), obj.GetKind(), i, item)
+		}
+
+		child := unstructured.Unstructured{Object: content}
+
+		if child.GetAPIVersion() == "" {
+			child.SetAPIVersion(obj.GetAPIVersion())
+		}
+
+		if child.GetKind() == "" {
+			child.SetKind(itemKind)
+		}
+
+		if child.GetKind() == "" {
+			return nil, fmt.Errorf("%w: %s %s items[%d] has no kind",
+				ErrInvalidItem, obj.GetAPIVersion(), obj.GetKind(), i)
+		}
+
+		var err error
+
+		result, err = flatten(child, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package list_test
+
+import (
+	"testing"
+
+	"github.com/k8s-manifest-kit/pkg/util/k8s"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/util/list"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	genericList = `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: cm1
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: app
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`
+
+	typedList = `
+apiVersion: v1
+kind: ConfigMapList
+items:
+- metadata:
+    name: cm1
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: cm2
+`
+
+	nestedList = `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: cm1
+- apiVersion: v1
+  kind: List
+  items:
+  - apiVersion: v1
+    kind: SecretList
+    items:
+    - metadata:
+        name: secret1
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: cm2
+`
+)
+
+func TestFlatten(t *testing.T) {
+
+	decode := func(g *WithT, yaml string) []unstructured.Unstructured {
+		objects, err := k8s.DecodeYAML([]byte(yaml))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		return objects
+	}
+
+	identities := func(objects []unstructured.Unstructured) []string {
+		result := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			result = append(result, obj.GetAPIVersion()+":"+obj.GetKind()+":"+obj.GetName())
+		}
+
+		return result
+	}
+
+	t.Run("should flatten a v1/List keeping the items' own kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := list.Flatten(decode(g, genericList))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(identities(objects)).Should(Equal([]string{
+			"v1:ConfigMap:cm1",
+			"apps/v1:Deployment:app",
+			"v1:Service:svc",
+		}))
+	})
+
+	t.Run("should infer the kind of typed list items", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := list.Flatten(decode(g, typedList))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(identities(objects)).Should(Equal([]string{
+			"v1:ConfigMap:cm1",
+			"v1:ConfigMap:cm2",
+		}))
+	})
+
+	t.Run("should flatten nested lists recursively", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := list.Flatten(decode(g, nestedList))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(identities(objects)).Should(Equal([]string{
+			"v1:ConfigMap:cm1",
+			"v1:Secret:secret1",
+			"v1:ConfigMap:cm2",
+		}))
+	})
+
+	t.Run("should drop empty lists", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := list.Flatten(decode(g, "apiVersion: v1\nkind: List\nitems: []\n"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(BeEmpty())
+	})
+
+	t.Run("should leave kinds ending with List without items unchanged", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.com/v1",
+			"kind":       "AccessList",
+			"metadata":   map[string]any{"name": "allowed"},
+		}}
+
+		objects, err := list.Flatten([]unstructured.Unstructured{obj})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(Equal([]unstructured.Unstructured{obj}))
+	})
+
+	t.Run("should reject invalid items", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := list.Flatten(decode(g, "apiVersion: v1\nkind: List\nitems:\n- metadata:\n    name: x\n"))
+		g.Expect(err).Should(MatchError(list.ErrInvalidItem))
+		g.Expect(err.Error()).Should(ContainSubstring("items[0] has no kind"))
+
+		_, err = list.Flatten(decode(g, "apiVersion: v1\nkind: List\nitems:\n- not-an-object\n"))
+		g.Expect(err).Should(MatchError(list.ErrInvalidItem))
+	})
+}
@@ -0,0 +1,145 @@
+// Package podspec locates the pod template and pod spec of the built-in Kubernetes workload kinds,
+// for transformers editing the pods of workloads.
+package podspec
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+)
+
+// templatePaths maps the workload kinds holding a pod template to the path of that template.
+//
+//nolint:gochecknoglobals
+var templatePaths = map[schema.GroupKind][]string{
+	{Group: "apps", Kind: "Deployment"}:        {"spec", "template"},
+	{Group: "apps", Kind: "StatefulSet"}:       {"spec", "template"},
+	{Group: "apps", Kind: "DaemonSet"}:         {"spec", "template"},
+	{Group: "apps", Kind: "ReplicaSet"}:        {"spec", "template"},
+	{Group: "batch", Kind: "Job"}:              {"spec", "template"},
+	{Group: "batch", Kind: "CronJob"}:          {"spec", "jobTemplate", "spec", "template"},
+	{Group: "", Kind: "ReplicationController"}: {"spec", "template"},
+}
+
+// pod is the kind whose spec is a pod spec, without template.
+//
+//nolint:gochecknoglobals
+var pod = schema.GroupKind{Group: "", Kind: "Pod"}
+
+// TemplatePath returns the path of the pod template of the objects of kind gk, and whether gk is
+// a workload holding one: Deployment, StatefulSet, DaemonSet, ReplicaSet, ReplicationController,
+// Job or CronJob. The returned slice is a copy the caller may modify.
+func TemplatePath(gk schema.GroupKind) ([]string, bool) {
+	path, ok := templatePaths[gk]
+	if !ok {
+		return nil, false
+	}
+
+	return slices.Clone(path), true
+}
+
+// Path returns the path of the pod spec of the objects of kind gk, and whether gk is a Pod or one
+// of the workloads supported by TemplatePath. The returned slice is a copy the caller may modify.
+func Path(gk schema.GroupKind) ([]string, bool) {
+	if gk == pod {
+		return []string{"spec"}, true
+	}
+
+	path, ok := TemplatePath(gk)
+	if !ok {
+		return nil, false
+	}
+
+	return append(path, "spec"), true
+}
+
+// Kinds returns the kinds supported by Path, in a stable order.
+func Kinds() []schema.GroupKind {
+	kinds := slices.Collect(maps.Keys(templatePaths))
+	kinds = append(kinds, pod)
+
+	slices.SortFunc(kinds, func(a schema.GroupKind, b schema.GroupKind) int {
+		if a.Group != b.Group {
+			return cmp.Compare(a.Group, b.Group)
+		}
+
+		return cmp.Compare(a.Kind, b.Kind)
+	})
+
+	return kinds
+}
+
+// Transformer returns a transformer calling fn with a copy of the pod spec of the objects supported
+// by Path, or with an empty map if they have none. The spec is only written back when fn reports a
+// change, so that objects fn leaves alone, like objects of other kinds, are returned untouched.
+func Transformer(fn func(spec map[string]any) (bool, error)) types.Transformer {
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		path, ok := Path(obj.GroupVersionKind().GroupKind())
+		if !ok {
+			return obj, nil
+		}
+
+		spec, found, err := unstructured.NestedMap(obj.Object, path...)
+		if err != nil {
+			return obj, fmt.Errorf("failed to read %v: %w", path, err)
+		}
+
+		if !found {
+			spec = make(map[string]any)
+		}
+
+		changed, err := fn(spec)
+		if err != nil || !changed {
+			return obj, err
+		}
+
+		if err := unstructured.SetNestedMap(obj.Object, spec, path...); err != nil {
+			return obj, fmt.Errorf("failed to set %v: %w", path, err)
+		}
+
+		return obj, nil
+	}
+}
+
+// UpdateContainers calls fn with each container of the lists of spec named by fields, e.g.
+// "containers" and "initContainers", and reports whether it found any. The containers are updated
+// in place in spec; items that are not objects are skipped.
+func UpdateContainers(spec map[string]any, fields []string, fn func(container map[string]any) error) (bool, error) {
+	found := false
+
+	for _, field := range fields {
+		containers, ok, err := unstructured.NestedSlice(spec, field)
+		if err != nil {
+			return false, fmt.Errorf("failed to read %s: %w", field, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		for i, c := range containers {
+			container, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			if err := fn(container); err != nil {
+				return false, err
+			}
+
+			containers[i] = container
+			found = true
+		}
+
+		spec[field] = containers
+	}
+
+	return found, nil
+}
@@ -0,0 +1,186 @@
+package podspec_test
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/k8s-manifest-kit/engine/pkg/util/podspec"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPath(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		gk       schema.GroupKind
+		template []string
+		spec     []string
+	}{
+		{name: "pod", gk: schema.GroupKind{Kind: "Pod"}, spec: []string{"spec"}},
+		{
+			name:     "deployment",
+			gk:       schema.GroupKind{Group: "apps", Kind: "Deployment"},
+			template: []string{"spec", "template"},
+			spec:     []string{"spec", "template", "spec"},
+		},
+		{
+			name:     "cron job",
+			gk:       schema.GroupKind{Group: "batch", Kind: "CronJob"},
+			template: []string{"spec", "jobTemplate", "spec", "template"},
+			spec:     []string{"spec", "jobTemplate", "spec", "template", "spec"},
+		},
+		{name: "service", gk: schema.GroupKind{Kind: "Service"}},
+		{name: "kind in the wrong group", gk: schema.GroupKind{Group: "example.com", Kind: "Deployment"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			template, ok := podspec.TemplatePath(tt.gk)
+			g.Expect(ok).Should(Equal(tt.template != nil))
+			g.Expect(template).Should(Equal(tt.template))
+
+			spec, ok := podspec.Path(tt.gk)
+			g.Expect(ok).Should(Equal(tt.spec != nil))
+			g.Expect(spec).Should(Equal(tt.spec))
+		})
+	}
+
+	t.Run("should return copies", func(t *testing.T) {
+		g := NewWithT(t)
+
+		path, _ := podspec.TemplatePath(schema.GroupKind{Group: "apps", Kind: "Deployment"})
+		path[0] = "changed"
+
+		path, _ = podspec.Path(schema.GroupKind{Group: "apps", Kind: "Deployment"})
+		g.Expect(path).Should(Equal([]string{"spec", "template", "spec"}))
+	})
+
+	t.Run("should list every kind with a pod spec", func(t *testing.T) {
+		g := NewWithT(t)
+
+		kinds := podspec.Kinds()
+		g.Expect(kinds).Should(HaveLen(8))
+		g.Expect(kinds[0]).Should(Equal(schema.GroupKind{Kind: "Pod"}))
+
+		for _, gk := range kinds {
+			_, ok := podspec.Path(gk)
+			g.Expect(ok).Should(BeTrue(), gk.String())
+		}
+	})
+}
+
+func TestTransformer(t *testing.T) {
+
+	setHostname := podspec.Transformer(func(spec map[string]any) (bool, error) {
+		if spec["hostname"] == "web" {
+			return false, nil
+		}
+
+		spec["hostname"] = "web"
+
+		return true, nil
+	})
+
+	t.Run("should write back the changed pod spec", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := cronJob()
+
+		result, err := setHostname(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		hostname, _, _ := unstructured.NestedString(result.Object, "spec", "jobTemplate", "spec", "template", "spec", "hostname")
+		g.Expect(hostname).Should(Equal("web"))
+	})
+
+	t.Run("should leave objects untouched without change", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := cronJob()
+		original := obj.DeepCopy()
+
+		result, err := podspec.Transformer(func(_ map[string]any) (bool, error) {
+			return false, nil
+		})(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(original.Object))
+	})
+
+	t.Run("should pass other kinds through", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("Service")
+		original := obj.DeepCopy()
+
+		result, err := setHostname(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(Equal(original.Object))
+	})
+
+	t.Run("should return errors of fn", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := podspec.Transformer(func(_ map[string]any) (bool, error) {
+			return false, errors.New("boom")
+		})(t.Context(), cronJob())
+		g.Expect(err).Should(MatchError("boom"))
+	})
+}
+
+func TestUpdateContainers(t *testing.T) {
+
+	t.Run("should update the containers of the given lists", func(t *testing.T) {
+		g := NewWithT(t)
+
+		spec := map[string]any{
+			"containers":     []any{map[string]any{"name": "app"}, "invalid"},
+			"initContainers": []any{map[string]any{"name": "init"}},
+		}
+
+		found, err := podspec.UpdateContainers(spec, []string{"containers"}, func(container map[string]any) error {
+			container["image"] = "nginx"
+
+			return nil
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(found).Should(BeTrue())
+		g.Expect(spec["containers"]).Should(Equal([]any{map[string]any{"name": "app", "image": "nginx"}, "invalid"}))
+		g.Expect(spec["initContainers"]).Should(Equal([]any{map[string]any{"name": "init"}}))
+	})
+
+	t.Run("should report specs without containers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		found, err := podspec.UpdateContainers(map[string]any{}, []string{"containers"}, func(_ map[string]any) error {
+			return errors.New("unexpected call")
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(found).Should(BeFalse())
+	})
+
+	t.Run("should fail on malformed lists", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := podspec.UpdateContainers(map[string]any{"containers": "app"}, []string{"containers"}, func(_ map[string]any) error {
+			return nil
+		})
+		g.Expect(err).Should(MatchError(ContainSubstring("failed to read containers")))
+	})
+}
+
+func cronJob() unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion("batch/v1")
+	obj.SetKind("CronJob")
+	obj.SetName("backup")
+
+	return obj
+}
@@ -0,0 +1,34 @@
+package scope_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/k8s-manifest-kit/engine/pkg/util/scope"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestIsClusterScoped(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name     string
+		gk       schema.GroupKind
+		expected bool
+	}{
+		{name: "namespace", gk: schema.GroupKind{Kind: "Namespace"}, expected: true},
+		{name: "cluster role", gk: schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}, expected: true},
+		{name: "crd", gk: schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}, expected: true},
+		{name: "pod", gk: schema.GroupKind{Kind: "Pod"}, expected: false},
+		{name: "deployment", gk: schema.GroupKind{Group: "apps", Kind: "Deployment"}, expected: false},
+		{name: "kind in the wrong group", gk: schema.GroupKind{Group: "example.com", Kind: "Namespace"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.Expect(scope.IsClusterScoped(tt.gk)).Should(Equal(tt.expected))
+		})
+	}
+}
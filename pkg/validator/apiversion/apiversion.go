@@ -0,0 +1,150 @@
+// Package apiversion provides a validator rejecting objects whose apiVersion is no longer served by
+// the Kubernetes version a render targets, such as extensions/v1beta1 Ingresses on Kubernetes 1.22+.
+package apiversion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/validator"
+)
+
+var (
+	// ErrRemoved is returned for an object whose apiVersion is removed in the targeted Kubernetes version.
+	ErrRemoved = errors.New("apiVersion is not served by the targeted Kubernetes version")
+
+	// ErrInvalidVersion is returned by Supported when a version set with its options cannot be parsed.
+	ErrInvalidVersion = errors.New("invalid Kubernetes version")
+)
+
+// removals are the Kubernetes versions removing well-known built-in apiVersions, keyed by the
+// removed GroupVersionKind. See https://kubernetes.io/docs/reference/using-api/deprecation-guide/.
+//
+//nolint:gochecknoglobals
+var removals = map[schema.GroupVersionKind]string{
+	{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet"}:         "v1.16.0",
+	{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}:        "v1.16.0",
+	{Group: "extensions", Version: "v1beta1", Kind: "NetworkPolicy"}:     "v1.16.0",
+	{Group: "extensions", Version: "v1beta1", Kind: "PodSecurityPolicy"}: "v1.16.0",
+	{Group: "extensions", Version: "v1beta1", Kind: "ReplicaSet"}:        "v1.16.0",
+	{Group: "apps", Version: "v1beta1", Kind: "Deployment"}:              "v1.16.0",
+	{Group: "apps", Version: "v1beta1", Kind: "StatefulSet"}:             "v1.16.0",
+	{Group: "apps", Version: "v1beta2", Kind: "DaemonSet"}:               "v1.16.0",
+	{Group: "apps", Version: "v1beta2", Kind: "Deployment"}:              "v1.16.0",
+	{Group: "apps", Version: "v1beta2", Kind: "ReplicaSet"}:              "v1.16.0",
+	{Group: "apps", Version: "v1beta2", Kind: "StatefulSet"}:             "v1.16.0",
+
+	{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}:                                          "v1.22.0",
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}:                                   "v1.22.0",
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "IngressClass"}:                              "v1.22.0",
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "MutatingWebhookConfiguration"}:   "v1.22.0",
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingWebhookConfiguration"}: "v1.22.0",
+	{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition"}:               "v1.22.0",
+	{Group: "apiregistration.k8s.io", Version: "v1beta1", Kind: "APIService"}:                           "v1.22.0",
+	{Group: "certificates.k8s.io", Version: "v1beta1", Kind: "CertificateSigningRequest"}:               "v1.22.0",
+	{Group: "coordination.k8s.io", Version: "v1beta1", Kind: "Lease"}:                                   "v1.22.0",
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRole"}:                       "v1.22.0",
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRoleBinding"}:                "v1.22.0",
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "Role"}:                              "v1.22.0",
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "RoleBinding"}:                       "v1.22.0",
+	{Group: "scheduling.k8s.io", Version: "v1beta1", Kind: "PriorityClass"}:                             "v1.22.0",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIDriver"}:                                    "v1.22.0",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSINode"}:                                      "v1.22.0",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "StorageClass"}:                                 "v1.22.0",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "VolumeAttachment"}:                             "v1.22.0",
+
+	{Group: "batch", Version: "v1beta1", Kind: "CronJob"}:                                           "v1.25.0",
+	{Group: "discovery.k8s.io", Version: "v1beta1", Kind: "EndpointSlice"}:                          "v1.25.0",
+	{Group: "autoscaling", Version: "v2beta1", Kind: "HorizontalPodAutoscaler"}:                     "v1.25.0",
+	{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"}:                              "v1.25.0",
+	{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy"}:                                "v1.25.0",
+	{Group: "node.k8s.io", Version: "v1beta1", Kind: "RuntimeClass"}:                                "v1.25.0",
+	{Group: "autoscaling", Version: "v2beta2", Kind: "HorizontalPodAutoscaler"}:                     "v1.26.0",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "FlowSchema"}:                 "v1.26.0",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "PriorityLevelConfiguration"}: "v1.26.0",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIStorageCapacity"}:                       "v1.27.0",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "FlowSchema"}:                 "v1.29.0",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "PriorityLevelConfiguration"}: "v1.29.0",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema"}:                 "v1.32.0",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "PriorityLevelConfiguration"}: "v1.32.0",
+}
+
+// Supported returns a validator rejecting objects whose apiVersion is removed in the Kubernetes version
+// the render targets, with an error wrapping ErrRemoved. It knows the removals of the built-in
+// apiVersions listed in the Kubernetes deprecation guide; WithRemoval adds others, e.g. of CRDs.
+//
+// The targeted version is read from the context (see engine.WithKubeVersion), or set with
+// WithKubeVersion when the validator runs outside of the engine. Without a version, every object is
+// accepted. Supported returns an error wrapping ErrInvalidVersion if a version cannot be parsed.
+func Supported(opts ...Option) (types.Validator, error) {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	var (
+		fallback *version.Version
+		err      error
+	)
+
+	if options.KubeVersion != "" {
+		if fallback, err = parse(options.KubeVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	removedIn := make(map[schema.GroupVersionKind]*version.Version, len(removals)+len(options.Removals))
+
+	for gvk, v := range removals {
+		removedIn[gvk] = version.MustParseGeneric(v)
+	}
+
+	for gvk, v := range options.Removals {
+		if removedIn[gvk], err = parse(v); err != nil {
+			return nil, fmt.Errorf("removal of %s: %w", gvk, err)
+		}
+	}
+
+	return func(ctx context.Context, obj unstructured.Unstructured) error {
+		removed, ok := removedIn[obj.GroupVersionKind()]
+		if !ok {
+			return nil
+		}
+
+		target := fallback
+
+		if v, ok := types.KubeVersion(ctx); ok {
+			parsed, err := parse(v)
+			if err != nil {
+				return validator.Wrap(obj, err)
+			}
+
+			target = parsed
+		}
+
+		if target == nil || !target.AtLeast(removed) {
+			return nil
+		}
+
+		return validator.Wrap(obj, fmt.Errorf(
+			"%w: %s %s was removed in Kubernetes v%s, targeting v%s",
+			ErrRemoved, obj.GetAPIVersion(), obj.GetKind(), removed, target,
+		))
+	}, nil
+}
+
+// parse parses a Kubernetes version.
+func parse(v string) (*version.Version, error) {
+	parsed, err := version.ParseGeneric(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidVersion, v, err)
+	}
+
+	return parsed, nil
+}
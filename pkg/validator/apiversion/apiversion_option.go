@@ -0,0 +1,58 @@
+package apiversion
+
+import (
+	"maps"
+
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the apiVersion validator.
+type Options struct {
+	// KubeVersion is the Kubernetes version validated against when the context carries none.
+	KubeVersion string
+
+	// Removals are the Kubernetes versions removing apiVersions, keyed by the removed GroupVersionKind.
+	// They are added to, and take precedence over, the built-in removals.
+	Removals map[schema.GroupVersionKind]string
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.KubeVersion != "" {
+		target.KubeVersion = opts.KubeVersion
+	}
+
+	if len(opts.Removals) > 0 {
+		if target.Removals == nil {
+			target.Removals = make(map[schema.GroupVersionKind]string, len(opts.Removals))
+		}
+
+		maps.Copy(target.Removals, opts.Removals)
+	}
+}
+
+// WithKubeVersion sets the Kubernetes version validated against when the context carries none,
+// e.g. when the validator is run with pipeline.ApplyValidators. It accepts the formats of
+// engine.WithKubeVersion.
+func WithKubeVersion(v string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.KubeVersion = v
+	})
+}
+
+// WithRemoval declares that gvk is no longer served as of the Kubernetes version removedIn,
+// e.g. "v1.30.0", typically for the deprecated versions of CRDs.
+func WithRemoval(gvk schema.GroupVersionKind, removedIn string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if o.Removals == nil {
+			o.Removals = make(map[schema.GroupVersionKind]string)
+		}
+
+		o.Removals[gvk] = removedIn
+	})
+}
@@ -0,0 +1,88 @@
+package apiversion_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/validator"
+	"github.com/k8s-manifest-kit/engine/pkg/validator/apiversion"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSupported(t *testing.T) {
+	g := NewWithT(t)
+
+	ingress := makeObject("extensions/v1beta1", "Ingress")
+
+	t.Run("should reject apiVersions removed in the version of the context", func(t *testing.T) {
+		v, err := apiversion.Supported()
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		err = v(types.WithKubeVersion(t.Context(), "v1.22.0"), ingress)
+		g.Expect(err).Should(MatchError(apiversion.ErrRemoved))
+		g.Expect(err).Should(MatchError(ContainSubstring("removed in Kubernetes v1.22.0, targeting v1.22.0")))
+
+		var validatorErr *validator.Error
+		g.Expect(err).Should(BeAssignableToTypeOf(validatorErr))
+	})
+
+	t.Run("should accept apiVersions served by the version of the context", func(t *testing.T) {
+		v, err := apiversion.Supported()
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(v(types.WithKubeVersion(t.Context(), "v1.21.14"), ingress)).Should(Succeed())
+		g.Expect(v(types.WithKubeVersion(t.Context(), "v1.31.0"), makeObject("networking.k8s.io/v1", "Ingress"))).
+			Should(Succeed())
+	})
+
+	t.Run("should accept everything without a version", func(t *testing.T) {
+		v, err := apiversion.Supported()
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(v(t.Context(), ingress)).Should(Succeed())
+	})
+
+	t.Run("should fall back to the configured version", func(t *testing.T) {
+		v, err := apiversion.Supported(apiversion.WithKubeVersion("1.25"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(v(t.Context(), makeObject("batch/v1beta1", "CronJob"))).Should(MatchError(apiversion.ErrRemoved))
+		g.Expect(v(types.WithKubeVersion(t.Context(), "v1.24.0"), makeObject("batch/v1beta1", "CronJob"))).
+			Should(Succeed())
+	})
+
+	t.Run("should honor custom removals", func(t *testing.T) {
+		gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1alpha1", Kind: "Widget"}
+
+		v, err := apiversion.Supported(apiversion.WithRemoval(gvk, "v1.30.0"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(v(types.WithKubeVersion(t.Context(), "v1.30.0"), makeObject("example.com/v1alpha1", "Widget"))).
+			Should(MatchError(apiversion.ErrRemoved))
+		g.Expect(v(types.WithKubeVersion(t.Context(), "v1.30.0"), makeObject("example.com/v1", "Widget"))).
+			Should(Succeed())
+	})
+
+	t.Run("should reject invalid versions", func(t *testing.T) {
+		_, err := apiversion.Supported(apiversion.WithKubeVersion("latest"))
+		g.Expect(err).Should(MatchError(apiversion.ErrInvalidVersion))
+
+		gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1alpha1", Kind: "Widget"}
+		_, err = apiversion.Supported(apiversion.WithRemoval(gvk, ""))
+		g.Expect(err).Should(MatchError(apiversion.ErrInvalidVersion))
+	})
+}
+
+func makeObject(apiVersion string, kind string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName("test")
+	obj.SetNamespace("default")
+
+	return obj
+}
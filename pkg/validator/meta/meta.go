@@ -0,0 +1,55 @@
+package meta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/util/scope"
+)
+
+var (
+	// ErrMissingAPIVersion is returned when an object has an empty apiVersion.
+	ErrMissingAPIVersion = errors.New("apiVersion must not be empty")
+
+	// ErrMissingKind is returned when an object has an empty kind.
+	ErrMissingKind = errors.New("kind must not be empty")
+
+	// ErrMissingName is returned when an object has an empty metadata.name.
+	ErrMissingName = errors.New("metadata.name must not be empty")
+
+	// ErrNamespaceOnClusterScoped is returned when a namespace is set on a cluster-scoped kind.
+	ErrNamespaceOnClusterScoped = errors.New("metadata.namespace must not be set on cluster-scoped kind")
+)
+
+// Validate returns a validator that checks the basic structure of an object.
+// An object is valid if it has a non-empty apiVersion, kind and metadata.name,
+// and does not set metadata.namespace when its kind is a well-known cluster-scoped kind
+// (e.g. Namespace, ClusterRole, CustomResourceDefinition).
+func Validate() types.Validator {
+	return func(_ context.Context, object unstructured.Unstructured) error {
+		var errs []error
+
+		if object.GetAPIVersion() == "" {
+			errs = append(errs, ErrMissingAPIVersion)
+		}
+
+		if object.GetKind() == "" {
+			errs = append(errs, ErrMissingKind)
+		}
+
+		if object.GetName() == "" {
+			errs = append(errs, ErrMissingName)
+		}
+
+		gk := object.GroupVersionKind().GroupKind()
+		if object.GetNamespace() != "" && scope.IsClusterScoped(gk) {
+			errs = append(errs, fmt.Errorf("%w %s", ErrNamespaceOnClusterScoped, gk))
+		}
+
+		return errors.Join(errs...)
+	}
+}
@@ -0,0 +1,68 @@
+package meta_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/engine/pkg/validator/meta"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestValidate(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should accept a well-formed namespaced object", func(t *testing.T) {
+		err := meta.Validate()(t.Context(), makeObject("v1", "Pod", "test", "default"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	t.Run("should accept a cluster-scoped object without namespace", func(t *testing.T) {
+		err := meta.Validate()(t.Context(), makeObject("rbac.authorization.k8s.io/v1", "ClusterRole", "admin", ""))
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	t.Run("should reject missing apiVersion", func(t *testing.T) {
+		err := meta.Validate()(t.Context(), makeObject("", "Pod", "test", ""))
+		g.Expect(err).Should(MatchError(meta.ErrMissingAPIVersion))
+	})
+
+	t.Run("should reject missing kind", func(t *testing.T) {
+		err := meta.Validate()(t.Context(), makeObject("v1", "", "test", ""))
+		g.Expect(err).Should(MatchError(meta.ErrMissingKind))
+	})
+
+	t.Run("should reject missing name", func(t *testing.T) {
+		err := meta.Validate()(t.Context(), makeObject("v1", "Pod", "", ""))
+		g.Expect(err).Should(MatchError(meta.ErrMissingName))
+	})
+
+	t.Run("should report all problems at once", func(t *testing.T) {
+		err := meta.Validate()(t.Context(), unstructured.Unstructured{Object: map[string]any{}})
+		g.Expect(err).Should(MatchError(meta.ErrMissingAPIVersion))
+		g.Expect(err).Should(MatchError(meta.ErrMissingKind))
+		g.Expect(err).Should(MatchError(meta.ErrMissingName))
+	})
+
+	t.Run("should reject namespace on cluster-scoped kind", func(t *testing.T) {
+		err := meta.Validate()(t.Context(), makeObject("v1", "Namespace", "test", "default"))
+		g.Expect(err).Should(MatchError(meta.ErrNamespaceOnClusterScoped))
+		g.Expect(err).Should(MatchError(ContainSubstring("Namespace")))
+	})
+
+	t.Run("should accept namespace on unknown kinds", func(t *testing.T) {
+		err := meta.Validate()(t.Context(), makeObject("example.com/v1", "Widget", "test", "default"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+}
+
+func makeObject(apiVersion string, kind string, name string, namespace string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+
+	return obj
+}
@@ -0,0 +1,141 @@
+// Package scheme provides a validator catching fields that unstructured objects silently accept,
+// such as typos, by decoding each object strictly into its typed form.
+package scheme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/validator"
+)
+
+const extensionPreserveUnknownFields = "x-kubernetes-preserve-unknown-fields"
+
+var (
+	// ErrUnknownKind is returned, with WithRejectUnknownKinds, for an object whose kind is neither
+	// registered in the scheme nor has a schema.
+	ErrUnknownKind = errors.New("kind is not registered in the scheme")
+
+	// ErrSchemaMismatch is returned when an object has unknown fields or fields of the wrong type.
+	ErrSchemaMismatch = errors.New("object does not match the schema of its kind")
+)
+
+// New returns a validator decoding each object strictly into the Go type registered for its kind
+// in s, e.g. client-go's scheme.Scheme. Unknown fields, such as "replics" instead of "replicas",
+// and fields of the wrong type fail validation with an error wrapping ErrSchemaMismatch.
+//
+// Kinds not registered in s, such as custom resources, are validated against the OpenAPI schema
+// set with WithSchema, where fields not declared by the schema are unknown unless it allows them
+// with additionalProperties or x-kubernetes-preserve-unknown-fields, as for CRD pruning.
+// Other kinds are skipped, or reported as invalid with WithRejectUnknownKinds.
+func New(s *runtime.Scheme, opts ...Option) types.Validator {
+	options := Options{}
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) error {
+		gvk := obj.GroupVersionKind()
+
+		if s.Recognizes(gvk) {
+			typed, err := s.New(gvk)
+			if err != nil {
+				return validator.Wrap(obj, err)
+			}
+
+			err = runtime.DefaultUnstructuredConverter.FromUnstructuredWithValidation(obj.Object, typed, true)
+			if err != nil {
+				return validator.Wrap(obj, fmt.Errorf("%w: %w", ErrSchemaMismatch, err))
+			}
+
+			return nil
+		}
+
+		if openAPISchema, ok := options.Schemas[gvk]; ok {
+			return validator.Wrap(obj, validateSchema(obj, openAPISchema))
+		}
+
+		if options.RejectUnknownKinds {
+			return validator.Wrap(obj, fmt.Errorf("%w: %s", ErrUnknownKind, gvk))
+		}
+
+		return nil
+	}
+}
+
+// validateSchema validates obj against an OpenAPI schema, reporting unknown fields as well.
+func validateSchema(obj unstructured.Unstructured, s *spec.Schema) error {
+	var unknown []string
+
+	root := obj.DeepCopy().Object
+	for _, field := range []string{"apiVersion", "kind", "metadata"} {
+		if _, declared := s.Properties[field]; !declared {
+			delete(root, field)
+		}
+	}
+
+	unknownFields("", root, s, &unknown)
+
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+
+		return fmt.Errorf("%w: unknown fields %q", ErrSchemaMismatch, unknown)
+	}
+
+	if err := validate.AgainstSchema(s, root, strfmt.Default); err != nil {
+		return fmt.Errorf("%w: %w", ErrSchemaMismatch, err)
+	}
+
+	return nil
+}
+
+// unknownFields appends to fields the paths of the fields of value not declared by s.
+func unknownFields(path string, value any, s *spec.Schema, fields *[]string) {
+	if s == nil {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		preserve, _ := s.Extensions.GetBool(extensionPreserveUnknownFields)
+
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+
+			if property, ok := s.Properties[key]; ok {
+				unknownFields(childPath, child, &property, fields)
+
+				continue
+			}
+
+			switch {
+			case s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+				unknownFields(childPath, child, s.AdditionalProperties.Schema, fields)
+			case s.AdditionalProperties != nil && s.AdditionalProperties.Allows, preserve:
+			default:
+				*fields = append(*fields, childPath)
+			}
+		}
+	case []any:
+		if s.Items == nil || s.Items.Schema == nil {
+			return
+		}
+
+		for i, item := range v {
+			unknownFields(path+"["+strconv.Itoa(i)+"]", item, s.Items.Schema, fields)
+		}
+	}
+}
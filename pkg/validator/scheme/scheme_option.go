@@ -0,0 +1,57 @@
+package scheme
+
+import (
+	"maps"
+
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the scheme validator.
+type Options struct {
+	// Schemas are the OpenAPI v3 schemas of kinds not registered in the scheme, such as custom resources,
+	// keyed by GroupVersionKind.
+	Schemas map[schema.GroupVersionKind]*spec.Schema
+
+	// RejectUnknownKinds reports objects whose kind is neither registered in the scheme nor has a schema
+	// as invalid, instead of skipping them.
+	RejectUnknownKinds bool
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if len(opts.Schemas) > 0 {
+		if target.Schemas == nil {
+			target.Schemas = make(map[schema.GroupVersionKind]*spec.Schema, len(opts.Schemas))
+		}
+
+		maps.Copy(target.Schemas, opts.Schemas)
+	}
+
+	target.RejectUnknownKinds = opts.RejectUnknownKinds
+}
+
+// WithSchema validates the objects of kind gvk against an OpenAPI v3 schema, typically the
+// openAPIV3Schema of a CustomResourceDefinition version. It is used for kinds not registered in the scheme.
+func WithSchema(gvk schema.GroupVersionKind, s *spec.Schema) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if o.Schemas == nil {
+			o.Schemas = make(map[schema.GroupVersionKind]*spec.Schema)
+		}
+
+		o.Schemas[gvk] = s
+	})
+}
+
+// WithRejectUnknownKinds controls whether objects whose kind is neither registered in the scheme nor has
+// a schema are reported as invalid (true) or skipped (false, the default).
+func WithRejectUnknownKinds(reject bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.RejectUnknownKinds = reject
+	})
+}
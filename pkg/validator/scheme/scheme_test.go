@@ -0,0 +1,131 @@
+package scheme_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	"github.com/k8s-manifest-kit/engine/pkg/validator"
+	"github.com/k8s-manifest-kit/engine/pkg/validator/scheme"
+
+	. "github.com/onsi/gomega"
+)
+
+const widgetSchema = `{
+	"type": "object",
+	"properties": {
+		"spec": {
+			"type": "object",
+			"properties": {
+				"size": {"type": "integer"},
+				"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+				"config": {"type": "object", "x-kubernetes-preserve-unknown-fields": true}
+			}
+		}
+	}
+}`
+
+var widgetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+func makeDeployment(spec map[string]any) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "web", "namespace": "default"},
+		"spec":       spec,
+	}}
+}
+
+func makeWidget(spec map[string]any) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]any{"name": "w1"},
+		"spec":       spec,
+	}}
+}
+
+func parseSchema(g *WithT, data string) *spec.Schema {
+	s := &spec.Schema{}
+	g.Expect(json.Unmarshal([]byte(data), s)).Should(Succeed())
+
+	return s
+}
+
+func TestNew(t *testing.T) {
+
+	t.Run("should accept a well-typed object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := scheme.New(clientgoscheme.Scheme)(t.Context(), makeDeployment(map[string]any{
+			"replicas": int64(3),
+			"selector": map[string]any{"matchLabels": map[string]any{"app": "web"}},
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	t.Run("should reject unknown fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := makeDeployment(map[string]any{"replics": int64(3)})
+
+		err := scheme.New(clientgoscheme.Scheme)(t.Context(), obj)
+		g.Expect(err).Should(MatchError(scheme.ErrSchemaMismatch))
+		g.Expect(err).Should(MatchError(ContainSubstring("spec.replics")))
+
+		var validatorErr *validator.Error
+		g.Expect(err).Should(BeAssignableToTypeOf(validatorErr))
+	})
+
+	t.Run("should reject fields of the wrong type", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := scheme.New(clientgoscheme.Scheme)(t.Context(), makeDeployment(map[string]any{"replicas": "three"}))
+		g.Expect(err).Should(MatchError(scheme.ErrSchemaMismatch))
+	})
+
+	t.Run("should skip unregistered kinds by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := scheme.New(clientgoscheme.Scheme)(t.Context(), makeWidget(map[string]any{"anything": true}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	t.Run("should reject unregistered kinds when configured", func(t *testing.T) {
+		g := NewWithT(t)
+
+		v := scheme.New(clientgoscheme.Scheme, scheme.WithRejectUnknownKinds(true))
+
+		err := v(t.Context(), makeWidget(nil))
+		g.Expect(err).Should(MatchError(scheme.ErrUnknownKind))
+		g.Expect(err).Should(MatchError(ContainSubstring("example.com/v1, Kind=Widget")))
+	})
+
+	t.Run("should validate unregistered kinds against their schema", func(t *testing.T) {
+		g := NewWithT(t)
+
+		v := scheme.New(clientgoscheme.Scheme, scheme.WithSchema(widgetGVK, parseSchema(g, widgetSchema)))
+
+		err := v(t.Context(), makeWidget(map[string]any{
+			"size":   int64(2),
+			"labels": map[string]any{"team": "web"},
+			"config": map[string]any{"free": map[string]any{"form": true}},
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		err = v(t.Context(), makeWidget(map[string]any{"sise": int64(2), "labels": map[string]any{"team": "web"}}))
+		g.Expect(err).Should(MatchError(scheme.ErrSchemaMismatch))
+		g.Expect(err).Should(MatchError(ContainSubstring(`unknown fields ["spec.sise"]`)))
+
+		err = v(t.Context(), makeWidget(map[string]any{"size": "two"}))
+		g.Expect(err).Should(MatchError(scheme.ErrSchemaMismatch))
+		g.Expect(err).Should(MatchError(ContainSubstring("spec.size")))
+
+		err = v(t.Context(), makeWidget(map[string]any{"labels": map[string]any{"replicas": int64(1)}}))
+		g.Expect(err).Should(MatchError(scheme.ErrSchemaMismatch))
+	})
+}
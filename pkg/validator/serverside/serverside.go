@@ -0,0 +1,103 @@
+package serverside
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/engine/pkg/validator"
+)
+
+// New returns a validator that submits each object to the API server as a dry-run
+// server-side apply, so that the server runs its full validation and admission chain
+// without persisting anything.
+//
+// A rejected object results in a validator.Error identifying the object and wrapping the
+// API server's error, which can be inspected with the k8s.io/apimachinery/pkg/api/errors helpers.
+func New(client dynamic.Interface, opts ...Option) types.Validator {
+	options := Options{
+		FieldManager: DefaultFieldManager,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(ctx context.Context, obj unstructured.Unstructured) error {
+		ri, err := resourceFor(client, options.RESTMapper, obj)
+		if err != nil {
+			if options.SkipUnregistered && meta.IsNoMatchError(err) {
+				return nil
+			}
+
+			return validator.Wrap(obj, err)
+		}
+
+		_, err = ri.Apply(ctx, obj.GetName(), &obj, metav1.ApplyOptions{
+			DryRun:       []string{metav1.DryRunAll},
+			FieldManager: options.FieldManager,
+			Force:        true,
+		})
+
+		if err != nil {
+			if options.SkipUnregistered && isUnregistered(err) {
+				return nil
+			}
+
+			return validator.Wrap(obj, fmt.Errorf("dry-run apply failed: %w", err))
+		}
+
+		return nil
+	}
+}
+
+// resourceFor returns the dynamic resource client for the object's kind and scope.
+func resourceFor(
+	client dynamic.Interface,
+	mapper meta.RESTMapper,
+	obj unstructured.Unstructured,
+) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+
+	if mapper == nil {
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		if obj.GetNamespace() == "" {
+			return client.Resource(gvr), nil
+		}
+
+		return client.Resource(gvr).Namespace(obj.GetNamespace()), nil
+	}
+
+	mapping, err := mapper.RESTMapping(schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}, gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to map %s to a resource: %w", gvk, err)
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return client.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+	}
+
+	return client.Resource(mapping.Resource), nil
+}
+
+// isUnregistered reports whether err is the API server's answer to a request for a resource
+// it does not serve. Such responses are NotFound errors without a named object, which
+// distinguishes them from e.g. a missing namespace.
+func isUnregistered(err error) bool {
+	var statusErr *apierrors.StatusError
+	if !errors.As(err, &statusErr) || !apierrors.IsNotFound(err) {
+		return false
+	}
+
+	details := statusErr.Status().Details
+
+	return details == nil || details.Name == ""
+}
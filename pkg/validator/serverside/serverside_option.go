@@ -0,0 +1,64 @@
+package serverside
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// DefaultFieldManager is the field manager used for dry-run requests when none is configured.
+const DefaultFieldManager = "k8s-manifest-kit"
+
+// Option is a generic option for Options.
+type Option = util.Option[Options]
+
+// Options represents the configuration of the server-side validator.
+type Options struct {
+	// FieldManager is the field manager sent with dry-run apply requests.
+	FieldManager string
+
+	// RESTMapper maps object kinds to API resources.
+	// If nil, resources are guessed from the kind and scope is inferred from metadata.namespace.
+	RESTMapper meta.RESTMapper
+
+	// SkipUnregistered skips objects whose kind is not served by the API server
+	// instead of reporting them as invalid.
+	SkipUnregistered bool
+}
+
+// ApplyTo implements the Option interface for Options.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.FieldManager != "" {
+		target.FieldManager = opts.FieldManager
+	}
+
+	if opts.RESTMapper != nil {
+		target.RESTMapper = opts.RESTMapper
+	}
+
+	target.SkipUnregistered = opts.SkipUnregistered
+}
+
+// WithFieldManager sets the field manager sent with dry-run apply requests.
+func WithFieldManager(fieldManager string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.FieldManager = fieldManager
+	})
+}
+
+// WithRESTMapper sets the RESTMapper used to resolve object kinds to API resources.
+// Without a mapper, the resource name is guessed from the kind, which does not work for
+// kinds with irregular plurals.
+func WithRESTMapper(mapper meta.RESTMapper) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.RESTMapper = mapper
+	})
+}
+
+// WithSkipUnregistered controls whether objects whose kind is not registered on the server
+// are skipped (true) or reported as validation failures (false, the default).
+func WithSkipUnregistered(skip bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.SkipUnregistered = skip
+	})
+}
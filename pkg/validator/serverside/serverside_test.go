@@ -0,0 +1,165 @@
+package serverside_test
+
+import (
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/k8s-manifest-kit/engine/pkg/validator"
+	"github.com/k8s-manifest-kit/engine/pkg/validator/serverside"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testNamespace = "default"
+)
+
+func TestNew(t *testing.T) {
+
+	t.Run("should submit a namespaced object as dry-run apply", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+		var actions []k8stesting.PatchAction
+		client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			actions = append(actions, action.(k8stesting.PatchAction))
+
+			return true, makeObject("v1", "Pod", "pod1", testNamespace), nil
+		})
+
+		v := serverside.New(client, serverside.WithRESTMapper(newMapper()))
+
+		err := v(t.Context(), *makeObject("v1", "Pod", "pod1", testNamespace))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(actions).Should(HaveLen(1))
+		g.Expect(actions[0].GetPatchType()).Should(Equal(k8stypes.ApplyPatchType))
+		g.Expect(actions[0].GetResource().Resource).Should(Equal("pods"))
+		g.Expect(actions[0].GetNamespace()).Should(Equal(testNamespace))
+		g.Expect(actions[0].GetName()).Should(Equal("pod1"))
+	})
+
+	t.Run("should submit a cluster-scoped object without namespace", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+		var actions []k8stesting.PatchAction
+		client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			actions = append(actions, action.(k8stesting.PatchAction))
+
+			return true, makeObject("v1", "Namespace", "team-a", ""), nil
+		})
+
+		v := serverside.New(client, serverside.WithRESTMapper(newMapper()))
+
+		err := v(t.Context(), *makeObject("v1", "Namespace", "team-a", ""))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(actions).Should(HaveLen(1))
+		g.Expect(actions[0].GetResource().Resource).Should(Equal("namespaces"))
+		g.Expect(actions[0].GetNamespace()).Should(BeEmpty())
+	})
+
+	t.Run("should return the server validation error with object identity", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		client.PrependReactor("patch", "*", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewInvalid(
+				schema.GroupKind{Kind: "Pod"},
+				"pod1",
+				field.ErrorList{field.Required(field.NewPath("spec", "containers"), "")},
+			)
+		})
+
+		v := serverside.New(client, serverside.WithRESTMapper(newMapper()))
+
+		err := v(t.Context(), *makeObject("v1", "Pod", "pod1", testNamespace))
+		g.Expect(err).Should(HaveOccurred())
+		g.Expect(apierrors.IsInvalid(err)).Should(BeTrue())
+		g.Expect(err.Error()).Should(ContainSubstring("spec.containers"))
+
+		var validatorErr *validator.Error
+		g.Expect(errors.As(err, &validatorErr)).Should(BeTrue())
+		g.Expect(validatorErr.Object.GetName()).Should(Equal("pod1"))
+	})
+
+	t.Run("should fail on kinds unknown to the mapper", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		v := serverside.New(client, serverside.WithRESTMapper(newMapper()))
+
+		err := v(t.Context(), *makeObject("example.com/v1", "Widget", "w1", testNamespace))
+		g.Expect(meta.IsNoMatchError(err)).Should(BeTrue())
+	})
+
+	t.Run("should skip kinds unknown to the mapper when requested", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		v := serverside.New(client,
+			serverside.WithRESTMapper(newMapper()),
+			serverside.WithSkipUnregistered(true),
+		)
+
+		err := v(t.Context(), *makeObject("example.com/v1", "Widget", "w1", testNamespace))
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	t.Run("should skip resources not served by the server when requested", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		client.PrependReactor("patch", "*", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "example.com", Resource: "widgets"}, "")
+		})
+
+		v := serverside.New(client, serverside.WithSkipUnregistered(true))
+
+		err := v(t.Context(), *makeObject("example.com/v1", "Widget", "w1", testNamespace))
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	t.Run("should not treat a missing namespace as an unregistered kind", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		client.PrependReactor("patch", "*", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "missing")
+		})
+
+		v := serverside.New(client, serverside.WithSkipUnregistered(true))
+
+		err := v(t.Context(), *makeObject("v1", "Pod", "pod1", "missing"))
+		g.Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+	})
+}
+
+func newMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+
+	return mapper
+}
+
+func makeObject(apiVersion string, kind string, name string, namespace string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+
+	return obj
+}